@@ -0,0 +1,25 @@
+package config
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+
+	"xquant/biz/handler"
+	appconfig "xquant/pkg/config"
+	"xquant/pkg/log"
+	"xquant/pkg/openapi_error"
+)
+
+// ReloadConfig 重新加载配置文件
+//
+//	校验失败时保持原有配置不变, 成功时返回变更项
+func ReloadConfig(ctx context.Context, c *app.RequestContext) {
+	result, err := appconfig.ReloadConfig("http")
+	if err != nil {
+		log.CtxErrorf(ctx, "reload config failed: %+v", err)
+		handler.OpenAPIFail(ctx, c, openapi_error.NewInvalidParameterError(ctx, "config", err.Error()))
+		return
+	}
+	handler.OpenAPISuccess(ctx, c, result)
+}
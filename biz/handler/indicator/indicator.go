@@ -0,0 +1,71 @@
+package indicator
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cloudwego/hertz/pkg/app"
+
+	"xquant/biz/handler"
+	indicatormodel "xquant/biz/model/indicator"
+	indicatorservice "xquant/biz/service/indicator"
+	"xquant/pkg/datasource/base"
+	"xquant/pkg/indicators"
+	"xquant/pkg/log"
+	"xquant/pkg/openapi_error"
+)
+
+// ListIndicators 已注册的技术指标及参数
+func ListIndicators(ctx context.Context, c *app.RequestContext) {
+	handler.OpenAPISuccess(ctx, c, indicatorservice.ListIndicators())
+}
+
+// IndicatorChart 证券的技术指标序列, 用于绘制图表
+func IndicatorChart(ctx context.Context, c *app.RequestContext) {
+	var req indicatormodel.ChartRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		log.CtxErrorf(ctx, "[IndicatorChart] error: %s", err)
+		handler.OpenAPIFail(ctx, c, openapi_error.NewInvalidParameterError(ctx, "", err.Error()))
+		return
+	}
+	if len(strings.TrimSpace(req.Code)) == 0 {
+		handler.OpenAPIFail(ctx, c, openapi_error.NewInvalidParameterError(ctx, "code", "证券代码不能为空"))
+		return
+	}
+	if _, ok := indicators.Lookup(req.Name); !ok {
+		handler.OpenAPIFail(ctx, c, openapi_error.NewInvalidParameterError(ctx, "name", "指标不存在"))
+		return
+	}
+	params, err := indicators.ParseParams(req.Params)
+	if err != nil {
+		handler.OpenAPIFail(ctx, c, openapi_error.NewInvalidParameterError(ctx, "params", err.Error()))
+		return
+	}
+	mode, err := base.ParseAdjustMode(req.Adjust)
+	if err != nil {
+		handler.OpenAPIFail(ctx, c, openapi_error.NewInvalidParameterError(ctx, "adjust", err.Error()))
+		return
+	}
+	adjustment := base.Adjustment{Mode: mode}
+	if anchor := strings.TrimSpace(req.Anchor); len(anchor) > 0 {
+		if mode != base.AdjustForward {
+			handler.OpenAPIFail(ctx, c, openapi_error.NewInvalidParameterError(ctx, "anchor", "锚定日期只适用于前复权"))
+			return
+		}
+		adjustment = base.ForwardAdjustedAt(anchor)
+	}
+	result, err := indicatorservice.RunChart(ctx, indicatorservice.ChartParams{
+		Code:       req.Code,
+		Name:       req.Name,
+		Params:     params,
+		StartDate:  req.StartDate,
+		EndDate:    req.EndDate,
+		Limit:      req.Limit,
+		Adjustment: adjustment,
+	})
+	if err != nil {
+		handler.OpenAPIFail(ctx, c, openapi_error.NewInvalidParameterError(ctx, "params", err.Error()))
+		return
+	}
+	handler.OpenAPISuccess(ctx, c, result)
+}
@@ -0,0 +1,50 @@
+package research
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cloudwego/hertz/pkg/app"
+
+	"xquant/biz/handler"
+	researchmodel "xquant/biz/model/research"
+	researchservice "xquant/biz/service/research"
+	"xquant/pkg/log"
+	"xquant/pkg/openapi_error"
+	"xquant/pkg/research"
+)
+
+// FactorResearch 计算因子的IC、IR、分位数收益和IC衰减
+func FactorResearch(ctx context.Context, c *app.RequestContext) {
+	var req researchmodel.FactorRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		log.CtxErrorf(ctx, "[FactorResearch] error: %s", err)
+		handler.OpenAPIFail(ctx, c, openapi_error.NewInvalidParameterError(ctx, "", err.Error()))
+		return
+	}
+	if len(strings.TrimSpace(req.Factor)) == 0 {
+		handler.OpenAPIFail(ctx, c, openapi_error.NewInvalidParameterError(ctx, "factor", "因子不能为空"))
+		return
+	}
+	if len(strings.TrimSpace(req.StartDate)) == 0 {
+		handler.OpenAPIFail(ctx, c, openapi_error.NewInvalidParameterError(ctx, "startDate", "开始日期不能为空"))
+		return
+	}
+	params := researchservice.ResearchParams{
+		Parameter: research.Parameter{
+			Factor:    req.Factor,
+			StartDate: req.StartDate,
+			EndDate:   req.EndDate,
+			Horizons:  req.Horizons,
+			Quantiles: req.Quantiles,
+			Decay:     req.Decay,
+		},
+		Export: req.Export,
+	}
+	result, err := researchservice.RunResearch(ctx, params)
+	if err != nil {
+		handler.OpenAPIFail(ctx, c, openapi_error.NewInvalidParameterError(ctx, "factor", err.Error()))
+		return
+	}
+	handler.OpenAPISuccess(ctx, c, result)
+}
@@ -0,0 +1,55 @@
+package sector
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudwego/hertz/pkg/app"
+
+	"xquant/biz/handler"
+	sectormodel "xquant/biz/model/sector"
+	sectorservice "xquant/biz/service/sector"
+	"xquant/pkg/log"
+	"xquant/pkg/openapi_error"
+	"xquant/pkg/sector"
+)
+
+// SectorRotation 板块轮动分析, 包括板块动量、排名变化、龙头持续性和新晋热点
+func SectorRotation(ctx context.Context, c *app.RequestContext) {
+	var req sectormodel.RotationRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		log.CtxErrorf(ctx, "[SectorRotation] error: %s", err)
+		handler.OpenAPIFail(ctx, c, openapi_error.NewInvalidParameterError(ctx, "", err.Error()))
+		return
+	}
+	if req.Days < 0 || req.TopN < 0 {
+		handler.OpenAPIFail(ctx, c, openapi_error.NewInvalidParameterError(ctx, "days", "天数和排名阈值不能为负数"))
+		return
+	}
+	result, err := sectorservice.RunRotation(ctx, sectorservice.RotationParams{
+		Date: req.Date,
+		Days: req.Days,
+		TopN: req.TopN,
+		Type: req.Type,
+	})
+	if err != nil {
+		field := ""
+		if errors.Is(err, sector.ErrNoRankings) {
+			field = "date"
+		}
+		handler.OpenAPIFail(ctx, c, openapi_error.NewInvalidParameterError(ctx, field, err.Error()))
+		return
+	}
+	handler.OpenAPISuccess(ctx, c, result)
+}
+
+// SectorRanking 板块每日排名或盘中的扫描记录
+func SectorRanking(ctx context.Context, c *app.RequestContext) {
+	var req sectormodel.RankingRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		log.CtxErrorf(ctx, "[SectorRanking] error: %s", err)
+		handler.OpenAPIFail(ctx, c, openapi_error.NewInvalidParameterError(ctx, "", err.Error()))
+		return
+	}
+	handler.OpenAPISuccess(ctx, c, sectorservice.ListRankings(ctx, req.Date, req.Intraday, req.Type))
+}
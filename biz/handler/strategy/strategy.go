@@ -0,0 +1,80 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/hertz/pkg/app"
+
+	"xquant/biz/handler"
+	strategymodel "xquant/biz/model/strategy"
+	"xquant/pkg/config"
+	"xquant/pkg/log"
+	"xquant/pkg/models"
+	"xquant/pkg/openapi_error"
+)
+
+// StrategySchemaResponse 策略参数描述
+type StrategySchemaResponse struct {
+	Metadata models.StrategyMetadata `json:"metadata"` // 策略元数据
+	Config   []config.FieldSchema    `json:"config"`   // 策略配置的字段描述
+}
+
+// EffectiveParameterResponse 策略生效的参数
+type EffectiveParameterResponse struct {
+	Configured bool                     `json:"configured"` // 配置文件中是否存在
+	Enabled    bool                     `json:"enabled"`    // 是否自动执行
+	Parameter  config.StrategyParameter `json:"parameter"`  // 合并默认值后的策略配置
+	Params     map[string]string        `json:"params"`     // 合并策略默认参数后的params
+}
+
+// ListStrategies 已注册的策略列表
+func ListStrategies(ctx context.Context, c *app.RequestContext) {
+	handler.OpenAPISuccess(ctx, c, models.StrategyCatalog())
+}
+
+// StrategySchema 策略的元数据和参数描述
+func StrategySchema(ctx context.Context, c *app.RequestContext) {
+	model, ok := bindStrategy(ctx, c)
+	if !ok {
+		return
+	}
+	resp := StrategySchemaResponse{
+		Metadata: models.GetMetadata(model),
+		Config:   config.StrategyParameterSchema(),
+	}
+	handler.OpenAPISuccess(ctx, c, resp)
+}
+
+// EffectiveParameter 策略生效的参数
+func EffectiveParameter(ctx context.Context, c *app.RequestContext) {
+	model, ok := bindStrategy(ctx, c)
+	if !ok {
+		return
+	}
+	meta := models.GetMetadata(model)
+	strategyParameter, found := config.LookupStrategyParameter(meta.Code)
+	resp := EffectiveParameterResponse{
+		Configured: found,
+		Enabled:    strategyParameter.Enable(),
+		Parameter:  strategyParameter,
+		Params:     meta.EffectiveParams(strategyParameter),
+	}
+	handler.OpenAPISuccess(ctx, c, resp)
+}
+
+// bindStrategy 解析请求中的策略编码并捡出策略
+func bindStrategy(ctx context.Context, c *app.RequestContext) (models.Strategy, bool) {
+	var req strategymodel.StrategyCodeRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		log.CtxErrorf(ctx, "[Strategy] 参数绑定失败: %s", err)
+		handler.OpenAPIFail(ctx, c, openapi_error.NewInvalidParameterError(ctx, "", err.Error()))
+		return nil, false
+	}
+	model, err := models.CheckoutStrategy(req.StrategyCode)
+	if err != nil {
+		handler.OpenAPIFail(ctx, c, openapi_error.NewInvalidParameterError(ctx, "strategyCode", fmt.Sprintf("%d, %s", req.StrategyCode, err.Error())))
+		return nil, false
+	}
+	return model, true
+}
@@ -3,6 +3,7 @@ package tracker
 import (
 	"context"
 
+	"gitee.com/quant1x/exchange"
 	"github.com/cloudwego/hertz/pkg/app"
 
 	"xquant/biz/handler"
@@ -10,6 +11,7 @@ import (
 	trackerservice "xquant/biz/service/tracker"
 	"xquant/pkg/log"
 	"xquant/pkg/openapi_error"
+	pkgtracker "xquant/pkg/tracker"
 )
 
 // Tracker 实时跟踪策略在当前市场的表现，输出表格
@@ -30,3 +32,18 @@ func Tracker(ctx context.Context, c *app.RequestContext) {
 
 	trackerservice.RunTrackerCore(ctx, trackerservice.TrackerCoreParams{TrackerStrategyCodes: trackerStrategyCodes, IsDebug: req.IsDebug})
 }
+
+// StrategyReport 实盘和影子模式策略的对照报告
+func StrategyReport(ctx context.Context, c *app.RequestContext) {
+	var req trackermodel.StrategyReportRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		log.CtxErrorf(ctx, "[StrategyReport] 参数绑定失败: %s", err)
+		handler.OpenAPIFail(ctx, c, openapi_error.NewInvalidParameterError(ctx, "date", err.Error()))
+		return
+	}
+	date := req.Date
+	if len(date) == 0 {
+		date = exchange.LastTradeDate()
+	}
+	handler.OpenAPISuccess(ctx, c, pkgtracker.StrategyReports(date))
+}
@@ -0,0 +1,13 @@
+package indicator
+
+// ChartRequest 指标图表请求
+type ChartRequest struct {
+	Code      string `json:"code" form:"code" query:"code"`                          // 证券代码
+	Name      string `json:"name" form:"name" query:"name"`                          // 指标名称, 比如KDJ
+	Params    string `json:"params,omitempty" form:"params" query:"params"`          // 指标参数, 格式为N=9,M1=3, 默认使用指标的默认参数
+	StartDate string `json:"startDate,omitempty" form:"startDate" query:"startDate"` // 开始日期
+	EndDate   string `json:"endDate,omitempty" form:"endDate" query:"endDate"`       // 结束日期, 默认最近一个交易日
+	Limit     int    `json:"limit,omitempty" form:"limit" query:"limit"`             // 开始日期为空时返回的K线数量, 默认250
+	Adjust    string `json:"adjust,omitempty" form:"adjust" query:"adjust"`          // 复权方式, qfq前复权(默认), none不复权, hfq后复权
+	Anchor    string `json:"anchor,omitempty" form:"anchor" query:"anchor"`          // 前复权的锚定日期, 默认锚定最新的数据
+}
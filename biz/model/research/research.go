@@ -0,0 +1,12 @@
+package research
+
+// FactorRequest 因子研究请求
+type FactorRequest struct {
+	Factor    string `json:"factor" form:"factor" query:"factor"`                    // 因子, 格式为<特征关键字>.<字段名>, 比如history.ma5
+	StartDate string `json:"startDate" form:"startDate" query:"startDate"`           // 开始日期
+	EndDate   string `json:"endDate,omitempty" form:"endDate" query:"endDate"`       // 结束日期, 默认最近一个交易日
+	Horizons  []int  `json:"horizons,omitempty" form:"horizons" query:"horizons"`    // 未来收益周期, 默认1,3,5日
+	Quantiles int    `json:"quantiles,omitempty" form:"quantiles" query:"quantiles"` // 分组数, 默认5组
+	Decay     int    `json:"decay,omitempty" form:"decay" query:"decay"`             // IC衰减最大滞后天数, 默认10天
+	Export    bool   `json:"export,omitempty" form:"export" query:"export"`          // 是否导出csv和html
+}
@@ -0,0 +1,16 @@
+package sector
+
+// RotationRequest 板块轮动分析请求
+type RotationRequest struct {
+	Date string `json:"date,omitempty" form:"date" query:"date"` // 截止日期, 默认最近一个交易日
+	Days int    `json:"days,omitempty" form:"days" query:"days"` // 观察的交易日数, 默认5
+	TopN int    `json:"topN,omitempty" form:"topN" query:"topN"` // 热点板块的排名阈值, 默认20
+	Type string `json:"type,omitempty" form:"type" query:"type"` // 板块类型, 为空时不过滤
+}
+
+// RankingRequest 板块排名请求
+type RankingRequest struct {
+	Date     string `json:"date,omitempty" form:"date" query:"date"`             // 日期, 默认最近一个交易日
+	Intraday bool   `json:"intraday,omitempty" form:"intraday" query:"intraday"` // 是否返回盘中全部的扫描记录
+	Type     string `json:"type,omitempty" form:"type" query:"type"`             // 板块类型, 为空时不过滤
+}
@@ -0,0 +1,6 @@
+package tracker
+
+// StrategyReportRequest 策略运行报告请求
+type StrategyReportRequest struct {
+	Date string `json:"date,omitempty" form:"date" query:"date"` // 交易日期, 默认最近一个交易日
+}
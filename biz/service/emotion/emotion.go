@@ -0,0 +1,56 @@
+package emotion
+
+import (
+	"context"
+	"fmt"
+
+	"gitee.com/quant1x/exchange"
+
+	"xquant/pkg/cache"
+	"xquant/pkg/emotion"
+	"xquant/pkg/log"
+)
+
+// EmotionParams 市场情绪指数计算参数
+// - StartDate: 开始日期, 默认同结束日期（对应 cmd 的 --start）
+// - EndDate: 结束日期, 默认当前可读缓存的日期（对应 cmd 的 --end）
+type EmotionParams struct {
+	StartDate string // 开始日期
+	EndDate   string // 结束日期
+}
+
+// RunEmotion 按日期升序逐日计算并保存收盘后的市场情绪指数
+//
+//	情绪周期阶段依赖前一个交易日的情绪分, 补算历史数据时需要按日期升序执行
+func RunEmotion(ctx context.Context, params EmotionParams) ([]emotion.Emotion, error) {
+	endDate := params.EndDate
+	if len(endDate) == 0 {
+		endDate = cache.DefaultCanReadDate()
+	}
+	endDate = exchange.FixTradeDate(endDate)
+	startDate := params.StartDate
+	if len(startDate) == 0 {
+		startDate = endDate
+	}
+	startDate = exchange.FixTradeDate(startDate)
+	dates := exchange.TradingDateRange(startDate, endDate)
+	if len(dates) == 0 {
+		err := fmt.Errorf("日期范围[%s, %s]内没有交易日", startDate, endDate)
+		log.CtxWarnf(ctx, "[RunEmotion] %v", err)
+		return nil, err
+	}
+	var list []emotion.Emotion
+	for _, date := range dates {
+		select {
+		case <-ctx.Done():
+			return list, ctx.Err()
+		default:
+		}
+		emotion.UpdateDaily(date)
+		if e := emotion.GetDaily(date); e != nil {
+			list = append(list, *e)
+		}
+		log.CtxInfof(ctx, "[RunEmotion] %s...OK", date)
+	}
+	return list, nil
+}
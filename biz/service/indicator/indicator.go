@@ -0,0 +1,121 @@
+package indicator
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"gitee.com/quant1x/exchange"
+	"xquant/pkg/cache"
+	"xquant/pkg/datasource/base"
+	"xquant/pkg/indicators"
+	"xquant/pkg/log"
+)
+
+const (
+	defaultChartLimit = 250 // 默认返回的K线数量
+)
+
+// ChartParams 指标图表参数
+type ChartParams struct {
+	Code       string             // 证券代码
+	Name       string             // 指标名称
+	Params     map[string]float64 // 指标参数, 未指定的取默认值
+	StartDate  string             // 开始日期
+	EndDate    string             // 结束日期, 默认最近一个交易日
+	Limit      int                // 开始日期为空时返回的K线数量
+	Adjustment base.Adjustment    // 复权方式, 默认前复权
+}
+
+// ChartResult 指标图表数据, 序列按日期升序对齐, 数据不足的位置为null
+type ChartResult struct {
+	Code    string                `json:"code"`    // 证券代码
+	Name    string                `json:"name"`    // 指标名称
+	Params  indicators.Params     `json:"params"`  // 生效的参数
+	Adjust  string                `json:"adjust"`  // 复权方式
+	Dates   []string              `json:"dates"`   // 日期
+	Open    []float64             `json:"open"`    // 开盘价
+	High    []float64             `json:"high"`    // 最高价
+	Low     []float64             `json:"low"`     // 最低价
+	Close   []float64             `json:"close"`   // 收盘价
+	Volume  []float64             `json:"volume"`  // 成交量
+	Columns map[string][]*float64 `json:"columns"` // 指标输出列
+}
+
+// ListIndicators 已注册的指标列表
+func ListIndicators() []indicators.Definition {
+	return indicators.List()
+}
+
+// RunChart 计算证券的指标序列
+//
+//	指标按截止结束日期的全部K线计算, 再截取展示区间, 避免预热期不足导致的偏差
+func RunChart(ctx context.Context, params ChartParams) (*ChartResult, error) {
+	def, ok := indicators.Lookup(params.Name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", indicators.ErrIndicatorNotFound, params.Name)
+	}
+	resolved, err := def.Resolve(params.Params)
+	if err != nil {
+		return nil, err
+	}
+	securityCode := exchange.CorrectSecurityCode(params.Code)
+	endDate := strings.TrimSpace(params.EndDate)
+	if len(endDate) == 0 {
+		endDate = cache.DefaultCanReadDate()
+	}
+	endDate = exchange.FixTradeDate(endDate)
+	klines := base.CheckoutAdjustedKLines(securityCode, endDate, params.Adjustment)
+	if len(klines) == 0 {
+		return nil, fmt.Errorf("%s: 没有截止%s的K线数据", securityCode, endDate)
+	}
+	bars := indicators.BarsFromKLines(klines)
+	values, err := def.Compute(bars, resolved)
+	if err != nil {
+		return nil, err
+	}
+	begin := 0
+	if startDate := strings.TrimSpace(params.StartDate); len(startDate) > 0 {
+		startDate = exchange.FixTradeDate(startDate)
+		for begin < len(klines) && klines[begin].Date < startDate {
+			begin++
+		}
+	} else {
+		limit := params.Limit
+		if limit <= 0 {
+			limit = defaultChartLimit
+		}
+		begin = max(len(klines)-limit, 0)
+	}
+	log.CtxInfof(ctx, "[RunChart] 证券=%s, 指标=%s, 复权=%s, 日期=%s~%s", securityCode, def.Name, params.Adjustment.Key(), bars.Date[min(begin, len(klines)-1)], endDate)
+	view := bars.Slice(begin, bars.Len())
+	result := &ChartResult{
+		Code:    securityCode,
+		Name:    def.Name,
+		Params:  resolved,
+		Adjust:  params.Adjustment.Key(),
+		Dates:   view.Date,
+		Open:    view.Open,
+		High:    view.High,
+		Low:     view.Low,
+		Close:   view.Close,
+		Volume:  view.Volume,
+		Columns: make(map[string][]*float64, len(def.Columns)),
+	}
+	for _, col := range def.Columns {
+		result.Columns[col] = nullable(values[col][begin:])
+	}
+	return result, nil
+}
+
+// nullable NaN和Inf转为null, json不支持NaN
+func nullable(values []float64) []*float64 {
+	out := make([]*float64, len(values))
+	for i := range values {
+		if !math.IsNaN(values[i]) && !math.IsInf(values[i], 0) {
+			out[i] = &values[i]
+		}
+	}
+	return out
+}
@@ -0,0 +1,77 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+
+	"xquant/pkg/cache"
+	"xquant/pkg/factors"
+	"xquant/pkg/log"
+)
+
+// MigrateParams 缓存转换参数
+// - Wide: 是否转换宽表（对应 cmd 的 --wide）
+// - KLine: 是否转换日K线（对应 cmd 的 --kline）
+// - Features: 是否转换特征缓存（对应 cmd 的 --features）
+// - FeaturesKeywords: 特征关键词, 为空时转换全部特征（对应 cmd 的 --keywords）
+// - RemoveSource: 转换成功后是否删除csv文件（对应 cmd 的 --remove-csv）
+type MigrateParams struct {
+	Wide             bool     // 转换宽表
+	KLine            bool     // 转换日K线
+	Features         bool     // 转换特征缓存
+	FeaturesKeywords []string // 特征关键词
+	RemoveSource     bool     // 删除csv文件
+}
+
+// MigrateResult 转换结果
+type MigrateResult struct {
+	WideFiles    int // 转换的宽表文件数
+	KLineFiles   int // 转换的日K线文件数
+	FeatureFiles int // 转换的特征文件数
+}
+
+// RunMigrate 把csv缓存转换成列式存储格式
+func RunMigrate(ctx context.Context, params MigrateParams) (MigrateResult, error) {
+	var result MigrateResult
+	if !params.Wide && !params.KLine && !params.Features {
+		err := fmt.Errorf("必须指定转换宽表、日K线或特征缓存")
+		log.CtxWarnf(ctx, "[RunMigrate] %v", err)
+		return result, err
+	}
+	if cache.StorageFormat() != cache.StorageFormatColumnar {
+		// 存储格式仍是csv时, 下次保存缓存会删除列式文件
+		log.CtxWarnf(ctx, "[RunMigrate] 当前存储格式为%s, 需要配置data.storage.format=%s才会使用列式文件", cache.StorageFormat(), cache.StorageFormatColumnar)
+	}
+	var err error
+	if params.Wide {
+		result.WideFiles, err = factors.MigrateWideTableToColumnar(params.RemoveSource)
+		if err != nil {
+			log.CtxErrorf(ctx, "[RunMigrate] 宽表转换失败: %v", err)
+			return result, err
+		}
+		log.CtxInfof(ctx, "[RunMigrate] 宽表转换完成, 文件数=%d", result.WideFiles)
+	}
+	if ctx.Err() != nil {
+		return result, ctx.Err()
+	}
+	if params.KLine {
+		result.KLineFiles, err = factors.MigrateKLineToColumnar(params.RemoveSource)
+		if err != nil {
+			log.CtxErrorf(ctx, "[RunMigrate] 日K线转换失败: %v", err)
+			return result, err
+		}
+		log.CtxInfof(ctx, "[RunMigrate] 日K线转换完成, 文件数=%d", result.KLineFiles)
+	}
+	if ctx.Err() != nil {
+		return result, ctx.Err()
+	}
+	if params.Features {
+		result.FeatureFiles, err = factors.MigrateFeaturesToColumnar(params.FeaturesKeywords, params.RemoveSource)
+		if err != nil {
+			log.CtxErrorf(ctx, "[RunMigrate] 特征缓存转换失败: %v", err)
+			return result, err
+		}
+		log.CtxInfof(ctx, "[RunMigrate] 特征缓存转换完成, 文件数=%d", result.FeatureFiles)
+	}
+	return result, nil
+}
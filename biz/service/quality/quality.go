@@ -0,0 +1,156 @@
+package quality
+
+import (
+	"context"
+	"fmt"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/gox/api"
+
+	"xquant/pkg/cache"
+	"xquant/pkg/factors"
+	"xquant/pkg/log"
+)
+
+// QualityParams 数据质量检查参数
+// - StartDate: 开始日期, 默认同结束日期（对应 cmd 的 --start）
+// - EndDate: 结束日期, 默认当前可读缓存的日期（对应 cmd 的 --end）
+// - FeaturesKeywords: 特征关键词, 为空时检查全部特征（对应 cmd 的 --features）
+type QualityParams struct {
+	StartDate        string   // 开始日期
+	EndDate          string   // 结束日期
+	FeaturesKeywords []string // 特征关键词
+}
+
+// FeatureReport 单个特征单个日期的检查结果
+type FeatureReport struct {
+	Name     string                 // 特征名称
+	Kind     cache.Kind             // 特征类型
+	Count    int                    // 检查数
+	Passed   int                    // 通过数
+	PassRate float64                // 通过率
+	LastRate float64                // 上一次记录的通过率, 没有记录时为-1
+	Failures []factors.CheckFailure // 不通过的证券代码
+}
+
+// Regressed 通过率比上一次记录下降
+func (r FeatureReport) Regressed() bool {
+	return r.LastRate >= 0 && r.PassRate < r.LastRate
+}
+
+// DateReport 单个日期的检查结果
+type DateReport struct {
+	Date        string          // 缓存日期
+	FeatureDate string          // 特征日期
+	Filename    string          // 不通过明细的文件名
+	Features    []FeatureReport // 特征检查结果
+}
+
+// QualityResult 数据质量检查结果
+type QualityResult struct {
+	Reports         []DateReport // 按日期的检查结果
+	HistoryFilename string       // 指标历史记录的文件名
+}
+
+// failureRecord 不通过明细的落地记录
+type failureRecord struct {
+	Date    string `name:"日期" dataframe:"date"`    // 缓存日期
+	Feature string `name:"特征" dataframe:"feature"` // 特征名称
+	Code    string `name:"证券代码" dataframe:"code"`  // 证券代码
+	Reason  string `name:"原因" dataframe:"reason"`  // 不通过的原因
+}
+
+// RunQuality 按日期范围检查特征数据, 保存不通过明细和指标历史记录
+func RunQuality(ctx context.Context, params QualityParams) (QualityResult, error) {
+	var result QualityResult
+	endDate := params.EndDate
+	if len(endDate) == 0 {
+		endDate = cache.DefaultCanReadDate()
+	}
+	endDate = exchange.FixTradeDate(endDate)
+	startDate := params.StartDate
+	if len(startDate) == 0 {
+		startDate = endDate
+	}
+	startDate = exchange.FixTradeDate(startDate)
+	dates := exchange.TradingDateRange(startDate, endDate)
+	if len(dates) == 0 {
+		err := fmt.Errorf("日期范围[%s, %s]内没有交易日", startDate, endDate)
+		log.CtxWarnf(ctx, "[RunQuality] %v", err)
+		return result, err
+	}
+	plugins := cache.PluginsWithName(cache.PluginMaskFeature, params.FeaturesKeywords...)
+	if len(plugins) == 0 {
+		plugins = cache.Plugins(cache.PluginMaskFeature)
+	}
+	var adapters []factors.FeatureRotationAdapter
+	for _, plugin := range plugins {
+		if adapter, ok := plugin.(factors.FeatureRotationAdapter); ok {
+			adapters = append(adapters, adapter)
+		}
+	}
+	if len(adapters) == 0 {
+		err := fmt.Errorf("没有可以检查的特征")
+		log.CtxWarnf(ctx, "[RunQuality] %v", err)
+		return result, err
+	}
+	history, err := cache.LoadMetricHistory()
+	if err != nil {
+		log.CtxWarnf(ctx, "[RunQuality] 指标历史加载失败: %v", err)
+	}
+	for _, date := range dates {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		cacheDate, featureDate := cache.CorrectDate(date)
+		report := DateReport{Date: cacheDate, FeatureDate: featureDate}
+		var metrics []cache.AdapterMetric
+		var records []failureRecord
+		for _, adapter := range adapters {
+			v := adapter.Validate(cacheDate, featureDate)
+			metrics = append(metrics, v.Metric)
+			record := cache.NewMetricRecord(cacheDate, v.Metric)
+			report.Features = append(report.Features, FeatureReport{
+				Name:     adapter.Name(),
+				Kind:     adapter.Kind(),
+				Count:    v.Metric.Count,
+				Passed:   v.Metric.Passed,
+				PassRate: record.PassRate,
+				LastRate: lastPassRate(history, adapter.Kind(), cacheDate),
+				Failures: v.Failures,
+			})
+			for _, failure := range v.Failures {
+				records = append(records, failureRecord{Date: cacheDate, Feature: adapter.Name(), Code: failure.Code, Reason: failure.Reason})
+			}
+			log.CtxInfof(ctx, "[RunQuality] %s %s, 检查数=%d, 通过数=%d", cacheDate, adapter.Name(), v.Metric.Count, v.Metric.Passed)
+		}
+		for _, metric := range metrics {
+			history = append(history, cache.NewMetricRecord(cacheDate, metric))
+		}
+		if err := cache.SaveMetricHistory(cacheDate, metrics); err != nil {
+			log.CtxErrorf(ctx, "[RunQuality] %s 指标历史保存失败: %v", cacheDate, err)
+			return result, err
+		}
+		if len(records) > 0 {
+			report.Filename = fmt.Sprintf("%s/failures.%s.csv", cache.GetQualityPath(), cacheDate)
+			if err := api.SlicesToCsv(report.Filename, records); err != nil {
+				log.CtxErrorf(ctx, "[RunQuality] %s 不通过明细保存失败: %v", cacheDate, err)
+				return result, err
+			}
+		}
+		result.Reports = append(result.Reports, report)
+	}
+	result.HistoryFilename = cache.MetricHistoryFilename()
+	return result, nil
+}
+
+// lastPassRate 指定日期之前最近一次记录的通过率, 没有记录时返回-1
+func lastPassRate(history []cache.MetricRecord, kind cache.Kind, date string) float64 {
+	rate, lastDate := -1.00, ""
+	for _, v := range history {
+		if v.Kind == kind && v.Date < date && v.Date > lastDate {
+			rate, lastDate = v.PassRate, v.Date
+		}
+	}
+	return rate
+}
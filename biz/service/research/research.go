@@ -0,0 +1,67 @@
+package research
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"xquant/pkg/cache"
+	"xquant/pkg/log"
+	"xquant/pkg/research"
+)
+
+// ResearchParams 因子研究参数
+type ResearchParams struct {
+	Parameter research.Parameter // 因子研究参数
+	Export    bool               // 是否导出csv和html
+	OutputDir string             // 导出路径, 为空时使用默认路径
+}
+
+// ResearchResult 因子研究结果
+type ResearchResult struct {
+	Report *research.Report `json:"report"`          // 研究报告
+	Files  []string         `json:"files,omitempty"` // 导出的文件
+}
+
+// RunResearch 执行因子研究, CLI和HTTP共用
+func RunResearch(ctx context.Context, params ResearchParams) (*ResearchResult, error) {
+	if len(strings.TrimSpace(params.Parameter.StartDate)) == 0 {
+		return nil, fmt.Errorf("开始日期不能为空")
+	}
+	if len(strings.TrimSpace(params.Parameter.EndDate)) == 0 {
+		params.Parameter.EndDate = cache.DefaultCanReadDate()
+	}
+	log.CtxInfof(ctx, "[RunResearch] 因子=%s, 日期=%s~%s", params.Parameter.Factor, params.Parameter.StartDate, params.Parameter.EndDate)
+	report, err := research.Analyze(params.Parameter)
+	if err != nil {
+		log.CtxErrorf(ctx, "[RunResearch] 因子研究失败: %v", err)
+		return nil, err
+	}
+	result := &ResearchResult{Report: report}
+	if params.Export {
+		result.Files, err = research.Export(report, params.OutputDir)
+		if err != nil {
+			log.CtxErrorf(ctx, "[RunResearch] 导出失败: %v", err)
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// ParseHorizons 解析逗号分隔的周期, 如 "1,3,5"
+func ParseHorizons(text string) ([]int, error) {
+	var list []int
+	for _, item := range strings.Split(text, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		h, err := strconv.Atoi(item)
+		if err != nil || h < 1 {
+			return nil, fmt.Errorf("无效的周期: %s", item)
+		}
+		list = append(list, h)
+	}
+	return list, nil
+}
@@ -0,0 +1,75 @@
+package sector
+
+import (
+	"context"
+	"strings"
+
+	"gitee.com/quant1x/exchange"
+	"xquant/pkg/cache"
+	"xquant/pkg/log"
+	"xquant/pkg/sector"
+)
+
+// RotationParams 板块轮动分析参数
+type RotationParams struct {
+	Date string // 截止日期
+	Days int    // 观察的交易日数
+	TopN int    // 热点板块的排名阈值
+	Type string // 板块类型
+}
+
+// RunRotation 分析板块轮动
+func RunRotation(ctx context.Context, params RotationParams) (*sector.Rotation, error) {
+	date := checkoutDate(params.Date)
+	log.CtxInfof(ctx, "[RunRotation] 日期=%s, 天数=%d, 前N=%d, 类型=%s", date, params.Days, params.TopN, params.Type)
+	rotation, err := sector.Analyze(date, params.Days, params.TopN)
+	if err != nil {
+		return nil, err
+	}
+	if blockType := strings.TrimSpace(params.Type); len(blockType) > 0 {
+		rotation.Sectors = filterByType(rotation.Sectors, blockType)
+		rotation.Emerging = filterByType(rotation.Emerging, blockType)
+	}
+	return rotation, nil
+}
+
+// ListRankings 板块排名
+func ListRankings(ctx context.Context, date string, intraday bool, blockType string) []sector.Ranking {
+	date = checkoutDate(date)
+	log.CtxInfof(ctx, "[ListRankings] 日期=%s, 盘中=%t, 类型=%s", date, intraday, blockType)
+	var list []sector.Ranking
+	if intraday {
+		list = sector.LoadIntraday(date)
+	} else {
+		list = sector.LoadRankings(date)
+	}
+	blockType = strings.TrimSpace(blockType)
+	if len(blockType) == 0 {
+		return list
+	}
+	var result []sector.Ranking
+	for _, v := range list {
+		if v.Type == blockType {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func checkoutDate(date string) string {
+	date = strings.TrimSpace(date)
+	if len(date) == 0 {
+		date = cache.DefaultCanReadDate()
+	}
+	return exchange.FixTradeDate(date)
+}
+
+func filterByType(list []sector.SectorRotation, blockType string) []sector.SectorRotation {
+	var result []sector.SectorRotation
+	for _, v := range list {
+		if v.Type == blockType {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"time"
+
+	"gitee.com/quant1x/exchange"
+
+	"xquant/pkg/emotion"
+	"xquant/pkg/log"
+)
+
+// 任务 - 盘中定时计算市场情绪指数
+func jobEmotion() {
+	now := time.Now()
+	updateInRealTime, status := exchange.CanUpdateInRealtime(now)
+	// 交易时间计算情绪指数
+	if updateInRealTime && (IsTrading(status) || exchange.CheckCallAuctionClose(now)) {
+		emotion.UpdateIntraday()
+	}
+}
+
+// 任务 - 收盘后计算当日的市场情绪指数
+func jobEmotionClose() {
+	if !exchange.DateIsTradingDay() {
+		return
+	}
+	date := exchange.GetCurrentlyDay()
+	log.Infof("计算市场情绪指数: %s...", date)
+	emotion.UpdateDaily(date)
+	log.Infof("计算市场情绪指数: %s...OK", date)
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"time"
+
+	"gitee.com/quant1x/exchange"
+
+	"xquant/pkg/log"
+	"xquant/pkg/tracker"
+)
+
+// 任务 - 盘中定时保存板块排名
+func jobSectorRanking() {
+	now := time.Now()
+	updateInRealTime, status := exchange.CanUpdateInRealtime(now)
+	// 交易时间保存板块排名
+	if updateInRealTime && (IsTrading(status) || exchange.CheckCallAuctionClose(now)) {
+		tracker.PersistSectorRankings(true)
+	}
+}
+
+// 任务 - 收盘后保存当日的板块排名
+func jobSectorRankingClose() {
+	if !exchange.DateIsTradingDay() {
+		return
+	}
+	log.Infof("保存板块排名...")
+	tracker.PersistSectorRankings(false)
+	log.Infof("保存板块排名...OK")
+}
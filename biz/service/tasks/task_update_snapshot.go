@@ -6,6 +6,7 @@ import (
 
 	"gitee.com/quant1x/exchange"
 
+	"xquant/pkg/factors"
 	"xquant/pkg/log"
 	"xquant/pkg/models"
 )
@@ -18,6 +19,9 @@ func jobUpdateSnapshot() {
 	// 交易时间更新数据
 	if updateInRealTime && (IsTrading(status) || exchange.CheckCallAuctionClose(now)) {
 		realtimeUpdateSnapshot()
+	} else {
+		// 非交易时间落盘缓冲的盘口记录和每日汇总
+		factors.FlushOrderBooks()
 	}
 	// debug环境可以 realtimeUpdateSnapshot()
 }
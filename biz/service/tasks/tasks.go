@@ -18,6 +18,14 @@ const (
 	cronSyncOrdersInterval = "2 15-23 * * *"
 	// cronMarginTrading 更新融资融券
 	cronMarginTrading = "5 9 * * *"
+	// cronSectorRanking 盘中保存板块排名的频次
+	cronSectorRanking = "@every 5m"
+	// cronSectorRankingClose 收盘后保存当日板块排名, 每天15点05分
+	cronSectorRankingClose = "5 15 * * *"
+	// cronEmotion 盘中计算市场情绪指数的频次
+	cronEmotion = "@every 5m"
+	// cronEmotionClose 收盘后计算当日市场情绪指数, 每天15点10分, 在K线更新之后
+	cronEmotionClose = "10 15 * * *"
 )
 
 const (
@@ -37,6 +45,10 @@ const (
 	keyCronSyncQmtOrder     = "sync_orders"     // 同步订单
 	keyCronResetNetwork     = "reset_network"   // 重置网络
 	keyCronMarginTrading    = "update_rzrq"     // 更新融资融券
+	keyCronSectorRanking    = "sector_rank"     // 盘中保存板块排名
+	keyCronSectorRankClose  = "sector_rank_eod" // 收盘保存板块排名
+	keyCronEmotion          = "emotion"         // 盘中计算市场情绪指数
+	keyCronEmotionClose     = "emotion_eod"     // 收盘计算市场情绪指数
 )
 
 func init() {
@@ -59,6 +71,30 @@ func init() {
 	if err != nil {
 		logger.Fatal(err)
 	}
+
+	// 盘中保存板块排名
+	err = Register(keyCronSectorRanking, cronSectorRanking, jobSectorRanking)
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	// 收盘保存板块排名
+	err = Register(keyCronSectorRankClose, cronSectorRankingClose, jobSectorRankingClose)
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	// 盘中计算市场情绪指数
+	err = Register(keyCronEmotion, cronEmotion, jobEmotion)
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	// 收盘计算市场情绪指数
+	err = Register(keyCronEmotionClose, cronEmotionClose, jobEmotionClose)
+	if err != nil {
+		logger.Fatal(err)
+	}
 }
 
 // IsTrading 状态是否交易中
@@ -70,6 +70,8 @@ func executeSingleTrack(ctx context.Context, cancel func(), params TrackerCorePa
 	// 非交易时段且非调试模式：调用取消函数，终止外层循环
 	if !isAllowed && !params.IsDebug {
 		log.CtxInfof(ctx, "[TrackerCore] 非交易时段（状态：%v）且未开启调试模式，停止跟踪", exchangeStatus)
+		// 收盘后未成交的影子订单作废
+		tracker.MatchShadowOrders(exchange.LastTradeDate(), true)
 		cancel() // 调用取消函数，触发 coreCtx.Done()，终止外层循环
 		return nil
 	}
@@ -83,6 +85,8 @@ func executeSingleTrack(ctx context.Context, cancel func(), params TrackerCorePa
 	// 2. 同步所有股票快照（准备数据）
 	barIndex := 1
 	models.SnapshotMgr.SyncAllSnapshots(ctx, &barIndex)
+	// 用最新快照撮合影子订单
+	tracker.MatchShadowOrders(exchange.LastTradeDate(), false)
 	// 3. 并行处理所有策略
 	var wg sync.WaitGroup
 	for _, strategyCode := range params.TrackerStrategyCodes {
@@ -217,34 +221,28 @@ func evaluateStocks(model models.Strategy, snapshots []factors.QuoteSnapshot) []
 		return nil
 	}
 
-	// 1. 提取股票代码
-	stockCodes := make([]string, 0, len(snapshots))
-	for _, snap := range snapshots {
-		stockCodes = append(stockCodes, snap.SecurityCode)
-	}
-
-	// 2. 创建结果映射（线程安全的 TreeMap）
+	// 1. 创建结果映射（线程安全的 TreeMap）
 	mapStock := concurrent.NewTreeMap[string, models.ResultInfo]()
 
-	// 3. 并发执行 Evaluate（每个股票独立评估）
+	// 2. 并发执行 Evaluate（每个股票独立评估, 多周期数据合并被评估的快照）
 	var wg sync.WaitGroup
-	for _, code := range stockCodes {
+	for i := range snapshots {
 		wg.Add(1)
-		go func(securityCode string) {
+		go func(snapshot *factors.QuoteSnapshot) {
 			defer wg.Done()
 			// Evaluate 方法会分析日线数据，符合条件的会写入 mapStock
-			model.Evaluate(securityCode, mapStock)
-		}(code)
+			models.Evaluate(model, snapshot.SecurityCode, snapshot, mapStock)
+		}(&snapshots[i])
 	}
 	wg.Wait()
 
-	// 4. 提取通过评估的股票代码集合
+	// 3. 提取通过评估的股票代码集合
 	evaluatedCodes := make(map[string]bool, mapStock.Size())
 	mapStock.Each(func(key string, value models.ResultInfo) {
 		evaluatedCodes[key] = true
 	})
 
-	// 5. 从原始快照中筛选出通过 Evaluate 的股票（保留完整快照数据）
+	// 4. 从原始快照中筛选出通过 Evaluate 的股票（保留完整快照数据）
 	var evaluatedSnapshots []factors.QuoteSnapshot
 	for _, snap := range snapshots {
 		if evaluatedCodes[snap.SecurityCode] {
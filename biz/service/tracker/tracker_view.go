@@ -107,7 +107,7 @@ func handleFullUpdateCore(ctx context.Context, cacheDate, featureDate string) {
 	// 2. 更新所有特征数据
 	featurePlugins := cache.Plugins(cache.PluginMaskFeature)
 	log.CtxInfof(ctx, "[handleFullUpdateCore] 特征数据插件数量: %d", len(featurePlugins))
-	if err := storages.FeaturesUpdate(&barIndex, cacheDate, featureDate, featurePlugins, cache.OpUpdate); err != nil {
+	if _, err := storages.FeaturesUpdate(&barIndex, cacheDate, featureDate, featurePlugins, cache.OpUpdate); err != nil {
 		log.CtxErrorf(ctx, "[handleFullUpdateCore] 特征数据全量更新失败: %v", err)
 		fmt.Printf("警告: 特征数据全量更新失败: %v\n", err)
 		return
@@ -150,7 +150,7 @@ func handleFeaturesUpdateCore(ctx context.Context, cacheDate, featureDate string
 
 	log.CtxInfof(ctx, "[handleFeaturesUpdateCore] 开始特征数据定向更新，关键词：%v，插件数量：%d", keywords, len(plugins))
 	// 执行更新
-	if err := storages.FeaturesUpdate(&barIndex, cacheDate, featureDate, plugins, cache.OpUpdate); err != nil {
+	if _, err := storages.FeaturesUpdate(&barIndex, cacheDate, featureDate, plugins, cache.OpUpdate); err != nil {
 		log.CtxErrorf(ctx, "[handleFeaturesUpdateCore] 特征数据定向更新失败: %v", err)
 		fmt.Printf("警告: 特征数据定向更新失败: %v\n", err)
 		return
@@ -116,7 +116,11 @@ func handleFullUpdate(ctx context.Context, cacheDate, featureDate string) {
 		featurePlugins = cache.Plugins(mask)
 	}
 	log.CtxInfof(ctx, "[handleFullUpdateCore] 特征数据插件数量: %d", len(featurePlugins))
-	storages.FeaturesUpdate(utils.IntPtr(1), cacheDate, featureDate, featurePlugins, cache.OpUpdate)
+	if _, err := storages.FeaturesUpdate(utils.IntPtr(1), cacheDate, featureDate, featurePlugins, cache.OpUpdate); err != nil {
+		log.CtxErrorf(ctx, "[handleFullUpdateCore] 特征数据全量更新失败: %v", err)
+		fmt.Printf("警告: 特征数据全量更新失败: %v\n", err)
+		return
+	}
 
 	log.CtxInfof(ctx, "[handleFullUpdateCore] 全量更新完成")
 	fmt.Println("全量更新完成")
@@ -142,5 +146,7 @@ func handleUpdateFeaturesWithKeywords(cacheDate, featureDate string, keywords ..
 		mask := cache.PluginMaskFeature
 		plugins = cache.Plugins(mask)
 	}
-	storages.FeaturesUpdate(utils.IntPtr(1), cacheDate, featureDate, plugins, cache.OpUpdate)
+	if _, err := storages.FeaturesUpdate(utils.IntPtr(1), cacheDate, featureDate, plugins, cache.OpUpdate); err != nil {
+		log.Errorf("特征数据更新失败: %v", err)
+	}
 }
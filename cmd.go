@@ -4,9 +4,11 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"xquant/pkg/config"
 	"xquant/pkg/models"
 )
 
@@ -49,6 +51,9 @@ var (
 func serverBootstrap(cmd *cobra.Command, args []string) {
 	//metrics.Init()
 	SnapshotManager = models.NewSnapshotManager()
+	// 监控配置文件, 修改后自动重载
+	stopWatch := config.WatchConfig(5 * time.Second)
+	defer stopWatch()
 
 	runHTTP()
 
@@ -119,6 +119,10 @@ func GlobalFlags() *cli.Command {
 
 	// 添加子命令
 	rootCmd.AddCommand(InitUpdateCmd())
+	rootCmd.AddCommand(InitMigrateCmd())
+	rootCmd.AddCommand(InitResearchCmd())
+	rootCmd.AddCommand(InitQualityCmd())
+	rootCmd.AddCommand(InitEmotionCmd())
 	// rootCmd.AddCommand(cmdBackTest)
 
 	return rootCmd
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"gitee.com/quant1x/pkg/tablewriter"
+	cmder "github.com/spf13/cobra"
+
+	emotionservice "xquant/biz/service/emotion"
+	"xquant/pkg/emotion"
+)
+
+var emotionFlags = struct {
+	Start string // --start：开始日期
+	End   string // --end：结束日期
+}{}
+
+// InitEmotionCmd 初始化市场情绪周期命令
+func InitEmotionCmd() *cmder.Command {
+	cmd := &cmder.Command{
+		Use:     "emotion",
+		Short:   "市场情绪周期命令",
+		Long:    "按日期范围逐日计算收盘后的市场情绪指数, 包括涨停数、炸板率、连板高度、晋级率、跌停数和昨日涨停溢价, 并保存为时间序列",
+		Example: "xquant emotion\nxquant emotion --start=2024-06-03 --end=2024-06-28",
+		Run:     runEmotionCmd,
+	}
+
+	cmd.Flags().StringVar(&emotionFlags.Start, "start", "", "开始日期, 默认同结束日期")
+	cmd.Flags().StringVar(&emotionFlags.End, "end", "", "结束日期, 默认最近一个交易日")
+
+	return cmd
+}
+
+// runEmotionCmd 参数转换和调用市场情绪指数计算
+func runEmotionCmd(cmd *cmder.Command, args []string) {
+	params := emotionservice.EmotionParams{
+		StartDate: emotionFlags.Start,
+		EndDate:   emotionFlags.End,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	setupCmdSignalHandler(ctx, cancel)
+
+	list, err := emotionservice.RunEmotion(ctx, params)
+	if err != nil {
+		fmt.Printf("市场情绪指数计算失败: %v\n", err)
+		_ = cmd.Usage()
+		return
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"日期", "涨停", "炸板率", "最高连板", "晋级率", "跌停", "昨日涨停溢价", "情绪分", "阶段"})
+	for _, v := range list {
+		table.Append([]string{
+			v.Date,
+			fmt.Sprintf("%d", v.LimitUpNum),
+			fmt.Sprintf("%.2f%%", v.BrokenRate),
+			fmt.Sprintf("%d", v.MaxBoards),
+			fmt.Sprintf("%.2f%%", v.PromotionRate),
+			fmt.Sprintf("%d", v.LimitDownNum),
+			fmt.Sprintf("%.2f%%", v.PremiumRate),
+			fmt.Sprintf("%.2f", v.Score),
+			v.PhaseName(),
+		})
+	}
+	table.Render()
+	fmt.Printf("情绪指数时间序列: %s\n", emotion.Filename())
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	cmder "github.com/spf13/cobra"
+
+	migrateservice "xquant/biz/service/migrate"
+	updateservice "xquant/biz/service/update"
+)
+
+var migrateFlags = struct {
+	Wide      bool   // --wide：转换宽表
+	KLine     bool   // --kline：转换日K线
+	Features  bool   // --features：转换特征缓存
+	Keywords  string // --keywords：特征关键词（逗号分隔）
+	RemoveCsv bool   // --remove-csv：转换后删除csv文件
+}{}
+
+// InitMigrateCmd 初始化缓存转换命令
+func InitMigrateCmd() *cmder.Command {
+	cmd := &cmder.Command{
+		Use:     "migrate",
+		Short:   "缓存格式转换命令",
+		Long:    "把已有的csv缓存（宽表、日K线、特征）转换成列式存储格式, 需要配合 data.storage.format=columnar 使用",
+		Example: "xquant migrate --wide --kline --features\nxquant migrate --features --keywords=misc,history --remove-csv",
+		Run:     runMigrateCmd,
+	}
+
+	cmd.Flags().BoolVar(&migrateFlags.Wide, "wide", false, "转换宽表缓存")
+	cmd.Flags().BoolVar(&migrateFlags.KLine, "kline", false, "转换日K线缓存")
+	cmd.Flags().BoolVar(&migrateFlags.Features, "features", false, "转换特征缓存")
+	cmd.Flags().StringVar(&migrateFlags.Keywords, "keywords", "", "特征关键词（逗号分隔，为空时转换全部特征）")
+	cmd.Flags().BoolVar(&migrateFlags.RemoveCsv, "remove-csv", false, "转换成功后删除csv文件")
+
+	return cmd
+}
+
+// runMigrateCmd 参数转换和调用转换逻辑
+func runMigrateCmd(cmd *cmder.Command, args []string) {
+	params := migrateservice.MigrateParams{
+		Wide:             migrateFlags.Wide,
+		KLine:            migrateFlags.KLine,
+		Features:         migrateFlags.Features,
+		FeaturesKeywords: updateservice.ParseFieldKeywords(migrateFlags.Keywords),
+		RemoveSource:     migrateFlags.RemoveCsv,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	setupCmdSignalHandler(ctx, cancel)
+
+	result, err := migrateservice.RunMigrate(ctx, params)
+	if err != nil {
+		fmt.Printf("转换失败: %v\n", err)
+		_ = cmd.Usage()
+		return
+	}
+	fmt.Printf("缓存转换完成, 宽表文件数=%d, 日K线文件数=%d, 特征文件数=%d\n", result.WideFiles, result.KLineFiles, result.FeatureFiles)
+}
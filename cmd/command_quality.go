@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"gitee.com/quant1x/pkg/tablewriter"
+	cmder "github.com/spf13/cobra"
+
+	qualityservice "xquant/biz/service/quality"
+	updateservice "xquant/biz/service/update"
+)
+
+var qualityFlags = struct {
+	Start    string // --start：开始日期
+	End      string // --end：结束日期
+	Features string // --features：特征关键词（逗号分隔）
+	Failures int    // --failures：每个特征输出的不通过明细条数
+}{}
+
+// InitQualityCmd 初始化数据质量检查命令
+func InitQualityCmd() *cmder.Command {
+	cmd := &cmder.Command{
+		Use:     "quality",
+		Short:   "数据质量检查命令",
+		Long:    "按特征的校验策略检查日期范围内的特征数据, 输出每个特征不通过的证券代码, 并保存检查指标的历史记录",
+		Example: "xquant quality\nxquant quality --start=2024-06-03 --end=2024-06-28 --features=misc,history",
+		Run:     runQualityCmd,
+	}
+
+	cmd.Flags().StringVar(&qualityFlags.Start, "start", "", "开始日期, 默认同结束日期")
+	cmd.Flags().StringVar(&qualityFlags.End, "end", "", "结束日期, 默认最近一个交易日")
+	cmd.Flags().StringVar(&qualityFlags.Features, "features", "", "特征关键词（逗号分隔，为空时检查全部特征）")
+	cmd.Flags().IntVar(&qualityFlags.Failures, "failures", 5, "每个特征输出的不通过明细条数")
+
+	return cmd
+}
+
+// runQualityCmd 参数转换和调用数据质量检查
+func runQualityCmd(cmd *cmder.Command, args []string) {
+	params := qualityservice.QualityParams{
+		StartDate:        qualityFlags.Start,
+		EndDate:          qualityFlags.End,
+		FeaturesKeywords: updateservice.ParseFieldKeywords(qualityFlags.Features),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	setupCmdSignalHandler(ctx, cancel)
+
+	result, err := qualityservice.RunQuality(ctx, params)
+	if err != nil {
+		fmt.Printf("数据质量检查失败: %v\n", err)
+		_ = cmd.Usage()
+		return
+	}
+	for _, report := range result.Reports {
+		fmt.Printf("缓存日期: %s, 特征日期: %s\n", report.Date, report.FeatureDate)
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"特征", "检查数", "通过数", "通过率", "上次通过率", "回退"})
+		for _, v := range report.Features {
+			lastRate := "-"
+			if v.LastRate >= 0 {
+				lastRate = fmt.Sprintf("%.2f%%", v.LastRate*100)
+			}
+			regressed := ""
+			if v.Regressed() {
+				regressed = "是"
+			}
+			table.Append([]string{
+				v.Name,
+				fmt.Sprintf("%d", v.Count),
+				fmt.Sprintf("%d", v.Passed),
+				fmt.Sprintf("%.2f%%", v.PassRate*100),
+				lastRate,
+				regressed,
+			})
+		}
+		table.Render()
+		for _, v := range report.Features {
+			for i, failure := range v.Failures {
+				if i >= qualityFlags.Failures {
+					fmt.Printf("  %s: 其余%d条省略\n", v.Name, len(v.Failures)-i)
+					break
+				}
+				fmt.Printf("  %s: %s, %s\n", v.Name, failure.Code, failure.Reason)
+			}
+		}
+		if len(report.Filename) > 0 {
+			fmt.Printf("不通过明细: %s\n", report.Filename)
+		}
+	}
+	fmt.Printf("指标历史记录: %s\n", result.HistoryFilename)
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"gitee.com/quant1x/pkg/tablewriter"
+	cmder "github.com/spf13/cobra"
+
+	researchservice "xquant/biz/service/research"
+	"xquant/pkg/research"
+)
+
+var researchFlags = struct {
+	Factor    string // --factor：因子
+	Start     string // --start：开始日期
+	End       string // --end：结束日期
+	Horizons  string // --horizons：未来收益周期（逗号分隔）
+	Quantiles int    // --quantiles：分组数
+	Decay     int    // --decay：IC衰减最大滞后天数
+	Output    string // --output：导出路径
+}{}
+
+// InitResearchCmd 初始化因子研究命令
+func InitResearchCmd() *cmder.Command {
+	cmd := &cmder.Command{
+		Use:     "research",
+		Short:   "因子研究命令",
+		Long:    "计算特征字段的截面RankIC、IC均值、IR、分位数组合收益、多空收益和IC衰减, 导出csv和html",
+		Example: "xquant research --factor=history.ma5 --start=2024-01-01\nxquant research --factor=wide.turnover_rate --start=2024-01-01 --end=2024-06-30 --horizons=1,5,10",
+		Run:     runResearchCmd,
+	}
+
+	cmd.Flags().StringVar(&researchFlags.Factor, "factor", "", "因子, 格式为<特征关键字>.<字段名>, wide表示宽表字段")
+	cmd.Flags().StringVar(&researchFlags.Start, "start", "", "开始日期")
+	cmd.Flags().StringVar(&researchFlags.End, "end", "", "结束日期, 默认最近一个交易日")
+	cmd.Flags().StringVar(&researchFlags.Horizons, "horizons", "1,3,5", "未来收益周期（逗号分隔）")
+	cmd.Flags().IntVar(&researchFlags.Quantiles, "quantiles", 5, "分组数")
+	cmd.Flags().IntVar(&researchFlags.Decay, "decay", 10, "IC衰减最大滞后天数")
+	cmd.Flags().StringVar(&researchFlags.Output, "output", "", "导出路径, 默认"+research.DefaultOutputPath())
+
+	return cmd
+}
+
+// runResearchCmd 参数转换和调用因子研究
+func runResearchCmd(cmd *cmder.Command, args []string) {
+	horizons, err := researchservice.ParseHorizons(researchFlags.Horizons)
+	if err != nil {
+		fmt.Println(err)
+		_ = cmd.Usage()
+		return
+	}
+	params := researchservice.ResearchParams{
+		Parameter: research.Parameter{
+			Factor:    researchFlags.Factor,
+			StartDate: researchFlags.Start,
+			EndDate:   researchFlags.End,
+			Horizons:  horizons,
+			Quantiles: researchFlags.Quantiles,
+			Decay:     researchFlags.Decay,
+		},
+		Export:    true,
+		OutputDir: researchFlags.Output,
+	}
+	result, err := researchservice.RunResearch(context.Background(), params)
+	if err != nil {
+		fmt.Printf("因子研究失败: %v\n", err)
+		_ = cmd.Usage()
+		return
+	}
+	report := result.Report
+	fmt.Printf("因子: %s, 日期: %s ~ %s\n", report.Factor, report.StartDate, report.EndDate)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"周期", "天数", "IC均值", "IC标准差", "IR", "IC为正占比", "多空收益"})
+	for _, v := range report.Summary {
+		table.Append([]string{
+			fmt.Sprintf("%d", v.Horizon),
+			fmt.Sprintf("%d", v.Days),
+			fmt.Sprintf("%.4f", v.ICMean),
+			fmt.Sprintf("%.4f", v.ICStd),
+			fmt.Sprintf("%.4f", v.IR),
+			fmt.Sprintf("%.2f%%", v.PositiveRatio*100),
+			fmt.Sprintf("%.4f%%", v.LongShort*100),
+		})
+	}
+	table.Render()
+	for _, filename := range result.Files {
+		fmt.Println(filename)
+	}
+}
@@ -0,0 +1,415 @@
+package alpha
+
+import (
+	"math"
+)
+
+// Bars 按时间升序排列的日K线序列
+type Bars struct {
+	Open   []float64
+	High   []float64
+	Low    []float64
+	Close  []float64
+	Volume []float64 // 成交量, 单位股
+	Amount []float64 // 成交额, 单位元
+}
+
+// Len K线数量
+func (b Bars) Len() int {
+	return len(b.Close)
+}
+
+// Vwap 成交均价
+func (b Bars) Vwap() []float64 {
+	return Zip(b.Amount, b.Volume, func(amount, volume float64) float64 {
+		if volume <= 0 {
+			return math.NaN()
+		}
+		return amount / volume
+	})
+}
+
+// Factor 因子定义
+type Factor struct {
+	Name        string               // 因子名称, 同特征字段的dataframe标签
+	Description string               // 因子说明
+	Window      int                  // 计算需要的最少K线数量
+	Compute     func(b Bars) float64 // 计算最后一个交易日的因子值
+}
+
+// Factors 全部因子, 顺序和特征字段一致
+//
+//	Alpha101中截面rank在最外层的公式, 去掉rank只保留时间序列部分, 单调变换不影响截面排序;
+//	rank嵌套在内层的公式依赖全市场截面, 不在单证券特征里计算
+var Factors = []Factor{
+	{Name: "alpha006", Description: "-1*correlation(open, volume, 10)", Window: 10, Compute: Alpha006},
+	{Name: "alpha009", Description: "收盘价变化的趋势延续/反转", Window: 6, Compute: Alpha009},
+	{Name: "alpha012", Description: "sign(delta(volume, 1))*(-1*delta(close, 1))", Window: 2, Compute: Alpha012},
+	{Name: "alpha021", Description: "均线和波动带的位置与量比", Window: 20, Compute: Alpha021},
+	{Name: "alpha023", Description: "突破20日均高后的回落", Window: 20, Compute: Alpha023},
+	{Name: "alpha024", Description: "100日均线斜率和收盘价位置", Window: 201, Compute: Alpha024},
+	{Name: "alpha026", Description: "-1*ts_max(correlation(ts_rank(volume, 5), ts_rank(high, 5), 5), 3)", Window: 15, Compute: Alpha026},
+	{Name: "alpha033", Description: "-1*(1-open/close)", Window: 1, Compute: Alpha033},
+	{Name: "alpha035", Description: "ts_rank(volume, 32)*(1-ts_rank(close+high-low, 16))*(1-ts_rank(returns, 32))", Window: 33, Compute: Alpha035},
+	{Name: "alpha041", Description: "sqrt(high*low)-vwap", Window: 1, Compute: Alpha041},
+	{Name: "alpha046", Description: "20日和10日收盘价斜率差的反转", Window: 21, Compute: Alpha046},
+	{Name: "alpha049", Description: "收盘价斜率差小于-0.1时做多", Window: 21, Compute: Alpha049},
+	{Name: "alpha051", Description: "收盘价斜率差小于-0.05时做多", Window: 21, Compute: Alpha051},
+	{Name: "alpha053", Description: "-1*delta(((close-low)-(high-close))/(close-low), 9)", Window: 10, Compute: Alpha053},
+	{Name: "alpha054", Description: "-1*(low-close)*open^5/((low-high)*close^5)", Window: 1, Compute: Alpha054},
+	{Name: "alpha101", Description: "(close-open)/(high-low+0.001)", Window: 1, Compute: Alpha101},
+	{Name: "mom5", Description: "5日动量", Window: 6, Compute: Momentum(5)},
+	{Name: "mom20", Description: "20日动量", Window: 21, Compute: Momentum(20)},
+	{Name: "mom60", Description: "60日动量", Window: 61, Compute: Momentum(60)},
+	{Name: "mom120_20", Description: "跳过最近20日的120日动量", Window: 121, Compute: MomentumSkip(120, 20)},
+	{Name: "rev1", Description: "1日反转", Window: 2, Compute: Reversal(1)},
+	{Name: "rev5", Description: "5日反转", Window: 6, Compute: Reversal(5)},
+	{Name: "bias20", Description: "20日乖离率", Window: 20, Compute: Bias(20)},
+	{Name: "volatility20", Description: "20日收益率标准差", Window: 21, Compute: Volatility(20)},
+	{Name: "volatility60", Description: "60日收益率标准差", Window: 61, Compute: Volatility(60)},
+	{Name: "downside_vol20", Description: "20日下行波动率", Window: 21, Compute: DownsideVolatility(20)},
+	{Name: "skew20", Description: "20日收益率偏度", Window: 21, Compute: Skewness(20)},
+	{Name: "max_ret20", Description: "20日最大单日收益", Window: 21, Compute: MaxReturn(20)},
+	{Name: "atr14", Description: "14日平均真实波幅占收盘价比", Window: 15, Compute: ATRRatio(14)},
+	{Name: "amihud20", Description: "20日Amihud非流动性, 每亿元成交额的价格冲击", Window: 21, Compute: Amihud(20)},
+	{Name: "volume_ratio5_20", Description: "5日均量与20日均量之比", Window: 20, Compute: VolumeRatio(5, 20)},
+	{Name: "log_amount20", Description: "20日平均成交额的对数", Window: 20, Compute: LogAmount(20)},
+	{Name: "position20", Description: "收盘价在20日高低区间的位置", Window: 20, Compute: Position(20)},
+}
+
+// Compute 计算全部因子, 数据不足的因子为NaN
+func Compute(b Bars) map[string]float64 {
+	values := make(map[string]float64, len(Factors))
+	for _, f := range Factors {
+		if b.Len() < f.Window {
+			values[f.Name] = math.NaN()
+			continue
+		}
+		values[f.Name] = f.Compute(b)
+	}
+	return values
+}
+
+// Alpha006 -1 * correlation(open, volume, 10)
+func Alpha006(b Bars) float64 {
+	return -1 * Last(Correlation(b.Open, b.Volume, 10))
+}
+
+// Alpha009 (0 < ts_min(delta(close, 1), 5)) ? delta(close, 1) : ((ts_max(delta(close, 1), 5) < 0) ? delta(close, 1) : (-1 * delta(close, 1)))
+func Alpha009(b Bars) float64 {
+	d := Delta(b.Close, 1)
+	delta := Last(d)
+	if Last(TsMin(d, 5)) > 0 || Last(TsMax(d, 5)) < 0 {
+		return delta
+	}
+	return -1 * delta
+}
+
+// Alpha012 sign(delta(volume, 1)) * (-1 * delta(close, 1))
+func Alpha012(b Bars) float64 {
+	return Sign(Last(Delta(b.Volume, 1))) * (-1 * Last(Delta(b.Close, 1)))
+}
+
+// Alpha021 ((sum(close, 8) / 8) + stddev(close, 8)) < (sum(close, 2) / 2)) ? -1 : (((sum(close, 2) / 2) < ((sum(close, 8) / 8) - stddev(close, 8))) ? 1 : (((1 < (volume / adv20)) || ((volume / adv20) == 1)) ? 1 : -1))
+func Alpha021(b Bars) float64 {
+	ma8, sd8, ma2 := Last(Mean(b.Close, 8)), Last(StdDev(b.Close, 8)), Last(Mean(b.Close, 2))
+	adv20 := Last(Mean(b.Volume, 20))
+	switch {
+	case math.IsNaN(ma8) || math.IsNaN(adv20) || adv20 <= 0:
+		return math.NaN()
+	case ma8+sd8 < ma2:
+		return -1
+	case ma2 < ma8-sd8:
+		return 1
+	case Last(b.Volume)/adv20 >= 1:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// Alpha023 ((sum(high, 20) / 20) < high) ? (-1 * delta(high, 2)) : 0
+func Alpha023(b Bars) float64 {
+	if Last(Mean(b.High, 20)) < Last(b.High) {
+		return -1 * Last(Delta(b.High, 2))
+	}
+	return 0
+}
+
+// Alpha024 ((delta(sum(close, 100) / 100, 100) / delay(close, 100)) <= 0.05) ? (-1 * (close - ts_min(close, 100))) : (-1 * delta(close, 3))
+func Alpha024(b Bars) float64 {
+	slope := Last(Delta(Mean(b.Close, 100), 100)) / Last(Delay(b.Close, 100))
+	if math.IsNaN(slope) {
+		return math.NaN()
+	}
+	if slope <= 0.05 {
+		return -1 * (Last(b.Close) - Last(TsMin(b.Close, 100)))
+	}
+	return -1 * Last(Delta(b.Close, 3))
+}
+
+// Alpha026 -1 * ts_max(correlation(ts_rank(volume, 5), ts_rank(high, 5), 5), 3)
+func Alpha026(b Bars) float64 {
+	corr := Correlation(TsRank(b.Volume, 5), TsRank(b.High, 5), 5)
+	// 窗口内价量排名不变时相关系数无定义, 视为不相关
+	corr = Apply(corr, func(v float64) float64 {
+		if math.IsNaN(v) {
+			return 0
+		}
+		return v
+	})
+	return -1 * Last(TsMax(corr, 3))
+}
+
+// Alpha033 rank(-1 * ((1 - (open / close))^1)), 去掉rank
+func Alpha033(b Bars) float64 {
+	c := Last(b.Close)
+	if c == 0 {
+		return math.NaN()
+	}
+	return -1 * (1 - Last(b.Open)/c)
+}
+
+// Alpha035 (ts_rank(volume, 32) * (1 - ts_rank(((close + high) - low), 16))) * (1 - ts_rank(returns, 32))
+func Alpha035(b Bars) float64 {
+	chl := Zip(Zip(b.Close, b.High, func(c, h float64) float64 { return c + h }), b.Low, func(v, l float64) float64 { return v - l })
+	return Last(TsRank(b.Volume, 32)) * (1 - Last(TsRank(chl, 16))) * (1 - Last(TsRank(Returns(b.Close), 32)))
+}
+
+// Alpha041 ((high * low)^0.5) - vwap
+func Alpha041(b Bars) float64 {
+	return math.Sqrt(Last(b.High)*Last(b.Low)) - Last(b.Vwap())
+}
+
+// slopeSpread ((delay(close, 20) - delay(close, 10)) / 10) - ((delay(close, 10) - close) / 10)
+func slopeSpread(b Bars) float64 {
+	c10, c20 := Last(Delay(b.Close, 10)), Last(Delay(b.Close, 20))
+	return (c20-c10)/10 - (c10-Last(b.Close))/10
+}
+
+// Alpha046 (0.25 < x) ? -1 : ((x < 0) ? 1 : (-1 * (close - delay(close, 1)))), x为slopeSpread
+func Alpha046(b Bars) float64 {
+	x := slopeSpread(b)
+	switch {
+	case math.IsNaN(x):
+		return math.NaN()
+	case x > 0.25:
+		return -1
+	case x < 0:
+		return 1
+	default:
+		return -1 * Last(Delta(b.Close, 1))
+	}
+}
+
+// Alpha049 (x < -0.1) ? 1 : (-1 * (close - delay(close, 1))), x为slopeSpread
+func Alpha049(b Bars) float64 {
+	return slopeThreshold(b, -0.1)
+}
+
+// Alpha051 (x < -0.05) ? 1 : (-1 * (close - delay(close, 1))), x为slopeSpread
+func Alpha051(b Bars) float64 {
+	return slopeThreshold(b, -0.05)
+}
+
+func slopeThreshold(b Bars, threshold float64) float64 {
+	x := slopeSpread(b)
+	switch {
+	case math.IsNaN(x):
+		return math.NaN()
+	case x < threshold:
+		return 1
+	default:
+		return -1 * Last(Delta(b.Close, 1))
+	}
+}
+
+// Alpha053 -1 * delta((((close - low) - (high - close)) / (close - low)), 9)
+func Alpha053(b Bars) float64 {
+	n := b.Len()
+	x := make([]float64, n)
+	for i := 0; i < n; i++ {
+		cl := b.Close[i] - b.Low[i]
+		if cl == 0 {
+			// 收在最低价, 用极小的价差避免除0
+			cl = 0.0001
+		}
+		x[i] = (cl - (b.High[i] - b.Close[i])) / cl
+	}
+	return -1 * Last(Delta(x, 9))
+}
+
+// Alpha054 (-1 * ((low - close) * (open^5))) / ((low - high) * (close^5))
+func Alpha054(b Bars) float64 {
+	o, h, l, c := Last(b.Open), Last(b.High), Last(b.Low), Last(b.Close)
+	d := (l - h) * math.Pow(c, 5)
+	if d == 0 {
+		return 0
+	}
+	return -1 * (l - c) * math.Pow(o, 5) / d
+}
+
+// Alpha101 ((close - open) / ((high - low) + .001))
+func Alpha101(b Bars) float64 {
+	return (Last(b.Close) - Last(b.Open)) / (Last(b.High) - Last(b.Low) + 0.001)
+}
+
+// Momentum n日动量, close / delay(close, n) - 1
+func Momentum(n int) func(b Bars) float64 {
+	return MomentumSkip(n, 0)
+}
+
+// MomentumSkip 跳过最近skip日的n日动量, delay(close, skip) / delay(close, n) - 1
+func MomentumSkip(n, skip int) func(b Bars) float64 {
+	return func(b Bars) float64 {
+		begin, end := Last(Delay(b.Close, n)), Last(Delay(b.Close, skip))
+		if begin == 0 {
+			return math.NaN()
+		}
+		return end/begin - 1
+	}
+}
+
+// Reversal n日反转, 动量取反
+func Reversal(n int) func(b Bars) float64 {
+	momentum := Momentum(n)
+	return func(b Bars) float64 {
+		return -1 * momentum(b)
+	}
+}
+
+// Bias n日乖离率, close / mean(close, n) - 1
+func Bias(n int) func(b Bars) float64 {
+	return func(b Bars) float64 {
+		ma := Last(Mean(b.Close, n))
+		if ma == 0 {
+			return math.NaN()
+		}
+		return Last(b.Close)/ma - 1
+	}
+}
+
+// Volatility n日收益率标准差
+func Volatility(n int) func(b Bars) float64 {
+	return func(b Bars) float64 {
+		return Last(StdDev(Returns(b.Close), n))
+	}
+}
+
+// DownsideVolatility n日下行波动率, 只统计负收益的平方和
+func DownsideVolatility(n int) func(b Bars) float64 {
+	return func(b Bars) float64 {
+		returns := tail(Returns(b.Close), n)
+		if len(returns) < n {
+			return math.NaN()
+		}
+		s := 0.00
+		for _, v := range returns {
+			if v < 0 {
+				s += v * v
+			}
+		}
+		return math.Sqrt(s / float64(n))
+	}
+}
+
+// Skewness n日收益率偏度
+func Skewness(n int) func(b Bars) float64 {
+	return func(b Bars) float64 {
+		returns := tail(Returns(b.Close), n)
+		if len(returns) < n {
+			return math.NaN()
+		}
+		m, sd := mean(returns), stddev(returns)
+		if sd == 0 {
+			return 0
+		}
+		s := 0.00
+		for _, v := range returns {
+			s += math.Pow((v-m)/sd, 3)
+		}
+		return s / float64(n)
+	}
+}
+
+// MaxReturn n日最大单日收益
+func MaxReturn(n int) func(b Bars) float64 {
+	return func(b Bars) float64 {
+		return Last(TsMax(Returns(b.Close), n))
+	}
+}
+
+// ATRRatio n日平均真实波幅占收盘价的比例
+func ATRRatio(n int) func(b Bars) float64 {
+	return func(b Bars) float64 {
+		size := b.Len()
+		tr := nans(size)
+		for i := 1; i < size; i++ {
+			prev := b.Close[i-1]
+			tr[i] = max(b.High[i]-b.Low[i], math.Abs(b.High[i]-prev), math.Abs(b.Low[i]-prev))
+		}
+		c := Last(b.Close)
+		if c == 0 {
+			return math.NaN()
+		}
+		return Last(Mean(tr, n)) / c
+	}
+}
+
+// Amihud n日Amihud非流动性, mean(abs(returns) / amount), 成交额按亿元计
+func Amihud(n int) func(b Bars) float64 {
+	return func(b Bars) float64 {
+		x := Zip(Returns(b.Close), b.Amount, func(r, amount float64) float64 {
+			if amount <= 0 {
+				return math.NaN()
+			}
+			return math.Abs(r) / (amount / 1e8)
+		})
+		return Last(Mean(x, n))
+	}
+}
+
+// VolumeRatio 短期均量与长期均量之比
+func VolumeRatio(short, long int) func(b Bars) float64 {
+	return func(b Bars) float64 {
+		mvLong := Last(Mean(b.Volume, long))
+		if mvLong == 0 {
+			return math.NaN()
+		}
+		return Last(Mean(b.Volume, short)) / mvLong
+	}
+}
+
+// LogAmount n日平均成交额的自然对数
+func LogAmount(n int) func(b Bars) float64 {
+	return func(b Bars) float64 {
+		amount := Last(Mean(b.Amount, n))
+		if amount <= 0 {
+			return math.NaN()
+		}
+		return math.Log(amount)
+	}
+}
+
+// Position 收盘价在n日最高价和最低价区间的位置, 取值[0, 1]
+func Position(n int) func(b Bars) float64 {
+	return func(b Bars) float64 {
+		hhv, llv := Last(TsMax(b.High, n)), Last(TsMin(b.Low, n))
+		if hhv <= llv {
+			return math.NaN()
+		}
+		return (Last(b.Close) - llv) / (hhv - llv)
+	}
+}
+
+func tail(x []float64, n int) []float64 {
+	if len(x) > n {
+		x = x[len(x)-n:]
+	}
+	for _, v := range x {
+		if math.IsNaN(v) {
+			return nil
+		}
+	}
+	return x
+}
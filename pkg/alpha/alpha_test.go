@@ -0,0 +1,65 @@
+package alpha
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// testBars 生成n根K线, 收盘价按正弦波动向上
+func testBars(n int) Bars {
+	var b Bars
+	for i := 0; i < n; i++ {
+		c := 10 + float64(i)*0.05 + math.Sin(float64(i)/3)
+		b.Open = append(b.Open, c-0.1)
+		b.High = append(b.High, c+0.3)
+		b.Low = append(b.Low, c-0.4)
+		b.Close = append(b.Close, c)
+		v := 1e6 + float64(i%7)*1e5
+		b.Volume = append(b.Volume, v)
+		b.Amount = append(b.Amount, v*c)
+	}
+	return b
+}
+
+func TestSeries(t *testing.T) {
+	x := []float64{1, 2, 3, 4, 5}
+	if v := Last(Delta(x, 2)); v != 2 {
+		t.Errorf("delta = %f", v)
+	}
+	if v := Last(Mean(x, 5)); v != 3 {
+		t.Errorf("mean = %f", v)
+	}
+	if v := Last(TsRank(x, 3)); v != 1 {
+		t.Errorf("ts_rank = %f", v)
+	}
+	if v := Last(Correlation(x, []float64{2, 4, 6, 8, 10}, 5)); math.Abs(v-1) > 1e-12 {
+		t.Errorf("correlation = %f", v)
+	}
+	if v := Mean(x, 6); !math.IsNaN(Last(v)) {
+		t.Errorf("insufficient window should be NaN")
+	}
+}
+
+func TestCompute(t *testing.T) {
+	b := testBars(260)
+	values := Compute(b)
+	if len(values) != len(Factors) {
+		t.Fatalf("factors = %d, values = %d", len(Factors), len(values))
+	}
+	for _, f := range Factors {
+		v := values[f.Name]
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			t.Errorf("%s = %v", f.Name, v)
+		}
+		fmt.Printf("%-18s %12.6f %s\n", f.Name, v, f.Description)
+	}
+	if v := values["position20"]; v < 0 || v > 1 {
+		t.Errorf("position20 = %f", v)
+	}
+	// 数据不足时为NaN
+	short := Compute(testBars(10))
+	if !math.IsNaN(short["mom60"]) {
+		t.Errorf("mom60 should be NaN, got %f", short["mom60"])
+	}
+}
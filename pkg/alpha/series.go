@@ -0,0 +1,193 @@
+package alpha
+
+import (
+	"math"
+	"slices"
+)
+
+// 时间序列算子, 输入输出按时间升序对齐, 窗口数据不足或含NaN的位置输出NaN
+
+// Delay d个周期前的值
+func Delay(x []float64, d int) []float64 {
+	out := nans(len(x))
+	for i := d; i < len(x); i++ {
+		out[i] = x[i-d]
+	}
+	return out
+}
+
+// Delta 当前值减去d个周期前的值
+func Delta(x []float64, d int) []float64 {
+	out := nans(len(x))
+	for i := d; i < len(x); i++ {
+		out[i] = x[i] - x[i-d]
+	}
+	return out
+}
+
+// Sum 窗口求和
+func Sum(x []float64, w int) []float64 {
+	return rolling(x, w, func(window []float64) float64 {
+		s := 0.00
+		for _, v := range window {
+			s += v
+		}
+		return s
+	})
+}
+
+// Mean 窗口均值
+func Mean(x []float64, w int) []float64 {
+	return rolling(x, w, mean)
+}
+
+// StdDev 窗口标准差, 总体标准差
+func StdDev(x []float64, w int) []float64 {
+	return rolling(x, w, stddev)
+}
+
+// TsMax 窗口最大值
+func TsMax(x []float64, w int) []float64 {
+	return rolling(x, w, slices.Max[[]float64])
+}
+
+// TsMin 窗口最小值
+func TsMin(x []float64, w int) []float64 {
+	return rolling(x, w, slices.Min[[]float64])
+}
+
+// TsRank 当前值在窗口内的百分比排名, 取值(0, 1]
+func TsRank(x []float64, w int) []float64 {
+	return rolling(x, w, func(window []float64) float64 {
+		last := window[len(window)-1]
+		less, equal := 0, 0
+		for _, v := range window {
+			if v < last {
+				less++
+			} else if v == last {
+				equal++
+			}
+		}
+		// 相同值取平均排名
+		return (float64(less) + float64(equal+1)/2) / float64(len(window))
+	})
+}
+
+// Correlation 窗口皮尔逊相关系数, 任一序列方差为0时输出NaN
+func Correlation(x, y []float64, w int) []float64 {
+	n := min(len(x), len(y))
+	out := nans(n)
+	for i := w - 1; i < n; i++ {
+		out[i] = pearson(x[i-w+1:i+1], y[i-w+1:i+1])
+	}
+	return out
+}
+
+// Returns 日收益率
+func Returns(x []float64) []float64 {
+	out := nans(len(x))
+	for i := 1; i < len(x); i++ {
+		if x[i-1] != 0 {
+			out[i] = x[i]/x[i-1] - 1
+		}
+	}
+	return out
+}
+
+// Zip 两个序列逐元素计算
+func Zip(x, y []float64, f func(a, b float64) float64) []float64 {
+	n := min(len(x), len(y))
+	out := make([]float64, n)
+	for i := 0; i < n; i++ {
+		out[i] = f(x[i], y[i])
+	}
+	return out
+}
+
+// Apply 序列逐元素计算
+func Apply(x []float64, f func(v float64) float64) []float64 {
+	out := make([]float64, len(x))
+	for i, v := range x {
+		out[i] = f(v)
+	}
+	return out
+}
+
+// Last 序列的最后一个值, 空序列返回NaN
+func Last(x []float64) float64 {
+	if len(x) == 0 {
+		return math.NaN()
+	}
+	return x[len(x)-1]
+}
+
+// Sign 符号函数
+func Sign(v float64) float64 {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	default:
+		return v
+	}
+}
+
+func nans(n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	return out
+}
+
+// rolling 滑动窗口计算, 窗口内有NaN时输出NaN
+func rolling(x []float64, w int, f func(window []float64) float64) []float64 {
+	out := nans(len(x))
+	if w < 1 {
+		return out
+	}
+	for i := w - 1; i < len(x); i++ {
+		window := x[i-w+1 : i+1]
+		if slices.ContainsFunc(window, math.IsNaN) {
+			continue
+		}
+		out[i] = f(window)
+	}
+	return out
+}
+
+func mean(values []float64) float64 {
+	s := 0.00
+	for _, v := range values {
+		s += v
+	}
+	return s / float64(len(values))
+}
+
+func stddev(values []float64) float64 {
+	m := mean(values)
+	s := 0.00
+	for _, v := range values {
+		s += (v - m) * (v - m)
+	}
+	return math.Sqrt(s / float64(len(values)))
+}
+
+func pearson(x, y []float64) float64 {
+	if slices.ContainsFunc(x, math.IsNaN) || slices.ContainsFunc(y, math.IsNaN) {
+		return math.NaN()
+	}
+	mx, my := mean(x), mean(y)
+	sxy, sxx, syy := 0.00, 0.00, 0.00
+	for i := range x {
+		dx, dy := x[i]-mx, y[i]-my
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return math.NaN()
+	}
+	return sxy / math.Sqrt(sxx*syy)
+}
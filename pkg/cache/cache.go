@@ -81,10 +81,12 @@ func initCache() {
 	}
 	// 检查配置文件并加载配置
 	if !found {
-		config.GlobalConfig = config.ReadConfig(GetRootPath())
+		config.SetGlobalConfig(config.ReadConfig(GetRootPath()))
 	} else {
-		config.GlobalConfig = tmpConfig
+		config.SetGlobalConfig(tmpConfig)
 	}
+	// 配置变更审计日志
+	config.SetAuditFilename(cacheLogPath + "/config_audit.log")
 
 	// 启动性能分析
 	config.StartPprof()
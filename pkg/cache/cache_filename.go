@@ -42,6 +42,14 @@ func WideFilename(code string) string {
 	return filepath
 }
 
+// ChipsFilename 筹码分布缓存路径, protobuf格式
+func ChipsFilename(code string) string {
+	cacheId := CacheId(code)
+	length := len(cacheId)
+	filename := fmt.Sprintf("%s/%s/%s.pb", GetChipsPath(), cacheId[:length-3], cacheId)
+	return filename
+}
+
 func MinuteFilename(code, date string) string {
 	date = exchange.FixTradeDate(date, FilenameDate)
 	cacheId := CacheId(code)
@@ -103,6 +111,13 @@ func TransFilename(code, date string) string {
 	return tickPath
 }
 
+// LhbFilename 龙虎榜席位明细, 每个交易日一个文件, 目录结构${lhb}/${YYYY}/${YYYYMMDD}.csv
+func LhbFilename(date string) string {
+	date = exchange.FixTradeDate(date, FilenameDate)
+	filename := fmt.Sprintf("%s/%s/%s.csv", GetLhbPath(), date[0:4], date)
+	return filename
+}
+
 // FundFlowFilename 通过证券代码获取资金流向的缓存文件路径
 func FundFlowFilename(securityCode string) string {
 	cacheId := CacheId(securityCode)
@@ -111,7 +126,30 @@ func FundFlowFilename(securityCode string) string {
 	return filepath
 }
 
-// SnapshotFilename 快照数据文件
+// OrderFlowFilename 成交分析缓存路径, 每个证券一个文件, 每个交易日一条记录
+func OrderFlowFilename(securityCode string) string {
+	cacheId := CacheId(securityCode)
+	length := len(cacheId)
+	filepath := fmt.Sprintf("%s/%s/%s.csv", GetOrderFlowPath(), cacheId[:length-3], cacheId)
+	return filepath
+}
+
+// UnlockFilename 限售解禁缓存路径, 每个证券一个文件, 每个解禁批次一条记录
+func UnlockFilename(securityCode string) string {
+	cacheId := CacheId(securityCode)
+	length := len(cacheId)
+	filepath := fmt.Sprintf("%s/%s/%s.csv", GetUnlockPath(), cacheId[:length-3], cacheId)
+	return filepath
+}
+
+// OrderBookSummaryFilename 盘口分析的每日汇总, 每个证券最后一个快照一条记录, 目录结构${snapshot}/${YYYY}/${YYYYMMDD}.csv
+func OrderBookSummaryFilename(date string) string {
+	date = exchange.FixTradeDate(date, FilenameDate)
+	filename := fmt.Sprintf("%s/%s/%s.csv", GetSnapshotPath(), date[0:4], date)
+	return filename
+}
+
+// SnapshotFilename 快照数据文件, 目录结构${snapshot}/${YYYY}/${YYYYMMDD}/${CacheId}.csv
 func SnapshotFilename(securityCode string, date string) string {
 	date = exchange.FixTradeDate(date, FilenameDate)
 	cacheId := CacheId(securityCode)
@@ -11,13 +11,20 @@ const (
 	cacheMinutePath = "minutes" // 分时路径
 	cacheInfoPath   = "info"    // 信息路径
 	//cacheTickPath     = "tick"     // tick路径
-	cacheXdxrPath     = "xdxr"     // 除权除息路径
-	cacheWidePath     = "wide"     // 宽表路径
-	cacheFinancePath  = "finance"  // 财务信息路径
-	cacheSnapshotPath = "snapshot" // 快照数据路径
-	cacheHoldingPath  = "holding"  // 流通股东数据路径
-	cacheFundFlowPath = "fund"     // 资金流向
-	cacheTransPath    = "trans"    // 成交数据
+	cacheXdxrPath      = "xdxr"      // 除权除息路径
+	cacheWidePath      = "wide"      // 宽表路径
+	cacheFinancePath   = "finance"   // 财务信息路径
+	cacheSnapshotPath  = "snapshot"  // 快照数据路径
+	cacheHoldingPath   = "holding"   // 流通股东数据路径
+	cacheFundFlowPath  = "fund"      // 资金流向
+	cacheTransPath     = "trans"     // 成交数据
+	cacheChipsPath     = "chips"     // 筹码分布
+	cacheQualityPath   = "quality"   // 数据质量报告
+	cacheSectorPath    = "sector"    // 板块排名
+	cacheEmotionPath   = "emotion"   // 市场情绪周期
+	cacheLhbPath       = "lhb"       // 龙虎榜
+	cacheOrderFlowPath = "orderflow" // 成交分析
+	cacheUnlockPath    = "unlock"    // 限售解禁
 )
 
 // GetMetaPath 元数据路径
@@ -47,6 +54,41 @@ func GetWidePath() string {
 	return GetRootPath() + "/" + cacheWidePath
 }
 
+// GetChipsPath 筹码分布路径
+func GetChipsPath() string {
+	return GetRootPath() + "/" + cacheChipsPath
+}
+
+// GetQualityPath 数据质量报告路径
+func GetQualityPath() string {
+	return GetRootPath() + "/" + cacheQualityPath
+}
+
+// GetSectorPath 板块排名路径
+func GetSectorPath() string {
+	return GetRootPath() + "/" + cacheSectorPath
+}
+
+// GetEmotionPath 市场情绪周期路径
+func GetEmotionPath() string {
+	return GetRootPath() + "/" + cacheEmotionPath
+}
+
+// GetLhbPath 龙虎榜路径
+func GetLhbPath() string {
+	return GetRootPath() + "/" + cacheLhbPath
+}
+
+// GetOrderFlowPath 成交分析路径
+func GetOrderFlowPath() string {
+	return GetRootPath() + "/" + cacheOrderFlowPath
+}
+
+// GetUnlockPath 限售解禁路径
+func GetUnlockPath() string {
+	return GetRootPath() + "/" + cacheUnlockPath
+}
+
 // GetXdxrPath 除权除息文件存储路径
 func GetXdxrPath() string {
 	return GetRootPath() + "/" + cacheXdxrPath
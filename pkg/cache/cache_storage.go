@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"os"
+	"strings"
+
+	"gitee.com/quant1x/gox/api"
+	"gitee.com/quant1x/gox/logger"
+
+	"xquant/pkg/columnar"
+	"xquant/pkg/config"
+)
+
+const (
+	StorageFormatCSV      = "csv"      // csv格式
+	StorageFormatColumnar = "columnar" // 列式二进制格式
+)
+
+// ColumnarFilename 获取csv缓存文件对应的列式文件名
+//
+//	去掉.csv扩展名后加上.col, 没有扩展名的文件直接加上.col
+func ColumnarFilename(filename string) string {
+	return strings.TrimSuffix(filename, ".csv") + columnar.FileExtension
+}
+
+// StorageFormat 获取配置的存储格式
+func StorageFormat() string {
+	format := strings.ToLower(strings.TrimSpace(config.GetDataConfig().Storage.Format))
+	if format == StorageFormatColumnar {
+		return StorageFormatColumnar
+	}
+	return StorageFormatCSV
+}
+
+// LoadSlices 加载缓存文件到结构体切片, pointer为切片的指针
+//
+//	优先读取列式文件, 列式文件不存在时读取csv文件
+func LoadSlices(filename string, pointer any) error {
+	colFilename := ColumnarFilename(filename)
+	if api.FileExist(colFilename) {
+		f, err := columnar.Open(colFilename, config.GetDataConfig().Storage.Mmap)
+		if err == nil {
+			// 解码时数据已复制到结构体, 可以立即关闭
+			err = f.Unmarshal(pointer)
+			_ = f.Close()
+			if err == nil {
+				return nil
+			}
+		}
+		logger.Errorf("%s 读取失败, 回退csv, error=%+v", colFilename, err)
+	}
+	return api.CsvToSlices(filename, pointer)
+}
+
+// SaveSlices 按配置的存储格式保存结构体切片
+//
+//	列式格式下删除旧的csv文件, 避免两份数据不一致
+func SaveSlices(filename string, slice any, force ...bool) error {
+	if StorageFormat() != StorageFormatColumnar {
+		colFilename := ColumnarFilename(filename)
+		// 切回csv时删除列式文件, 否则加载时会优先读到旧数据
+		if api.FileExist(colFilename) {
+			_ = os.Remove(colFilename)
+		}
+		return api.SlicesToCsv(filename, slice, force...)
+	}
+	if err := WriteColumnar(filename, slice); err != nil {
+		return err
+	}
+	if api.FileExist(filename) {
+		_ = os.Remove(filename)
+	}
+	return nil
+}
+
+// WriteColumnar 将结构体切片写入列式文件, filename为csv缓存文件名
+func WriteColumnar(filename string, slice any) error {
+	compression := columnar.Compression(strings.ToLower(config.GetDataConfig().Storage.Compression))
+	if compression != columnar.CompressionNone {
+		compression = columnar.CompressionFlate
+	}
+	return columnar.WriteFile(ColumnarFilename(filename), slice, columnar.WithCompression(compression))
+}
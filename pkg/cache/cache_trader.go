@@ -26,15 +26,14 @@ func defaultQmtCachePath() string {
 }
 
 func lazyInitQmt() {
-	traderParameter := &config.GlobalConfig.Trader
-	traderParameter.OrderPath = strings.TrimSpace(traderParameter.OrderPath)
-	if len(traderParameter.OrderPath) > 0 && api.CheckFilepath(traderParameter.OrderPath, true) == nil {
+	orderPath := strings.TrimSpace(config.TraderConfig().OrderPath)
+	if len(orderPath) > 0 && api.CheckFilepath(orderPath, true) == nil {
 		// 如果配置了路径且有效
-		qmtOrderPath = traderParameter.OrderPath
+		qmtOrderPath = orderPath
 	} else {
 		qmtOrderPath = defaultQmtCachePath()
 	}
-	traderParameter.OrderPath = qmtOrderPath
+	config.SetOrderPath(qmtOrderPath)
 }
 
 func initMiniQmt() {
@@ -34,7 +34,11 @@ type Handover interface {
 	ChangingOverDate(date string)
 }
 
+// Depend 插件依赖接口
+//
+//	更新数据时, 插件在依赖的插件完成之后才会执行
 type Depend interface {
+	// DependOn 依赖的插件类型
 	DependOn() []Kind
 }
 
@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	ErrCircularDependency = errors.New("the plugins have circular dependency")
+)
+
+// DependOn 获取插件声明的依赖, 没有实现Depend接口的插件没有依赖
+func DependOn(plugin any) []Kind {
+	if v, ok := plugin.(Depend); ok {
+		return v.DependOn()
+	}
+	return nil
+}
+
+// SortByDependency 按依赖关系对插件进行拓扑排序
+//
+//	返回分层的插件列表, 每一层只依赖前面的层, 同一层的插件之间没有依赖, 可以并行执行.
+//	依赖的插件不在列表中时视为已经就绪, 存在循环依赖时返回ErrCircularDependency
+func SortByDependency[T interface{ Kind() Kind }](plugins []T) ([][]T, error) {
+	mapPlugins := make(map[Kind]T, len(plugins))
+	for _, v := range plugins {
+		mapPlugins[v.Kind()] = v
+	}
+	// 入度和反向边
+	inDegree := make(map[Kind]int, len(plugins))
+	dependents := make(map[Kind][]Kind, len(plugins))
+	for kind, v := range mapPlugins {
+		inDegree[kind] += 0
+		for _, dep := range DependOn(v) {
+			if dep == kind {
+				return nil, fmt.Errorf("%w: %d depends on itself", ErrCircularDependency, kind)
+			}
+			if _, ok := mapPlugins[dep]; !ok {
+				continue
+			}
+			inDegree[kind]++
+			dependents[dep] = append(dependents[dep], kind)
+		}
+	}
+	var layers [][]T
+	var current []Kind
+	for kind, degree := range inDegree {
+		if degree == 0 {
+			current = append(current, kind)
+		}
+	}
+	visited := 0
+	for len(current) > 0 {
+		slices.Sort(current)
+		layer := make([]T, 0, len(current))
+		var next []Kind
+		for _, kind := range current {
+			layer = append(layer, mapPlugins[kind])
+			visited++
+			for _, child := range dependents[kind] {
+				inDegree[child]--
+				if inDegree[child] == 0 {
+					next = append(next, child)
+				}
+			}
+		}
+		layers = append(layers, layer)
+		current = next
+	}
+	if visited != len(mapPlugins) {
+		var cycle []Kind
+		for kind, degree := range inDegree {
+			if degree > 0 {
+				cycle = append(cycle, kind)
+			}
+		}
+		slices.Sort(cycle)
+		return nil, fmt.Errorf("%w: %v", ErrCircularDependency, cycle)
+	}
+	return layers, nil
+}
@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type testPlugin struct {
+	kind Kind
+	deps []Kind
+}
+
+func (p testPlugin) Kind() Kind {
+	return p.kind
+}
+
+func (p testPlugin) DependOn() []Kind {
+	return p.deps
+}
+
+func TestSortByDependency(t *testing.T) {
+	plugins := []testPlugin{
+		{kind: 4, deps: []Kind{1, 2}},
+		{kind: 2},
+		{kind: 1, deps: []Kind{2}},
+		{kind: 3, deps: []Kind{2, 99}},
+	}
+	layers, err := SortByDependency(plugins)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, layer := range layers {
+		fmt.Println(i, layer)
+	}
+	if len(layers) != 3 || layers[0][0].kind != 2 || layers[2][0].kind != 4 {
+		t.Errorf("unexpected layers: %v", layers)
+	}
+
+	plugins = append(plugins, testPlugin{kind: 5, deps: []Kind{6}}, testPlugin{kind: 6, deps: []Kind{5}})
+	_, err = SortByDependency(plugins)
+	fmt.Println(err)
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Errorf("circular dependency not detected")
+	}
+}
@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"cmp"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"gitee.com/quant1x/gox/api"
+)
+
+const (
+	metricHistoryFilename = "metrics.csv" // 适配器指标的历史记录
+)
+
+// MetricRecord 适配器指标的历史记录, 一个日期一个适配器一条
+type MetricRecord struct {
+	Date      string  `name:"日期" dataframe:"date"`            // 日期
+	Name      string  `name:"名称" dataframe:"name"`            // 名称
+	Kind      Kind    `name:"类型" dataframe:"kind"`            // 类型
+	Count     int     `name:"总数" dataframe:"count"`           // 总数
+	Passed    int     `name:"通过数" dataframe:"passed"`         // 通过检测数
+	PassRate  float64 `name:"通过率" dataframe:"pass_rate"`      // 通过率
+	Max       float64 `name:"最大耗时(ms)" dataframe:"max"`       // 最大耗时, 毫秒
+	Min       float64 `name:"最小耗时(ms)" dataframe:"min"`       // 最小耗时, 毫秒
+	CrossTime float64 `name:"总耗时(ms)" dataframe:"cross_time"` // 总耗时, 毫秒
+	Speed     float64 `name:"速度" dataframe:"speed"`           // 速度
+	Timestamp string  `name:"记录时间" dataframe:"timestamp"`     // 记录时间
+}
+
+// NewMetricRecord 指定日期的适配器指标转换成历史记录
+func NewMetricRecord(date string, metric AdapterMetric) MetricRecord {
+	record := MetricRecord{
+		Date:      date,
+		Name:      metric.Name,
+		Kind:      metric.Kind,
+		Count:     metric.Count,
+		Passed:    metric.Passed,
+		Max:       float64(metric.Max) / float64(time.Millisecond),
+		Min:       float64(metric.Min) / float64(time.Millisecond),
+		CrossTime: float64(metric.CrossTime) / float64(time.Millisecond),
+		Speed:     metric.Speed,
+		Timestamp: time.Now().Format(time.DateTime),
+	}
+	if metric.Count > 0 {
+		record.PassRate = float64(metric.Passed) / float64(metric.Count)
+	}
+	return record
+}
+
+// MetricHistoryFilename 适配器指标历史记录的文件名
+func MetricHistoryFilename() string {
+	return GetQualityPath() + "/" + metricHistoryFilename
+}
+
+// LoadMetricHistory 加载适配器指标的历史记录
+func LoadMetricHistory() ([]MetricRecord, error) {
+	var list []MetricRecord
+	filename := MetricHistoryFilename()
+	if !api.FileExist(filename) {
+		return list, nil
+	}
+	err := api.CsvToSlices(filename, &list)
+	return list, err
+}
+
+// SaveMetricHistory 保存指定日期的适配器指标
+//
+//	同一日期同一类型的记录会被覆盖, 按日期和类型排序
+func SaveMetricHistory(date string, metrics []AdapterMetric) error {
+	list, err := LoadMetricHistory()
+	if err != nil {
+		return err
+	}
+	for _, metric := range metrics {
+		record := NewMetricRecord(date, metric)
+		list = slices.DeleteFunc(list, func(v MetricRecord) bool {
+			return v.Date == record.Date && v.Kind == record.Kind
+		})
+		list = append(list, record)
+	}
+	slices.SortFunc(list, func(a, b MetricRecord) int {
+		if a.Date != b.Date {
+			return strings.Compare(a.Date, b.Date)
+		}
+		return cmp.Compare(a.Kind, b.Kind)
+	})
+	filename := MetricHistoryFilename()
+	if err := os.MkdirAll(filepath.Dir(filename), cacheDirMode); err != nil {
+		return err
+	}
+	return api.SlicesToCsv(filename, list)
+}
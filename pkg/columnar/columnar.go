@@ -0,0 +1,87 @@
+// Package columnar 列式二进制存储格式
+//
+//	文件结构:
+//	  magic(4字节) + version(2字节) + flags(2字节) + header长度(4字节) + header(json) + 填充到8字节对齐
+//	  数据区: 按列连续存放, 每一列的起始位置8字节对齐
+//	列类型:
+//	  int64/float64 小端定长8字节, bool 1字节, string/json 为(rows+1)个uint32偏移量+字节数据
+//	未压缩的数值列在内存映射模式下可以零拷贝读取
+package columnar
+
+import (
+	"errors"
+)
+
+const (
+	// FileExtension 列式存储文件的扩展名
+	FileExtension = ".col"
+	// Version 当前格式版本
+	Version uint16 = 1
+)
+
+var (
+	fileMagic = [4]byte{'X', 'Q', 'C', 'L'}
+)
+
+const (
+	prefixSize = 12 // magic + version + flags + header长度
+	alignment  = 8  // 数据对齐字节数
+)
+
+// ColumnType 列类型
+type ColumnType string
+
+const (
+	TypeInt64   ColumnType = "int64"
+	TypeFloat64 ColumnType = "float64"
+	TypeBool    ColumnType = "bool"
+	TypeString  ColumnType = "string"
+	TypeJson    ColumnType = "json" // 其它类型, 用json序列化
+)
+
+// Compression 压缩方式
+type Compression string
+
+const (
+	CompressionNone  Compression = "none"
+	CompressionFlate Compression = "flate"
+)
+
+var (
+	ErrInvalidFormat      = errors.New("columnar: invalid file format")
+	ErrUnsupportedVersion = errors.New("columnar: unsupported version")
+	ErrColumnNotFound     = errors.New("columnar: column not found")
+	ErrColumnType         = errors.New("columnar: column type mismatch")
+	ErrNotSlice           = errors.New("columnar: the value must be a slice of struct")
+	ErrNotSlicePointer    = errors.New("columnar: the value must be a pointer to slice of struct")
+)
+
+// Column 列描述
+type Column struct {
+	Name        string      `json:"name"`        // 列名, 取自dataframe标签
+	Type        ColumnType  `json:"type"`        // 列类型
+	Offset      int64       `json:"offset"`      // 数据区内的偏移量
+	Size        int64       `json:"size"`        // 存储的字节数
+	RawSize     int64       `json:"raw_size"`    // 解压后的字节数
+	Compression Compression `json:"compression"` // 压缩方式
+}
+
+// Header 文件头, 即schema
+type Header struct {
+	Rows    int      `json:"rows"`    // 行数
+	Columns []Column `json:"columns"` // 列
+}
+
+// Column 按名称查找列
+func (h *Header) Column(name string) (Column, bool) {
+	for _, v := range h.Columns {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return Column{}, false
+}
+
+func alignUp(n int64) int64 {
+	return (n + alignment - 1) / alignment * alignment
+}
@@ -0,0 +1,104 @@
+package columnar
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+type testEmbedded struct {
+	Code string `dataframe:"code"`
+}
+
+type testRow struct {
+	testEmbedded
+	Ignore string            `dataframe:"-"`
+	Date   string            `name:"日期" dataframe:"date,string"`
+	Close  float64           `name:"收盘" dataframe:"close,float64"`
+	Volume int64             `name:"成交量" dataframe:"volume,int64"`
+	Up     int               `name:"上涨家数" dataframe:"up,int64"`
+	State  uint64            `name:"样本状态" dataframe:"state"`
+	Ok     bool              `dataframe:"ok"`
+	Tags   map[string]string `dataframe:"tags"`
+}
+
+func TestColumnarRoundTrip(t *testing.T) {
+	list := []testRow{
+		{testEmbedded: testEmbedded{Code: "sh600000"}, Ignore: "x", Date: "2024-01-02", Close: 10.01, Volume: 100, Up: -1, State: 1 << 63, Ok: true, Tags: map[string]string{"a": "b"}},
+		{testEmbedded: testEmbedded{Code: "sh600000"}, Date: "2024-01-03", Close: 10.23, Volume: 200, Up: 2},
+	}
+	for _, compression := range []Compression{CompressionNone, CompressionFlate} {
+		for _, mmap := range []bool{false, true} {
+			filename := filepath.Join(t.TempDir(), "test"+FileExtension)
+			if err := WriteFile(filename, list, WithCompression(compression)); err != nil {
+				t.Fatal(err)
+			}
+			if !IsColumnarFile(filename) {
+				t.Fatal("magic not matched")
+			}
+			f, err := Open(filename, mmap)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fmt.Printf("%s mmap=%t: %+v\n", compression, mmap, f.Header())
+			closes, err := f.Float64s("close")
+			if err != nil || len(closes) != 2 || closes[1] != 10.23 {
+				t.Errorf("Float64s = %v, %v", closes, err)
+			}
+			var got []*testRow
+			if err = f.Unmarshal(&got); err != nil {
+				t.Fatal(err)
+			}
+			_ = f.Close()
+			if len(got) != 2 || got[0].Code != "sh600000" || got[0].Ignore != "" || got[0].Up != -1 ||
+				got[0].State != 1<<63 || !got[0].Ok || got[0].Tags["a"] != "b" || got[1].Volume != 200 {
+				t.Errorf("Unmarshal = %+v", got[0])
+			}
+		}
+	}
+}
+
+func TestColumnarEmpty(t *testing.T) {
+	data, err := Marshal([]testRow{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []testRow
+	if err = Unmarshal(data, &got); err != nil || len(got) != 0 {
+		t.Errorf("Unmarshal = %v, %v", got, err)
+	}
+	if _, err = Marshal([]int{1}); err != ErrNotSlice {
+		t.Errorf("Marshal non-struct = %v", err)
+	}
+}
+
+func TestColumnarCorrupt(t *testing.T) {
+	list := []testRow{
+		{Date: "2024-01-02", Close: 10.01},
+		{Date: "2024-01-03", Close: 10.23},
+	}
+	data, err := Marshal(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 行数和列数据的长度不一致
+	rows := bytes.Replace(data, []byte(`"rows":2`), []byte(`"rows":3`), 1)
+	if _, err = NewFile(rows); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("NewFile rows = %v", err)
+	}
+	// 变长列的偏移量越界
+	f, err := NewFile(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, _ := f.header.Column("date")
+	offsets := bytes.Clone(data)
+	binary.LittleEndian.PutUint32(offsets[f.base+c.Offset+4:], 0xffff)
+	var got []testRow
+	if err = Unmarshal(offsets, &got); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("Unmarshal offsets = %v", err)
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !unix
+
+package columnar
+
+import (
+	"os"
+)
+
+// mapFile 不支持内存映射的平台, 读取整个文件
+func mapFile(filename string) ([]byte, func() error, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, nil, nil
+}
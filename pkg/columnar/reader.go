@@ -0,0 +1,406 @@
+package columnar
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// 本机是否小端字节序, 小端才能零拷贝
+var nativeLittleEndian = func() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}()
+
+// File 列式文件
+//
+//	数值列的零拷贝切片直接引用文件数据, 只在Close之前有效
+type File struct {
+	header  Header
+	data    []byte
+	base    int64
+	release func() error
+	m       sync.Mutex
+	columns map[string][]byte // 解压后的列数据
+}
+
+// Open 打开列式文件, mmap为true时使用内存映射, 不支持内存映射的平台自动读取整个文件
+func Open(filename string, mmap bool) (*File, error) {
+	var data []byte
+	var release func() error
+	var err error
+	if mmap {
+		data, release, err = mapFile(filename)
+	} else {
+		data, err = os.ReadFile(filename)
+	}
+	if err != nil {
+		return nil, err
+	}
+	f, err := newFile(data)
+	if err != nil {
+		if release != nil {
+			_ = release()
+		}
+		return nil, err
+	}
+	f.release = release
+	return f, nil
+}
+
+// NewFile 从内存数据创建列式文件对象
+func NewFile(data []byte) (*File, error) {
+	return newFile(data)
+}
+
+func newFile(data []byte) (*File, error) {
+	if len(data) < prefixSize || [4]byte(data[0:4]) != fileMagic {
+		return nil, ErrInvalidFormat
+	}
+	if binary.LittleEndian.Uint16(data[4:6]) > Version {
+		return nil, ErrUnsupportedVersion
+	}
+	headerSize := int64(binary.LittleEndian.Uint32(data[8:12]))
+	if prefixSize+headerSize > int64(len(data)) {
+		return nil, ErrInvalidFormat
+	}
+	f := &File{
+		data:    data,
+		base:    alignUp(prefixSize + headerSize),
+		columns: map[string][]byte{},
+	}
+	if err := json.Unmarshal(data[prefixSize:prefixSize+headerSize], &f.header); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+	}
+	rows := f.header.Rows
+	if rows < 0 {
+		return nil, ErrInvalidFormat
+	}
+	for _, c := range f.header.Columns {
+		if c.Offset < 0 || c.Size < 0 || c.RawSize < 0 || f.base+c.Offset+c.Size > int64(len(data)) {
+			return nil, ErrInvalidFormat
+		}
+		// 压缩的列在解压之后校验
+		if c.Compression == CompressionFlate {
+			continue
+		}
+		block := data[f.base+c.Offset : f.base+c.Offset+c.Size]
+		if err := checkColumn(c, block, rows); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// checkColumn 校验列数据的长度和变长列的偏移量, 防止读取时越界
+//
+//	定长列为rows*8字节, 布尔列为rows字节, 变长列的(rows+1)个偏移量单调递增且不超出数据区
+func checkColumn(c Column, b []byte, rows int) error {
+	switch c.Type {
+	case TypeInt64, TypeFloat64:
+		if len(b) != rows*8 {
+			return fmt.Errorf("%w: column %s size %d, rows %d", ErrInvalidFormat, c.Name, len(b), rows)
+		}
+	case TypeBool:
+		if len(b) != rows {
+			return fmt.Errorf("%w: column %s size %d, rows %d", ErrInvalidFormat, c.Name, len(b), rows)
+		}
+	case TypeString, TypeJson:
+		size := (rows + 1) * 4
+		if len(b) < size {
+			return fmt.Errorf("%w: column %s size %d, rows %d", ErrInvalidFormat, c.Name, len(b), rows)
+		}
+		values := len(b) - size
+		prev := uint32(0)
+		for i := 0; i <= rows; i++ {
+			offset := binary.LittleEndian.Uint32(b[i*4:])
+			if offset < prev || int64(offset) > int64(values) {
+				return fmt.Errorf("%w: column %s offset %d at row %d", ErrInvalidFormat, c.Name, offset, i)
+			}
+			prev = offset
+		}
+	default:
+		return fmt.Errorf("%w: column %s is %s", ErrColumnType, c.Name, c.Type)
+	}
+	return nil
+}
+
+// Header 文件头
+func (f *File) Header() Header {
+	return f.header
+}
+
+// Rows 行数
+func (f *File) Rows() int {
+	return f.header.Rows
+}
+
+// Close 关闭文件, 释放内存映射
+func (f *File) Close() error {
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.columns = nil
+	f.data = nil
+	if f.release != nil {
+		release := f.release
+		f.release = nil
+		return release()
+	}
+	return nil
+}
+
+// raw 获取列的原始字节, 压缩的列解压后缓存
+func (f *File) raw(c Column) ([]byte, error) {
+	block := f.data[f.base+c.Offset : f.base+c.Offset+c.Size]
+	if c.Compression != CompressionFlate {
+		return block, nil
+	}
+	f.m.Lock()
+	defer f.m.Unlock()
+	if v, ok := f.columns[c.Name]; ok {
+		return v, nil
+	}
+	v, err := inflate(block, c.RawSize)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+	}
+	if err = checkColumn(c, v, f.header.Rows); err != nil {
+		return nil, err
+	}
+	f.columns[c.Name] = v
+	return v, nil
+}
+
+func (f *File) column(name string, types ...ColumnType) (Column, []byte, error) {
+	c, ok := f.header.Column(name)
+	if !ok {
+		return c, nil, fmt.Errorf("%w: %s", ErrColumnNotFound, name)
+	}
+	matched := false
+	for _, t := range types {
+		if c.Type == t {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return c, nil, fmt.Errorf("%w: %s is %s", ErrColumnType, name, c.Type)
+	}
+	b, err := f.raw(c)
+	return c, b, err
+}
+
+// fixed64 8字节定长列, 满足条件时零拷贝
+func fixed64[E int64 | float64](b []byte, rows int) []E {
+	if rows == 0 {
+		return []E{}
+	}
+	if nativeLittleEndian && uintptr(unsafe.Pointer(&b[0]))%alignment == 0 {
+		return unsafe.Slice((*E)(unsafe.Pointer(&b[0])), rows)
+	}
+	list := make([]E, rows)
+	for i := range list {
+		bits := binary.LittleEndian.Uint64(b[i*8:])
+		*(*uint64)(unsafe.Pointer(&list[i])) = bits
+	}
+	return list
+}
+
+// Float64s 读取浮点列
+//
+//	未压缩的列返回零拷贝的切片, 调用方不能修改
+func (f *File) Float64s(name string) ([]float64, error) {
+	_, b, err := f.column(name, TypeFloat64)
+	if err != nil {
+		return nil, err
+	}
+	return fixed64[float64](b, f.header.Rows), nil
+}
+
+// Int64s 读取整型列
+//
+//	未压缩的列返回零拷贝的切片, 调用方不能修改
+func (f *File) Int64s(name string) ([]int64, error) {
+	_, b, err := f.column(name, TypeInt64)
+	if err != nil {
+		return nil, err
+	}
+	return fixed64[int64](b, f.header.Rows), nil
+}
+
+// Bools 读取布尔列
+func (f *File) Bools(name string) ([]bool, error) {
+	_, b, err := f.column(name, TypeBool)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]bool, f.header.Rows)
+	for i := range list {
+		list[i] = b[i] != 0
+	}
+	return list, nil
+}
+
+// Strings 读取字符串列, json列返回json文本
+func (f *File) Strings(name string) ([]string, error) {
+	_, b, err := f.column(name, TypeString, TypeJson)
+	if err != nil {
+		return nil, err
+	}
+	rows := f.header.Rows
+	list := make([]string, rows)
+	for i := range list {
+		list[i] = string(variableAt(b, rows, i))
+	}
+	return list, nil
+}
+
+// variableAt 变长列第i行的字节
+func variableAt(b []byte, rows, i int) []byte {
+	start := binary.LittleEndian.Uint32(b[i*4:])
+	end := binary.LittleEndian.Uint32(b[(i+1)*4:])
+	values := b[(rows+1)*4:]
+	return values[start:end]
+}
+
+// Unmarshal 解码到结构体切片, pointer为切片的指针
+//
+//	按列名匹配字段, 文件中不存在的字段保持零值
+func (f *File) Unmarshal(pointer any) error {
+	pv := reflect.ValueOf(pointer)
+	if pv.Kind() != reflect.Pointer || pv.Elem().Kind() != reflect.Slice {
+		return ErrNotSlicePointer
+	}
+	sv := pv.Elem()
+	sliceElem := sv.Type().Elem()
+	elemType, ok := elemStruct(sliceElem)
+	if !ok {
+		return ErrNotSlicePointer
+	}
+	isPointer := sliceElem.Kind() == reflect.Pointer
+	rows := f.header.Rows
+	list := reflect.MakeSlice(sv.Type(), rows, rows)
+	if isPointer {
+		for i := 0; i < rows; i++ {
+			list.Index(i).Set(reflect.New(elemType))
+		}
+	}
+	row := func(i int) reflect.Value {
+		if isPointer {
+			return list.Index(i).Elem()
+		}
+		return list.Index(i)
+	}
+	for _, fd := range structFields(elemType) {
+		c, found := f.header.Column(fd.name)
+		// 字段类型变化时跳过, 保持零值
+		if !found || !compatible(c.Type, fd.typ) {
+			continue
+		}
+		b, err := f.raw(c)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < rows; i++ {
+			v := row(i).FieldByIndex(fd.index)
+			if err = setValue(v, c.Type, b, rows, i); err != nil {
+				return fmt.Errorf("columnar: column %s row %d: %w", c.Name, i, err)
+			}
+		}
+	}
+	sv.Set(list)
+	return nil
+}
+
+// compatible 列类型和字段类型是否兼容, 数值类型之间可以互相转换
+func compatible(columnType, fieldType ColumnType) bool {
+	if columnType == fieldType {
+		return true
+	}
+	isNumber := func(t ColumnType) bool { return t == TypeInt64 || t == TypeFloat64 }
+	return isNumber(columnType) && isNumber(fieldType)
+}
+
+func setValue(v reflect.Value, typ ColumnType, b []byte, rows, i int) error {
+	switch typ {
+	case TypeInt64, TypeFloat64:
+		bits := binary.LittleEndian.Uint64(b[i*8:])
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if typ == TypeFloat64 {
+				v.SetInt(int64(math.Float64frombits(bits)))
+			} else {
+				v.SetInt(int64(bits))
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if typ == TypeFloat64 {
+				v.SetUint(uint64(math.Float64frombits(bits)))
+			} else {
+				v.SetUint(bits)
+			}
+		case reflect.Float32, reflect.Float64:
+			if typ == TypeInt64 {
+				v.SetFloat(float64(int64(bits)))
+			} else {
+				v.SetFloat(math.Float64frombits(bits))
+			}
+		default:
+			return ErrColumnType
+		}
+	case TypeBool:
+		if v.Kind() != reflect.Bool {
+			return ErrColumnType
+		}
+		v.SetBool(b[i] != 0)
+	case TypeString:
+		if v.Kind() != reflect.String {
+			return ErrColumnType
+		}
+		v.SetString(string(variableAt(b, rows, i)))
+	default:
+		value := variableAt(b, rows, i)
+		if len(value) == 0 {
+			return nil
+		}
+		return json.Unmarshal(value, v.Addr().Interface())
+	}
+	return nil
+}
+
+// Unmarshal 从内存数据解码到结构体切片
+func Unmarshal(data []byte, pointer any) error {
+	f, err := newFile(data)
+	if err != nil {
+		return err
+	}
+	return f.Unmarshal(pointer)
+}
+
+// ReadFile 读取文件并解码到结构体切片
+func ReadFile(filename string, pointer any) error {
+	f, err := Open(filename, false)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	return f.Unmarshal(pointer)
+}
+
+// IsColumnarFile 判断文件是否列式格式
+func IsColumnarFile(filename string) bool {
+	file, err := os.Open(filename)
+	if err != nil {
+		return false
+	}
+	defer func() { _ = file.Close() }()
+	var magic [4]byte
+	if _, err = file.Read(magic[:]); err != nil {
+		return false
+	}
+	return magic == fileMagic
+}
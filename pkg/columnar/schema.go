@@ -0,0 +1,85 @@
+package columnar
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// field 结构体字段和列的映射关系
+type field struct {
+	name  string
+	typ   ColumnType
+	index []int
+}
+
+var (
+	schemaMutex sync.RWMutex
+	mapSchemas  = map[reflect.Type][]field{}
+)
+
+// elemStruct 取出切片元素的结构体类型, 元素可以是结构体指针
+func elemStruct(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t, t.Kind() == reflect.Struct
+}
+
+// structFields 获取结构体的字段列表, 列名和csv一样取dataframe标签的第一部分
+func structFields(t reflect.Type) []field {
+	schemaMutex.RLock()
+	fields, ok := mapSchemas[t]
+	schemaMutex.RUnlock()
+	if ok {
+		return fields
+	}
+	fields = collectFields(t, nil)
+	schemaMutex.Lock()
+	mapSchemas[t] = fields
+	schemaMutex.Unlock()
+	return fields
+}
+
+func collectFields(t reflect.Type, parent []int) []field {
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, hasTag := sf.Tag.Lookup("dataframe")
+		name, _, _ := strings.Cut(tag, ",")
+		name = strings.TrimSpace(name)
+		if name == "-" {
+			continue
+		}
+		index := append(append([]int{}, parent...), i)
+		// 没有标签的匿名结构体展开
+		if sf.Anonymous && !hasTag && sf.Type.Kind() == reflect.Struct {
+			fields = append(fields, collectFields(sf.Type, index)...)
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if len(name) == 0 {
+			name = sf.Name
+		}
+		fields = append(fields, field{name: name, typ: columnTypeOf(sf.Type), index: index})
+	}
+	return fields
+}
+
+func columnTypeOf(t reflect.Type) ColumnType {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return TypeInt64
+	case reflect.Float32, reflect.Float64:
+		return TypeFloat64
+	case reflect.Bool:
+		return TypeBool
+	case reflect.String:
+		return TypeString
+	default:
+		return TypeJson
+	}
+}
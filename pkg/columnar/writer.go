@@ -0,0 +1,194 @@
+package columnar
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+)
+
+// Options 写入选项
+type Options struct {
+	Compression Compression // 压缩方式, 默认不压缩
+}
+
+// Option 写入选项函数
+type Option func(*Options)
+
+// WithCompression 设置压缩方式
+func WithCompression(compression Compression) Option {
+	return func(o *Options) {
+		if len(compression) > 0 {
+			o.Compression = compression
+		}
+	}
+}
+
+// Marshal 将结构体切片编码成列式格式
+func Marshal(slice any, opts ...Option) ([]byte, error) {
+	options := Options{Compression: CompressionNone}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	rv := reflect.ValueOf(slice)
+	if rv.Kind() != reflect.Slice {
+		return nil, ErrNotSlice
+	}
+	elemType, ok := elemStruct(rv.Type().Elem())
+	if !ok {
+		return nil, ErrNotSlice
+	}
+	isPointer := rv.Type().Elem().Kind() == reflect.Pointer
+	fields := structFields(elemType)
+	rows := rv.Len()
+	header := Header{Rows: rows, Columns: make([]Column, 0, len(fields))}
+	var data bytes.Buffer
+	for _, f := range fields {
+		raw, err := encodeColumn(rv, rows, isPointer, f)
+		if err != nil {
+			return nil, err
+		}
+		block := raw
+		if options.Compression == CompressionFlate {
+			block, err = deflate(raw)
+			if err != nil {
+				return nil, err
+			}
+		}
+		offset := alignUp(int64(data.Len()))
+		data.Write(make([]byte, offset-int64(data.Len())))
+		data.Write(block)
+		header.Columns = append(header.Columns, Column{
+			Name:        f.name,
+			Type:        f.typ,
+			Offset:      offset,
+			Size:        int64(len(block)),
+			RawSize:     int64(len(raw)),
+			Compression: options.Compression,
+		})
+	}
+	headerBytes, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	dataOffset := alignUp(int64(prefixSize + len(headerBytes)))
+	buf := make([]byte, dataOffset, dataOffset+int64(data.Len()))
+	copy(buf[0:4], fileMagic[:])
+	binary.LittleEndian.PutUint16(buf[4:6], Version)
+	binary.LittleEndian.PutUint16(buf[6:8], 0)
+	binary.LittleEndian.PutUint32(buf[8:12], uint32(len(headerBytes)))
+	copy(buf[prefixSize:], headerBytes)
+	buf = append(buf, data.Bytes()...)
+	return buf, nil
+}
+
+// WriteFile 将结构体切片写入文件
+//
+//	先写临时文件再改名, 避免读到写了一半的文件
+func WriteFile(filename string, slice any, opts ...Option) error {
+	data, err := Marshal(slice, opts...)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+		return err
+	}
+	tmpFilename := filename + ".tmp"
+	if err = os.WriteFile(tmpFilename, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpFilename, filename)
+}
+
+func encodeColumn(rv reflect.Value, rows int, isPointer bool, f field) ([]byte, error) {
+	row := func(i int) (reflect.Value, bool) {
+		v := rv.Index(i)
+		if isPointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		return v.FieldByIndex(f.index), true
+	}
+	switch f.typ {
+	case TypeInt64, TypeFloat64:
+		buf := make([]byte, rows*8)
+		for i := 0; i < rows; i++ {
+			v, ok := row(i)
+			if !ok {
+				continue
+			}
+			var bits uint64
+			switch v.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				bits = uint64(v.Int())
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				bits = v.Uint()
+			default:
+				bits = math.Float64bits(v.Float())
+			}
+			binary.LittleEndian.PutUint64(buf[i*8:], bits)
+		}
+		return buf, nil
+	case TypeBool:
+		buf := make([]byte, rows)
+		for i := 0; i < rows; i++ {
+			if v, ok := row(i); ok && v.Bool() {
+				buf[i] = 1
+			}
+		}
+		return buf, nil
+	default:
+		offsets := make([]byte, (rows+1)*4)
+		var values bytes.Buffer
+		for i := 0; i < rows; i++ {
+			binary.LittleEndian.PutUint32(offsets[i*4:], uint32(values.Len()))
+			v, ok := row(i)
+			if !ok {
+				continue
+			}
+			if f.typ == TypeString {
+				values.WriteString(v.String())
+				continue
+			}
+			b, err := json.Marshal(v.Interface())
+			if err != nil {
+				return nil, err
+			}
+			values.Write(b)
+		}
+		binary.LittleEndian.PutUint32(offsets[rows*4:], uint32(values.Len()))
+		return append(offsets, values.Bytes()...), nil
+	}
+}
+
+func deflate(raw []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(raw); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func inflate(block []byte, rawSize int64) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(block))
+	defer func() { _ = r.Close() }()
+	raw := make([]byte, rawSize)
+	if _, err := io.ReadFull(r, raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
@@ -123,8 +123,8 @@ func GetConfigFilename() string {
 }
 
 var (
-	// GlobalConfig engine配置信息
-	GlobalConfig Quant1XConfig
+	// globalConfig engine配置信息, 只能通过configMutex保护的函数访问
+	globalConfig Quant1XConfig
 )
 
 // LoadConfig 加载配置文件
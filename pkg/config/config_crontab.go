@@ -9,7 +9,7 @@ type JobParameter struct {
 
 // CrontabConfig 获取定时任务配置
 func CrontabConfig() map[string]JobParameter {
-	return GlobalConfig.Runtime.Crontab
+	return snapshotConfig().Runtime.Crontab
 }
 
 // GetJobParameter 获取计划执行任务
@@ -12,12 +12,15 @@ type DataParameter struct {
 	Trans       HistoricalTradingDataParameter `name:"历史成交数据" yaml:"trans"`          // 历史成交参数
 	Feature     FeatureParameter               `name:"特征" yaml:"feature"`            // 特征参数
 	Snapshot    SnapshotParameter              `name:"快照" yaml:"snapshot"`           // 快照参数
+	Storage     StorageParameter               `name:"存储" yaml:"storage"`            // 存储参数
+	BillBoard   BillBoardParameter             `name:"龙虎榜" yaml:"billboard"`         // 龙虎榜参数
+	OrderFlow   OrderFlowParameter             `name:"成交分析" yaml:"order_flow"`       // 成交分析参数
 	Cache       map[string]map[string]any      `name:"缓存" yaml:"cache" default:"{}"` // 缓存的其它未尽参数
 }
 
 // GetDataConfig 取得数据配置
 func GetDataConfig() DataParameter {
-	dataParameter := GlobalConfig.Data
+	dataParameter := snapshotConfig().Data
 	backTestingParameter := dataParameter.BackTesting
 	backTestingParameter.TargetIndex = exchange.CorrectSecurityCode(backTestingParameter.TargetIndex)
 	return dataParameter
@@ -40,6 +43,7 @@ type FeatureParameter struct {
 	Tendency       int         `name:"趋势类型" yaml:"tendency" default:"0"`               // 策略是趋势主导还是股价主导, 默认是0, 0-股价主导,1-趋势主导,2-股价或趋势
 	Wave           FeatureWave `name:"波浪" yaml:"wave"`                                 // 波浪
 	CrossStarRatio float64     `name:"十字星实体占比" yaml:"cross_star_ratio" default:"0.50"` // 判断十字星, K线实体(OPEN-CLOSE)在K线长度(HIGH-LOW)中的占比
+	Concurrency    int         `name:"并发数" yaml:"concurrency" default:"2"`             // 同时更新的特征数, 默认2, 没有依赖关系的特征并行更新
 }
 
 // FeatureF10 F10特征数据参数
@@ -62,5 +66,26 @@ type FeatureWaveFields struct {
 
 // SnapshotParameter 快照参数
 type SnapshotParameter struct {
-	Concurrency int `name:"并发数" yaml:"concurrency" default:"0"` // 并发数, 默认是0, 使用服务器数量的半数
+	Concurrency   int     `name:"并发数" yaml:"concurrency" default:"0"`         // 并发数, 默认是0, 使用服务器数量的半数
+	OrderBook     bool    `name:"记录盘口" yaml:"order_book" default:"true"`      // 同步快照时是否记录个股的盘口分析, 用于回测
+	SealAlertRate float64 `name:"封单异动比例" yaml:"seal_alert_rate" default:"30"` // 封单量较上一个快照变化的百分比不低于该值时视为异动, 默认30%
+}
+
+// StorageParameter 缓存存储参数
+type StorageParameter struct {
+	Format      string `name:"存储格式" yaml:"format" default:"csv"`        // 宽表和特征缓存的存储格式, csv或columnar, 默认csv
+	Compression string `name:"压缩方式" yaml:"compression" default:"flate"` // 列式存储的压缩方式, none或flate, 默认flate
+	Mmap        bool   `name:"内存映射" yaml:"mmap" default:"false"`        // 读取列式文件时是否使用内存映射
+}
+
+// BillBoardParameter 龙虎榜参数
+type BillBoardParameter struct {
+	HotMoneySeats []string `name:"游资席位" yaml:"hot_money_seats" default:"[\"上海溧阳路\",\"绍兴证券营业部\",\"杭州上塘路\",\"南京太平南路\",\"宁波桑田路\"]"` // 营业部名称包含任意一个关键字即为游资席位
+}
+
+// OrderFlowParameter 成交分析参数, 按单笔成交金额划分订单大小
+type OrderFlowParameter struct {
+	MediumAmount     float64 `name:"中单金额" yaml:"medium_amount" default:"40000"`         // 单笔成交金额不低于4万元为中单
+	LargeAmount      float64 `name:"大单金额" yaml:"large_amount" default:"200000"`         // 单笔成交金额不低于20万元为大单
+	SuperLargeAmount float64 `name:"超大单金额" yaml:"super_large_amount" default:"1000000"` // 单笔成交金额不低于100万元为超大单
 }
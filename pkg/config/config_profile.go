@@ -14,7 +14,7 @@ type PprofParameter struct {
 
 // PprofEnable 获取配置中pprof开关
 func PprofEnable() bool {
-	return GlobalConfig.Runtime.Pprof.Enable
+	return snapshotConfig().Runtime.Pprof.Enable
 }
 
 // StartPprof 启动性能分析工具
@@ -22,8 +22,9 @@ func StartPprof() {
 	if !PprofEnable() {
 		return
 	}
+	port := snapshotConfig().Runtime.Pprof.Port
 	go func() {
-		addr := fmt.Sprintf("localhost:%d", GlobalConfig.Runtime.Pprof.Port)
+		addr := fmt.Sprintf("localhost:%d", port)
 		err := http.ListenAndServe(addr, nil)
 		logger.Info("启动pprof性能分析工具", err)
 	}()
@@ -0,0 +1,298 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"gitee.com/quant1x/gox/api"
+	"gitee.com/quant1x/gox/logger"
+)
+
+var (
+	ErrConfigNotFound = errors.New("配置文件不存在")
+)
+
+var (
+	configMutex   sync.RWMutex
+	reloadMutex   sync.Mutex
+	reloadHooks   []ReloadHook
+	auditFilename string
+)
+
+// ReloadHook 配置重载钩子
+//
+//	Validate在替换之前调用, 任何一个返回错误都会放弃本次重载
+//	Apply在校验通过之后、新配置生效之前调用, 生效后读取配置的代码和钩子应用的状态一致
+type ReloadHook struct {
+	Name     string
+	Validate func(newConfig *Quant1XConfig) error
+	Apply    func(newConfig *Quant1XConfig)
+}
+
+// RegisterReloadHook 注册配置重载钩子
+func RegisterReloadHook(hook ReloadHook) {
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+	reloadHooks = append(reloadHooks, hook)
+}
+
+// SetAuditFilename 设置配置变更审计日志的文件名
+func SetAuditFilename(filename string) {
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+	auditFilename = filename
+}
+
+// snapshotConfig 读取当前配置的副本
+func snapshotConfig() Quant1XConfig {
+	configMutex.RLock()
+	defer configMutex.RUnlock()
+	return globalConfig
+}
+
+// SetGlobalConfig 替换当前配置
+func SetGlobalConfig(c Quant1XConfig) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+	globalConfig = c
+}
+
+// ConfigChange 配置变更项
+type ConfigChange struct {
+	Key      string `json:"key"`      // yaml路径
+	OldValue string `json:"oldValue"` // 旧值
+	NewValue string `json:"newValue"` // 新值
+}
+
+// ReloadResult 配置重载结果
+type ReloadResult struct {
+	Filename string         `json:"filename"` // 配置文件
+	Time     string         `json:"time"`     // 重载时间
+	Source   string         `json:"source"`   // 触发来源
+	Changes  []ConfigChange `json:"changes"`  // 变更项
+}
+
+// ValidateConfig 校验配置
+func ValidateConfig(c *Quant1XConfig) error {
+	ids := map[uint64]bool{}
+	for _, v := range c.Trader.Strategies {
+		if ids[v.Id] {
+			return fmt.Errorf("策略ID重复: %d", v.Id)
+		}
+		ids[v.Id] = true
+		if v.Total < 0 {
+			return fmt.Errorf("策略[%d]订单数上限不能小于0", v.Id)
+		}
+		if mode := strings.ToLower(strings.TrimSpace(v.Mode)); len(mode) > 0 && mode != StrategyModeLive && mode != StrategyModeShadow {
+			return fmt.Errorf("策略[%d]运行模式无效: %s", v.Id, v.Mode)
+		}
+		if v.FeeMax > 0 && v.FeeMin > v.FeeMax {
+			return fmt.Errorf("策略[%d]最小费用大于最大费用", v.Id)
+		}
+	}
+	kinds := map[int]bool{}
+	names := map[string]bool{}
+	for _, g := range c.Trader.RuleGroups {
+		if err := g.Validate(); err != nil {
+			return err
+		}
+		if kinds[g.Kind] || names[g.Name] {
+			return fmt.Errorf("规则组重复: kind=%d, name=%s", g.Kind, g.Name)
+		}
+		kinds[g.Kind] = true
+		names[g.Name] = true
+	}
+	if c.Trader.PositionRatio < 0 || c.Trader.PositionRatio > 1 {
+		return fmt.Errorf("持仓占比超出范围: %f", c.Trader.PositionRatio)
+	}
+	return nil
+}
+
+// ReloadConfig 重新加载配置文件
+//
+//	解析并校验新配置, 校验通过后先执行Apply钩子再原子替换配置, 运行中的tracker在下一个tick读取新的策略参数
+func ReloadConfig(source string) (*ReloadResult, error) {
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+	filename := GetConfigFilename()
+	if len(filename) == 0 || !api.FileExist(filename) {
+		return nil, ErrConfigNotFound
+	}
+	var newConfig Quant1XConfig
+	if err := parseYamlConfig(filename, &newConfig); err != nil {
+		return nil, err
+	}
+	oldConfig := snapshotConfig()
+	// 保留启动时修正过的路径
+	if len(strings.TrimSpace(newConfig.Trader.OrderPath)) == 0 {
+		newConfig.Trader.OrderPath = oldConfig.Trader.OrderPath
+	}
+	if err := ValidateConfig(&newConfig); err != nil {
+		return nil, err
+	}
+	for _, hook := range reloadHooks {
+		if hook.Validate == nil {
+			continue
+		}
+		if err := hook.Validate(&newConfig); err != nil {
+			return nil, fmt.Errorf("%s: %w", hook.Name, err)
+		}
+	}
+	result := &ReloadResult{
+		Filename: filename,
+		Time:     time.Now().Format(time.DateTime),
+		Source:   source,
+		Changes:  DiffConfig(oldConfig, newConfig),
+	}
+	if len(result.Changes) == 0 {
+		return result, nil
+	}
+	for _, hook := range reloadHooks {
+		if hook.Apply != nil {
+			hook.Apply(&newConfig)
+		}
+	}
+	// 原子替换
+	SetGlobalConfig(newConfig)
+	writeAudit(result)
+	return result, nil
+}
+
+// writeAudit 记录配置变更
+func writeAudit(result *ReloadResult) {
+	for _, v := range result.Changes {
+		logger.Infof("config reload[%s]: %s: %s => %s", result.Source, v.Key, v.OldValue, v.NewValue)
+	}
+	if len(auditFilename) == 0 {
+		return
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		logger.Errorf("%+v", err)
+		return
+	}
+	f, err := os.OpenFile(auditFilename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		logger.Errorf("%+v", err)
+		return
+	}
+	defer api.CloseQuietly(f)
+	_, _ = f.Write(append(data, '\n'))
+}
+
+// WatchConfig 监控配置文件, 文件修改后自动重载
+//
+//	返回的函数用于停止监控
+func WatchConfig(interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	done := make(chan struct{})
+	var once sync.Once
+	go func() {
+		var lastModified time.Time
+		if fi, err := os.Stat(GetConfigFilename()); err == nil {
+			lastModified = fi.ModTime()
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				fi, err := os.Stat(GetConfigFilename())
+				if err != nil || !fi.ModTime().After(lastModified) {
+					continue
+				}
+				lastModified = fi.ModTime()
+				if _, err := ReloadConfig("watch"); err != nil {
+					logger.Errorf("配置文件重载失败: %+v", err)
+				}
+			}
+		}
+	}()
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// DiffConfig 比较两份配置的差异
+func DiffConfig(oldConfig, newConfig Quant1XConfig) []ConfigChange {
+	var changes []ConfigChange
+	diffValue("", reflect.ValueOf(oldConfig), reflect.ValueOf(newConfig), &changes)
+	return changes
+}
+
+var typeOfStringer = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+func diffValue(key string, a, b reflect.Value, changes *[]ConfigChange) {
+	t := a.Type()
+	isLeaf := t.Implements(typeOfStringer) || reflect.PointerTo(t).Implements(typeOfStringer)
+	switch {
+	case !isLeaf && t.Kind() == reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+			if len(name) == 0 || name == "-" {
+				continue
+			}
+			diffValue(joinKey(key, name), a.Field(i), b.Field(i), changes)
+		}
+	case !isLeaf && t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Struct:
+		n := max(a.Len(), b.Len())
+		for i := 0; i < n; i++ {
+			itemKey := fmt.Sprintf("%s[%d]", key, i)
+			switch {
+			case i >= a.Len():
+				*changes = append(*changes, ConfigChange{Key: itemKey, OldValue: "", NewValue: toJson(b.Index(i))})
+			case i >= b.Len():
+				*changes = append(*changes, ConfigChange{Key: itemKey, OldValue: toJson(a.Index(i)), NewValue: ""})
+			default:
+				diffValue(itemKey, a.Index(i), b.Index(i), changes)
+			}
+		}
+	default:
+		oldValue, newValue := leafString(a), leafString(b)
+		if oldValue != newValue {
+			*changes = append(*changes, ConfigChange{Key: key, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+}
+
+func joinKey(prefix, name string) string {
+	if len(prefix) == 0 {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func leafString(v reflect.Value) string {
+	if v.CanInterface() {
+		if s, ok := v.Interface().(fmt.Stringer); ok {
+			return s.String()
+		}
+		if v.Kind() == reflect.Slice || v.Kind() == reflect.Map {
+			return toJson(v)
+		}
+		return fmt.Sprint(v.Interface())
+	}
+	return ""
+}
+
+func toJson(v reflect.Value) string {
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return fmt.Sprint(v.Interface())
+	}
+	return string(data)
+}
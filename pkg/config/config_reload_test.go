@@ -0,0 +1,27 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiffConfig(t *testing.T) {
+	var oldConfig, newConfig Quant1XConfig
+	oldConfig.Trader.Strategies = []StrategyParameter{{Id: 1, Total: 3}}
+	newConfig.Trader.Strategies = []StrategyParameter{{Id: 1, Total: 5}, {Id: 82}}
+	newConfig.Trader.PositionRatio = 0.5
+	changes := DiffConfig(oldConfig, newConfig)
+	for _, v := range changes {
+		fmt.Println(v.Key, v.OldValue, "=>", v.NewValue)
+	}
+	if len(changes) != 3 {
+		t.Errorf("changes = %d, want 3", len(changes))
+	}
+	if err := ValidateConfig(&newConfig); err != nil {
+		t.Error(err)
+	}
+	newConfig.Trader.Strategies[1].Id = 1
+	if err := ValidateConfig(&newConfig); err == nil {
+		t.Error("duplicate strategy id should fail")
+	}
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExpressionRuleParameter 表达式规则参数
+//
+//	表达式成立即为通过, 不成立则返回Code和Message组成的错误
+//	字段引用形如 snapshot.OpenTurnZ, history.MA5, f10.Capital, misc.RZYEZB
+type ExpressionRuleParameter struct {
+	Name    string `name:"规则名称" yaml:"name"`    // 规则名称
+	Expr    string `name:"表达式" yaml:"expr"`     // 通过条件表达式
+	Code    int    `name:"错误码" yaml:"code"`     // 错误码, 为0时由规则组按顺序分配
+	Message string `name:"错误信息" yaml:"message"` // 错误信息
+}
+
+// 缺失数据的处理策略
+//
+//	表达式引用的特征不存在或者字段值为NaN时视为数据缺失, NaN参与比较的结果都不成立
+const (
+	MissingDataFail = "fail" // 表达式不成立时不通过, 错误信息附带缺失的字段
+	MissingDataSkip = "skip" // 跳过引用了缺失数据的规则
+)
+
+// RuleGroupParameter 规则组参数
+type RuleGroupParameter struct {
+	Kind        int                       `name:"规则类型" yaml:"kind"`         // 规则类型, 自定义规则组必须不小于100
+	Name        string                    `name:"规则组名称" yaml:"name"`        // 规则组名称, 策略通过名称选择规则组
+	Description string                    `name:"描述" yaml:"description"`    // 描述
+	ErrorCode   int                       `name:"错误码基数" yaml:"error_code"`  // 错误码基数, 默认kind*1000
+	MissingData string                    `name:"缺失数据" yaml:"missing_data"` // 缺失数据的处理策略: fail, skip, 默认fail
+	Rules       []ExpressionRuleParameter `name:"规则列表" yaml:"rules"`        // 规则列表
+}
+
+// MinimumCustomRuleKind 自定义规则组最小的类型编码, 小于这个值的保留给内置规则
+const MinimumCustomRuleKind = 100
+
+// BaseErrorCode 获取错误码基数
+func (g RuleGroupParameter) BaseErrorCode() int {
+	if g.ErrorCode > 0 {
+		return g.ErrorCode
+	}
+	return g.Kind * 1000
+}
+
+// MissingDataPolicy 获取缺失数据的处理策略
+func (g RuleGroupParameter) MissingDataPolicy() string {
+	policy := strings.ToLower(strings.TrimSpace(g.MissingData))
+	if len(policy) == 0 {
+		return MissingDataFail
+	}
+	return policy
+}
+
+// Validate 校验规则组配置
+func (g RuleGroupParameter) Validate() error {
+	if g.Kind < MinimumCustomRuleKind {
+		return fmt.Errorf("规则组[%s]的kind=%d, 必须不小于%d", g.Name, g.Kind, MinimumCustomRuleKind)
+	}
+	if len(strings.TrimSpace(g.Name)) == 0 {
+		return fmt.Errorf("规则组[kind=%d]名称不能为空", g.Kind)
+	}
+	if policy := g.MissingDataPolicy(); policy != MissingDataFail && policy != MissingDataSkip {
+		return fmt.Errorf("规则组[%s]缺失数据的处理策略无效: %s", g.Name, g.MissingData)
+	}
+	for i, v := range g.Rules {
+		if len(strings.TrimSpace(v.Expr)) == 0 {
+			return fmt.Errorf("规则组[%s]第%d条规则表达式为空", g.Name, i+1)
+		}
+	}
+	return nil
+}
+
+// GetRuleGroups 获取配置的自定义规则组
+func GetRuleGroups() []RuleGroupParameter {
+	return TraderConfig().RuleGroups
+}
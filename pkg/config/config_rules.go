@@ -6,6 +6,7 @@ type RuleParameter struct {
 	SectorsTopN                 int         `yaml:"sectors_top_n" default:"3"`                   // 最多关联多少个板块, 默认3个
 	StockTopNInSector           int         `yaml:"stock_top_n_in_sector" default:"5"`           // 板块内个股排名前N
 	IgnoreRuleGroup             []int       `yaml:"ignore_rule_group"`                           // 忽略规则组合
+	Groups                      []string    `yaml:"groups"`                                      // 选择的自定义规则组名称, 内置规则总是执行
 	IgnoreCodes                 []string    `yaml:"ignore_codes" default:"[\"sh68\",\"bj\"]"`    // 忽略的证券代码段, 默认忽略科创板和北交所全部
 	MaximumIncreaseWithin5days  float64     `yaml:"maximum_increase_within_5d" default:"20.00"`  // 20.00 5日累计最大涨幅
 	MaximumIncreaseWithin10days float64     `yaml:"maximum_increase_within_10d" default:"70.00"` // 70.00 10日累计最大涨幅
@@ -24,10 +25,17 @@ type RuleParameter struct {
 	AmplitudeRatio              NumberRange `yaml:"amplitude_ratio" default:""`                  // 振幅范围, 默认不限制
 	BiddingVolume               NumberRange `yaml:"bidding_volume" default:""`                   // 5档行情委托平均值范围, 默认不限制
 	Sentiment                   NumberRange `yaml:"sentiment" default:"38.2~61.80"`              // 情绪范围
+	EmotionScore                NumberRange `yaml:"emotion_score" default:""`                    // 市场情绪周期的情绪分范围, 默认不限制
+	EmotionPhases               []string    `yaml:"emotion_phases"`                              // 允许交易的情绪周期阶段: ice, warming, climax, retreat, 为空时不限制
 	GapDown                     bool        `yaml:"gap_down" default:"true"`                     // 买入是否允许跳空低开, 默认是允许
 	CheckEPS                    bool        `yaml:"check_eps" default:"false"`                   // 是否检测每股收益, 默认不检测
 	CheckBPS                    bool        `yaml:"check_bps" default:"false"`                   // 是否检测每股净资产, 默认不检测
 	CheckSafetyScore            bool        `yaml:"check_safety_score" default:"false"`          // 是否检测安全分
 	FinancingBalanceRatio       float64     `yaml:"financing_balance_ratio" default:"10"`        // 融资余额占比阀值, 过滤超过阀值的标的
+	UnlockDays                  int         `yaml:"unlock_days" default:"0"`                     // 限售解禁风险期, N个交易日内有解禁的标的需要检测解禁规模, 0为不检测
+	UnlockRatio                 float64     `yaml:"unlock_ratio" default:"0"`                    // 解禁数量占流通股本的比例%阀值, 风险期内解禁规模不低于阀值的标的过滤掉, 0为有解禁即过滤
+	AvoidRecordDate             bool        `yaml:"avoid_record_date" default:"false"`           // 股权登记日, 即除权除息日的前一个交易日不买入, 默认不启用
+	DividendDays                int         `yaml:"dividend_days" default:"0"`                   // 抢权窗口, 大于0时只买入N个交易日内除权除息的标的, 0为不限制
+	DividendYield               NumberRange `yaml:"dividend_yield" default:""`                   // 抢权的股息率范围, 默认不限制
 	Verbose                     bool        `yaml:"verbose" default:"false"`                     // 冗详模式
 }
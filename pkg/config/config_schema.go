@@ -0,0 +1,123 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gitee.com/quant1x/pkg/yaml"
+)
+
+// FieldSchema 配置字段描述
+type FieldSchema struct {
+	Key         string        `json:"key"`                // yaml路径, 多级用.分隔
+	Name        string        `json:"name"`               // 中文名称
+	Type        string        `json:"type"`               // 类型
+	Default     string        `json:"default"`            // 默认值
+	Description string        `json:"description"`        // 描述
+	Fields      []FieldSchema `json:"fields,omitempty"`   // 结构体的子字段
+	Elements    string        `json:"elements,omitempty"` // 数组元素类型
+}
+
+var (
+	typeOfNumberRange    = reflect.TypeOf(NumberRange{})
+	typeOfTradingSession = reflect.TypeOf(TradingSession{})
+)
+
+// StructSchema 通过name, yaml和default标签获取结构体的字段描述
+func StructSchema(v any) []FieldSchema {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return structSchema(t, "")
+}
+
+func structSchema(t reflect.Type, prefix string) []FieldSchema {
+	var fields []FieldSchema
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		key, _, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+		if len(key) == 0 || key == "-" {
+			continue
+		}
+		field := FieldSchema{
+			Key:     prefix + key,
+			Name:    sf.Tag.Get("name"),
+			Type:    schemaTypeName(sf.Type),
+			Default: sf.Tag.Get("default"),
+		}
+		if len(field.Name) == 0 {
+			field.Name = sf.Name
+		}
+		switch {
+		case sf.Type == typeOfNumberRange:
+			field.Description = "数值范围, 格式为min~max, 省略表示不限制"
+		case sf.Type == typeOfTradingSession:
+			field.Description = "交易时段, 格式为hh:mm:ss~hh:mm:ss, 多个时段用逗号分隔"
+		case sf.Type.Kind() == reflect.Struct:
+			field.Fields = structSchema(sf.Type, field.Key+".")
+		case sf.Type.Kind() == reflect.Slice:
+			field.Elements = schemaTypeName(sf.Type.Elem())
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func schemaTypeName(t reflect.Type) string {
+	switch t {
+	case typeOfNumberRange:
+		return "range"
+	case typeOfTradingSession:
+		return "session"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "int"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	case reflect.String:
+		return "string"
+	case reflect.Slice:
+		return "array"
+	case reflect.Map:
+		return "map"
+	case reflect.Struct:
+		return "object"
+	}
+	return t.Kind().String()
+}
+
+// StrategyParameterSchema 策略参数的字段描述
+func StrategyParameterSchema() []FieldSchema {
+	return StructSchema(StrategyParameter{})
+}
+
+// DefaultStrategyParameter 获取只包含默认值的策略参数
+func DefaultStrategyParameter(strategyCode uint64) StrategyParameter {
+	var v StrategyParameter
+	text := fmt.Sprintf("id: %d\n", strategyCode)
+	_ = yaml.Unmarshal([]byte(text), &v)
+	v.Id = strategyCode
+	return v
+}
+
+// LookupStrategyParameter 查找策略配置, 不区分是否自动执行
+//
+//	配置文件中不存在时返回默认值, found为false
+func LookupStrategyParameter(strategyCode uint64) (v StrategyParameter, found bool) {
+	strategies := TraderConfig().Strategies
+	for _, sp := range strategies {
+		if sp.Id == strategyCode {
+			return sp, true
+		}
+	}
+	return DefaultStrategyParameter(strategyCode), false
+}
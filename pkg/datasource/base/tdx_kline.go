@@ -45,10 +45,8 @@ func UpdateCacheKLines(securityCode string, klines []KLine) {
 	klineMutex.Unlock()
 }
 
-// CheckoutKLines 捡出指定日期的K线数据
-func CheckoutKLines(code, date string) []KLine {
-	securityCode := exchange.CorrectSecurityCode(code)
-	date = exchange.FixTradeDate(date)
+// 取缓存的全部K线, 缓存早于date时重新加载
+func checkoutCacheKLines(securityCode, date string) []KLine {
 	// 1. 取缓存的K线
 	klineMutex.RLock()
 	cacheKLines, ok := routineLocalKLines[securityCode]
@@ -61,23 +59,40 @@ func CheckoutKLines(code, date string) []KLine {
 	if rows == 0 {
 		return nil
 	}
-	// 1.1 检查是否最新数据
+	// 2. 检查是否最新数据
 	kline := cacheKLines[rows-1]
 	if kline.Date < date {
 		// 数据太旧, 重新加载
 		cacheKLines = LoadBasicKline(securityCode)
 		UpdateCacheKLines(securityCode, cacheKLines)
 	}
-	// 2. 对齐数据缓存的日期, 过滤可能存在停牌没有数据的情况
+	return cacheKLines
+}
+
+// 截取date及之前的K线
+func sliceKLines(cacheKLines []KLine, date string) []KLine {
+	rows := len(cacheKLines)
+	if rows == 0 {
+		return nil
+	}
+	// 对齐数据缓存的日期, 过滤可能存在停牌没有数据的情况
 	offset := checkKLineOffset(cacheKLines, date)
 	if offset < 0 {
 		return nil
 	}
-	// 3. 返回指定日期前的K线数据
 	klines := cacheKLines[0 : rows-offset]
 	return klines
 }
 
+// CheckoutKLines 捡出指定日期的K线数据
+func CheckoutKLines(code, date string) []KLine {
+	securityCode := exchange.CorrectSecurityCode(code)
+	date = exchange.FixTradeDate(date)
+	cacheKLines := checkoutCacheKLines(securityCode, date)
+	// 返回指定日期前的K线数据
+	return sliceKLines(cacheKLines, date)
+}
+
 // 由日线计算日线以上级别的K线
 func getPeriodKLine(checkPeriod func(date ...string) (s, e string), securityCode string, cacheKLine ...[]KLine) (list []KLine) {
 	baseKLines := []KLine{}
@@ -0,0 +1,258 @@
+package base
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/gotdx/quotes"
+)
+
+// AdjustMode K线的复权方式
+type AdjustMode int
+
+const (
+	AdjustForward  AdjustMode = iota // 前复权, 本地K线缓存的存储方式
+	AdjustNone                       // 不复权
+	AdjustBackward                   // 后复权
+)
+
+var (
+	ErrInvalidAdjustMode = errors.New("无效的复权方式")
+)
+
+var (
+	ForwardAdjusted  = Adjustment{Mode: AdjustForward}  // 前复权, 锚定最新的数据, 和本地K线缓存一致
+	Unadjusted       = Adjustment{Mode: AdjustNone}     // 不复权, 实际成交价格
+	BackwardAdjusted = Adjustment{Mode: AdjustBackward} // 后复权, 上市首日的价格等于实际价格
+)
+
+var (
+	// 复权之后的K线按证券代码和复权方式缓存
+	adjustedMutex         sync.RWMutex
+	routineAdjustedKLines = map[string]adjustedKLines{}
+)
+
+// 复权之后的K线缓存
+type adjustedKLines struct {
+	source string  // 源K线的校验信息, 源K线更新或重新复权之后缓存失效
+	klines []KLine // 复权之后的K线
+}
+
+func (m AdjustMode) String() string {
+	switch m {
+	case AdjustNone:
+		return "none"
+	case AdjustBackward:
+		return "hfq"
+	default:
+		return "qfq"
+	}
+}
+
+// ParseAdjustMode 解析复权方式
+//
+//	qfq或forward为前复权, none或bfq为不复权, hfq或backward为后复权, 空字符串默认前复权
+func ParseAdjustMode(text string) (AdjustMode, error) {
+	switch strings.ToLower(strings.TrimSpace(text)) {
+	case "", "qfq", "forward":
+		return AdjustForward, nil
+	case "none", "bfq":
+		return AdjustNone, nil
+	case "hfq", "backward":
+		return AdjustBackward, nil
+	}
+	return AdjustForward, fmt.Errorf("%w: %s", ErrInvalidAdjustMode, text)
+}
+
+// Adjustment K线的复权参数
+type Adjustment struct {
+	Mode   AdjustMode // 复权方式
+	Anchor string     // 前复权的锚定日期, 锚定日期的价格等于实际价格, 为空时锚定最新的数据
+}
+
+// ForwardAdjustedAt 锚定在anchor日期的前复权
+func ForwardAdjustedAt(anchor string) Adjustment {
+	return Adjustment{Mode: AdjustForward, Anchor: exchange.FixTradeDate(anchor)}
+}
+
+// Key 复权参数的缓存关键字
+func (a Adjustment) Key() string {
+	if a.Mode == AdjustForward && len(a.Anchor) > 0 {
+		return a.Mode.String() + "@" + a.Anchor
+	}
+	return a.Mode.String()
+}
+
+// 是否和本地K线缓存的复权方式相同
+func (a Adjustment) isStorage() bool {
+	return a.Mode == AdjustForward && len(a.Anchor) == 0
+}
+
+// 还原除权除息的前复权价格
+//
+//	前复权价格 = (价格 - 每股现金) / (1 + 每股股数), 配股视为按配股价支付现金
+func restoreXdxr(xdxr quotes.XdxrInfo) func(p float64) float64 {
+	cash := (xdxr.FenHong - xdxr.PeiGuJia*xdxr.PeiGu) / 10
+	shares := (xdxr.SongZhuanGu + xdxr.PeiGu) / 10
+	return func(p float64) float64 {
+		return p*(1+shares) + cash
+	}
+}
+
+// AdjustKLines 本地缓存的前复权K线转换成指定的复权方式, 返回新的切片
+//
+//	本地缓存的K线已经按除权除息日不晚于最后一根K线下一个交易日的记录前复权
+func AdjustKLines(klines []KLine, xdxrs []quotes.XdxrInfo, adjustment Adjustment) []KLine {
+	rows := len(klines)
+	if rows == 0 || adjustment.isStorage() {
+		return klines
+	}
+	lastDayNext := exchange.NextTradeDate(klines[rows-1].Date)
+	return adjustKLines(klines, xdxrs, lastDayNext, adjustment)
+}
+
+func adjustKLines(klines []KLine, xdxrs []quotes.XdxrInfo, lastDayNext string, adjustment Adjustment) []KLine {
+	// 1. 参与了前复权的除权除息记录, 按日期升序
+	var events []quotes.XdxrInfo
+	for _, v := range xdxrs {
+		if v.Category != 1 || v.Date > lastDayNext {
+			continue
+		}
+		events = append(events, v)
+	}
+	if len(events) == 0 {
+		return klines
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Date < events[j].Date
+	})
+	restores := make([]func(p float64) float64, len(events))
+	for i, v := range events {
+		restores[i] = restoreXdxr(v)
+	}
+	// 2. 从最近的除权除息开始倒序还原, 除权除息日晚于起始日期的记录需要还原
+	//	不复权还原K线日期之后的除权除息, 锚定前复权还原锚定日期之后的除权除息, 后复权还原全部的除权除息
+	list := make([]KLine, len(klines))
+	copy(list, klines)
+	for i := range list {
+		kl := &list[i]
+		begin := kl.Date
+		switch adjustment.Mode {
+		case AdjustForward:
+			if adjustment.Anchor > begin {
+				begin = adjustment.Anchor
+			}
+		case AdjustBackward:
+			begin = ""
+		}
+		transform := func(p float64) float64 {
+			for j := len(events) - 1; j >= 0 && events[j].Date > begin; j-- {
+				p = restores[j](p)
+			}
+			return p
+		}
+		// 以成交金额为基准, 用复权之后的均价计算成交量
+		if kl.Volume > 0 && kl.Amount > 0 {
+			kl.Volume = kl.Amount / transform(kl.Amount/kl.Volume)
+		}
+		kl.Open = transform(kl.Open)
+		kl.Close = transform(kl.Close)
+		kl.High = transform(kl.High)
+		kl.Low = transform(kl.Low)
+	}
+	return list
+}
+
+// 锚定日期之后第一个参与前复权的除权除息日, 没有时返回空字符串
+func firstXdxrAfter(xdxrs []quotes.XdxrInfo, anchor, lastDayNext string) string {
+	first := ""
+	for _, v := range xdxrs {
+		if v.Category != 1 || v.Date <= anchor || v.Date > lastDayNext {
+			continue
+		}
+		if len(first) == 0 || v.Date < first {
+			first = v.Date
+		}
+	}
+	return first
+}
+
+func lookupAdjustedCache(key, source string) ([]KLine, bool) {
+	adjustedMutex.RLock()
+	defer adjustedMutex.RUnlock()
+	v, ok := routineAdjustedKLines[key]
+	if !ok || v.source != source {
+		return nil, false
+	}
+	return v.klines, true
+}
+
+func storeAdjustedCache(key, source string, klines []KLine) {
+	adjustedMutex.Lock()
+	defer adjustedMutex.Unlock()
+	routineAdjustedKLines[key] = adjustedKLines{source: source, klines: klines}
+}
+
+// 复权之后的K线, 按证券代码和复权方式缓存
+//
+//	锚定前复权只还原锚定日期之后的除权除息, 按锚定日期之后的第一个除权除息日归并缓存,
+//	回测逐日锚定时每个证券的缓存数不超过除权除息的次数
+func checkoutAdjustedCache(securityCode string, klines []KLine, adjustment Adjustment) []KLine {
+	rows := len(klines)
+	if rows == 0 || adjustment.isStorage() {
+		return klines
+	}
+	lastDayNext := exchange.NextTradeDate(klines[rows-1].Date)
+	anchored := adjustment.Mode == AdjustForward
+	if anchored && adjustment.Anchor >= lastDayNext {
+		// 锚定日期之后没有参与前复权的除权除息, 和本地缓存相同
+		return klines
+	}
+	key := adjustment.Key() + "/" + securityCode
+	source := fmt.Sprintf("%d,%s,%s", rows, klines[0].Datetime, klines[rows-1].Date)
+	if list, ok := lookupAdjustedCache(key, source); ok {
+		return list
+	}
+	xdxrs := GetCacheXdxrList(securityCode)
+	if !anchored {
+		list := adjustKLines(klines, xdxrs, lastDayNext, adjustment)
+		storeAdjustedCache(key, source, list)
+		return list
+	}
+	list := klines
+	if first := firstXdxrAfter(xdxrs, adjustment.Anchor, lastDayNext); len(first) > 0 {
+		mergedKey := AdjustForward.String() + ">" + first + "/" + securityCode
+		var ok bool
+		if list, ok = lookupAdjustedCache(mergedKey, source); !ok {
+			list = adjustKLines(klines, xdxrs, lastDayNext, adjustment)
+			storeAdjustedCache(mergedKey, source, list)
+		}
+	}
+	// 锚定最新日期时(实时)同一个锚定日期会反复使用, 再按锚定日期缓存一份切片的引用
+	if adjustment.Anchor >= klines[rows-1].Date {
+		storeAdjustedCache(key, source, list)
+	}
+	return list
+}
+
+// LoadAdjustedKLines 按复权方式加载全部的日K线
+func LoadAdjustedKLines(securityCode string, adjustment Adjustment) []KLine {
+	securityCode = exchange.CorrectSecurityCode(securityCode)
+	klines := checkoutCacheKLines(securityCode, "")
+	return checkoutAdjustedCache(securityCode, klines, adjustment)
+}
+
+// CheckoutAdjustedKLines 按复权方式捡出指定日期的K线数据
+//
+//	复权是对截止最新的全部K线进行的, 锚定前复权不受date影响
+func CheckoutAdjustedKLines(code, date string, adjustment Adjustment) []KLine {
+	securityCode := exchange.CorrectSecurityCode(code)
+	date = exchange.FixTradeDate(date)
+	klines := checkoutCacheKLines(securityCode, date)
+	klines = checkoutAdjustedCache(securityCode, klines, adjustment)
+	return sliceKLines(klines, date)
+}
@@ -0,0 +1,106 @@
+package base
+
+import (
+	"math"
+	"testing"
+
+	"gitee.com/quant1x/gotdx/quotes"
+)
+
+func testAdjustXdxrs() []quotes.XdxrInfo {
+	return []quotes.XdxrInfo{
+		// 10派10元
+		{Date: "2024-01-03", Category: 1, FenHong: 10},
+		// 10转10
+		{Date: "2024-01-05", Category: 1, SongZhuanGu: 10},
+		// 股本变化, 不参与复权
+		{Date: "2024-01-04", Category: 5},
+		// 提前公布, 尚未除权
+		{Date: "2024-02-01", Category: 1, FenHong: 5},
+	}
+}
+
+// 本地缓存的前复权K线, 实际收盘价: 01-02为11, 01-03除息后为10, 01-04为10, 01-05除权后为5
+func testAdjustKLines() []KLine {
+	return []KLine{
+		{Date: "2024-01-02", Open: 4.5, Close: 5, High: 5, Low: 4.5, Volume: 220, Amount: 1100},
+		{Date: "2024-01-03", Open: 5, Close: 5, High: 5, Low: 5, Volume: 200, Amount: 1000},
+		{Date: "2024-01-04", Open: 5, Close: 5, High: 5, Low: 5, Volume: 200, Amount: 1000},
+		{Date: "2024-01-05", Open: 5, Close: 5, High: 5, Low: 5, Volume: 200, Amount: 1000},
+	}
+}
+
+func assertCloses(t *testing.T, klines []KLine, want []float64) {
+	t.Helper()
+	if len(klines) != len(want) {
+		t.Fatalf("rows = %d, want %d", len(klines), len(want))
+	}
+	for i, v := range klines {
+		if math.Abs(v.Close-want[i]) > 1e-9 {
+			t.Errorf("%s close = %v, want %v", v.Date, v.Close, want[i])
+		}
+	}
+}
+
+func Test_adjustKLines(t *testing.T) {
+	klines := testAdjustKLines()
+	xdxrs := testAdjustXdxrs()
+	lastDayNext := "2024-01-08"
+
+	none := adjustKLines(klines, xdxrs, lastDayNext, Unadjusted)
+	assertCloses(t, none, []float64{11, 10, 10, 5})
+	// 成交金额不变, 成交量按实际均价折算
+	if math.Abs(none[0].Volume-100) > 1e-9 || math.Abs(none[3].Volume-200) > 1e-9 {
+		t.Errorf("volume = %v, %v", none[0].Volume, none[3].Volume)
+	}
+
+	anchored := adjustKLines(klines, xdxrs, lastDayNext, ForwardAdjustedAt("2024-01-03"))
+	assertCloses(t, anchored, []float64{10, 10, 10, 5})
+
+	backward := adjustKLines(klines, xdxrs, lastDayNext, BackwardAdjusted)
+	assertCloses(t, backward, []float64{11, 11, 11, 11})
+
+	// 源数据不变
+	assertCloses(t, klines, []float64{5, 5, 5, 5})
+}
+
+func TestParseAdjustMode(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    AdjustMode
+		wantErr bool
+	}{
+		{text: "", want: AdjustForward},
+		{text: "qfq", want: AdjustForward},
+		{text: "none", want: AdjustNone},
+		{text: "HFQ", want: AdjustBackward},
+		{text: "bad", want: AdjustForward, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAdjustMode(tt.text)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseAdjustMode(%q) = %v, %v", tt.text, got, err)
+		}
+	}
+	if key := ForwardAdjusted.Key(); key != "qfq" {
+		t.Errorf("key = %s", key)
+	}
+}
+
+func Test_firstXdxrAfter(t *testing.T) {
+	xdxrs := testAdjustXdxrs()
+	lastDayNext := "2024-01-08"
+	if v := firstXdxrAfter(xdxrs, "2024-01-02", lastDayNext); v != "2024-01-03" {
+		t.Errorf("first after 01-02 = %q", v)
+	}
+	// 股本变化和尚未除权的记录不参与
+	if v := firstXdxrAfter(xdxrs, "2024-01-05", lastDayNext); v != "" {
+		t.Errorf("first after 01-05 = %q", v)
+	}
+	// 第一个除权除息日相同的锚定日期, 复权结果相同, 可以共用缓存
+	if v := firstXdxrAfter(xdxrs, "2024-01-04", lastDayNext); v != "2024-01-05" {
+		t.Errorf("first after 01-04 = %q", v)
+	}
+	klines := testAdjustKLines()
+	assertCloses(t, adjustKLines(klines, xdxrs, lastDayNext, ForwardAdjustedAt("2024-01-04")), []float64{10, 10, 10, 5})
+}
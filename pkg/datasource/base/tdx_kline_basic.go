@@ -52,11 +52,11 @@ func (k *KLine) GetAdjustmentCount() int {
 	return adjustTimes
 }
 
-// LoadBasicKline 加载基础K线
+// LoadBasicKline 加载基础K线, 按配置的存储格式读取
 func LoadBasicKline(securityCode string) []KLine {
 	filename := cache.KLineFilename(securityCode)
 	var klines []KLine
-	_ = api.CsvToSlices(filename, &klines)
+	_ = cache.LoadSlices(filename, &klines)
 	return klines
 }
 
@@ -221,7 +221,7 @@ func UpdateAllBasicKLine(securityCode string) []KLine {
 	if len(klines) > 0 {
 		UpdateCacheKLines(securityCode, klines)
 		fname := cache.KLineFilename(securityCode)
-		_ = api.SlicesToCsv(fname, klines)
+		_ = cache.SaveSlices(fname, klines)
 	}
 	return klines
 }
@@ -86,6 +86,7 @@ func BatchRealtimeBasicKLine(codes []string) error {
 			text := api.Bytes2String(data)
 			logger.Errorf("realtime kline error, code: %s, date=[%s]", securityCode, text)
 			_ = os.Remove(klineFilename)
+			_ = os.Remove(cache.ColumnarFilename(klineFilename))
 			// 全量更新K线
 			UpdateAllBasicKLine(securityCode)
 			continue
@@ -116,7 +117,7 @@ func BatchRealtimeBasicKLine(codes []string) error {
 		}
 		// 连接缓存和实时数据
 		klines = append(klines, kl)
-		err := api.SlicesToCsv(klineFilename, klines)
+		err := cache.SaveSlices(klineFilename, klines)
 		if err != nil {
 			logger.Errorf("更新K线数据文件失败:%s", v.Code)
 		}
@@ -176,6 +177,7 @@ func BasicKLineForSnapshot(v quotes.Snapshot) {
 		text := api.Bytes2String(data)
 		logger.Errorf("realtime kline error, code: %s, date=[%s]", securityCode, text)
 		_ = os.Remove(klineFilename)
+		_ = os.Remove(cache.ColumnarFilename(klineFilename))
 		// 全量更新K线
 		UpdateAllBasicKLine(securityCode)
 		return
@@ -208,7 +210,7 @@ func BasicKLineForSnapshot(v quotes.Snapshot) {
 	UpdateCacheKLines(securityCode, klines)
 	// 连接缓存和实时数据
 	klines = append(klines, kl)
-	err := api.SlicesToCsv(klineFilename, klines)
+	err := cache.SaveSlices(klineFilename, klines)
 	if err != nil {
 		logger.Errorf("更新K线数据文件失败:%s", v.Code)
 	}
@@ -100,10 +100,8 @@ type StockCapital struct {
 	UpdateTime      string // 更新时间
 }
 
-// CapitalChange 获取股本变动记录
-//
-//	deprecated: 不推荐, 太慢
-func CapitalChange(securityCode string) (list []StockCapital) {
+// 获取F10的股本结构
+func rawCapitalStructure(securityCode string) (*rawCapital, string, error) {
 	code := exchange.CorrectSecurityCode(securityCode)
 	params := urlpkg.Values{
 		"code": {strings.ToUpper(code)},
@@ -113,17 +111,28 @@ func CapitalChange(securityCode string) (list []StockCapital) {
 	data, lastModified, err := http.Request(url, http.MethodGet, "")
 	//fmt.Println(api.Bytes2String(data))
 	if err != nil {
-		return
+		return nil, "", err
 	}
 	var css rawCapital
 	err = json.Unmarshal(data, &css)
 	if err != nil {
-		return
+		return nil, "", err
 	}
 	if lastModified.UnixMilli() > 0 {
 		lastModified = time.Now()
 	}
 	updateTime := lastModified.Format(time.DateTime)
+	return &css, updateTime, nil
+}
+
+// CapitalChange 获取股本变动记录
+//
+//	deprecated: 不推荐, 太慢
+func CapitalChange(securityCode string) (list []StockCapital) {
+	css, updateTime, err := rawCapitalStructure(securityCode)
+	if err != nil {
+		return
+	}
 	for _, v := range css.Lngbbd {
 		sc := StockCapital{
 			Code:            securityCode,
@@ -141,3 +150,42 @@ func CapitalChange(securityCode string) (list []StockCapital) {
 	})
 	return
 }
+
+// ShareUnlock 限售解禁
+type ShareUnlock struct {
+	Code       string  // 证券代码
+	LiftDate   string  // 解禁日期
+	LiftNum    int     // 解禁数量, 单位是股
+	LiftType   string  // 限售股类型
+	TotalRatio float64 // 占总股本比例%
+	FloatRatio float64 // 占已流通A股比例%
+	UpdateTime string  // 更新时间
+}
+
+// StockUnlocks 获取个股的限售解禁记录, 包括已经解禁和尚未解禁的批次, 按解禁日期升序
+func StockUnlocks(securityCode string) ([]ShareUnlock, error) {
+	securityCode = exchange.CorrectSecurityCode(securityCode)
+	css, updateTime, err := rawCapitalStructure(securityCode)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]ShareUnlock, 0, len(css.Xsjj))
+	for _, v := range css.Xsjj {
+		if len(v.LIFTDATE) == 0 {
+			continue
+		}
+		list = append(list, ShareUnlock{
+			Code:       securityCode,
+			LiftDate:   exchange.FixTradeDate(v.LIFTDATE),
+			LiftNum:    v.LIFTNUM,
+			LiftType:   v.LIFTTYPE,
+			TotalRatio: v.TOTALSHARESRATIO,
+			FloatRatio: v.UNLIMITEDASHARESRATIO,
+			UpdateTime: updateTime,
+		})
+	}
+	api.SliceSort(list, func(a, b ShareUnlock) bool {
+		return a.LiftDate < b.LiftDate
+	})
+	return list, nil
+}
@@ -12,3 +12,9 @@ func TestCapitalChange(t *testing.T) {
 	fmt.Println(v)
 	fmt.Println(time.Local)
 }
+
+func TestStockUnlocks(t *testing.T) {
+	code := "sh688981"
+	list, err := StockUnlocks(code)
+	fmt.Println(list, err)
+}
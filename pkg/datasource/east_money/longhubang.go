@@ -309,3 +309,53 @@ func rawBillBoardList(date string, pageNumber int, direction string) ([]BillBoar
 	}
 	return raw.Data, raw.Pages, nil
 }
+
+// 按方向获取全部分页的龙虎榜明细
+func billBoardListByDirection(date string, direction string) ([]BillBoard, error) {
+	var list []BillBoard
+	pages := 1
+	for i := 0; i < pages; i++ {
+		tmpList, tmpPages, err := rawBillBoardList(date, i+1, direction)
+		if err != nil {
+			return list, err
+		}
+		list = append(list, tmpList...)
+		if len(tmpList) < rzrqPageSize {
+			break
+		}
+		if pages == 1 {
+			pages = tmpPages
+		}
+	}
+	return list, nil
+}
+
+// GetBillBoardList 获取指定日期的龙虎榜席位明细
+//
+//	买入前五和卖出前五的席位合并, 同一个席位因多个上榜原因重复出现时只保留一条, 买入和卖出取较大值
+func GetBillBoardList(date string) ([]BillBoard, error) {
+	var list []BillBoard
+	mapSeats := map[string]int{}
+	for _, direction := range []string{lhbBuy, lhbSell} {
+		tmpList, err := billBoardListByDirection(date, direction)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range tmpList {
+			key := v.SECURITY_CODE + "|" + v.OPERATEDEPT_CODE
+			idx, ok := mapSeats[key]
+			if !ok {
+				mapSeats[key] = len(list)
+				list = append(list, v)
+				continue
+			}
+			seat := &list[idx]
+			seat.BUY = max(seat.BUY, v.BUY)
+			seat.SELL = max(seat.SELL, v.SELL)
+			seat.NET = seat.BUY - seat.SELL
+			seat.RISE_PROBABILITY_3DAY = max(seat.RISE_PROBABILITY_3DAY, v.RISE_PROBABILITY_3DAY)
+			seat.TOTAL_BUYER_SALESTIMES_3DAY = max(seat.TOTAL_BUYER_SALESTIMES_3DAY, v.TOTAL_BUYER_SALESTIMES_3DAY)
+		}
+	}
+	return list, nil
+}
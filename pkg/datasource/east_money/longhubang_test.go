@@ -10,3 +10,9 @@ func Test_rawBillBoard(t *testing.T) {
 	v, n, err := rawBillBoardList(date, 1, lhbBuy)
 	fmt.Println(v, n, err)
 }
+
+func TestGetBillBoardList(t *testing.T) {
+	date := "20240716"
+	list, err := GetBillBoardList(date)
+	fmt.Println(len(list), err)
+}
@@ -0,0 +1,151 @@
+package emotion
+
+import (
+	"math"
+	"time"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/gox/logger"
+	"gitee.com/quant1x/num"
+	"xquant/pkg/datasource/base"
+	"xquant/pkg/factors"
+	"xquant/pkg/market"
+	"xquant/pkg/models"
+)
+
+const (
+	closeTime = "15:00:00" // 收盘数据的计算时间
+)
+
+// consecutiveBoards 按K线逐日计算连板数
+//
+//	涨停价取PriceLimitSeries, 只取最近的观察周期, 连板高度不会超过观察周期.
+//	涨停价按前一日的实际收盘价计算, klines必须是不复权的K线
+func consecutiveBoards(securityCode string, klines []base.KLine) (limitUp, limitDown []float64, boards []int) {
+	if n := len(klines); n > factors.S8PeriodOfLimitUp+1 {
+		klines = klines[n-factors.S8PeriodOfLimitUp-1:]
+	}
+	limitUp, limitDown = factors.PriceLimitSeries(securityCode, klines)
+	boards = make([]int, len(klines))
+	for i, v := range klines {
+		if math.IsNaN(limitUp[i]) || num.Decimal(v.Close) < limitUp[i] {
+			continue
+		}
+		boards[i] = 1
+		if i > 0 {
+			boards[i] += boards[i-1]
+		}
+	}
+	return
+}
+
+// checkoutKLines 截止date的不复权K线, 涨跌停按实际价格判断
+func checkoutKLines(securityCode, date string) []base.KLine {
+	return base.CheckoutAdjustedKLines(securityCode, date, base.Unadjusted)
+}
+
+// stockDayOfKLines 个股在最后一根K线的涨跌停状态
+func stockDayOfKLines(securityCode string, klines []base.KLine) (StockDay, bool) {
+	n := len(klines)
+	if n < 2 {
+		return StockDay{}, false
+	}
+	limitUp, limitDown, boards := consecutiveBoards(securityCode, klines)
+	m := len(boards)
+	v := klines[n-1]
+	day := StockDay{
+		Code:       securityCode,
+		LastClose:  klines[n-2].Close,
+		Open:       v.Open,
+		Close:      v.Close,
+		High:       v.High,
+		Boards:     boards[m-1],
+		PrevBoards: boards[m-2],
+	}
+	if !math.IsNaN(limitUp[m-1]) {
+		day.LimitUp, day.LimitDown = limitUp[m-1], limitDown[m-1]
+	}
+	return day, true
+}
+
+// CollectDaily 按收盘后的K线计算指定日期的情绪指数
+func CollectDaily(date string) Emotion {
+	date = exchange.FixTradeDate(date)
+	var stocks []StockDay
+	for _, securityCode := range market.GetStockCodeList() {
+		klines := checkoutKLines(securityCode, date)
+		// 当日停牌的个股不参与统计
+		if len(klines) == 0 || klines[len(klines)-1].Date != date {
+			continue
+		}
+		if day, ok := stockDayOfKLines(securityCode, klines); ok {
+			stocks = append(stocks, day)
+		}
+	}
+	e := Compute(date, stocks, Previous(date))
+	e.Time = closeTime
+	return e
+}
+
+// CollectIntraday 按实时快照计算当日的情绪指数
+//
+//	连板数按截至前一个交易日的K线计算, 涨停价按快照的昨收计算
+func CollectIntraday() Emotion {
+	date := exchange.GetCurrentlyDay()
+	var stocks []StockDay
+	for _, securityCode := range market.GetStockCodeList() {
+		snapshot := models.SnapshotMgr.GetStrategySnapshot(securityCode)
+		if snapshot == nil || snapshot.Price <= 0 || snapshot.LastClose <= 0 {
+			continue
+		}
+		klines := checkoutKLines(securityCode, date)
+		// 实时K线任务可能已经追加了当日的K线
+		for len(klines) > 0 && klines[len(klines)-1].Date >= date {
+			klines = klines[:len(klines)-1]
+		}
+		day := StockDay{
+			Code:      securityCode,
+			LastClose: snapshot.LastClose,
+			Open:      snapshot.Open,
+			Close:     snapshot.Price,
+			High:      snapshot.High,
+		}
+		if len(klines) > 0 {
+			_, _, boards := consecutiveBoards(securityCode, klines)
+			day.PrevBoards = boards[len(boards)-1]
+		}
+		limit := factors.CheckoutPriceLimit(securityCode, date, snapshot.LastClose)
+		if !limit.NoLimit {
+			day.LimitUp, day.LimitDown = limit.LimitUp, limit.LimitDown
+		}
+		if day.IsLimitUp() {
+			day.Boards = day.PrevBoards + 1
+		}
+		stocks = append(stocks, day)
+	}
+	e := Compute(date, stocks, Previous(date))
+	e.Time = time.Now().Format(time.TimeOnly)
+	return e
+}
+
+// UpdateDaily 计算并保存指定日期收盘后的情绪指数
+func UpdateDaily(date string) {
+	e := CollectDaily(date)
+	if e.Count == 0 {
+		return
+	}
+	if err := SaveDaily(e); err != nil {
+		logger.Errorf("情绪指数保存失败: %s, error=%+v", e.Date, err)
+	}
+}
+
+// UpdateIntraday 计算并追加盘中的情绪指数
+func UpdateIntraday() {
+	e := CollectIntraday()
+	if e.Count == 0 {
+		return
+	}
+	if err := SaveIntraday(e); err != nil {
+		logger.Errorf("盘中情绪指数保存失败: %s, error=%+v", e.Date, err)
+	}
+}
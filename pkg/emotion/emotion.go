@@ -0,0 +1,223 @@
+package emotion
+
+import (
+	"math"
+
+	"gitee.com/quant1x/num"
+)
+
+// Phase 情绪周期的阶段
+type Phase = string
+
+const (
+	PhaseIcePoint Phase = "ice"     // 冰点
+	PhaseWarming  Phase = "warming" // 回暖
+	PhaseClimax   Phase = "climax"  // 高潮
+	PhaseRetreat  Phase = "retreat" // 退潮
+)
+
+// PhaseName 情绪周期阶段的中文名称
+func PhaseName(phase Phase) string {
+	switch phase {
+	case PhaseIcePoint:
+		return "冰点"
+	case PhaseWarming:
+		return "回暖"
+	case PhaseClimax:
+		return "高潮"
+	case PhaseRetreat:
+		return "退潮"
+	default:
+		return "未知"
+	}
+}
+
+const (
+	scoreIcePoint = 30.00 // 情绪分不高于30为冰点
+	scoreClimax   = 70.00 // 情绪分不低于70为高潮
+	scoreNeutral  = 50.00 // 没有前一日数据时, 区分回暖和退潮的情绪分
+
+	fullLimitUpNum   = 100.00 // 涨停家数达到100家时, 涨停因子满分
+	fullLimitDownNum = 50.00  // 跌停家数达到50家时, 跌停因子为0分
+	fullBoards       = 7.00   // 最高连板达到7板时, 高度因子满分
+	fullPremium      = 5.00   // 昨日涨停的平均溢价达到±5%时, 溢价因子为满分或0分
+)
+
+// StockDay 个股在一个交易日的涨跌停状态
+type StockDay struct {
+	Code       string  // 证券代码
+	LastClose  float64 // 昨收
+	Open       float64 // 开盘价
+	Close      float64 // 收盘价, 盘中为现价
+	High       float64 // 最高价
+	LimitUp    float64 // 涨停价, 不设涨跌幅限制时为0
+	LimitDown  float64 // 跌停价, 不设涨跌幅限制时为0
+	Boards     int     // 截至当日的连板数, 当日未涨停为0
+	PrevBoards int     // 截至前一个交易日的连板数, 前一个交易日未涨停为0
+}
+
+// IsLimitUp 是否收盘涨停
+func (this StockDay) IsLimitUp() bool {
+	return this.LimitUp > 0 && num.Decimal(this.Close) >= this.LimitUp
+}
+
+// IsTouched 盘中是否触及涨停
+func (this StockDay) IsTouched() bool {
+	return this.LimitUp > 0 && num.Decimal(this.High) >= this.LimitUp
+}
+
+// IsLimitDown 是否收盘跌停
+func (this StockDay) IsLimitDown() bool {
+	return this.LimitDown > 0 && num.Decimal(this.Close) <= this.LimitDown
+}
+
+// Emotion 市场情绪指数
+type Emotion struct {
+	Date                string  `name:"日期" dataframe:"date"`                     // 交易日期
+	Time                string  `name:"时间" dataframe:"time"`                     // 计算时间, 收盘数据为15:00:00
+	Count               int     `name:"个股数" dataframe:"count"`                   // 参与统计的个股数
+	UpCount             int     `name:"上涨家数" dataframe:"up_count"`               // 上涨家数
+	DownCount           int     `name:"下跌家数" dataframe:"down_count"`             // 下跌家数
+	LimitUpNum          int     `name:"涨停数" dataframe:"limit_up_num"`            // 收盘涨停家数
+	TouchedNum          int     `name:"触板数" dataframe:"touched_num"`             // 盘中触及涨停的家数
+	BrokenNum           int     `name:"炸板数" dataframe:"broken_num"`              // 触及涨停但收盘未封住的家数
+	BrokenRate          float64 `name:"炸板率%" dataframe:"broken_rate"`            // 炸板数/触板数
+	LimitDownNum        int     `name:"跌停数" dataframe:"limit_down_num"`          // 收盘跌停家数
+	MaxBoards           int     `name:"最高连板" dataframe:"max_boards"`             // 最高连板高度
+	MultiBoardNum       int     `name:"连板数" dataframe:"multi_board_num"`         // 2板及以上的家数
+	PrevLimitUpNum      int     `name:"昨日涨停数" dataframe:"prev_limit_up_num"`     // 前一个交易日涨停的家数
+	PromotionRate       float64 `name:"连板晋级率%" dataframe:"promotion_rate"`       // 昨日涨停今日继续涨停的比例
+	FirstPromotionRate  float64 `name:"1进2%" dataframe:"first_promotion_rate"`   // 昨日首板今日2板的比例
+	SecondPromotionRate float64 `name:"2进3%" dataframe:"second_promotion_rate"`  // 昨日2板今日3板的比例
+	HighPromotionRate   float64 `name:"高位晋级率%" dataframe:"high_promotion_rate"`  // 昨日3板及以上今日继续涨停的比例
+	PremiumRate         float64 `name:"昨日涨停溢价%" dataframe:"premium_rate"`        // 昨日涨停个股今日的平均涨幅
+	OpenPremiumRate     float64 `name:"昨日涨停开盘溢价%" dataframe:"open_premium_rate"` // 昨日涨停个股今日的平均开盘涨幅
+	Score               float64 `name:"情绪分" dataframe:"score"`                   // 情绪分, 0~100
+	Phase               Phase   `name:"情绪阶段" dataframe:"phase"`                  // 情绪周期阶段
+	UpdateTime          string  `name:"更新时间" dataframe:"update_time"`            // 更新时间
+}
+
+// PhaseName 情绪周期阶段的中文名称
+func (this Emotion) PhaseName() string {
+	return PhaseName(this.Phase)
+}
+
+// Compute 根据全市场个股的涨跌停状态计算情绪指数
+//
+//	prev为前一个交易日的情绪指数, 用于判断情绪周期的阶段, 可以为nil
+func Compute(date string, stocks []StockDay, prev *Emotion) Emotion {
+	e := Emotion{Date: date}
+	var (
+		promoted      [4]int // 按昨日连板数统计的今日晋级数, 3代表3板及以上
+		prevBoards    [4]int // 按昨日连板数统计的家数
+		premium, open float64
+		premiumCount  int
+	)
+	for _, v := range stocks {
+		if v.LastClose <= 0 || v.Close <= 0 {
+			continue
+		}
+		e.Count++
+		if v.Close > v.LastClose {
+			e.UpCount++
+		} else if v.Close < v.LastClose {
+			e.DownCount++
+		}
+		if v.IsTouched() {
+			e.TouchedNum++
+		}
+		if v.IsLimitUp() {
+			e.LimitUpNum++
+			e.MaxBoards = max(e.MaxBoards, v.Boards)
+			if v.Boards >= 2 {
+				e.MultiBoardNum++
+			}
+		} else if v.IsTouched() {
+			e.BrokenNum++
+		}
+		if v.IsLimitDown() {
+			e.LimitDownNum++
+		}
+		if v.PrevBoards > 0 {
+			level := min(v.PrevBoards, 3)
+			prevBoards[level]++
+			if v.IsLimitUp() {
+				promoted[level]++
+			}
+			premium += changeRate(v.LastClose, v.Close)
+			if v.Open > 0 {
+				open += changeRate(v.LastClose, v.Open)
+			}
+			premiumCount++
+		}
+	}
+	e.BrokenRate = rate(e.BrokenNum, e.TouchedNum)
+	e.PrevLimitUpNum = prevBoards[1] + prevBoards[2] + prevBoards[3]
+	e.PromotionRate = rate(promoted[1]+promoted[2]+promoted[3], e.PrevLimitUpNum)
+	e.FirstPromotionRate = rate(promoted[1], prevBoards[1])
+	e.SecondPromotionRate = rate(promoted[2], prevBoards[2])
+	e.HighPromotionRate = rate(promoted[3], prevBoards[3])
+	if premiumCount > 0 {
+		e.PremiumRate = num.Decimal(premium / float64(premiumCount))
+		e.OpenPremiumRate = num.Decimal(open / float64(premiumCount))
+	}
+	e.Score = score(e)
+	e.Phase = classify(e, prev)
+	return e
+}
+
+// rate 百分比, 分母为0时返回0
+func rate(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return num.Decimal(float64(n) / float64(total) * 100)
+}
+
+// changeRate 涨跌幅百分比
+func changeRate(base, price float64) float64 {
+	return (price/base - 1) * 100
+}
+
+// clamp01 限制在0~1之间
+func clamp01(v float64) float64 {
+	return math.Min(math.Max(v, 0), 1)
+}
+
+// score 情绪分
+//
+//	由涨停家数、封板率、连板晋级率、昨日涨停溢价、跌停家数和连板高度加权得到, 范围0~100
+func score(e Emotion) float64 {
+	limitUp := clamp01(float64(e.LimitUpNum) / fullLimitUpNum)
+	sealed := 1.00
+	if e.TouchedNum > 0 {
+		sealed = 1 - e.BrokenRate/100
+	}
+	promotion := e.PromotionRate / 100
+	premium := clamp01((e.PremiumRate + fullPremium) / (2 * fullPremium))
+	limitDown := 1 - clamp01(float64(e.LimitDownNum)/fullLimitDownNum)
+	height := clamp01(float64(e.MaxBoards) / fullBoards)
+	v := 0.20*limitUp + 0.15*sealed + 0.20*promotion + 0.20*premium + 0.10*limitDown + 0.15*height
+	return num.Decimal(v * 100)
+}
+
+// classify 情绪周期阶段
+//
+//	情绪分不高于30为冰点, 不低于70为高潮, 介于两者之间时, 情绪分较前一日上升为回暖, 下降为退潮
+func classify(e Emotion, prev *Emotion) Phase {
+	switch {
+	case e.Score <= scoreIcePoint:
+		return PhaseIcePoint
+	case e.Score >= scoreClimax:
+		return PhaseClimax
+	case prev == nil:
+		if e.Score >= scoreNeutral {
+			return PhaseWarming
+		}
+		return PhaseRetreat
+	case e.Score >= prev.Score:
+		return PhaseWarming
+	default:
+		return PhaseRetreat
+	}
+}
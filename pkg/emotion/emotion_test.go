@@ -0,0 +1,82 @@
+package emotion
+
+import (
+	"testing"
+)
+
+func TestCompute(t *testing.T) {
+	stocks := []StockDay{
+		// 昨日首板, 今日2板
+		{Code: "sh600001", LastClose: 10, Open: 10.5, Close: 11, High: 11, LimitUp: 11, LimitDown: 9, Boards: 2, PrevBoards: 1},
+		// 昨日2板, 今日炸板
+		{Code: "sh600002", LastClose: 10, Open: 10.2, Close: 10.5, High: 11, LimitUp: 11, LimitDown: 9, PrevBoards: 2},
+		// 首板
+		{Code: "sh600003", LastClose: 10, Open: 10, Close: 11, High: 11, LimitUp: 11, LimitDown: 9, Boards: 1},
+		// 跌停
+		{Code: "sh600004", LastClose: 10, Open: 9.5, Close: 9, High: 9.8, LimitUp: 11, LimitDown: 9},
+		// 不设涨跌幅限制的新股
+		{Code: "sh600005", LastClose: 10, Open: 15, Close: 20, High: 20},
+	}
+	e := Compute("2024-01-02", stocks, nil)
+	if e.Count != 5 || e.UpCount != 4 || e.DownCount != 1 {
+		t.Errorf("count: %+v", e)
+	}
+	if e.LimitUpNum != 2 || e.TouchedNum != 3 || e.BrokenNum != 1 || e.BrokenRate != 33.33 || e.LimitDownNum != 1 {
+		t.Errorf("limit: %+v", e)
+	}
+	if e.MaxBoards != 2 || e.MultiBoardNum != 1 || e.PrevLimitUpNum != 2 {
+		t.Errorf("boards: %+v", e)
+	}
+	if e.PromotionRate != 50 || e.FirstPromotionRate != 100 || e.SecondPromotionRate != 0 || e.HighPromotionRate != 0 {
+		t.Errorf("promotion: %+v", e)
+	}
+	if e.PremiumRate != 7.5 || e.OpenPremiumRate != 3.5 {
+		t.Errorf("premium: %+v", e)
+	}
+	if e.Score <= 0 || e.Score > 100 || len(e.Phase) == 0 {
+		t.Errorf("score: %+v", e)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		score float64
+		prev  *Emotion
+		want  Phase
+	}{
+		{score: 20, want: PhaseIcePoint},
+		{score: 80, prev: &Emotion{Score: 90}, want: PhaseClimax},
+		{score: 55, want: PhaseWarming},
+		{score: 45, want: PhaseRetreat},
+		{score: 45, prev: &Emotion{Score: 25}, want: PhaseWarming},
+		{score: 55, prev: &Emotion{Score: 75}, want: PhaseRetreat},
+	}
+	for _, tt := range tests {
+		if got := classify(Emotion{Score: tt.score}, tt.prev); got != tt.want {
+			t.Errorf("classify(%v, %+v)=%s, want %s", tt.score, tt.prev, got, tt.want)
+		}
+	}
+}
+
+func TestAvailable(t *testing.T) {
+	emotionMutex.Lock()
+	loaded, series := emotionLoaded, emotionSeries
+	emotionLoaded = true
+	emotionSeries = []Emotion{{Date: "2024-06-17", Score: 30}, {Date: "2024-06-18", Score: 70}}
+	emotionMutex.Unlock()
+	defer func() {
+		emotionMutex.Lock()
+		emotionLoaded, emotionSeries = loaded, series
+		emotionMutex.Unlock()
+	}()
+	// 历史日期不能使用当日收盘后的结果
+	if v := Available("2024-06-18"); v == nil || v.Date != "2024-06-17" {
+		t.Errorf("2024-06-18: %+v", v)
+	}
+	if v := Available("2024-06-19"); v == nil || v.Date != "2024-06-18" {
+		t.Errorf("2024-06-19: %+v", v)
+	}
+	if v := Available("2024-06-17"); v != nil {
+		t.Errorf("2024-06-17: %+v", v)
+	}
+}
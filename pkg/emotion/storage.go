@@ -0,0 +1,187 @@
+package emotion
+
+import (
+	"cmp"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/gox/api"
+	"xquant/pkg/cache"
+)
+
+const (
+	emotionFilename = "emotion.csv" // 每日情绪指数的时间序列
+	intradayPath    = "intraday"    // 盘中情绪指数路径
+	intradayPrefix  = "emotion."    // 盘中情绪指数文件前缀
+	intradayExt     = ".csv"        // 盘中情绪指数文件扩展名
+)
+
+var (
+	emotionMutex  sync.RWMutex
+	emotionLoaded bool
+	emotionSeries []Emotion // 每日情绪指数, 按日期升序
+	latest        *Emotion  // 最近一次盘中计算的情绪指数
+)
+
+// Filename 每日情绪指数的时间序列文件名
+//
+//	emotion/emotion.csv
+func Filename() string {
+	return filepath.Join(cache.GetEmotionPath(), emotionFilename)
+}
+
+// IntradayFilename 盘中情绪指数文件名, 按计算时间追加
+//
+//	emotion/intraday/emotion.yyyy-mm-dd.csv
+func IntradayFilename(date string) string {
+	return filepath.Join(cache.GetEmotionPath(), intradayPath, intradayPrefix+exchange.FixTradeDate(date)+intradayExt)
+}
+
+// loadSeries 加载每日情绪指数, 调用方需持有锁
+func loadSeries() {
+	if emotionLoaded {
+		return
+	}
+	var list []Emotion
+	_ = api.CsvToSlices(Filename(), &list)
+	slices.SortFunc(list, func(a, b Emotion) int {
+		return cmp.Compare(a.Date, b.Date)
+	})
+	emotionSeries = list
+	emotionLoaded = true
+}
+
+// Series 每日情绪指数的时间序列, 按日期升序
+func Series() []Emotion {
+	emotionMutex.Lock()
+	defer emotionMutex.Unlock()
+	loadSeries()
+	return slices.Clone(emotionSeries)
+}
+
+// LoadIntraday 加载指定日期的盘中情绪指数
+func LoadIntraday(date string) []Emotion {
+	var list []Emotion
+	_ = api.CsvToSlices(IntradayFilename(date), &list)
+	return list
+}
+
+// GetDaily 获取指定日期收盘后的情绪指数, 不存在时返回nil
+func GetDaily(date string) *Emotion {
+	date = exchange.FixTradeDate(date)
+	emotionMutex.Lock()
+	defer emotionMutex.Unlock()
+	loadSeries()
+	i, ok := slices.BinarySearchFunc(emotionSeries, date, func(e Emotion, date string) int {
+		return cmp.Compare(e.Date, date)
+	})
+	if !ok {
+		return nil
+	}
+	v := emotionSeries[i]
+	return &v
+}
+
+// Previous 获取早于date的最近一个交易日的情绪指数, 不存在时返回nil
+func Previous(date string) *Emotion {
+	date = exchange.FixTradeDate(date)
+	emotionMutex.Lock()
+	defer emotionMutex.Unlock()
+	loadSeries()
+	i, _ := slices.BinarySearchFunc(emotionSeries, date, func(e Emotion, date string) int {
+		return cmp.Compare(e.Date, date)
+	})
+	if i == 0 {
+		return nil
+	}
+	v := emotionSeries[i-1]
+	return &v
+}
+
+// Checkout 获取指定日期的情绪指数
+//
+//	当日优先取最近一次盘中计算的结果, 历史日期取收盘后的结果
+func Checkout(date string) *Emotion {
+	date = exchange.FixTradeDate(date)
+	if date == exchange.GetCurrentlyDay() {
+		emotionMutex.RLock()
+		v := latest
+		emotionMutex.RUnlock()
+		if v != nil && v.Date == date {
+			return v
+		}
+		if list := LoadIntraday(date); len(list) > 0 {
+			v = &list[len(list)-1]
+			emotionMutex.Lock()
+			latest = v
+			emotionMutex.Unlock()
+			return v
+		}
+	}
+	return GetDaily(date)
+}
+
+// Available 获取date交易时已经可知的情绪指数, 用于交易决策
+//
+//	当日取Checkout的结果; 历史日期的收盘结果在回测的开盘买入时还不可知,
+//	取前一个交易日收盘后的结果, 避免未来函数
+func Available(date string) *Emotion {
+	date = exchange.FixTradeDate(date)
+	if date == exchange.GetCurrentlyDay() {
+		if v := Checkout(date); v != nil {
+			return v
+		}
+	}
+	return Previous(date)
+}
+
+// SaveDaily 保存收盘后的情绪指数, 同一日期的记录会被替换
+func SaveDaily(e Emotion) error {
+	e.Date = exchange.FixTradeDate(e.Date)
+	e.UpdateTime = time.Now().Format(cache.TimeStampMilli)
+	emotionMutex.Lock()
+	defer emotionMutex.Unlock()
+	loadSeries()
+	i, ok := slices.BinarySearchFunc(emotionSeries, e.Date, func(v Emotion, date string) int {
+		return cmp.Compare(v.Date, date)
+	})
+	list := slices.Clone(emotionSeries)
+	if ok {
+		list[i] = e
+	} else {
+		list = slices.Insert(list, i, e)
+	}
+	if err := saveEmotions(Filename(), list); err != nil {
+		return err
+	}
+	emotionSeries = list
+	return nil
+}
+
+// SaveIntraday 追加盘中的情绪指数
+func SaveIntraday(e Emotion) error {
+	e.Date = exchange.FixTradeDate(e.Date)
+	e.UpdateTime = time.Now().Format(cache.TimeStampMilli)
+	emotionMutex.Lock()
+	defer emotionMutex.Unlock()
+	filename := IntradayFilename(e.Date)
+	var list []Emotion
+	_ = api.CsvToSlices(filename, &list)
+	list = append(list, e)
+	if err := saveEmotions(filename, list); err != nil {
+		return err
+	}
+	latest = &e
+	return nil
+}
+
+func saveEmotions(filename string, list []Emotion) error {
+	if err := os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+		return err
+	}
+	return api.SlicesToCsv(filename, list, true)
+}
@@ -44,6 +44,8 @@ type FeatureRotationAdapter interface {
 	Merge(p *treemap.Map)
 	// Factory 工厂
 	Factory(date, securityCode string) Feature
+	// Validate 校验指定日期的特征数据
+	Validate(cacheDate, featureDate string) CheckReport
 }
 
 var (
@@ -58,6 +60,8 @@ func RegisterFeatureRotationAdapter(key string, adapter FeatureRotationAdapter)
 }
 
 // SwitchDate 统一切换数据的缓存日期
+//
+//	只用于实时数据的换日, 读取历史日期的特征使用At或Acquire获取日期视图
 func SwitchDate(date string) {
 	__mutexFeatureRotationAdapters.Lock()
 	defer __mutexFeatureRotationAdapters.Unlock()
@@ -71,3 +75,23 @@ func Get(key string) FeatureRotationAdapter {
 	defer __mutexFeatureRotationAdapters.Unlock()
 	return __mapFeatureRotationAdapters[key]
 }
+
+// sliceLoader 按日期加载全部数据的特征
+type sliceLoader interface {
+	loadSlices(date string) (any, error)
+}
+
+// LoadFeatureSlices 加载指定特征指定日期的全部数据
+//
+//	返回特征结构体的切片, 不切换特征当前的缓存日期, 供回测和研究工具批量加载多个日期
+func LoadFeatureSlices(key, date string) (any, error) {
+	adapter := Get(key)
+	if adapter == nil {
+		return nil, fmt.Errorf("%w: %s", ErrFeatureNotFound, key)
+	}
+	loader, ok := adapter.(sliceLoader)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrFeatureNotFound, key)
+	}
+	return loader.loadSlices(date)
+}
@@ -2,6 +2,7 @@ package factors
 
 import (
 	"context"
+	"fmt"
 	"gitee.com/quant1x/exchange"
 	"gitee.com/quant1x/gox/api"
 	"gitee.com/quant1x/gox/concurrent"
@@ -11,8 +12,10 @@ import (
 	"gitee.com/quant1x/gox/util/treemap"
 	"gitee.com/quant1x/pkg/tablewriter"
 	"os"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 	"xquant/pkg/cache"
 	"xquant/pkg/market"
 )
@@ -85,6 +88,22 @@ func (this *Cache1D[T]) Usage() string {
 	return this.tShadow.Usage()
 }
 
+// DependOn 实现 cache.Depend 接口
+//
+//	除历史数据以外的特征都从历史数据加载, 隐含依赖历史数据
+func (this *Cache1D[T]) DependOn() []cache.Kind {
+	var list []cache.Kind
+	if this.Kind() != FeatureHistory {
+		list = append(list, FeatureHistory)
+	}
+	for _, v := range cache.DependOn(this.tShadow) {
+		if !slices.Contains(list, v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
 // Length 获取长度
 func (this *Cache1D[T]) Length() int {
 	return len(this.allCodes)
@@ -99,7 +118,7 @@ func (this *Cache1D[T]) loadCache(date string) {
 	this.filename = getCache1DFilepath(this.cacheKey, this.Date)
 	logger.Warnf("%s: date=%s, filename=%s", this.cacheKey, this.Date, this.filename)
 	var list []T
-	err := api.CsvToSlices(this.filename, &list)
+	err := cache.LoadSlices(this.filename, &list)
 	if err != nil || len(list) == 0 {
 		logger.Errorf("%s 没有有效数据, error=%+v", this.filename, err)
 		return
@@ -110,6 +129,14 @@ func (this *Cache1D[T]) loadCache(date string) {
 	}
 }
 
+// loadSlices 加载指定日期的全部数据, 返回[]T, 不影响当前的缓存日期
+func (this *Cache1D[T]) loadSlices(date string) (any, error) {
+	filename := this.filename4(date)
+	var list []T
+	err := cache.LoadSlices(filename, &list)
+	return list, err
+}
+
 // 加载默认数据, 日期为当前交易中的日期
 func (this *Cache1D[T]) loadDefault() {
 	this.loadCache(this.Date)
@@ -170,15 +197,90 @@ func (this *Cache1D[T]) Print(code string, date ...string) {
 	}
 }
 
-func (this *Cache1D[T]) Check(cacheDate, featureDate string) {
-	_ = cacheDate
-	_ = featureDate
-	//TODO implement me
-	panic("implement me")
+// Check 校验指定日期的特征数据, 有不通过的证券代码时返回错误
+func (this *Cache1D[T]) Check(cacheDate, featureDate string) error {
+	report := this.Validate(cacheDate, featureDate)
+	if report.Metric.Count == 0 {
+		return fmt.Errorf("%w: %s 没有有效数据", ErrFeatureCheck, this.filename4(cacheDate))
+	}
+	if n := len(report.Failures); n > 0 {
+		return fmt.Errorf("%w: %s, 检查数=%d, 不通过数=%d", ErrFeatureCheck, this.Name(), report.Metric.Count, n)
+	}
+	return nil
+}
+
+// Validate 按校验策略检查指定日期的全部特征数据
+//
+//	直接加载缓存文件, 不切换当前缓存的日期, 缓存中缺失的证券代码也视为不通过
+func (this *Cache1D[T]) Validate(cacheDate, featureDate string) CheckReport {
+	cacheDate = exchange.FixTradeDate(cacheDate)
+	featureDate = exchange.FixTradeDate(featureDate)
+	var sb cache.ScoreBoard
+	sb.From(this)
+	report := CheckReport{Date: featureDate}
+	var list []T
+	err := cache.LoadSlices(this.filename4(cacheDate), &list)
+	if err != nil || len(list) == 0 {
+		logger.Errorf("%s 没有有效数据, error=%+v", this.filename4(cacheDate), err)
+		report.Metric = sb.Metric()
+		return report
+	}
+	mapFeature := make(map[string]T, len(list))
+	for _, v := range list {
+		mapFeature[v.GetSecurityCode()] = v
+	}
+	for _, securityCode := range market.GetCodeList() {
+		now := time.Now()
+		v, ok := mapFeature[securityCode]
+		if !ok {
+			err = fmt.Errorf("%w: 数据不存在", ErrFeatureCheck)
+		} else {
+			err = CheckFeature(v, featureDate)
+		}
+		if err != nil {
+			report.Failures = append(report.Failures, CheckFailure{Code: securityCode, Reason: err.Error()})
+		}
+		sb.Add(1, time.Since(now), err == nil)
+	}
+	report.Metric = sb.Metric()
+	return report
+}
+
+// filename4 指定日期的缓存文件名
+func (this *Cache1D[T]) filename4(date string) string {
+	return getCache1DFilepath(this.cacheKey, exchange.FixTradeDate(date))
+}
+
+// currentDate 当前缓存的日期, 切换中的日期优先
+func (this *Cache1D[T]) currentDate() string {
+	this.m.RLock()
+	defer this.m.RUnlock()
+	if len(this.replaceDate) > 0 {
+		return this.replaceDate
+	}
+	return this.Date
+}
+
+// viewDate 判断是否需要从日期视图读取数据
+//
+//	指定的日期和当前缓存的日期不一致时从视图读取, 不切换当前缓存的日期, 避免并发的回测和实时跟踪互相干扰
+func (this *Cache1D[T]) viewDate(date ...string) (string, bool) {
+	if len(date) == 0 || len(date[0]) == 0 {
+		return "", false
+	}
+	destDate := exchange.FixTradeDate(date[0])
+	return destDate, destDate != this.currentDate()
 }
 
 // Get 获取指定证券代码的数据
 func (this *Cache1D[T]) Get(securityCode string, date ...string) *T {
+	if destDate, ok := this.viewDate(date...); ok {
+		t, ok := At(destDate).Element(this.cacheKey, securityCode).(T)
+		if ok {
+			return &t
+		}
+		return nil
+	}
 	this.Checkout(date...)
 	this.once.Do(this.loadDefault)
 	t, ok := this.mapCache.Get(securityCode)
@@ -189,6 +291,9 @@ func (this *Cache1D[T]) Get(securityCode string, date ...string) *T {
 }
 
 func (this *Cache1D[T]) Element(securityCode string, date ...string) Feature {
+	if destDate, ok := this.viewDate(date...); ok {
+		return At(destDate).Element(this.cacheKey, securityCode)
+	}
 	this.Checkout(date...)
 	this.once.Do(this.loadDefault)
 	t, ok := this.mapCache.Get(securityCode)
@@ -241,10 +346,11 @@ func (this *Cache1D[T]) Apply(merge func(code string, local *T) (updated bool),
 		list = append(list, v)
 	}
 	if len(list) > 0 {
-		err := api.SlicesToCsv(this.filename, list, force...)
+		err := cache.SaveSlices(this.filename, list, force...)
 		if err != nil {
 			logger.Errorf("刷新%s异常:%+v", this.filename, err)
 		}
+		__views.invalidate(this.cacheKey, this.Date)
 	}
 	_ = cacheDate
 }
@@ -267,10 +373,11 @@ func (this *Cache1D[T]) Merge(p *treemap.Map) {
 		list = append(list, v)
 	}
 	if len(list) > 0 {
-		err := api.SlicesToCsv(this.filename, list)
+		err := cache.SaveSlices(this.filename, list)
 		if err != nil {
 			logger.Errorf("刷新%s异常:%+v", this.filename, err)
 		}
+		__views.invalidate(this.cacheKey, this.Date)
 	}
 	_ = cacheDate
 }
@@ -1,101 +1,431 @@
 package factors
 
 import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
 	"gitee.com/quant1x/exchange"
 	"gitee.com/quant1x/gotdx/quotes"
-	"gitee.com/quant1x/pkg/yaml"
+	"gitee.com/quant1x/gox/logger"
+	"gitee.com/quant1x/pkg/tablewriter"
 	"google.golang.org/protobuf/proto"
-	"os"
 	"xquant/pkg/cache"
 	"xquant/pkg/datasource/base"
 	"xquant/pkg/factors/pb"
 )
 
+const (
+	chipRetainDays  = 5    // 缓存文件保留最近几个交易日的筹码分布, 用于增量计算和回补
+	chipDecayFactor = 1.0  // 历史换手衰减系数
+	chipMinRatio    = 1e-6 // 小于总量百万分之一的筹码档位丢弃, 控制分布的档位数
+	chipMaxLevels   = 2000 // 单日新增筹码最多分布的价格档位数
+)
+
+// DataChip 筹码分布
+//
+//	用日K线和换手率逐日计算, 每天的历史筹码按换手率衰减, 当日成交按三角分布落在最低价和最高价之间,
+//	有成交数据的交易日按实际成交价格分布. K线和成交数据都是实际价格(不复权), 除权除息日把历史筹码平移到除权除息参考价,
+//	已缓存的分布不受之后的除权除息影响, 不需要重新计算
 type DataChip struct {
-	cache.DataSummary
-	Date string
-	Code string
+	Manifest
 }
 
-//func init() {
-//	summary := __mapDataSets[BaseChipDistribution]
-//	_ = cache.Register(&DataChip{DataSummary: summary})
-//}
-
-// Print 控制台输出指定日期的数据
-func (d *DataChip) Print(code string, date ...string) {
-	//TODO implement me
-	panic("implement me")
+func init() {
+	summary := __mapDataSets[BaseChipDistribution]
+	_ = cache.Register(&DataChip{Manifest: Manifest{DataSummary: summary}})
 }
 
-func (d *DataChip) Clone(date, code string) BaseData {
-	//TODO implement me
-	panic("implement me")
+func (this *DataChip) Clone(date string, code string) DataSet {
+	summary := __mapDataSets[BaseChipDistribution]
+	var dest = DataChip{
+		Manifest: Manifest{
+			DataSummary: summary,
+			Date:        date,
+			Code:        code,
+		},
+	}
+	return &dest
 }
 
-func (d *DataChip) Update(date, code string) error {
-	//TODO implement me
-	panic("implement me")
+func (this *DataChip) Init(ctx context.Context, date string) error {
+	_ = ctx
+	_ = date
+	return nil
 }
 
-func (d *DataChip) Repair(date, code string) error {
-	//TODO implement me
-	panic("implement me")
+func (this *DataChip) Update(date string) {
+	_ = pullChipsByDate(this.GetSecurityCode(), date)
 }
 
-func (d *DataChip) Increase(snapshot quotes.Snapshot) error {
-	//TODO implement me
-	panic("implement me")
+func (this *DataChip) Repair(date string) {
+	this.Update(date)
 }
 
-type tt struct {
-	CD map[float64]float64 `dataframe:"CD"`
+func (this *DataChip) Increase(snapshot quotes.Snapshot) {
+	_ = snapshot
 }
 
-// 更新筹码分布
-func v1updateChipDistribution(date, code string) error {
+// Print 控制台输出指定日期的筹码统计
+func (this *DataChip) Print(code string, date ...string) {
 	securityCode := exchange.CorrectSecurityCode(code)
-	cacheDate := exchange.FixTradeDate(date)
-	trans := base.CheckoutTransactionData(securityCode, cacheDate, true)
-	tmp := map[float64]float64{}
+	tradeDate := cache.DefaultCanReadDate()
+	if len(date) > 0 {
+		tradeDate = exchange.FixTradeDate(date[0])
+	}
+	chips := CheckoutChips(securityCode, tradeDate)
+	if chips == nil {
+		fmt.Printf("%s %s 没有筹码分布数据\n", securityCode, tradeDate)
+		return
+	}
+	price := 0.00
+	klines := checkoutKLines(BaseChipDistribution, securityCode, tradeDate)
+	if n := len(klines); n > 0 {
+		price = klines[n-1].Close
+	}
+	stats := ComputeChipStats(chips.GetDist(), price)
+	headers, records := checkoutTable(stats)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(headers)
+	table.SetColumnAlignment([]int{tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_LEFT})
+	table.AppendBulk(records)
+	table.Render()
+}
+
+// chipStep 价格档位的间隔, 单位是分, 高价股放大间隔
+func chipStep(price float64) float64 {
+	switch {
+	case price >= 1000:
+		return 100
+	case price >= 100:
+		return 10
+	default:
+		return 1
+	}
+}
+
+// chipPrice 价格转换成以分为单位的筹码档位
+func chipPrice(price float64) int32 {
+	step := chipStep(price)
+	return int32(math.Round(price*100/step) * step)
+}
+
+// chipTotal 筹码总量
+func chipTotal(dist map[int32]float64) float64 {
+	total := 0.00
+	for _, v := range dist {
+		total += v
+	}
+	return total
+}
+
+// decayChips 历史筹码按换手率衰减
+//
+//	capital为流通股本, 无效时用筹码总量估算换手率
+func decayChips(dist map[int32]float64, volume, capital float64) {
+	if volume <= 0 {
+		return
+	}
+	if capital <= 0 {
+		capital = chipTotal(dist)
+	}
+	turnover := 1.00
+	if capital > 0 {
+		turnover = min(1, volume*chipDecayFactor/capital)
+	}
+	for k, v := range dist {
+		dist[k] = v * (1 - turnover)
+	}
+}
+
+// addDailyChips 当日成交量按三角分布落在最低价和最高价之间, 峰值在均价
+func addDailyChips(dist map[int32]float64, kline base.KLine) {
+	volume := kline.Volume
+	if volume <= 0 {
+		return
+	}
+	low, high := kline.Low, kline.High
+	if low <= 0 || high <= low {
+		price := kline.Close
+		if price <= 0 {
+			price = high
+		}
+		dist[chipPrice(price)] += volume
+		return
+	}
+	avg := kline.Amount / volume
+	if avg < low || avg > high || math.IsNaN(avg) {
+		avg = (high + low + kline.Close) / 3
+	}
+	step := chipStep(avg) / 100
+	if levels := (high - low) / step; levels > chipMaxLevels {
+		step = (high - low) / chipMaxLevels
+	}
+	prices := []float64{}
+	weights := []float64{}
+	sum := 0.00
+	for p := low; p <= high+step/2; p += step {
+		w := 0.00
+		if p <= avg {
+			w = 1 - (avg-p)/(avg-low+step)
+		} else {
+			w = 1 - (p-avg)/(high-avg+step)
+		}
+		if w <= 0 {
+			continue
+		}
+		prices = append(prices, p)
+		weights = append(weights, w)
+		sum += w
+	}
+	if sum <= 0 {
+		dist[chipPrice(avg)] += volume
+		return
+	}
+	for i, p := range prices {
+		dist[chipPrice(p)] += volume * weights[i] / sum
+	}
+}
+
+// addTransactionChips 当日成交按实际成交价格分布, 总量对齐日K线的成交量
+func addTransactionChips(dist map[int32]float64, trans []quotes.TickTransaction, volume float64) bool {
+	tmp := map[int32]float64{}
+	sum := 0.00
 	for _, v := range trans {
-		price := v.Price
-		vol := v.Vol
-		tmp[price] = tmp[price] + float64(vol)
-	}
-	filename := "t1.yaml"
-	//var list []tt
-	//t1 := tt{CD: tmp}
-	//list = append(list, t1)
-	//err := api.SlicesToCsv(fn, list)
-	//if err != nil {
-	//	fmt.Println(err)
-	//}
-	data, err := yaml.Marshal(tmp)
+		if v.Price <= 0 || v.Vol <= 0 {
+			continue
+		}
+		tmp[chipPrice(v.Price)] += float64(v.Vol)
+		sum += float64(v.Vol)
+	}
+	if sum <= 0 {
+		return false
+	}
+	if volume <= 0 {
+		volume = sum
+	}
+	for k, v := range tmp {
+		dist[k] += volume * v / sum
+	}
+	return true
+}
+
+// rebaseChips 除权除息日把历史筹码平移到除权除息参考价, 送转和配股按比例增加筹码数量
+func rebaseChips(dist map[int32]float64, event DividendEvent) {
+	ratio := 1 + event.BonusShares + event.RightsShares
+	tmp := make(map[int32]float64, len(dist))
+	for k, v := range dist {
+		price := event.AdjustPrice(float64(k) / 100)
+		if price <= 0 {
+			continue
+		}
+		tmp[chipPrice(price)] += v * ratio
+	}
+	clear(dist)
+	for k, v := range tmp {
+		dist[k] = v
+	}
+}
+
+// pruneChips 丢弃过小的筹码档位
+func pruneChips(dist map[int32]float64) {
+	threshold := chipTotal(dist) * chipMinRatio
+	for k, v := range dist {
+		if v <= threshold {
+			delete(dist, k)
+		}
+	}
+}
+
+// loadChipDistribution 加载筹码分布缓存
+func loadChipDistribution(securityCode string) *pb.ChipDistribution {
+	cd := pb.ChipDistribution{}
+	filename := cache.ChipsFilename(securityCode)
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return &cd
+	}
+	if err = proto.Unmarshal(data, &cd); err != nil {
+		logger.Errorf("%s 筹码分布解析失败: %+v", filename, err)
+		return &pb.ChipDistribution{}
+	}
+	return &cd
+}
+
+// saveChipDistribution 保存筹码分布缓存, 只保留最近chipRetainDays个交易日
+func saveChipDistribution(securityCode string, cd *pb.ChipDistribution) error {
+	dates := make([]string, 0, len(cd.Data))
+	for date := range cd.Data {
+		dates = append(dates, date)
+	}
+	slices.Sort(dates)
+	if n := len(dates); n > chipRetainDays {
+		for _, date := range dates[:n-chipRetainDays] {
+			delete(cd.Data, date)
+		}
+	}
+	data, err := proto.Marshal(cd)
 	if err != nil {
 		return err
 	}
-	err = os.WriteFile(filename, data, 0644)
-	return err
+	filename := cache.ChipsFilename(securityCode)
+	if err = os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0644)
 }
 
-func updateChipDistribution(date, code string) error {
-	securityCode := exchange.CorrectSecurityCode(code)
-	cacheDate := exchange.FixTradeDate(date)
-	trans := base.CheckoutTransactionData(securityCode, cacheDate, true)
-	chips := pb.Chips{
-		Date: cacheDate,
-		Dist: map[int32]float64{},
+// latestChips 缓存中不晚于date的最近一个交易日的筹码分布
+func latestChips(cd *pb.ChipDistribution, date string) *pb.Chips {
+	var latest *pb.Chips
+	for d, v := range cd.GetData() {
+		if d <= date && (latest == nil || d > latest.GetDate()) {
+			latest = v
+		}
 	}
-	for _, v := range trans {
-		price := int32(100 * v.Price)
-		vol := v.Vol
-		chips.Dist[price] = chips.Dist[price] + float64(vol)
-	}
-	cd := pb.ChipDistribution{Data: make(map[string]*pb.Chips)}
-	cd.Data[cacheDate] = &chips
-	filename := "t1.bin"
-	data, err := proto.Marshal(&cd)
-	err = os.WriteFile(filename, data, 0644)
-	return err
+	return latest
+}
+
+// pullChipsByDate 计算指定日期的筹码分布并缓存
+//
+//	从缓存中最近一个交易日的分布开始增量计算, 没有缓存时从上市第一天开始计算
+func pullChipsByDate(securityCode, date string) *pb.Chips {
+	securityCode = exchange.CorrectSecurityCode(securityCode)
+	tradeDate := exchange.FixTradeDate(date)
+	cd := loadChipDistribution(securityCode)
+	if cd.Data == nil {
+		cd.Data = map[string]*pb.Chips{}
+	}
+	if v, ok := cd.Data[tradeDate]; ok {
+		return v
+	}
+	klines := checkoutKLines(BaseChipDistribution, securityCode, tradeDate)
+	if len(klines) == 0 {
+		return nil
+	}
+	dist := map[int32]float64{}
+	beginDate := ""
+	if latest := latestChips(cd, tradeDate); latest != nil {
+		for k, v := range latest.GetDist() {
+			dist[k] = v
+		}
+		beginDate = latest.GetDate()
+	}
+	// 流通股本变化不频繁, 统一使用计算日期的流通股本
+	capital := 0.00
+	if f10 := GetL5F10(securityCode, tradeDate); f10 != nil {
+		capital = f10.Capital
+	}
+	transBeginDate := exchange.FixTradeDate(base.GetBeginDateOfHistoricalTradingData())
+	events := DividendEvents(securityCode)
+	start := sort.Search(len(klines), func(i int) bool {
+		return klines[i].Date > beginDate
+	})
+	for _, kline := range klines[start:] {
+		if kline.Date > tradeDate {
+			break
+		}
+		if event, ok := DividendOnDate(events, kline.Date); ok {
+			rebaseChips(dist, event)
+		}
+		decayChips(dist, kline.Volume, capital)
+		refined := false
+		if kline.Date >= transBeginDate {
+			trans := base.CheckoutTransactionData(securityCode, kline.Date, true)
+			refined = addTransactionChips(dist, trans, kline.Volume)
+		}
+		if !refined {
+			addDailyChips(dist, kline)
+		}
+		pruneChips(dist)
+	}
+	lastDate := klines[len(klines)-1].Date
+	if lastDate > tradeDate {
+		lastDate = tradeDate
+	}
+	chips := &pb.Chips{Date: lastDate, Dist: dist}
+	cd.Data[lastDate] = chips
+	if err := saveChipDistribution(securityCode, cd); err != nil {
+		logger.Errorf("%s 筹码分布保存失败: %+v", securityCode, err)
+	}
+	return chips
+}
+
+// CheckoutChips 获取指定日期的筹码分布, 缓存中没有时计算
+func CheckoutChips(securityCode, date string) *pb.Chips {
+	securityCode = exchange.CorrectSecurityCode(securityCode)
+	tradeDate := exchange.FixTradeDate(date)
+	cd := loadChipDistribution(securityCode)
+	if v, ok := cd.GetData()[tradeDate]; ok {
+		return v
+	}
+	return pullChipsByDate(securityCode, tradeDate)
+}
+
+// ChipStats 筹码分布的统计
+type ChipStats struct {
+	Price           float64 `name:"价格" dataframe:"price"`               // 计算获利比例的价格
+	ProfitRatio     float64 `name:"获利比例" dataframe:"profit_ratio"`      // 成本不高于价格的筹码占比
+	AvgCost         float64 `name:"平均成本" dataframe:"avg_cost"`          // 加权平均成本
+	Cost5           float64 `name:"5%成本" dataframe:"cost5"`             // 5%分位成本, 90%筹码的下沿
+	Cost15          float64 `name:"15%成本" dataframe:"cost15"`           // 15%分位成本, 70%筹码的下沿
+	Cost50          float64 `name:"50%成本" dataframe:"cost50"`           // 50%分位成本
+	Cost85          float64 `name:"85%成本" dataframe:"cost85"`           // 85%分位成本, 70%筹码的上沿
+	Cost95          float64 `name:"95%成本" dataframe:"cost95"`           // 95%分位成本, 90%筹码的上沿
+	Concentration90 float64 `name:"90%集中度" dataframe:"concentration90"` // 90%筹码集中度
+	Concentration70 float64 `name:"70%集中度" dataframe:"concentration70"` // 70%筹码集中度
+	PeakPrice       float64 `name:"筹码峰" dataframe:"peak_price"`         // 筹码最密集的价格
+}
+
+// ComputeChipStats 计算筹码分布的统计, dist的key为以分为单位的价格
+func ComputeChipStats(dist map[int32]float64, price float64) ChipStats {
+	stats := ChipStats{Price: price}
+	prices := make([]int32, 0, len(dist))
+	total := 0.00
+	for k, v := range dist {
+		if v <= 0 {
+			continue
+		}
+		prices = append(prices, k)
+		total += v
+	}
+	if total <= 0 {
+		return stats
+	}
+	slices.Sort(prices)
+	percentiles := []float64{0.05, 0.15, 0.50, 0.85, 0.95}
+	costs := make([]float64, len(percentiles))
+	cumulative, amount, profit, peak := 0.00, 0.00, 0.00, 0.00
+	index := 0
+	for _, k := range prices {
+		v := dist[k]
+		p := float64(k) / 100
+		amount += p * v
+		if p <= price {
+			profit += v
+		}
+		if v > peak {
+			peak = v
+			stats.PeakPrice = p
+		}
+		cumulative += v
+		for index < len(percentiles) && cumulative >= percentiles[index]*total {
+			costs[index] = p
+			index++
+		}
+	}
+	stats.ProfitRatio = profit / total
+	stats.AvgCost = amount / total
+	stats.Cost5, stats.Cost15, stats.Cost50, stats.Cost85, stats.Cost95 = costs[0], costs[1], costs[2], costs[3], costs[4]
+	if s := stats.Cost95 + stats.Cost5; s > 0 {
+		stats.Concentration90 = (stats.Cost95 - stats.Cost5) / s
+	}
+	if s := stats.Cost85 + stats.Cost15; s > 0 {
+		stats.Concentration70 = (stats.Cost85 - stats.Cost15) / s
+	}
+	return stats
 }
@@ -2,53 +2,56 @@ package factors
 
 import (
 	"fmt"
-	"gitee.com/quant1x/pkg/yaml"
-	"google.golang.org/protobuf/proto"
-	"os"
+	"math"
 	"testing"
-	"xquant/pkg/factors/pb"
+
+	"xquant/pkg/datasource/base"
 )
 
-func Test_updateChipDistribution(t *testing.T) {
+func TestPullChipsByDate(t *testing.T) {
 	code := "000701"
 	date := "2025-03-11"
-	err := updateChipDistribution(date, code)
-	if err != nil {
-		fmt.Println(err)
-	}
-	filename := "t1.bin"
-	dataBytes, err := os.ReadFile(filename)
-	if err != nil {
-		fmt.Println(err)
+	chips := pullChipsByDate(code, date)
+	if chips == nil {
 		return
 	}
-	cd := pb.ChipDistribution{}
-	err = proto.Unmarshal(dataBytes, &cd)
-	if err != nil {
-		fmt.Println(err)
-		return
+	stats := ComputeChipStats(chips.GetDist(), 0)
+	fmt.Printf("%s: %d, %+v\n", chips.GetDate(), len(chips.GetDist()), stats)
+}
+
+func TestComputeChipStats(t *testing.T) {
+	dist := map[int32]float64{}
+	addDailyChips(dist, base.KLine{Open: 10, Close: 10.5, High: 11, Low: 9, Volume: 1000, Amount: 10000})
+	total := chipTotal(dist)
+	if math.Abs(total-1000) > 1e-6 {
+		t.Errorf("total = %f", total)
+	}
+	stats := ComputeChipStats(dist, 10)
+	fmt.Printf("%+v\n", stats)
+	if stats.ProfitRatio < 0.4 || stats.ProfitRatio > 0.6 || math.Abs(stats.AvgCost-10) > 0.05 {
+		t.Errorf("stats = %+v", stats)
+	}
+	if !(stats.Cost5 < stats.Cost15 && stats.Cost15 <= stats.Cost50 && stats.Cost50 <= stats.Cost85 && stats.Cost85 < stats.Cost95) {
+		t.Errorf("cost bands = %+v", stats)
+	}
+	// 全部换手后, 历史筹码清零
+	decayChips(dist, 1000, 1000)
+	if chipTotal(dist) != 0 {
+		t.Errorf("decay total = %f", chipTotal(dist))
 	}
-	fmt.Println(cd.String())
 }
 
-func Test_v1updateChipDistribution(t *testing.T) {
-	code := "000701"
-	date := "2025-03-11"
-	err := updateChipDistribution(date, code)
-	if err != nil {
-		fmt.Println(err)
-	}
-	filename := "t1.yaml"
-	dataBytes, err := os.ReadFile(filename)
-	if err != nil {
-		fmt.Println(err)
-		return
+func Test_rebaseChips(t *testing.T) {
+	// 10派10元送5股, 12元的筹码平移到(12-1)/1.5=7.33元
+	dist := map[int32]float64{1200: 1000, 1100: 500}
+	rebaseChips(dist, DividendEvent{ExDate: "2024-06-19", CashDividend: 1, BonusShares: 0.5})
+	if v := dist[chipPrice(11.0/1.5)]; math.Abs(v-1500) > 1e-6 {
+		t.Errorf("dist = %v", dist)
 	}
-	tmp := map[float64]float64{}
-	err = yaml.Unmarshal(dataBytes, tmp)
-	if err != nil {
-		fmt.Println(err)
-		return
+	if v := dist[chipPrice(10.0/1.5)]; math.Abs(v-750) > 1e-6 {
+		t.Errorf("dist = %v", dist)
+	}
+	if total := chipTotal(dist); math.Abs(total-2250) > 1e-6 {
+		t.Errorf("total = %f", total)
 	}
-	fmt.Println(tmp)
 }
@@ -0,0 +1,263 @@
+package factors
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/gotdx/quotes"
+	"gitee.com/quant1x/gox/api"
+	"gitee.com/quant1x/gox/logger"
+	"xquant/pkg/cache"
+	"xquant/pkg/config"
+	"xquant/pkg/datasource/east_money"
+)
+
+// SeatType 龙虎榜席位类型
+type SeatType = int
+
+const (
+	SeatOrdinary     SeatType = iota // 普通营业部
+	SeatInstitution                  // 机构专用
+	SeatStockConnect                 // 沪股通或深股通专用
+	SeatHotMoney                     // 游资
+)
+
+const (
+	seatNameInstitution = "机构专用"
+	seatNameSHConnect   = "沪股通专用"
+	seatNameSZConnect   = "深股通专用"
+	lhbCacheDays        = 5                // 内存中缓存的龙虎榜交易日数
+	lhbRetryInterval    = 10 * time.Minute // 当日没有数据或拉取失败时, 重新拉取的间隔
+)
+
+var (
+	__mutexBillBoard sync.Mutex
+	__mapBillBoard   = map[string]billBoardEntry{}
+	__billBoardLocks sync.Map // 按日期拉取龙虎榜的锁, 同一日期只拉取一次
+)
+
+// 内存中缓存的龙虎榜
+type billBoardEntry struct {
+	seats []BillBoardSeat
+	err   error     // 拉取失败的错误
+	time  time.Time // 加载时间
+}
+
+// 当日没有数据或拉取失败的结果在重试间隔之后过期
+func (v billBoardEntry) expired(date string) bool {
+	if v.err == nil && (len(v.seats) > 0 || date < exchange.GetCurrentlyDay()) {
+		return false
+	}
+	return time.Since(v.time) >= lhbRetryInterval
+}
+
+// SeatTypeName 席位类型名称
+func SeatTypeName(seatType SeatType) string {
+	switch seatType {
+	case SeatInstitution:
+		return "机构"
+	case SeatStockConnect:
+		return "沪深股通"
+	case SeatHotMoney:
+		return "游资"
+	default:
+		return "营业部"
+	}
+}
+
+// ClassifySeat 按营业部名称对席位分类, hotMoneySeats为游资席位的名称关键字
+func ClassifySeat(name string, hotMoneySeats []string) SeatType {
+	name = strings.TrimSpace(name)
+	switch {
+	case name == seatNameInstitution:
+		return SeatInstitution
+	case name == seatNameSHConnect || name == seatNameSZConnect:
+		return SeatStockConnect
+	}
+	for _, keyword := range hotMoneySeats {
+		if len(keyword) > 0 && strings.Contains(name, keyword) {
+			return SeatHotMoney
+		}
+	}
+	return SeatOrdinary
+}
+
+// BillBoardSeat 龙虎榜席位明细
+type BillBoardSeat struct {
+	Date              string  `name:"日期" dataframe:"date"`                        // 交易日期
+	Code              string  `name:"证券代码" dataframe:"code"`                      // 证券代码
+	SeatCode          string  `name:"营业部代码" dataframe:"seat_code"`                // 营业部代码
+	SeatName          string  `name:"营业部名称" dataframe:"seat_name"`                // 营业部名称
+	SeatType          int     `name:"席位类型" dataframe:"seat_type"`                 // 席位类型
+	Buy               float64 `name:"买入金额" dataframe:"buy"`                       // 买入金额
+	Sell              float64 `name:"卖出金额" dataframe:"sell"`                      // 卖出金额
+	Net               float64 `name:"净额" dataframe:"net"`                         // 买入金额-卖出金额
+	RiseProbability3D float64 `name:"上榜后3日上涨概率%" dataframe:"rise_probability_3d"` // 席位上榜后3日的上涨概率
+	BuyerTimes3D      int     `name:"3日买入次数" dataframe:"buyer_times_3d"`          // 席位近期上榜买入的次数
+	ChangeRate        float64 `name:"涨跌幅%" dataframe:"change_rate"`               // 个股当日涨跌幅
+	Close             float64 `name:"收盘价" dataframe:"close"`                      // 个股当日收盘价
+	Amount            float64 `name:"成交金额" dataframe:"amount"`                    // 个股当日成交金额
+	Explanation       string  `name:"上榜原因" dataframe:"explanation"`               // 上榜原因
+}
+
+// DataBillBoard 龙虎榜
+//
+//	每个交易日的全部席位明细保存在一个文件中, 在Init阶段一次性拉取
+type DataBillBoard struct {
+	Manifest
+}
+
+func init() {
+	summary := __mapDataSets[BaseBillBoard]
+	_ = cache.Register(&DataBillBoard{Manifest: Manifest{DataSummary: summary}})
+}
+
+func (this *DataBillBoard) Clone(date string, code string) DataSet {
+	summary := __mapDataSets[BaseBillBoard]
+	var dest = DataBillBoard{
+		Manifest: Manifest{
+			DataSummary: summary,
+			Date:        date,
+			Code:        code,
+		},
+	}
+	return &dest
+}
+
+func (this *DataBillBoard) Init(ctx context.Context, date string) error {
+	_ = ctx
+	_, err := pullBillBoard(date)
+	return err
+}
+
+func (this *DataBillBoard) Update(date string) {
+	_ = date
+}
+
+func (this *DataBillBoard) Repair(date string) {
+	_ = date
+}
+
+func (this *DataBillBoard) Increase(snapshot quotes.Snapshot) {
+	_ = snapshot
+}
+
+func (this *DataBillBoard) Print(code string, date ...string) {
+	_ = code
+	_ = date
+}
+
+// pullBillBoard 拉取指定日期的龙虎榜并缓存
+//
+//	历史日期没有数据时生成空文件, 不再拉取; 当日的龙虎榜在收盘后才会陆续公布, 没有数据时不生成文件
+func pullBillBoard(date string) ([]BillBoardSeat, error) {
+	date = exchange.FixTradeDate(date)
+	list, err := east_money.GetBillBoardList(date)
+	if err != nil {
+		logger.Errorf("龙虎榜[%s]拉取失败: %+v", date, err)
+		return nil, err
+	}
+	filename := cache.LhbFilename(date)
+	if len(list) == 0 {
+		if date >= exchange.GetCurrentlyDay() {
+			return nil, nil
+		}
+		if err = os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+			return nil, err
+		}
+		return nil, os.WriteFile(filename, nil, 0644)
+	}
+	hotMoneySeats := config.GetDataConfig().BillBoard.HotMoneySeats
+	seats := make([]BillBoardSeat, 0, len(list))
+	for _, v := range list {
+		seats = append(seats, BillBoardSeat{
+			Date:              date,
+			Code:              exchange.CorrectSecurityCode(v.SECUCODE),
+			SeatCode:          v.OPERATEDEPT_CODE,
+			SeatName:          v.OPERATEDEPT_NAME,
+			SeatType:          ClassifySeat(v.OPERATEDEPT_NAME, hotMoneySeats),
+			Buy:               v.BUY,
+			Sell:              v.SELL,
+			Net:               v.BUY - v.SELL,
+			RiseProbability3D: v.RISE_PROBABILITY_3DAY,
+			BuyerTimes3D:      v.TOTAL_BUYER_SALESTIMES_3DAY,
+			ChangeRate:        v.CHANGE_RATE,
+			Close:             v.CLOSE_PRICE,
+			Amount:            float64(v.ACCUM_AMOUNT),
+			Explanation:       v.EXPLANATION,
+		})
+	}
+	if err = os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+		return nil, err
+	}
+	if err = api.SlicesToCsv(filename, seats); err != nil {
+		return nil, err
+	}
+	__mutexBillBoard.Lock()
+	delete(__mapBillBoard, date)
+	__mutexBillBoard.Unlock()
+	return seats, nil
+}
+
+// 从内存中捡出未过期的龙虎榜
+func lookupBillBoard(date string) (billBoardEntry, bool) {
+	__mutexBillBoard.Lock()
+	defer __mutexBillBoard.Unlock()
+	v, ok := __mapBillBoard[date]
+	if !ok || v.expired(date) {
+		return v, false
+	}
+	return v, true
+}
+
+// CheckoutBillBoard 获取指定日期的龙虎榜席位明细, 本地没有缓存时从网络拉取
+//
+//	同一日期只拉取一次, 全部个股共用内存中的结果; 没有数据和拉取失败的结果也会缓存, 当日的在重试间隔之后重新拉取
+func CheckoutBillBoard(date string) ([]BillBoardSeat, error) {
+	date = exchange.FixTradeDate(date)
+	if v, ok := lookupBillBoard(date); ok {
+		return v.seats, v.err
+	}
+	mutex, _ := __billBoardLocks.LoadOrStore(date, &sync.Mutex{})
+	mutex.(*sync.Mutex).Lock()
+	defer mutex.(*sync.Mutex).Unlock()
+	// 等锁期间可能已经被其它协程拉取
+	if v, ok := lookupBillBoard(date); ok {
+		return v.seats, v.err
+	}
+	entry := billBoardEntry{time: time.Now()}
+	filename := cache.LhbFilename(date)
+	if api.FileExist(filename) {
+		_ = api.CsvToSlices(filename, &entry.seats)
+	} else {
+		entry.seats, entry.err = pullBillBoard(date)
+	}
+	__mutexBillBoard.Lock()
+	defer __mutexBillBoard.Unlock()
+	if len(__mapBillBoard) >= lhbCacheDays {
+		clear(__mapBillBoard)
+	}
+	__mapBillBoard[date] = entry
+	return entry.seats, entry.err
+}
+
+// GetBillBoardSeats 获取个股在指定日期的龙虎榜席位明细, 未上榜时返回nil, 龙虎榜拉取失败时返回错误
+func GetBillBoardSeats(securityCode, date string) ([]BillBoardSeat, error) {
+	securityCode = exchange.CorrectSecurityCode(securityCode)
+	list, err := CheckoutBillBoard(date)
+	if err != nil {
+		return nil, err
+	}
+	var seats []BillBoardSeat
+	for _, v := range list {
+		if v.Code == securityCode {
+			seats = append(seats, v)
+		}
+	}
+	return seats, nil
+}
@@ -0,0 +1,310 @@
+package factors
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/gotdx/quotes"
+	"gitee.com/quant1x/gox/api"
+	"gitee.com/quant1x/gox/logger"
+	"gitee.com/quant1x/num"
+	"xquant/pkg/cache"
+	"xquant/pkg/config"
+	"xquant/pkg/datasource/base"
+)
+
+const (
+	orderFlowTailStartTime = "14:30" // 尾盘30分钟的开始时间
+	orderFlowDefaultUnit   = 100     // 默认每手100股
+)
+
+// 订单大小的分档
+const (
+	orderSizeSmall      = iota // 小单
+	orderSizeMedium            // 中单
+	orderSizeLarge             // 大单
+	orderSizeSuperLarge        // 超大单
+	orderSizeCount
+)
+
+var (
+	// 成交分析缓存文件按证券代码加锁, 数据集和特征会同时读写同一个文件
+	__orderFlowLocks sync.Map
+)
+
+// OrderFlowSummary 成交分析, 按历史成交数据逐笔统计
+//
+//	金额单位为元, 方向无法判断的成交按一半买入一半卖出计算
+type OrderFlowSummary struct {
+	Date           string  `name:"日期" dataframe:"date"`                  // 交易日期
+	Code           string  `name:"证券代码" dataframe:"code"`                // 证券代码
+	Count          int     `name:"成交笔数" dataframe:"count"`               // 成交笔数
+	Amount         float64 `name:"成交金额" dataframe:"amount"`              // 成交金额
+	BuyAmount      float64 `name:"主动买入" dataframe:"buy_amount"`          // 主动买入金额
+	SellAmount     float64 `name:"主动卖出" dataframe:"sell_amount"`         // 主动卖出金额
+	Imbalance      float64 `name:"订单流失衡%" dataframe:"imbalance"`         // (主动买入-主动卖出)/(主动买入+主动卖出)
+	SmallBuy       float64 `name:"小单买入" dataframe:"small_buy"`           // 小单主动买入金额
+	SmallSell      float64 `name:"小单卖出" dataframe:"small_sell"`          // 小单主动卖出金额
+	MediumBuy      float64 `name:"中单买入" dataframe:"medium_buy"`          // 中单主动买入金额
+	MediumSell     float64 `name:"中单卖出" dataframe:"medium_sell"`         // 中单主动卖出金额
+	LargeBuy       float64 `name:"大单买入" dataframe:"large_buy"`           // 大单主动买入金额
+	LargeSell      float64 `name:"大单卖出" dataframe:"large_sell"`          // 大单主动卖出金额
+	SuperLargeBuy  float64 `name:"超大单买入" dataframe:"super_large_buy"`    // 超大单主动买入金额
+	SuperLargeSell float64 `name:"超大单卖出" dataframe:"super_large_sell"`   // 超大单主动卖出金额
+	LargeNet       float64 `name:"大单净买入" dataframe:"large_net"`          // 大单和超大单的净买入金额
+	LargeNetRatio  float64 `name:"大单净买入占比%" dataframe:"large_net_ratio"` // 大单净买入/成交金额
+	BuyVWAP        float64 `name:"主买均价" dataframe:"buy_vwap"`            // 主动买入的成交量加权均价
+	SellVWAP       float64 `name:"主卖均价" dataframe:"sell_vwap"`           // 主动卖出的成交量加权均价
+	VWAPSpread     float64 `name:"买卖均价差%" dataframe:"vwap_spread"`       // 主买均价/主卖均价-1
+	TailBuy        float64 `name:"尾盘主动买入" dataframe:"tail_buy"`          // 尾盘30分钟主动买入金额
+	TailSell       float64 `name:"尾盘主动卖出" dataframe:"tail_sell"`         // 尾盘30分钟主动卖出金额
+	TailNet        float64 `name:"尾盘净买入" dataframe:"tail_net"`           // 尾盘30分钟净买入金额
+	TailImbalance  float64 `name:"尾盘订单流失衡%" dataframe:"tail_imbalance"`  // 尾盘30分钟的订单流失衡
+	TailRatio      float64 `name:"尾盘成交占比%" dataframe:"tail_ratio"`       // 尾盘30分钟成交金额/全天成交金额
+	UpdateTime     string  `name:"更新时间" dataframe:"update_time"`         // 更新时间
+}
+
+// orderSize 按单笔成交金额划分订单大小
+func orderSize(amount float64, parameter config.OrderFlowParameter) int {
+	switch {
+	case amount >= parameter.SuperLargeAmount:
+		return orderSizeSuperLarge
+	case amount >= parameter.LargeAmount:
+		return orderSizeLarge
+	case amount >= parameter.MediumAmount:
+		return orderSizeMedium
+	default:
+		return orderSizeSmall
+	}
+}
+
+// imbalanceRate 订单流失衡百分比
+func imbalanceRate(buy, sell float64) float64 {
+	if buy+sell <= 0 {
+		return 0
+	}
+	return num.Decimal((buy - sell) / (buy + sell) * 100)
+}
+
+// ComputeOrderFlow 逐笔统计成交数据
+//
+//	volUnit为每手股数, 成交数据的成交量单位是手
+func ComputeOrderFlow(list []quotes.TickTransaction, volUnit int, parameter config.OrderFlowParameter) OrderFlowSummary {
+	var (
+		summary     OrderFlowSummary
+		buys, sells [orderSizeCount]float64
+		buyVolume   float64
+		sellVolume  float64
+		tailAmount  float64
+		lastPrice   float64
+	)
+	if volUnit <= 0 {
+		volUnit = orderFlowDefaultUnit
+	}
+	for _, v := range list {
+		price := v.Price
+		if price <= 0 || v.Vol <= 0 {
+			continue
+		}
+		if lastPrice == 0 {
+			lastPrice = price
+		}
+		direction := int32(v.BuyOrSell)
+		if direction != quotes.TICK_BUY && direction != quotes.TICK_SELL {
+			// 方向不明的成交按价格变化判断
+			switch {
+			case price > lastPrice:
+				direction = quotes.TICK_BUY
+			case price < lastPrice:
+				direction = quotes.TICK_SELL
+			}
+		}
+		lastPrice = price
+		volume := float64(v.Vol) * float64(volUnit)
+		amount := volume * price
+		buyRatio := 0.50
+		if direction == quotes.TICK_BUY {
+			buyRatio = 1.00
+		} else if direction == quotes.TICK_SELL {
+			buyRatio = 0.00
+		}
+		size := orderSize(amount, parameter)
+		buy, sell := amount*buyRatio, amount*(1-buyRatio)
+		buys[size] += buy
+		sells[size] += sell
+		summary.BuyAmount += buy
+		summary.SellAmount += sell
+		buyVolume += volume * buyRatio
+		sellVolume += volume * (1 - buyRatio)
+		summary.Count++
+		summary.Amount += amount
+		if v.Time >= orderFlowTailStartTime {
+			summary.TailBuy += buy
+			summary.TailSell += sell
+			tailAmount += amount
+		}
+	}
+	summary.Imbalance = imbalanceRate(summary.BuyAmount, summary.SellAmount)
+	summary.SmallBuy, summary.SmallSell = buys[orderSizeSmall], sells[orderSizeSmall]
+	summary.MediumBuy, summary.MediumSell = buys[orderSizeMedium], sells[orderSizeMedium]
+	summary.LargeBuy, summary.LargeSell = buys[orderSizeLarge], sells[orderSizeLarge]
+	summary.SuperLargeBuy, summary.SuperLargeSell = buys[orderSizeSuperLarge], sells[orderSizeSuperLarge]
+	summary.LargeNet = summary.LargeBuy + summary.SuperLargeBuy - summary.LargeSell - summary.SuperLargeSell
+	if summary.Amount > 0 {
+		summary.LargeNetRatio = num.Decimal(summary.LargeNet / summary.Amount * 100)
+		summary.TailRatio = num.Decimal(tailAmount / summary.Amount * 100)
+	}
+	if buyVolume > 0 && sellVolume > 0 {
+		buyVWAP := summary.BuyAmount / buyVolume
+		sellVWAP := summary.SellAmount / sellVolume
+		summary.BuyVWAP = num.Decimal(buyVWAP)
+		summary.SellVWAP = num.Decimal(sellVWAP)
+		summary.VWAPSpread = num.Decimal((buyVWAP/sellVWAP - 1) * 100)
+	}
+	summary.TailNet = summary.TailBuy - summary.TailSell
+	summary.TailImbalance = imbalanceRate(summary.TailBuy, summary.TailSell)
+	return summary
+}
+
+// DataOrderFlow 成交分析
+//
+//	按历史成交数据统计订单大小分档、订单流失衡、主买主卖均价和尾盘资金, 每个证券一个文件, 每个交易日一条记录
+type DataOrderFlow struct {
+	Manifest
+}
+
+func init() {
+	summary := __mapDataSets[BaseOrderFlow]
+	_ = cache.Register(&DataOrderFlow{Manifest: Manifest{DataSummary: summary}})
+}
+
+func (this *DataOrderFlow) Clone(date string, code string) DataSet {
+	summary := __mapDataSets[BaseOrderFlow]
+	var dest = DataOrderFlow{
+		Manifest: Manifest{
+			DataSummary: summary,
+			Date:        date,
+			Code:        code,
+		},
+	}
+	return &dest
+}
+
+func (this *DataOrderFlow) Init(ctx context.Context, date string) error {
+	_ = ctx
+	_ = date
+	return nil
+}
+
+func (this *DataOrderFlow) Update(date string) {
+	_, _ = pullOrderFlow(this.GetSecurityCode(), date)
+}
+
+func (this *DataOrderFlow) Repair(date string) {
+	this.Update(date)
+}
+
+func (this *DataOrderFlow) Increase(snapshot quotes.Snapshot) {
+	_ = snapshot
+}
+
+// Print 控制台输出指定日期的成交分析
+func (this *DataOrderFlow) Print(code string, date ...string) {
+	securityCode := exchange.CorrectSecurityCode(code)
+	tradeDate := cache.DefaultCanReadDate()
+	if len(date) > 0 {
+		tradeDate = exchange.FixTradeDate(date[0])
+	}
+	v := CheckoutOrderFlow(securityCode, tradeDate)
+	if v == nil {
+		fmt.Printf("%s %s 没有成交分析数据\n", securityCode, tradeDate)
+		return
+	}
+	fmt.Printf("%+v\n", *v)
+}
+
+// loadOrderFlows 加载个股的成交分析, 按日期升序, 调用方需持有锁
+func loadOrderFlows(securityCode string) []OrderFlowSummary {
+	var list []OrderFlowSummary
+	_ = api.CsvToSlices(cache.OrderFlowFilename(securityCode), &list)
+	slices.SortFunc(list, func(a, b OrderFlowSummary) int {
+		return cmp.Compare(a.Date, b.Date)
+	})
+	return list
+}
+
+// pullOrderFlow 统计个股指定日期的成交数据并保存, 同一日期的记录会被替换
+func pullOrderFlow(securityCode, date string) (*OrderFlowSummary, error) {
+	securityCode = exchange.CorrectSecurityCode(securityCode)
+	date = exchange.FixTradeDate(date)
+	if !exchange.AssertStockBySecurityCode(securityCode) {
+		return nil, nil
+	}
+	var v OrderFlowSummary
+	trans := base.CheckoutTransactionData(securityCode, date, true)
+	if len(trans) == 0 {
+		// 当日的成交数据可能还没有生成, 不保存
+		if date >= exchange.GetCurrentlyDay() {
+			return nil, nil
+		}
+		// 历史日期没有成交数据(如停牌), 保存成交笔数为0的空记录, 避免反复拉取
+	} else {
+		volUnit := orderFlowDefaultUnit
+		if f10 := GetL5F10(securityCode, date); f10 != nil && f10.VolUnit > 0 {
+			volUnit = f10.VolUnit
+		}
+		v = ComputeOrderFlow(trans, volUnit, config.GetDataConfig().OrderFlow)
+	}
+	v.Date = date
+	v.Code = securityCode
+	v.UpdateTime = GetTimestamp()
+
+	mutex, _ := __orderFlowLocks.LoadOrStore(securityCode, &sync.Mutex{})
+	mutex.(*sync.Mutex).Lock()
+	defer mutex.(*sync.Mutex).Unlock()
+	list := loadOrderFlows(securityCode)
+	i, found := slices.BinarySearchFunc(list, date, func(e OrderFlowSummary, date string) int {
+		return cmp.Compare(e.Date, date)
+	})
+	if found {
+		list[i] = v
+	} else {
+		list = slices.Insert(list, i, v)
+	}
+	filename := cache.OrderFlowFilename(securityCode)
+	if err := os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+		logger.Errorf("成交分析[%s]保存失败: %+v", securityCode, err)
+		return &v, err
+	}
+	if err := api.SlicesToCsv(filename, list); err != nil {
+		logger.Errorf("成交分析[%s]保存失败: %+v", securityCode, err)
+		return &v, err
+	}
+	return &v, nil
+}
+
+// CheckoutOrderFlow 获取个股指定日期的成交分析, 缓存中没有时按成交数据计算
+//
+//	历史日期没有成交数据时返回成交笔数为0的空记录
+func CheckoutOrderFlow(securityCode, date string) *OrderFlowSummary {
+	securityCode = exchange.CorrectSecurityCode(securityCode)
+	date = exchange.FixTradeDate(date)
+	mutex, _ := __orderFlowLocks.LoadOrStore(securityCode, &sync.Mutex{})
+	mutex.(*sync.Mutex).Lock()
+	list := loadOrderFlows(securityCode)
+	mutex.(*sync.Mutex).Unlock()
+	i, found := slices.BinarySearchFunc(list, date, func(e OrderFlowSummary, date string) int {
+		return cmp.Compare(e.Date, date)
+	})
+	if found {
+		return &list[i]
+	}
+	v, _ := pullOrderFlow(securityCode, date)
+	return v
+}
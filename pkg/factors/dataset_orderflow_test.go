@@ -0,0 +1,45 @@
+package factors
+
+import (
+	"math"
+	"testing"
+
+	"gitee.com/quant1x/gotdx/quotes"
+	"xquant/pkg/config"
+)
+
+func orderFlowAmountEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestComputeOrderFlow(t *testing.T) {
+	parameter := config.OrderFlowParameter{MediumAmount: 40000, LargeAmount: 200000, SuperLargeAmount: 1000000}
+	list := []quotes.TickTransaction{
+		{Time: "09:25", Price: 10.00, Vol: 20, BuyOrSell: 2},                 // 2万, 小单, 方向不明按一半计算
+		{Time: "10:00", Price: 10.10, Vol: 300, BuyOrSell: quotes.TICK_BUY},  // 30.3万, 大单买入
+		{Time: "10:01", Price: 10.00, Vol: 100, BuyOrSell: quotes.TICK_SELL}, // 10万, 中单卖出
+		{Time: "14:50", Price: 10.20, Vol: 1000, BuyOrSell: quotes.TICK_BUY}, // 102万, 超大单买入
+		{Time: "14:55", Price: 10.10, Vol: 50, BuyOrSell: 2},                 // 5.05万, 价格下跌按卖出
+		{Time: "15:00", Price: 0.00, Vol: 100, BuyOrSell: 2},                 // 无效成交
+	}
+	v := ComputeOrderFlow(list, 100, parameter)
+	if v.Count != 5 {
+		t.Fatalf("count=%d", v.Count)
+	}
+	if !orderFlowAmountEqual(v.LargeBuy, 303000) || !orderFlowAmountEqual(v.SuperLargeBuy, 1020000) ||
+		!orderFlowAmountEqual(v.MediumSell, 150500) || !orderFlowAmountEqual(v.SmallBuy, 10000) {
+		t.Errorf("large_buy=%f, super_large_buy=%f, medium_sell=%f, small_buy=%f", v.LargeBuy, v.SuperLargeBuy, v.MediumSell, v.SmallBuy)
+	}
+	if !orderFlowAmountEqual(v.LargeNet, 1323000) {
+		t.Errorf("large_net=%f", v.LargeNet)
+	}
+	if !orderFlowAmountEqual(v.TailNet, 969500) || v.TailImbalance <= 0 {
+		t.Errorf("tail_net=%f, tail_imbalance=%f", v.TailNet, v.TailImbalance)
+	}
+	if v.BuyVWAP <= v.SellVWAP || v.VWAPSpread <= 0 {
+		t.Errorf("buy_vwap=%f, sell_vwap=%f, vwap_spread=%f", v.BuyVWAP, v.SellVWAP, v.VWAPSpread)
+	}
+	if v.Imbalance <= 0 || v.Imbalance > 100 {
+		t.Errorf("imbalance=%f", v.Imbalance)
+	}
+}
@@ -0,0 +1,193 @@
+package factors
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/gotdx/quotes"
+	"gitee.com/quant1x/gox/api"
+	"gitee.com/quant1x/gox/logger"
+	"xquant/pkg/cache"
+	"xquant/pkg/datasource/east_money"
+)
+
+const (
+	unlockRefreshDays = 7 // 限售解禁缓存的刷新周期, 单位是自然日
+)
+
+var (
+	// 限售解禁缓存文件按证券代码加锁, 数据集和特征会同时读写同一个文件
+	__unlockLocks sync.Map
+)
+
+// UnlockRecord 限售解禁批次
+//
+//	包括已经解禁和尚未解禁的批次, 解禁数量单位为股
+type UnlockRecord struct {
+	Code       string  `name:"证券代码" dataframe:"code"`           // 证券代码
+	LiftDate   string  `name:"解禁日期" dataframe:"lift_date"`      // 解禁日期
+	LiftNum    float64 `name:"解禁数量" dataframe:"lift_num"`       // 解禁数量
+	LiftType   string  `name:"限售股类型" dataframe:"lift_type"`     // 限售股类型
+	TotalRatio float64 `name:"占总股本比例%" dataframe:"total_ratio"` // 解禁数量占总股本的比例
+	FloatRatio float64 `name:"占流通股比例%" dataframe:"float_ratio"` // 解禁数量占已流通A股的比例
+	UpdateTime string  `name:"更新时间" dataframe:"update_time"`    // 更新时间
+}
+
+// DataUnlock 限售解禁日历
+//
+//	每个证券一个文件, 按周刷新
+type DataUnlock struct {
+	Manifest
+}
+
+func init() {
+	summary := __mapDataSets[BaseUnlock]
+	_ = cache.Register(&DataUnlock{Manifest: Manifest{DataSummary: summary}})
+}
+
+func (this *DataUnlock) Clone(date string, code string) DataSet {
+	summary := __mapDataSets[BaseUnlock]
+	var dest = DataUnlock{
+		Manifest: Manifest{
+			DataSummary: summary,
+			Date:        date,
+			Code:        code,
+		},
+	}
+	return &dest
+}
+
+func (this *DataUnlock) Init(ctx context.Context, date string) error {
+	_ = ctx
+	_ = date
+	return nil
+}
+
+func (this *DataUnlock) Update(date string) {
+	_ = date
+	_ = CheckoutUnlocks(this.GetSecurityCode())
+}
+
+func (this *DataUnlock) Repair(date string) {
+	this.Update(date)
+}
+
+func (this *DataUnlock) Increase(snapshot quotes.Snapshot) {
+	_ = snapshot
+}
+
+// Print 控制台输出限售解禁日历
+func (this *DataUnlock) Print(code string, date ...string) {
+	_ = date
+	securityCode := exchange.CorrectSecurityCode(code)
+	list := CheckoutUnlocks(securityCode)
+	if len(list) == 0 {
+		fmt.Printf("%s 没有限售解禁数据\n", securityCode)
+		return
+	}
+	for _, v := range list {
+		fmt.Printf("%+v\n", v)
+	}
+}
+
+// CheckoutUnlocks 获取个股的限售解禁日历, 按解禁日期升序
+//
+//	本地缓存超过刷新周期时从东方财富重新拉取并合并到本地缓存, 拉取失败时使用本地缓存
+func CheckoutUnlocks(securityCode string) []UnlockRecord {
+	securityCode = exchange.CorrectSecurityCode(securityCode)
+	if !exchange.AssertStockBySecurityCode(securityCode) {
+		return nil
+	}
+	mutex, _ := __unlockLocks.LoadOrStore(securityCode, &sync.Mutex{})
+	mutex.(*sync.Mutex).Lock()
+	defer mutex.(*sync.Mutex).Unlock()
+
+	filename := cache.UnlockFilename(securityCode)
+	var cacheList []UnlockRecord
+	_ = api.CsvToSlices(filename, &cacheList)
+	if stat, err := os.Stat(filename); err == nil && time.Since(stat.ModTime()) < unlockRefreshDays*24*time.Hour {
+		return cacheList
+	}
+	unlocks, err := east_money.StockUnlocks(securityCode)
+	if err != nil {
+		logger.Errorf("code[%s] 限售解禁拉取失败: %+v", securityCode, err)
+		return cacheList
+	}
+	list := make([]UnlockRecord, 0, len(unlocks))
+	for _, v := range unlocks {
+		list = append(list, UnlockRecord{
+			Code:       securityCode,
+			LiftDate:   v.LiftDate,
+			LiftNum:    float64(v.LiftNum),
+			LiftType:   v.LiftType,
+			TotalRatio: v.TotalRatio,
+			FloatRatio: v.FloatRatio,
+			UpdateTime: v.UpdateTime,
+		})
+	}
+	// 接口可能只返回部分批次, 合并到本地缓存, 不丢弃已经缓存的批次
+	list = mergeUnlocks(cacheList, list)
+	if err = os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+		return list
+	}
+	if len(list) == 0 {
+		// 没有解禁记录时生成空文件, 刷新周期内不再拉取
+		_ = os.WriteFile(filename, nil, 0644)
+	} else {
+		_ = api.SlicesToCsv(filename, list)
+	}
+	return list
+}
+
+// mergeUnlocks 按解禁日期和限售股类型合并批次, 新拉取的批次覆盖缓存中的同一批次, 按解禁日期升序
+func mergeUnlocks(cacheList, list []UnlockRecord) []UnlockRecord {
+	key := func(v UnlockRecord) string {
+		return v.LiftDate + "/" + v.LiftType
+	}
+	mapUnlocks := make(map[string]UnlockRecord, len(cacheList)+len(list))
+	for _, v := range cacheList {
+		mapUnlocks[key(v)] = v
+	}
+	for _, v := range list {
+		mapUnlocks[key(v)] = v
+	}
+	merged := make([]UnlockRecord, 0, len(mapUnlocks))
+	for _, v := range mapUnlocks {
+		merged = append(merged, v)
+	}
+	slices.SortFunc(merged, func(a, b UnlockRecord) int {
+		return strings.Compare(key(a), key(b))
+	})
+	return merged
+}
+
+// NextUnlock 不早于date的第一个解禁日, 同一日期的多个批次合并为一条记录
+func NextUnlock(list []UnlockRecord, date string) (UnlockRecord, bool) {
+	var next UnlockRecord
+	var types []string
+	for _, v := range list {
+		if v.LiftDate < date {
+			continue
+		}
+		if len(next.LiftDate) == 0 {
+			next = UnlockRecord{Code: v.Code, LiftDate: v.LiftDate, UpdateTime: v.UpdateTime}
+		} else if v.LiftDate != next.LiftDate {
+			break
+		}
+		next.LiftNum += v.LiftNum
+		next.TotalRatio += v.TotalRatio
+		next.FloatRatio += v.FloatRatio
+		if len(v.LiftType) > 0 {
+			types = append(types, v.LiftType)
+		}
+	}
+	next.LiftType = strings.Join(types, ",")
+	return next, len(next.LiftDate) > 0
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"gitee.com/quant1x/exchange"
 	"gitee.com/quant1x/gotdx/quotes"
-	"gitee.com/quant1x/gox/api"
 	"gitee.com/quant1x/num"
 	"slices"
 	"xquant/pkg/cache"
@@ -67,7 +66,7 @@ func pullWideByDate(securityCode, date string) []SecurityFeature {
 	var beginDate string // 补数据的开始日期
 	var endDate string   // 补数据的结束日期
 	var cacheBeginDate, cacheEndDate string
-	err := api.CsvToSlices(filename, &list)
+	err := cache.LoadSlices(filename, &list)
 	if err != nil || len(list) == 0 {
 		// 如果文件为空, 暂定从1990-12-19
 		cacheBeginDate = exchange.MARKET_CH_FIRST_LISTTIME
@@ -103,7 +102,7 @@ func pullWideByDate(securityCode, date string) []SecurityFeature {
 	}
 	// 3. 补齐日线, 日线是必须要有的, 也肯定会有
 	// 数据为空, 从基础K线获取K线部分
-	klines := base.CheckoutKLines(securityCode, endDate)
+	klines := checkoutKLines(BaseWideKLine, securityCode, endDate)
 	kline_length := len(klines)
 	if kline_length == 0 {
 		// K线为空, 返回空
@@ -224,6 +223,6 @@ func pullWideByDate(securityCode, date string) []SecurityFeature {
 	}
 
 	// 7. 保存文件
-	_ = api.SlicesToCsv(filename, list)
+	_ = cache.SaveSlices(filename, list)
 	return list
 }
@@ -2,7 +2,6 @@ package factors
 
 import (
 	"gitee.com/quant1x/exchange"
-	"gitee.com/quant1x/gox/api"
 	"xquant/pkg/cache"
 )
 
@@ -59,7 +58,7 @@ import (
 func loadWideTable(securityCode string) []SecurityFeature {
 	filename := cache.WideFilename(securityCode)
 	var lines []SecurityFeature
-	_ = api.CsvToSlices(filename, &lines)
+	_ = cache.LoadSlices(filename, &lines)
 	return lines
 }
 
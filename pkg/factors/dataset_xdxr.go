@@ -2,6 +2,9 @@ package factors
 
 import (
 	"context"
+	"fmt"
+
+	"gitee.com/quant1x/exchange"
 	"gitee.com/quant1x/gotdx/quotes"
 	"xquant/pkg/cache"
 	"xquant/pkg/datasource/base"
@@ -49,9 +52,18 @@ func (x *DataXdxr) Init(ctx context.Context, date string) error {
 //		panic("implement me")
 //	}
 
+// Print 控制台输出除权除息日历
 func (x *DataXdxr) Print(code string, date ...string) {
-	//TODO implement me
-	panic("implement me")
+	_ = date
+	securityCode := exchange.CorrectSecurityCode(code)
+	list := DividendEvents(securityCode)
+	if len(list) == 0 {
+		fmt.Printf("%s 没有除权除息数据\n", securityCode)
+		return
+	}
+	for _, v := range list {
+		fmt.Printf("%+v\n", v)
+	}
 }
 
 func (x *DataXdxr) Filename(date, code string) string {
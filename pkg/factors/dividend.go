@@ -0,0 +1,132 @@
+package factors
+
+import (
+	"math"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/gotdx/quotes"
+	"gitee.com/quant1x/gox/api"
+	"xquant/pkg/datasource/base"
+)
+
+const (
+	dividendTrailingYears = 1 // 滚动股息率的统计周期, 单位是年
+)
+
+// DividendEvent 除权除息事件
+//
+//	通达信除权除息数据按每10股记录, 这里统一折算为每股
+type DividendEvent struct {
+	Code         string  `name:"证券代码" dataframe:"code"`          // 证券代码
+	RecordDate   string  `name:"股权登记日" dataframe:"record_date"`  // 股权登记日, 除权除息日的前一个交易日
+	ExDate       string  `name:"除权除息日" dataframe:"ex_date"`      // 除权除息日
+	CashDividend float64 `name:"每股派息" dataframe:"cash_dividend"` // 每股现金分红, 税前
+	BonusShares  float64 `name:"每股送转" dataframe:"bonus_shares"`  // 每股送股和转增股
+	RightsShares float64 `name:"每股配股" dataframe:"rights_shares"` // 每股配股
+	RightsPrice  float64 `name:"配股价" dataframe:"rights_price"`   // 配股价
+}
+
+// newDividendEvent 除权除息记录转换成事件
+func newDividendEvent(securityCode string, xdxr quotes.XdxrInfo) DividendEvent {
+	exDate := exchange.FixTradeDate(xdxr.Date)
+	recordDate := exDate
+	if dates := exchange.LastNDate(exDate, 1); len(dates) > 0 {
+		recordDate = dates[0]
+	}
+	return DividendEvent{
+		Code:         securityCode,
+		RecordDate:   recordDate,
+		ExDate:       exDate,
+		CashDividend: xdxr.FenHong / 10,
+		BonusShares:  xdxr.SongZhuanGu / 10,
+		RightsShares: xdxr.PeiGu / 10,
+		RightsPrice:  xdxr.PeiGuJia,
+	}
+}
+
+// DividendEvents 个股的除权除息日历, 按除权除息日升序
+//
+//	数据来自除权除息数据集的本地缓存, 包括已经公布但尚未实施的分红送转
+func DividendEvents(securityCode string) []DividendEvent {
+	securityCode = exchange.CorrectSecurityCode(securityCode)
+	list := base.GetCacheXdxrList(securityCode)
+	var events []DividendEvent
+	for _, v := range list {
+		if v.Category != 1 {
+			// 忽略非除权信息
+			continue
+		}
+		events = append(events, newDividendEvent(securityCode, v))
+	}
+	api.SliceSort(events, func(a, b DividendEvent) bool {
+		return a.ExDate < b.ExDate
+	})
+	return events
+}
+
+// NextDividend 除权除息日不早于date的第一个事件
+func NextDividend(list []DividendEvent, date string) (DividendEvent, bool) {
+	for _, v := range list {
+		if v.ExDate >= date {
+			return v, true
+		}
+	}
+	return DividendEvent{}, false
+}
+
+// DividendOnDate 除权除息日是date的事件
+func DividendOnDate(list []DividendEvent, date string) (DividendEvent, bool) {
+	v, ok := NextDividend(list, date)
+	if !ok || v.ExDate != date {
+		return DividendEvent{}, false
+	}
+	return v, true
+}
+
+// TrailingCashDividend 截至date近一年已经实施的每股现金分红合计
+func TrailingCashDividend(list []DividendEvent, date string) float64 {
+	tm, err := api.ParseTime(date)
+	if err != nil {
+		return 0
+	}
+	begin := tm.AddDate(-dividendTrailingYears, 0, 0).Format(exchange.TradingDayDateFormat)
+	total := 0.00
+	for _, v := range list {
+		if v.ExDate > begin && v.ExDate <= date {
+			total += v.CashDividend
+		}
+	}
+	return total
+}
+
+// AdjustPrice 除权除息参考价, 和除权除息数据的前复权因子一致
+func (e DividendEvent) AdjustPrice(price float64) float64 {
+	return (price - e.CashDividend + e.RightsShares*e.RightsPrice) / (1 + e.BonusShares + e.RightsShares)
+}
+
+// AdjustPosition 除权除息日调整持仓
+//
+//	volume为股权登记日的持仓数量, cost为持仓成本价. 送转股不足1股的部分舍去, 配股需要主动认购, 不计入持仓.
+//	返回调整后的持仓数量、成本价和应得的现金分红
+func (e DividendEvent) AdjustPosition(volume int, cost float64) (int, float64, float64) {
+	if volume <= 0 {
+		return volume, cost, 0
+	}
+	cash := float64(volume) * e.CashDividend
+	// 浮点误差可能导致整数送转少1股
+	bonus := int(math.Floor(float64(volume)*e.BonusShares + 1e-6))
+	newVolume := volume + bonus
+	newCost := (cost*float64(volume) - cash) / float64(newVolume)
+	return newVolume, newCost, cash
+}
+
+// ShareFactor 除权除息日在(date, endDate]区间内的送转股累计倍数, 用于把date的持仓数量折算到endDate
+func ShareFactor(list []DividendEvent, date, endDate string) float64 {
+	factor := 1.00
+	for _, v := range list {
+		if v.ExDate > date && v.ExDate <= endDate {
+			factor *= 1 + v.BonusShares
+		}
+	}
+	return factor
+}
@@ -41,14 +41,23 @@ const (
 
 // 登记所有的特征数据
 const (
-	FeatureF10                       = baseFeature + 1 // 特征数据-基本面
-	FeatureHistory                   = baseFeature + 2 // 特征数据-历史
-	FeatureNo1                       = baseFeature + 3 // 特征数据-1号策略
-	FeatureMisc                      = baseFeature + 4 // 特征数据-Misc
-	FeatureBreaksThroughBox          = baseFeature + 5 // 特征数据-box
-	FeatureKLineShap                 = baseFeature + 6 // 特征数据-K线形态等
-	FeatureInvestmentSentimentMaster = baseFeature + 7 // 狩猎者-情绪周期
-	FeatureSecuritiesMarginTrading   = baseFeature + 8 // 融资融券
+	FeatureF10                       = baseFeature + 1  // 特征数据-基本面
+	FeatureHistory                   = baseFeature + 2  // 特征数据-历史
+	FeatureNo1                       = baseFeature + 3  // 特征数据-1号策略
+	FeatureMisc                      = baseFeature + 4  // 特征数据-Misc
+	FeatureBreaksThroughBox          = baseFeature + 5  // 特征数据-box
+	FeatureKLineShap                 = baseFeature + 6  // 特征数据-K线形态等
+	FeatureInvestmentSentimentMaster = baseFeature + 7  // 狩猎者-情绪周期
+	FeatureSecuritiesMarginTrading   = baseFeature + 8  // 融资融券
+	FeatureWeeklyHistory             = baseFeature + 9  // 特征数据-周线历史
+	FeatureMonthlyHistory            = baseFeature + 10 // 特征数据-月线历史
+	FeatureChips                     = baseFeature + 11 // 特征数据-筹码分布
+	FeatureAlpha                     = baseFeature + 12 // 特征数据-量价因子
+	FeatureBillBoard                 = baseFeature + 13 // 特征数据-龙虎榜
+	FeatureFundFlow                  = baseFeature + 14 // 特征数据-资金流向
+	FeatureOrderFlow                 = baseFeature + 15 // 特征数据-成交分析
+	FeatureUnlock                    = baseFeature + 16 // 特征数据-限售解禁
+	FeatureDividend                  = baseFeature + 17 // 特征数据-除权除息
 )
 
 var (
@@ -59,9 +68,19 @@ var (
 		FeatureBreaksThroughBox:          cache.Summary(FeatureBreaksThroughBox, cacheL5KeyBox, "有效突破平台", cache.DefaultDataProvider),
 		FeatureInvestmentSentimentMaster: cache.Summary(FeatureInvestmentSentimentMaster, cacheL5KeyInvestmentSentimentMaster, "情绪大师", cache.DefaultDataProvider),
 		FeatureSecuritiesMarginTrading:   cache.Summary(FeatureSecuritiesMarginTrading, cacheL5KeySecuritiesMarginTrading, "融资融券", cache.DefaultDataProvider),
+		FeatureWeeklyHistory:             cache.Summary(FeatureWeeklyHistory, cacheL5KeyWeekly, "周线历史数据", cache.DefaultDataProvider),
+		FeatureMonthlyHistory:            cache.Summary(FeatureMonthlyHistory, cacheL5KeyMonthly, "月线历史数据", cache.DefaultDataProvider),
+		FeatureChips:                     cache.Summary(FeatureChips, cacheL5KeyChips, "筹码分布", cache.DefaultDataProvider),
+		FeatureAlpha:                     cache.Summary(FeatureAlpha, cacheL5KeyAlpha, "量价因子", cache.DefaultDataProvider),
+		FeatureBillBoard:                 cache.Summary(FeatureBillBoard, cacheL5KeyBillBoard, "龙虎榜", cache.DefaultDataProvider),
+		FeatureFundFlow:                  cache.Summary(FeatureFundFlow, cacheL5KeyFundFlow, "资金流向", cache.DefaultDataProvider),
+		FeatureOrderFlow:                 cache.Summary(FeatureOrderFlow, cacheL5KeyOrderFlow, "成交分析", cache.DefaultDataProvider),
+		FeatureUnlock:                    cache.Summary(FeatureUnlock, cacheL5KeyUnlock, "限售解禁", cache.DefaultDataProvider),
+		FeatureDividend:                  cache.Summary(FeatureDividend, cacheL5KeyDividend, "除权除息", cache.DefaultDataProvider),
 	}
 )
 
 var (
 	ErrInvalidFeatureSample = errors.New("无效的特征数据样本")
+	ErrFeatureNotFound      = errors.New("特征不存在")
 )
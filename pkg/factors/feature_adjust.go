@@ -0,0 +1,49 @@
+package factors
+
+import (
+	"xquant/pkg/cache"
+	"xquant/pkg/datasource/base"
+)
+
+var (
+	// 特征和数据集使用的K线复权方式
+	//
+	//	新增特征必须在这里声明复权方式. 价格形态类的特征使用前复权, 保证除权除息前后的价格连续;
+	//	需要实际价格的特征(如股息率、按昨收计算的涨停价)使用不复权
+	__mapAdjustments = map[cache.Kind]base.Adjustment{
+		FeatureF10:                       base.ForwardAdjusted,
+		FeatureHistory:                   base.ForwardAdjusted,
+		FeatureNo1:                       base.ForwardAdjusted,
+		FeatureMisc:                      base.ForwardAdjusted,
+		FeatureBreaksThroughBox:          base.ForwardAdjusted,
+		FeatureKLineShap:                 base.ForwardAdjusted,
+		FeatureInvestmentSentimentMaster: base.Unadjusted,
+		FeatureSecuritiesMarginTrading:   base.ForwardAdjusted,
+		FeatureWeeklyHistory:             base.ForwardAdjusted,
+		FeatureMonthlyHistory:            base.ForwardAdjusted,
+		FeatureChips:                     base.Unadjusted,
+		FeatureAlpha:                     base.ForwardAdjusted,
+		FeatureBillBoard:                 base.ForwardAdjusted,
+		FeatureFundFlow:                  base.ForwardAdjusted,
+		FeatureOrderFlow:                 base.ForwardAdjusted,
+		FeatureUnlock:                    base.ForwardAdjusted,
+		FeatureDividend:                  base.Unadjusted,
+		BaseKLine:                        base.ForwardAdjusted,
+		BaseWideKLine:                    base.ForwardAdjusted,
+		BaseChipDistribution:             base.Unadjusted,
+	}
+)
+
+// AdjustmentOf 特征或数据集使用的复权方式, 没有声明的默认前复权
+func AdjustmentOf(kind cache.Kind) base.Adjustment {
+	v, ok := __mapAdjustments[kind]
+	if !ok {
+		return base.ForwardAdjusted
+	}
+	return v
+}
+
+// 按特征或数据集声明的复权方式捡出指定日期的K线
+func checkoutKLines(kind cache.Kind, securityCode, date string) []base.KLine {
+	return base.CheckoutAdjustedKLines(securityCode, date, AdjustmentOf(kind))
+}
@@ -0,0 +1,22 @@
+package factors
+
+import (
+	"testing"
+
+	"xquant/pkg/datasource/base"
+)
+
+func TestAdjustmentOf(t *testing.T) {
+	// 每个特征都必须声明复权方式
+	for kind, summary := range __mapFeatures {
+		if _, ok := __mapAdjustments[kind]; !ok {
+			t.Errorf("feature[%s] 没有声明复权方式", summary.Key())
+		}
+	}
+	if v := AdjustmentOf(FeatureDividend); v != base.Unadjusted {
+		t.Errorf("dividend adjustment = %s", v.Key())
+	}
+	if v := AdjustmentOf(FeatureHistory); v != base.ForwardAdjusted {
+		t.Errorf("history adjustment = %s", v.Key())
+	}
+}
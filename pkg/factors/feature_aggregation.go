@@ -20,6 +20,23 @@ var (
 	__l5InvestmentSentimentMaster *Cache1D[*InvestmentSentimentMaster] = nil
 	// 融资融券
 	__l5SecuritiesMarginTrading *Cache1D[*SecuritiesMarginTrading] = nil
+	// 周线历史
+	__l5WeeklyHistory *Cache1D[*PeriodHistory] = nil
+	// 月线历史
+	__l5MonthlyHistory *Cache1D[*PeriodHistory] = nil
+	// 筹码分布
+	__l5Chip *Cache1D[*Chip] = nil
+	// 量价因子
+	__l5Alpha *Cache1D[*Alpha] = nil
+	// 龙虎榜
+	__l5BillBoard *Cache1D[*BillBoard] = nil
+	// 资金流向
+	__l5FundFlow *Cache1D[*FundFlow] = nil
+	// 成交分析
+	__l5OrderFlow *Cache1D[*OrderFlow] = nil
+	__l5Unlock    *Cache1D[*Unlock]    = nil
+	// 除权除息
+	__l5Dividend *Cache1D[*Dividend] = nil
 )
 
 func init() {
@@ -63,6 +80,60 @@ func lazyInitFeatures() {
 	if err != nil {
 		logger.Fatalf("%+v", err)
 	}
+	// 周线历史
+	__l5WeeklyHistory = NewCache1D[*PeriodHistory](cacheL5KeyWeekly, NewWeeklyHistory)
+	err = cache.Register(__l5WeeklyHistory)
+	if err != nil {
+		logger.Fatalf("%+v", err)
+	}
+	// 月线历史
+	__l5MonthlyHistory = NewCache1D[*PeriodHistory](cacheL5KeyMonthly, NewMonthlyHistory)
+	err = cache.Register(__l5MonthlyHistory)
+	if err != nil {
+		logger.Fatalf("%+v", err)
+	}
+	// 筹码分布
+	__l5Chip = NewCache1D[*Chip](cacheL5KeyChips, NewChip)
+	err = cache.Register(__l5Chip)
+	if err != nil {
+		logger.Fatalf("%+v", err)
+	}
+	// 量价因子
+	__l5Alpha = NewCache1D[*Alpha](cacheL5KeyAlpha, NewAlpha)
+	err = cache.Register(__l5Alpha)
+	if err != nil {
+		logger.Fatalf("%+v", err)
+	}
+	// 龙虎榜
+	__l5BillBoard = NewCache1D[*BillBoard](cacheL5KeyBillBoard, NewBillBoard)
+	err = cache.Register(__l5BillBoard)
+	if err != nil {
+		logger.Fatalf("%+v", err)
+	}
+	// 资金流向
+	__l5FundFlow = NewCache1D[*FundFlow](cacheL5KeyFundFlow, NewFundFlow)
+	err = cache.Register(__l5FundFlow)
+	if err != nil {
+		logger.Fatalf("%+v", err)
+	}
+	// 成交分析
+	__l5OrderFlow = NewCache1D[*OrderFlow](cacheL5KeyOrderFlow, NewOrderFlow)
+	err = cache.Register(__l5OrderFlow)
+	if err != nil {
+		logger.Fatalf("%+v", err)
+	}
+	// 限售解禁
+	__l5Unlock = NewCache1D[*Unlock](cacheL5KeyUnlock, NewUnlock)
+	err = cache.Register(__l5Unlock)
+	if err != nil {
+		logger.Fatalf("%+v", err)
+	}
+	// 除权除息
+	__l5Dividend = NewCache1D[*Dividend](cacheL5KeyDividend, NewDividend)
+	err = cache.Register(__l5Dividend)
+	if err != nil {
+		logger.Fatalf("%+v", err)
+	}
 }
 
 func GetL5History(securityCode string, date ...string) *History {
@@ -107,6 +178,18 @@ func RefreshL5Misc() {
 
 func FilterL5Misc(f func(v *Misc) bool, date ...string) []*Misc {
 	__l5Once.Do(lazyInitFeatures)
+	if f == nil {
+		return nil
+	}
+	if destDate, ok := __l5Misc.viewDate(date...); ok {
+		var list []*Misc
+		for _, v := range At(destDate).Filter(cacheL5KeyMisc, nil) {
+			if misc, ok := v.(*Misc); ok && f(misc) {
+				list = append(list, misc)
+			}
+		}
+		return list
+	}
 	__l5Misc.Checkout(date...)
 	return __l5Misc.Filter(f)
 }
@@ -152,3 +235,93 @@ func RefreshL5SecuritiesMarginTrading() {
 	__l5Once.Do(lazyInitFeatures)
 	__l5SecuritiesMarginTrading.Apply(nil, true)
 }
+
+// GetL5WeeklyHistory 获取周线历史数据
+func GetL5WeeklyHistory(securityCode string, date ...string) *PeriodHistory {
+	__l5Once.Do(lazyInitFeatures)
+	v := __l5WeeklyHistory.Get(securityCode, date...)
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+// GetL5MonthlyHistory 获取月线历史数据
+func GetL5MonthlyHistory(securityCode string, date ...string) *PeriodHistory {
+	__l5Once.Do(lazyInitFeatures)
+	v := __l5MonthlyHistory.Get(securityCode, date...)
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+// GetL5Chip 获取筹码分布特征
+func GetL5Chip(securityCode string, date ...string) *Chip {
+	__l5Once.Do(lazyInitFeatures)
+	v := __l5Chip.Get(securityCode, date...)
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+// GetL5Alpha 获取量价因子
+func GetL5Alpha(securityCode string, date ...string) *Alpha {
+	__l5Once.Do(lazyInitFeatures)
+	v := __l5Alpha.Get(securityCode, date...)
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+// GetL5BillBoard 获取龙虎榜特征
+func GetL5BillBoard(securityCode string, date ...string) *BillBoard {
+	__l5Once.Do(lazyInitFeatures)
+	v := __l5BillBoard.Get(securityCode, date...)
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+// GetL5FundFlow 获取资金流向特征
+func GetL5FundFlow(securityCode string, date ...string) *FundFlow {
+	__l5Once.Do(lazyInitFeatures)
+	v := __l5FundFlow.Get(securityCode, date...)
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+// GetL5OrderFlow 获取成交分析特征
+func GetL5OrderFlow(securityCode string, date ...string) *OrderFlow {
+	__l5Once.Do(lazyInitFeatures)
+	v := __l5OrderFlow.Get(securityCode, date...)
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+// GetL5Unlock 获取限售解禁特征
+func GetL5Unlock(securityCode string, date ...string) *Unlock {
+	__l5Once.Do(lazyInitFeatures)
+	v := __l5Unlock.Get(securityCode, date...)
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+// GetL5Dividend 获取除权除息特征
+func GetL5Dividend(securityCode string, date ...string) *Dividend {
+	__l5Once.Do(lazyInitFeatures)
+	v := __l5Dividend.Get(securityCode, date...)
+	if v == nil {
+		return nil
+	}
+	return *v
+}
@@ -0,0 +1,191 @@
+package factors
+
+import (
+	"context"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/gox/logger"
+	"xquant/pkg/alpha"
+	"xquant/pkg/cache"
+	"xquant/pkg/datasource/base"
+)
+
+const (
+	cacheL5KeyAlpha = "alpha"
+	alphaKLineCount = 260 // 计算因子使用的最近K线数量, 覆盖最长的因子窗口
+)
+
+// Alpha 量价因子, Alpha101中可以按单证券时间序列计算的公式和常用的动量、反转、波动率、流动性因子
+//
+//	数据不足的因子为NaN
+type Alpha struct {
+	cache.DataSummary `dataframe:"-"`
+	Date              string  `name:"日期" dataframe:"date"`                  // 数据日期
+	Code              string  `name:"证券代码" dataframe:"code"`                // 证券代码
+	Alpha006          float64 `name:"alpha006" dataframe:"alpha006"`        // -1*correlation(open, volume, 10)
+	Alpha009          float64 `name:"alpha009" dataframe:"alpha009"`        // 收盘价变化的趋势延续/反转
+	Alpha012          float64 `name:"alpha012" dataframe:"alpha012"`        // sign(delta(volume, 1))*(-1*delta(close, 1))
+	Alpha021          float64 `name:"alpha021" dataframe:"alpha021"`        // 均线和波动带的位置与量比
+	Alpha023          float64 `name:"alpha023" dataframe:"alpha023"`        // 突破20日均高后的回落
+	Alpha024          float64 `name:"alpha024" dataframe:"alpha024"`        // 100日均线斜率和收盘价位置
+	Alpha026          float64 `name:"alpha026" dataframe:"alpha026"`        // -1*ts_max(correlation(ts_rank(volume, 5), ts_rank(high, 5), 5), 3)
+	Alpha033          float64 `name:"alpha033" dataframe:"alpha033"`        // -1*(1-open/close)
+	Alpha035          float64 `name:"alpha035" dataframe:"alpha035"`        // ts_rank(volume, 32)*(1-ts_rank(close+high-low, 16))*(1-ts_rank(returns, 32))
+	Alpha041          float64 `name:"alpha041" dataframe:"alpha041"`        // sqrt(high*low)-vwap
+	Alpha046          float64 `name:"alpha046" dataframe:"alpha046"`        // 20日和10日收盘价斜率差的反转
+	Alpha049          float64 `name:"alpha049" dataframe:"alpha049"`        // 收盘价斜率差小于-0.1时做多
+	Alpha051          float64 `name:"alpha051" dataframe:"alpha051"`        // 收盘价斜率差小于-0.05时做多
+	Alpha053          float64 `name:"alpha053" dataframe:"alpha053"`        // -1*delta(((close-low)-(high-close))/(close-low), 9)
+	Alpha054          float64 `name:"alpha054" dataframe:"alpha054"`        // -1*(low-close)*open^5/((low-high)*close^5)
+	Alpha101          float64 `name:"alpha101" dataframe:"alpha101"`        // (close-open)/(high-low+0.001)
+	Mom5              float64 `name:"5日动量" dataframe:"mom5"`                // 5日动量
+	Mom20             float64 `name:"20日动量" dataframe:"mom20"`              // 20日动量
+	Mom60             float64 `name:"60日动量" dataframe:"mom60"`              // 60日动量
+	Mom120Skip20      float64 `name:"120日动量(跳过20日)" dataframe:"mom120_20"`  // 跳过最近20日的120日动量
+	Rev1              float64 `name:"1日反转" dataframe:"rev1"`                // 1日反转
+	Rev5              float64 `name:"5日反转" dataframe:"rev5"`                // 5日反转
+	Bias20            float64 `name:"20日乖离率" dataframe:"bias20"`            // 20日乖离率
+	Volatility20      float64 `name:"20日波动率" dataframe:"volatility20"`      // 20日收益率标准差
+	Volatility60      float64 `name:"60日波动率" dataframe:"volatility60"`      // 60日收益率标准差
+	DownsideVol20     float64 `name:"20日下行波动率" dataframe:"downside_vol20"`  // 20日下行波动率
+	Skew20            float64 `name:"20日偏度" dataframe:"skew20"`             // 20日收益率偏度
+	MaxRet20          float64 `name:"20日最大涨幅" dataframe:"max_ret20"`        // 20日最大单日收益
+	ATR14             float64 `name:"14日ATR比" dataframe:"atr14"`            // 14日平均真实波幅占收盘价比
+	Amihud20          float64 `name:"20日非流动性" dataframe:"amihud20"`         // 20日Amihud非流动性, 每亿元成交额的价格冲击
+	VolumeRatio5To20  float64 `name:"5/20日量比" dataframe:"volume_ratio5_20"` // 5日均量与20日均量之比
+	LogAmount20       float64 `name:"20日成交额对数" dataframe:"log_amount20"`    // 20日平均成交额的对数
+	Position20        float64 `name:"20日价格位置" dataframe:"position20"`       // 收盘价在20日高低区间的位置
+	UpdateTime        string  `name:"更新时间" dataframe:"update_time"`         // 更新时间
+	State             uint64  `name:"样本状态" dataframe:"样本状态"`                // 样本状态
+}
+
+func NewAlpha(date, code string) *Alpha {
+	summary := __mapFeatures[FeatureAlpha]
+	v := Alpha{
+		DataSummary: summary,
+		Date:        date,
+		Code:        code,
+	}
+	return &v
+}
+
+func (this *Alpha) GetDate() string {
+	return this.Date
+}
+
+func (this *Alpha) GetSecurityCode() string {
+	return this.Code
+}
+
+func (this *Alpha) Factory(date string, code string) Feature {
+	v := NewAlpha(date, code)
+	return v
+}
+
+func (this *Alpha) Init(ctx context.Context, date string) error {
+	_ = ctx
+	_ = date
+	return nil
+}
+
+func (this *Alpha) FromHistory(history History) Feature {
+	_ = history
+	return this
+}
+
+func (this *Alpha) Update(code, cacheDate, featureDate string, complete bool) {
+	securityCode := exchange.CorrectSecurityCode(code)
+	tradeDate := exchange.FixTradeDate(featureDate)
+	klines := checkoutKLines(FeatureAlpha, securityCode, tradeDate)
+	if len(klines) == 0 {
+		logger.Errorf("code[%s, %s] kline not found", code, featureDate)
+		return
+	}
+	if len(klines) > alphaKLineCount {
+		klines = klines[len(klines)-alphaKLineCount:]
+	}
+	values := alpha.Compute(alphaBars(klines))
+	this.Date = tradeDate
+	this.Alpha006 = values["alpha006"]
+	this.Alpha009 = values["alpha009"]
+	this.Alpha012 = values["alpha012"]
+	this.Alpha021 = values["alpha021"]
+	this.Alpha023 = values["alpha023"]
+	this.Alpha024 = values["alpha024"]
+	this.Alpha026 = values["alpha026"]
+	this.Alpha033 = values["alpha033"]
+	this.Alpha035 = values["alpha035"]
+	this.Alpha041 = values["alpha041"]
+	this.Alpha046 = values["alpha046"]
+	this.Alpha049 = values["alpha049"]
+	this.Alpha051 = values["alpha051"]
+	this.Alpha053 = values["alpha053"]
+	this.Alpha054 = values["alpha054"]
+	this.Alpha101 = values["alpha101"]
+	this.Mom5 = values["mom5"]
+	this.Mom20 = values["mom20"]
+	this.Mom60 = values["mom60"]
+	this.Mom120Skip20 = values["mom120_20"]
+	this.Rev1 = values["rev1"]
+	this.Rev5 = values["rev5"]
+	this.Bias20 = values["bias20"]
+	this.Volatility20 = values["volatility20"]
+	this.Volatility60 = values["volatility60"]
+	this.DownsideVol20 = values["downside_vol20"]
+	this.Skew20 = values["skew20"]
+	this.MaxRet20 = values["max_ret20"]
+	this.ATR14 = values["atr14"]
+	this.Amihud20 = values["amihud20"]
+	this.VolumeRatio5To20 = values["volume_ratio5_20"]
+	this.LogAmount20 = values["log_amount20"]
+	this.Position20 = values["position20"]
+	// 样本状态
+	this.State |= this.Kind()
+	this.UpdateTime = GetTimestamp()
+	_ = cacheDate
+	_ = complete
+}
+
+func (this *Alpha) Repair(code, cacheDate, featureDate string, complete bool) {
+	this.Update(code, cacheDate, featureDate, complete)
+}
+
+func (this *Alpha) Increase(snapshot QuoteSnapshot) Feature {
+	_ = snapshot
+	return this
+}
+
+// ValidateSample 验证样本数据
+func (this *Alpha) ValidateSample() error {
+	if this.State > 0 {
+		return nil
+	}
+	return ErrInvalidFeatureSample
+}
+
+// Check 实现 cache.Validator 接口, 按特征的校验策略检查数据
+func (this *Alpha) Check(featureDate string) error {
+	return CheckFeature(this, featureDate)
+}
+
+// alphaBars K线转换成因子计算的序列
+func alphaBars(klines []base.KLine) alpha.Bars {
+	n := len(klines)
+	b := alpha.Bars{
+		Open:   make([]float64, n),
+		High:   make([]float64, n),
+		Low:    make([]float64, n),
+		Close:  make([]float64, n),
+		Volume: make([]float64, n),
+		Amount: make([]float64, n),
+	}
+	for i, v := range klines {
+		b.Open[i] = v.Open
+		b.High[i] = v.High
+		b.Low[i] = v.Low
+		b.Close[i] = v.Close
+		b.Volume[i] = v.Volume
+		b.Amount[i] = v.Amount
+	}
+	return b
+}
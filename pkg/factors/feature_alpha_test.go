@@ -0,0 +1,22 @@
+package factors
+
+import (
+	"fmt"
+	"testing"
+
+	"gitee.com/quant1x/exchange"
+	"xquant/pkg/cache"
+)
+
+func TestAlpha_basic(t *testing.T) {
+	code := "600600"
+	date := "2024-06-24"
+	cacheDate, featureDate := cache.CorrectDate(date)
+	code = exchange.CorrectSecurityCode(code)
+	v := NewAlpha(featureDate, code)
+	v.Update(code, cacheDate, featureDate, true)
+	fmt.Printf("%+v\n", *v)
+	if err := v.Check(featureDate); err != nil {
+		fmt.Println(err)
+	}
+}
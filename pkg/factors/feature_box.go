@@ -162,6 +162,11 @@ func (this *Box) ValidateSample() error {
 	return ErrInvalidFeatureSample
 }
 
+// Check 实现 cache.Validator 接口, 按特征的校验策略检查数据
+func (this *Box) Check(featureDate string) error {
+	return CheckFeature(this, featureDate)
+}
+
 // SarIncr SAR增量计算
 func (this *Box) SarIncr(snapshot QuoteSnapshot) indicators.FeatureSar {
 	sar := indicators.FeatureSar{
@@ -7,7 +7,6 @@ import (
 	. "gitee.com/quant1x/pandas/formula"
 	"gitee.com/quant1x/ta-lib/indicators"
 	"xquant/pkg/cache"
-	"xquant/pkg/datasource/base"
 	"xquant/pkg/utils"
 )
 
@@ -55,7 +54,7 @@ type KLineBox struct {
 func NewKLineBox(code, date string) *KLineBox {
 	securityCode := exchange.CorrectSecurityCode(code)
 	tradeDate := exchange.FixTradeDate(date)
-	klines := base.CheckoutKLines(securityCode, tradeDate)
+	klines := checkoutKLines(FeatureBreaksThroughBox, securityCode, tradeDate)
 	if len(klines) < cache.KLineMin {
 		return nil
 	}
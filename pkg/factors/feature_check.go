@@ -0,0 +1,288 @@
+package factors
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"gitee.com/quant1x/exchange"
+	"xquant/pkg/cache"
+	"xquant/pkg/datasource/base"
+)
+
+var (
+	ErrFeatureCheck = errors.New("特征数据校验失败")
+)
+
+// CheckRule 特征字段的校验规则
+//
+//	Min和Max都为0时不检查取值范围
+type CheckRule struct {
+	Field    string  // 结构体字段名
+	Required bool    // 必须有值, 数值不为0, 字符串不为空
+	Min      float64 // 最小值
+	Max      float64 // 最大值
+}
+
+// CheckPolicy 特征的校验策略
+//
+//	浮点字段默认不允许NaN和Inf, AllowNaN中的字段除外
+type CheckPolicy struct {
+	AlignKLine  bool        // 数据日期和K线日期对齐, 停牌或K线缺失视为校验失败
+	AllowNaN    []string    // 允许NaN的浮点字段
+	AllowAllNaN bool        // 全部浮点字段允许NaN, 用于数据不足时输出NaN的因子
+	Rules       []CheckRule // 字段规则
+}
+
+var (
+	__mutexCheckPolicies sync.RWMutex
+	__mapCheckPolicies   = map[cache.Kind]CheckPolicy{
+		FeatureF10: {
+			Rules: []CheckRule{
+				{Field: "SecurityName", Required: true},
+				{Field: "TotalCapital", Required: true, Min: 0, Max: math.MaxFloat64},
+				{Field: "Capital", Required: true, Min: 0, Max: math.MaxFloat64},
+				{Field: "VolUnit", Required: true},
+			},
+		},
+		FeatureHistory: {
+			AlignKLine: true,
+			Rules: []CheckRule{
+				{Field: "CLOSE", Required: true, Min: 0, Max: math.MaxFloat64},
+				{Field: "OPEN", Required: true, Min: 0, Max: math.MaxFloat64},
+				{Field: "HIGH", Required: true, Min: 0, Max: math.MaxFloat64},
+				{Field: "LOW", Required: true, Min: 0, Max: math.MaxFloat64},
+				{Field: "VOL", Min: 0, Max: math.MaxFloat64},
+				{Field: "AMOUNT", Min: 0, Max: math.MaxFloat64},
+			},
+		},
+		FeatureMisc: {
+			AlignKLine: true,
+			Rules: []CheckRule{
+				{Field: "TurnoverRate", Min: 0, Max: 100},
+				{Field: "AmplitudeRatio", Min: 0, Max: 100},
+				{Field: "Volume", Min: 0, Max: math.MaxFloat64},
+			},
+		},
+		FeatureBreaksThroughBox: {
+			AlignKLine: true,
+		},
+		FeatureInvestmentSentimentMaster: {
+			AlignKLine: true,
+			Rules: []CheckRule{
+				{Field: "R1CLOSE", Required: true, Min: 0, Max: math.MaxFloat64},
+			},
+		},
+		FeatureSecuritiesMarginTrading: {
+			Rules: []CheckRule{
+				{Field: "RZYE", Min: 0, Max: math.MaxFloat64},
+				{Field: "RZYEZB", Min: 0, Max: 100},
+			},
+		},
+		FeatureWeeklyHistory: {
+			AlignKLine: true,
+			Rules: []CheckRule{
+				{Field: "PeriodDate", Required: true},
+				{Field: "CLOSE", Required: true, Min: 0, Max: math.MaxFloat64},
+			},
+		},
+		FeatureMonthlyHistory: {
+			AlignKLine: true,
+			Rules: []CheckRule{
+				{Field: "PeriodDate", Required: true},
+				{Field: "CLOSE", Required: true, Min: 0, Max: math.MaxFloat64},
+			},
+		},
+		FeatureChips: {
+			AlignKLine: true,
+			Rules: []CheckRule{
+				{Field: "AvgCost", Required: true, Min: 0, Max: math.MaxFloat64},
+				{Field: "ProfitRatio", Min: 0, Max: 1},
+				{Field: "Concentration90", Min: 0, Max: 1},
+				{Field: "Concentration70", Min: 0, Max: 1},
+			},
+		},
+		FeatureAlpha: {
+			AlignKLine:  true,
+			AllowAllNaN: true,
+			Rules: []CheckRule{
+				{Field: "Position20", Min: 0, Max: 1},
+				{Field: "Volatility20", Min: 0, Max: 1},
+			},
+		},
+		FeatureBillBoard: {
+			Rules: []CheckRule{
+				{Field: "BuyAmount", Min: 0, Max: math.MaxFloat64},
+				{Field: "SellAmount", Min: 0, Max: math.MaxFloat64},
+				{Field: "RiseProbability3D", Min: 0, Max: 100},
+			},
+		},
+		FeatureFundFlow: {
+			AlignKLine: true,
+			Rules: []CheckRule{
+				{Field: "MainRatio", Min: -100, Max: 100},
+				{Field: "MainInflowDays", Min: 0, Max: math.MaxInt32},
+			},
+		},
+		FeatureOrderFlow: {
+			AlignKLine: true,
+			Rules: []CheckRule{
+				{Field: "Imbalance", Min: -100, Max: 100},
+				{Field: "TailImbalance", Min: -100, Max: 100},
+				{Field: "TailRatio", Min: 0, Max: 100},
+			},
+		},
+		FeatureUnlock: {
+			Rules: []CheckRule{
+				{Field: "UnlockDays", Min: -1, Max: math.MaxInt32},
+				{Field: "LiftNum", Min: 0, Max: math.MaxFloat64},
+				{Field: "TotalRatio", Min: 0, Max: 100},
+			},
+		},
+		FeatureDividend: {
+			Rules: []CheckRule{
+				{Field: "ExDays", Min: -1, Max: math.MaxInt32},
+				{Field: "CashDividend", Min: 0, Max: math.MaxFloat64},
+				{Field: "DividendYield", Min: 0, Max: 100},
+			},
+		},
+	}
+)
+
+// RegisterCheckPolicy 注册特征的校验策略, 已存在的策略会被覆盖
+func RegisterCheckPolicy(kind cache.Kind, policy CheckPolicy) {
+	__mutexCheckPolicies.Lock()
+	defer __mutexCheckPolicies.Unlock()
+	__mapCheckPolicies[kind] = policy
+}
+
+// GetCheckPolicy 获取特征的校验策略
+func GetCheckPolicy(kind cache.Kind) CheckPolicy {
+	__mutexCheckPolicies.RLock()
+	defer __mutexCheckPolicies.RUnlock()
+	return __mapCheckPolicies[kind]
+}
+
+// CheckFeature 按特征的校验策略检查数据
+//
+//	检查样本状态、数据日期、K线日期对齐、NaN和字段规则, 返回全部不通过的原因
+func CheckFeature(feature Feature, featureDate string) error {
+	return checkFeature(feature, featureDate, lastKLineDate)
+}
+
+// lastKLineDate 证券代码截止指定日期的最后一根K线的日期
+//
+//	只用到K线的日期, 和复权方式无关, 直接读取本地缓存的前复权K线
+func lastKLineDate(securityCode, date string) string {
+	klines := base.CheckoutAdjustedKLines(securityCode, date, base.ForwardAdjusted)
+	if n := len(klines); n > 0 {
+		return klines[n-1].Date
+	}
+	return ""
+}
+
+func checkFeature(feature Feature, featureDate string, klineDate func(securityCode, date string) string) error {
+	if feature == nil || reflect.ValueOf(feature).IsNil() {
+		return fmt.Errorf("%w: 数据不存在", ErrFeatureCheck)
+	}
+	featureDate = exchange.FixTradeDate(featureDate)
+	policy := GetCheckPolicy(feature.Kind())
+	var reasons []string
+	if err := feature.ValidateSample(); err != nil {
+		reasons = append(reasons, err.Error())
+	}
+	if date := feature.GetDate(); date != featureDate {
+		reasons = append(reasons, fmt.Sprintf("数据日期[%s]与特征日期[%s]不一致", date, featureDate))
+	}
+	if policy.AlignKLine && klineDate != nil {
+		if date := klineDate(feature.GetSecurityCode(), featureDate); date != featureDate {
+			reasons = append(reasons, fmt.Sprintf("K线日期[%s]与特征日期[%s]不一致", date, featureDate))
+		}
+	}
+	value := reflect.Indirect(reflect.ValueOf(feature))
+	if value.Kind() == reflect.Struct {
+		if !policy.AllowAllNaN {
+			reasons = append(reasons, checkNaN(value, policy.AllowNaN)...)
+		}
+		for _, rule := range policy.Rules {
+			if reason := checkRule(value, rule); len(reason) > 0 {
+				reasons = append(reasons, reason)
+			}
+		}
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrFeatureCheck, strings.Join(reasons, "; "))
+}
+
+// checkNaN 检查浮点字段的NaN和Inf
+func checkNaN(value reflect.Value, allows []string) []string {
+	var reasons []string
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous || !field.IsExported() {
+			continue
+		}
+		kind := field.Type.Kind()
+		if kind != reflect.Float64 && kind != reflect.Float32 {
+			continue
+		}
+		f := value.Field(i).Float()
+		if (math.IsNaN(f) || math.IsInf(f, 0)) && !slices.Contains(allows, field.Name) {
+			reasons = append(reasons, fmt.Sprintf("%s=%v", field.Name, f))
+		}
+	}
+	return reasons
+}
+
+// checkRule 检查单个字段的规则, 通过时返回空字符串
+func checkRule(value reflect.Value, rule CheckRule) string {
+	field := value.FieldByName(rule.Field)
+	if !field.IsValid() {
+		return fmt.Sprintf("%s: 字段不存在", rule.Field)
+	}
+	var f float64
+	switch field.Kind() {
+	case reflect.String:
+		if rule.Required && len(strings.TrimSpace(field.String())) == 0 {
+			return fmt.Sprintf("%s: 不能为空", rule.Field)
+		}
+		return ""
+	case reflect.Bool:
+		return ""
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f = float64(field.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f = float64(field.Uint())
+	case reflect.Float32, reflect.Float64:
+		f = field.Float()
+	default:
+		return ""
+	}
+	if rule.Required && f == 0 {
+		return fmt.Sprintf("%s: 不能为0", rule.Field)
+	}
+	if (rule.Min != 0 || rule.Max != 0) && (f < rule.Min || f > rule.Max) {
+		return fmt.Sprintf("%s=%v: 超出范围[%v, %v]", rule.Field, f, rule.Min, rule.Max)
+	}
+	return ""
+}
+
+// CheckFailure 校验不通过的证券代码
+type CheckFailure struct {
+	Code   string `name:"证券代码" dataframe:"code"` // 证券代码
+	Reason string `name:"原因" dataframe:"reason"` // 不通过的原因
+}
+
+// CheckReport 特征数据的校验报告
+type CheckReport struct {
+	Metric   cache.AdapterMetric // 校验指标, Count为检查数, Passed为通过数
+	Date     string              // 特征日期
+	Failures []CheckFailure      // 不通过的证券代码
+}
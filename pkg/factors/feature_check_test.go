@@ -0,0 +1,39 @@
+package factors
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestCheckFeature(t *testing.T) {
+	date := "2024-06-28"
+	klineDate := func(securityCode, date string) string { return date }
+	v := NewHistory(date, "sh600600")
+	v.OPEN, v.CLOSE, v.HIGH, v.LOW = 10, 10.5, 11, 9.8
+	v.State |= v.Kind()
+	if err := checkFeature(v, date, klineDate); err != nil {
+		t.Errorf("check failed: %v", err)
+	}
+	v.MA5 = math.NaN()
+	v.VOL = -1
+	err := checkFeature(v, date, klineDate)
+	if !errors.Is(err, ErrFeatureCheck) {
+		t.Errorf("expect ErrFeatureCheck, got %v", err)
+	}
+	fmt.Println(err)
+	// 停牌, K线日期没有对齐
+	v.MA5, v.VOL = 10, 100
+	err = checkFeature(v, date, func(securityCode, date string) string { return "2024-06-27" })
+	if err == nil {
+		t.Errorf("expect kline date check failed")
+	}
+	fmt.Println(err)
+}
+
+func TestCache1DCheck(t *testing.T) {
+	cacheDate, featureDate := "2024-07-01", "2024-06-28"
+	report := __l5History.Validate(cacheDate, featureDate)
+	fmt.Println(report.Metric.Count, report.Metric.Passed, len(report.Failures))
+}
@@ -0,0 +1,126 @@
+package factors
+
+import (
+	"context"
+
+	"gitee.com/quant1x/gox/logger"
+	"xquant/pkg/cache"
+)
+
+const (
+	cacheL5KeyChips = "chips"
+)
+
+// Chip 筹码分布特征数据
+type Chip struct {
+	cache.DataSummary `dataframe:"-"`
+	Code              string  `name:"证券代码" dataframe:"证券代码"`        // 证券代码
+	Date              string  `name:"数据日期" dataframe:"数据日期"`        // 数据日期
+	Close             float64 `name:"收盘价" dataframe:"收盘价"`          // 收盘价
+	ProfitRatio       float64 `name:"获利比例" dataframe:"获利比例"`        // 获利比例
+	AvgCost           float64 `name:"平均成本" dataframe:"平均成本"`        // 平均成本
+	Cost5             float64 `name:"5%成本" dataframe:"5%成本"`        // 5%成本
+	Cost15            float64 `name:"15%成本" dataframe:"15%成本"`      // 15%成本
+	Cost50            float64 `name:"50%成本" dataframe:"50%成本"`      // 50%成本
+	Cost85            float64 `name:"85%成本" dataframe:"85%成本"`      // 85%成本
+	Cost95            float64 `name:"95%成本" dataframe:"95%成本"`      // 95%成本
+	Concentration90   float64 `name:"90%集中度" dataframe:"90%集中度"`    // 90%筹码集中度
+	Concentration70   float64 `name:"70%集中度" dataframe:"70%集中度"`    // 70%筹码集中度
+	PeakPrice         float64 `name:"筹码峰" dataframe:"筹码峰"`          // 筹码峰
+	UpdateTime        string  `name:"更新时间" dataframe:"update_time"` // 更新时间
+	State             uint64  `name:"样本状态" dataframe:"样本状态"`        // 样本状态
+}
+
+func NewChip(date, code string) *Chip {
+	summary := __mapFeatures[FeatureChips]
+	v := Chip{
+		DataSummary: summary,
+		Date:        date,
+		Code:        code,
+	}
+	return &v
+}
+
+func (this *Chip) GetDate() string {
+	return this.Date
+}
+
+func (this *Chip) GetSecurityCode() string {
+	return this.Code
+}
+
+func (this *Chip) Factory(date string, code string) Feature {
+	v := NewChip(date, code)
+	return v
+}
+
+func (this *Chip) Init(ctx context.Context, date string) error {
+	_ = ctx
+	_ = date
+	return nil
+}
+
+// DependOn 实现 cache.Depend 接口, 换手率衰减依赖基本面的流通股本
+func (this *Chip) DependOn() []cache.Kind {
+	return []cache.Kind{FeatureF10}
+}
+
+func (this *Chip) FromHistory(history History) Feature {
+	_ = history
+	return this
+}
+
+func (this *Chip) Update(code, cacheDate, featureDate string, complete bool) {
+	chips := CheckoutChips(code, featureDate)
+	if chips == nil {
+		logger.Errorf("code[%s, %s] chips not found", code, featureDate)
+		return
+	}
+	price := 0.00
+	klines := checkoutKLines(FeatureChips, code, featureDate)
+	if n := len(klines); n > 0 {
+		price = klines[n-1].Close
+	}
+	stats := ComputeChipStats(chips.GetDist(), price)
+	this.Date = featureDate
+	this.Close = price
+	this.ProfitRatio = stats.ProfitRatio
+	this.AvgCost = stats.AvgCost
+	this.Cost5 = stats.Cost5
+	this.Cost15 = stats.Cost15
+	this.Cost50 = stats.Cost50
+	this.Cost85 = stats.Cost85
+	this.Cost95 = stats.Cost95
+	this.Concentration90 = stats.Concentration90
+	this.Concentration70 = stats.Concentration70
+	this.PeakPrice = stats.PeakPrice
+	// 样本状态
+	if stats.AvgCost > 0 {
+		this.State |= this.Kind()
+	}
+	this.UpdateTime = GetTimestamp()
+	_ = cacheDate
+	_ = complete
+}
+
+func (this *Chip) Repair(code, cacheDate, featureDate string, complete bool) {
+	this.Update(code, cacheDate, featureDate, complete)
+}
+
+func (this *Chip) Increase(snapshot QuoteSnapshot) Feature {
+	_ = snapshot
+	return this
+}
+
+// ValidateSample 验证样本数据
+func (this *Chip) ValidateSample() error {
+	if this.State > 0 {
+		return nil
+	}
+	return ErrInvalidFeatureSample
+}
+
+// Check 实现 cache.Validator 接口, 按特征的校验策略检查数据
+func (this *Chip) Check(featureDate string) error {
+	return CheckFeature(this, featureDate)
+}
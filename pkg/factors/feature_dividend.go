@@ -0,0 +1,144 @@
+package factors
+
+import (
+	"context"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/num"
+	"xquant/pkg/cache"
+)
+
+const (
+	cacheL5KeyDividend = "dividend"
+)
+
+// Dividend 除权除息特征
+//
+//	特征在缓存日期使用, 下一个除权除息日和ExDays都相对缓存日期计算, 没有已公布的分红送转时ExDays为-1.
+//	股息率按特征日期的实际收盘价计算, 不受之后除权除息的前复权影响
+type Dividend struct {
+	cache.DataSummary `dataframe:"-"`
+	Date              string  `name:"日期" dataframe:"date"`                   // 数据日期
+	Code              string  `name:"证券代码" dataframe:"code"`                 // 证券代码
+	RecordDate        string  `name:"股权登记日" dataframe:"record_date"`         // 下一个除权除息的股权登记日
+	ExDate            string  `name:"除权除息日" dataframe:"ex_date"`             // 下一个除权除息日
+	ExDays            int     `name:"距除权除息交易日数" dataframe:"ex_days"`         // 缓存日期距离下一个除权除息日的交易日数, 当日除权为0, 股权登记日为1
+	CashDividend      float64 `name:"每股派息" dataframe:"cash_dividend"`        // 下一个除权除息日的每股现金分红
+	BonusShares       float64 `name:"每股送转" dataframe:"bonus_shares"`         // 下一个除权除息日的每股送转股
+	Price             float64 `name:"收盘价" dataframe:"price"`                 // 计算股息率的收盘价
+	DividendYield     float64 `name:"股息率%" dataframe:"dividend_yield"`       // 下一次分红的股息率
+	TrailingDividend  float64 `name:"近一年每股派息" dataframe:"trailing_dividend"` // 近一年已实施的每股现金分红
+	TrailingYield     float64 `name:"滚动股息率%" dataframe:"trailing_yield"`     // 近一年的股息率
+	UpdateTime        string  `name:"更新时间" dataframe:"update_time"`          // 更新时间
+	State             uint64  `name:"样本状态" dataframe:"样本状态"`                 // 样本状态
+}
+
+func NewDividend(date, code string) *Dividend {
+	summary := __mapFeatures[FeatureDividend]
+	v := Dividend{
+		DataSummary: summary,
+		Date:        date,
+		Code:        code,
+		ExDays:      -1,
+	}
+	return &v
+}
+
+func (this *Dividend) GetDate() string {
+	return this.Date
+}
+
+func (this *Dividend) GetSecurityCode() string {
+	return this.Code
+}
+
+func (this *Dividend) Factory(date string, code string) Feature {
+	v := NewDividend(date, code)
+	return v
+}
+
+func (this *Dividend) Init(ctx context.Context, date string) error {
+	_ = ctx
+	_ = date
+	return nil
+}
+
+func (this *Dividend) FromHistory(history History) Feature {
+	_ = history
+	return this
+}
+
+func (this *Dividend) Update(code, cacheDate, featureDate string, complete bool) {
+	securityCode := exchange.CorrectSecurityCode(code)
+	if !exchange.AssertStockBySecurityCode(securityCode) {
+		return
+	}
+	tradeDate := exchange.FixTradeDate(featureDate)
+	this.Date = tradeDate
+	events := DividendEvents(securityCode)
+	price := 0.00
+	// 不复权的K线, 收盘价就是特征日期的实际价格
+	klines := checkoutKLines(FeatureDividend, securityCode, tradeDate)
+	if n := len(klines); n > 0 && klines[n-1].Date == tradeDate {
+		price = klines[n-1].Close
+	}
+	this.Compute(events, price, exchange.FixTradeDate(cacheDate))
+	// 样本状态
+	this.State |= this.Kind()
+	this.UpdateTime = GetTimestamp()
+	_ = complete
+}
+
+// Compute 计算下一个除权除息日和股息率, price为特征日期的收盘价, cacheDate为使用特征的交易日
+func (this *Dividend) Compute(events []DividendEvent, price float64, cacheDate string) {
+	this.RecordDate = ""
+	this.ExDate = ""
+	this.ExDays = -1
+	this.CashDividend = 0
+	this.BonusShares = 0
+	this.DividendYield = 0
+	this.Price = num.Decimal(price)
+	this.TrailingDividend = num.Decimal(TrailingCashDividend(events, this.Date))
+	this.TrailingYield = 0
+	if price > 0 {
+		this.TrailingYield = num.Decimal(this.TrailingDividend / price * 100)
+	}
+	// 特征日期当天除权的已经实施, 缓存日期才是买入的交易日
+	next, ok := NextDividend(events, cacheDate)
+	if !ok {
+		return
+	}
+	this.RecordDate = next.RecordDate
+	this.ExDate = next.ExDate
+	this.ExDays = 0
+	if days := len(exchange.TradingDateRange(cacheDate, next.ExDate)) - 1; days > 0 {
+		this.ExDays = days
+	}
+	this.CashDividend = next.CashDividend
+	this.BonusShares = next.BonusShares
+	if price > 0 {
+		this.DividendYield = num.Decimal(next.CashDividend / price * 100)
+	}
+}
+
+func (this *Dividend) Repair(code, cacheDate, featureDate string, complete bool) {
+	this.Update(code, cacheDate, featureDate, complete)
+}
+
+func (this *Dividend) Increase(snapshot QuoteSnapshot) Feature {
+	_ = snapshot
+	return this
+}
+
+// ValidateSample 验证样本数据
+func (this *Dividend) ValidateSample() error {
+	if this.State > 0 {
+		return nil
+	}
+	return ErrInvalidFeatureSample
+}
+
+// Check 实现 cache.Validator 接口, 按特征的校验策略检查数据
+func (this *Dividend) Check(featureDate string) error {
+	return CheckFeature(this, featureDate)
+}
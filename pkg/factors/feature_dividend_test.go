@@ -0,0 +1,67 @@
+package factors
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"gitee.com/quant1x/exchange"
+	"xquant/pkg/cache"
+)
+
+func TestDividendEvent_AdjustPosition(t *testing.T) {
+	// 10派5元送3股
+	event := DividendEvent{Code: "sh600000", RecordDate: "2024-06-18", ExDate: "2024-06-19", CashDividend: 0.5, BonusShares: 0.3}
+	volume, cost, cash := event.AdjustPosition(1000, 13.5)
+	if volume != 1300 || cash != 500 || math.Abs(cost-10) > 1e-9 {
+		t.Errorf("volume=%d, cost=%f, cash=%f", volume, cost, cash)
+	}
+	if v := event.AdjustPrice(13.5); math.Abs(v-10) > 1e-9 {
+		t.Errorf("adjust price=%f", v)
+	}
+	// 不足1股的送转舍去
+	volume, _, _ = event.AdjustPosition(105, 13.5)
+	if volume != 136 {
+		t.Errorf("volume=%d", volume)
+	}
+}
+
+func TestDividendCalendar(t *testing.T) {
+	list := []DividendEvent{
+		{ExDate: "2023-06-16", CashDividend: 0.3},
+		{ExDate: "2024-01-10", CashDividend: 0.2, BonusShares: 0.5},
+		{ExDate: "2024-06-19", CashDividend: 0.5, BonusShares: 0.3},
+	}
+	v, ok := NextDividend(list, "2024-01-11")
+	if !ok || v.ExDate != "2024-06-19" {
+		t.Errorf("next=%+v", v)
+	}
+	if _, ok = DividendOnDate(list, "2024-06-18"); ok {
+		t.Errorf("2024-06-18 should not be ex-date")
+	}
+	if v := TrailingCashDividend(list, "2024-06-19"); math.Abs(v-0.7) > 1e-9 {
+		t.Errorf("trailing=%f", v)
+	}
+	if v := ShareFactor(list, "2024-01-09", "2024-06-19"); math.Abs(v-1.95) > 1e-9 {
+		t.Errorf("factor=%f", v)
+	}
+	// 2024-01-09的收盘价, 按两次除权前复权
+	price := 10.00
+	want := ((price-0.2)/1.5 - 0.5) / 1.3
+	if v := list[2].AdjustPrice(list[1].AdjustPrice(price)); math.Abs(v-want) > 1e-9 {
+		t.Errorf("adjusted=%f", v)
+	}
+}
+
+func TestDividend_basic(t *testing.T) {
+	code := "600000"
+	date := "2024-07-10"
+	cacheDate, featureDate := cache.CorrectDate(date)
+	code = exchange.CorrectSecurityCode(code)
+	v := NewDividend(featureDate, code)
+	v.Update(code, cacheDate, featureDate, true)
+	fmt.Printf("%+v\n", *v)
+	if err := v.Check(featureDate); err != nil {
+		fmt.Println(err)
+	}
+}
@@ -186,6 +186,11 @@ func (this *F10) ValidateSample() error {
 	return ErrInvalidFeatureSample
 }
 
+// Check 实现 cache.Validator 接口, 按特征的校验策略检查数据
+func (this *F10) Check(featureDate string) error {
+	return CheckFeature(this, featureDate)
+}
+
 func (this *F10) TurnZ(v any) float64 {
 	freeCapital := this.FreeCapital
 	if freeCapital == 0 {
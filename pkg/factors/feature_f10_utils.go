@@ -8,7 +8,6 @@ import (
 	"gitee.com/quant1x/num"
 	"strconv"
 	"time"
-	"xquant/pkg/datasource/base"
 )
 
 // 获取财务数据
@@ -47,7 +46,7 @@ func getFinanceInfo(securityCode, featureDate string) (capital, totalCapital flo
 
 func getIpoDate(securityCode, featureDate string) (ipoDate string) {
 	// IPO日期不存在, 从日K线第一条记录获取
-	kls := base.CheckoutKLines(securityCode, featureDate)
+	kls := checkoutKLines(FeatureF10, securityCode, featureDate)
 	if len(kls) > 0 {
 		ipoDate = kls[0].Date
 	}
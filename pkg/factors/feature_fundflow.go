@@ -0,0 +1,254 @@
+package factors
+
+import (
+	"cmp"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/gox/api"
+	"gitee.com/quant1x/gox/logger"
+	"xquant/pkg/cache"
+	"xquant/pkg/datasource/base"
+	"xquant/pkg/datasource/east_money"
+)
+
+const (
+	cacheL5KeyFundFlow = "fundflow"
+)
+
+var (
+	// 资金流向缓存文件按证券代码加锁, Misc和FundFlow特征会同时更新同一个文件
+	__fundFlowLocks sync.Map
+)
+
+// FundFlow 资金流向特征
+//
+//	单位为元, 主力=超大单+大单. 东方财富没有数据时用通达信F10的资金流向补充, 通达信没有中单和小单
+type FundFlow struct {
+	cache.DataSummary `dataframe:"-"`
+	Date              string  `name:"日期" dataframe:"date"`                    // 数据日期
+	Code              string  `name:"证券代码" dataframe:"code"`                  // 证券代码
+	Main              float64 `name:"主力净流入" dataframe:"main"`                 // 主力净流入
+	MainRatio         float64 `name:"主力净占比%" dataframe:"main_ratio"`          // 主力净流入占成交额的比例
+	SuperLarge        float64 `name:"超大单净流入" dataframe:"super_large"`         // 超大单净流入
+	Large             float64 `name:"大单净流入" dataframe:"large"`                // 大单净流入
+	Medium            float64 `name:"中单净流入" dataframe:"medium"`               // 中单净流入
+	Small             float64 `name:"小单净流入" dataframe:"small"`                // 小单净流入
+	Main3             float64 `name:"3日主力净流入" dataframe:"main3"`              // 3日主力净流入
+	Main5             float64 `name:"5日主力净流入" dataframe:"main5"`              // 5日主力净流入
+	Main10            float64 `name:"10日主力净流入" dataframe:"main10"`            // 10日主力净流入
+	SuperLarge3       float64 `name:"3日超大单净流入" dataframe:"super_large3"`      // 3日超大单净流入
+	SuperLarge5       float64 `name:"5日超大单净流入" dataframe:"super_large5"`      // 5日超大单净流入
+	SuperLarge10      float64 `name:"10日超大单净流入" dataframe:"super_large10"`    // 10日超大单净流入
+	Large3            float64 `name:"3日大单净流入" dataframe:"large3"`             // 3日大单净流入
+	Large5            float64 `name:"5日大单净流入" dataframe:"large5"`             // 5日大单净流入
+	Large10           float64 `name:"10日大单净流入" dataframe:"large10"`           // 10日大单净流入
+	Medium3           float64 `name:"3日中单净流入" dataframe:"medium3"`            // 3日中单净流入
+	Medium5           float64 `name:"5日中单净流入" dataframe:"medium5"`            // 5日中单净流入
+	Medium10          float64 `name:"10日中单净流入" dataframe:"medium10"`          // 10日中单净流入
+	Small3            float64 `name:"3日小单净流入" dataframe:"small3"`             // 3日小单净流入
+	Small5            float64 `name:"5日小单净流入" dataframe:"small5"`             // 5日小单净流入
+	Small10           float64 `name:"10日小单净流入" dataframe:"small10"`           // 10日小单净流入
+	MainInflowDays    int     `name:"主力连续净流入天数" dataframe:"main_inflow_days"` // 截至当日主力连续净流入的天数
+	UpdateTime        string  `name:"更新时间" dataframe:"update_time"`           // 更新时间
+	State             uint64  `name:"样本状态" dataframe:"样本状态"`                  // 样本状态
+}
+
+func NewFundFlow(date, code string) *FundFlow {
+	summary := __mapFeatures[FeatureFundFlow]
+	v := FundFlow{
+		DataSummary: summary,
+		Date:        date,
+		Code:        code,
+	}
+	return &v
+}
+
+func (this *FundFlow) GetDate() string {
+	return this.Date
+}
+
+func (this *FundFlow) GetSecurityCode() string {
+	return this.Code
+}
+
+func (this *FundFlow) Factory(date string, code string) Feature {
+	v := NewFundFlow(date, code)
+	return v
+}
+
+func (this *FundFlow) Init(ctx context.Context, date string) error {
+	_ = ctx
+	_ = date
+	return nil
+}
+
+func (this *FundFlow) FromHistory(history History) Feature {
+	_ = history
+	return this
+}
+
+func (this *FundFlow) Update(code, cacheDate, featureDate string, complete bool) {
+	securityCode := exchange.CorrectSecurityCode(code)
+	if !exchange.AssertStockBySecurityCode(securityCode) {
+		return
+	}
+	tradeDate := exchange.FixTradeDate(featureDate)
+	list := CheckoutFundFlow(securityCode, tradeDate)
+	if !this.compute(list, tradeDate) {
+		logger.Errorf("code[%s, %s] fund flow not found", code, featureDate)
+		return
+	}
+	// 样本状态
+	this.State |= this.Kind()
+	this.UpdateTime = GetTimestamp()
+	_ = cacheDate
+	_ = complete
+}
+
+// compute 计算截至date的资金流向, list按日期升序, date当日没有数据时返回false
+func (this *FundFlow) compute(list []east_money.FundFlow, date string) bool {
+	n, found := slices.BinarySearchFunc(list, date, func(v east_money.FundFlow, date string) int {
+		return cmp.Compare(v.Date, date)
+	})
+	if !found {
+		return false
+	}
+	list = list[:n+1]
+	last := list[n]
+	this.Date = last.Date
+	this.Main = last.Main
+	this.MainRatio = last.MainRatio
+	this.SuperLarge = last.SuperLarge
+	this.Large = last.Large
+	this.Medium = last.Medium
+	this.Small = last.Small
+	this.Main3, this.SuperLarge3, this.Large3, this.Medium3, this.Small3 = sumFundFlow(list, 3)
+	this.Main5, this.SuperLarge5, this.Large5, this.Medium5, this.Small5 = sumFundFlow(list, 5)
+	this.Main10, this.SuperLarge10, this.Large10, this.Medium10, this.Small10 = sumFundFlow(list, 10)
+	this.MainInflowDays = 0
+	for i := n; i >= 0 && list[i].Main > 0; i-- {
+		this.MainInflowDays++
+	}
+	return true
+}
+
+// sumFundFlow 最近days个交易日的净流入合计, 数据不足days时按已有的数据合计
+func sumFundFlow(list []east_money.FundFlow, days int) (main, superLarge, large, medium, small float64) {
+	if len(list) > days {
+		list = list[len(list)-days:]
+	}
+	for _, v := range list {
+		main += v.Main
+		superLarge += v.SuperLarge
+		large += v.Large
+		medium += v.Medium
+		small += v.Small
+	}
+	return
+}
+
+func (this *FundFlow) Repair(code, cacheDate, featureDate string, complete bool) {
+	this.Update(code, cacheDate, featureDate, complete)
+}
+
+func (this *FundFlow) Increase(snapshot QuoteSnapshot) Feature {
+	_ = snapshot
+	return this
+}
+
+// ValidateSample 验证样本数据
+func (this *FundFlow) ValidateSample() error {
+	if this.State > 0 {
+		return nil
+	}
+	return ErrInvalidFeatureSample
+}
+
+// Check 实现 cache.Validator 接口, 按特征的校验策略检查数据
+func (this *FundFlow) Check(featureDate string) error {
+	return CheckFeature(this, featureDate)
+}
+
+// CheckoutFundFlow 获取个股截至date的资金流向, 按日期升序
+//
+//	优先读取本地缓存, 缓存中没有date的数据时从东方财富增量拉取, 东方财富没有数据时使用通达信F10的资金流向
+func CheckoutFundFlow(securityCode, date string) []east_money.FundFlow {
+	securityCode = exchange.CorrectSecurityCode(securityCode)
+	date = exchange.FixTradeDate(date)
+	mutex, _ := __fundFlowLocks.LoadOrStore(securityCode, &sync.Mutex{})
+	mutex.(*sync.Mutex).Lock()
+	defer mutex.(*sync.Mutex).Unlock()
+
+	filename := cache.FundFlowFilename(securityCode)
+	var cacheList []east_money.FundFlow
+	_ = api.CsvToSlices(filename, &cacheList)
+	cacheLength := len(cacheList)
+	if cacheLength > 0 && cacheList[cacheLength-1].Date >= date {
+		return cacheList
+	}
+	// 最后一条缓存可能是盘中的数据, 重新拉取
+	beginDate := exchange.MARKET_CH_FIRST_LISTTIME
+	list := cacheList
+	if cacheLength > 0 {
+		beginDate = cacheList[cacheLength-1].Date
+		list = cacheList[: cacheLength-1 : cacheLength-1]
+	}
+	newList := east_money.IndividualStocksFundFlow(securityCode, beginDate)
+	if len(newList) == 0 {
+		// 通达信的数据只补充到返回值, 不写入缓存
+		newList = tdxFundFlow(securityCode, beginDate)
+		if len(newList) == 0 {
+			return cacheList
+		}
+		return append(list, newList...)
+	}
+	list = append(list, newList...)
+	if err := os.MkdirAll(filepath.Dir(filename), os.ModePerm); err == nil {
+		_ = api.SlicesToCsv(filename, list)
+	}
+	return list
+}
+
+// tdxFundFlow 通达信F10的资金流向, 只有最近20个交易日的主力、超大单和大单
+func tdxFundFlow(securityCode, beginDate string) []east_money.FundFlow {
+	df := base.FundFlow(securityCode)
+	if df.Nrow() == 0 {
+		return nil
+	}
+	var (
+		DATE            = df.Col(base.TdxFieldsFundFlow[0]).Strings()
+		MAIN            = df.Col(base.TdxFieldsFundFlow[1]).Float64s()
+		MAINRATIO       = df.Col(base.TdxFieldsFundFlow[2]).Float64s()
+		SUPERLARGE      = df.Col(base.TdxFieldsFundFlow[3]).Float64s()
+		SUPERLARGERATIO = df.Col(base.TdxFieldsFundFlow[4]).Float64s()
+		LARGE           = df.Col(base.TdxFieldsFundFlow[5]).Float64s()
+		LARGERATIO      = df.Col(base.TdxFieldsFundFlow[6]).Float64s()
+	)
+	var list []east_money.FundFlow
+	for i, date := range DATE {
+		date = exchange.FixTradeDate(date)
+		if date < beginDate {
+			continue
+		}
+		list = append(list, east_money.FundFlow{
+			Date:            date,
+			Code:            securityCode,
+			Main:            MAIN[i],
+			MainRatio:       MAINRATIO[i],
+			SuperLarge:      SUPERLARGE[i],
+			SuperLargeRatio: SUPERLARGERATIO[i],
+			Large:           LARGE[i],
+			LargeRatio:      LARGERATIO[i],
+		})
+	}
+	// 通达信的资金流向按日期降序
+	slices.SortFunc(list, func(a, b east_money.FundFlow) int {
+		return cmp.Compare(a.Date, b.Date)
+	})
+	return list
+}
@@ -0,0 +1,48 @@
+package factors
+
+import (
+	"fmt"
+	"testing"
+
+	"gitee.com/quant1x/exchange"
+	"xquant/pkg/cache"
+	"xquant/pkg/datasource/east_money"
+)
+
+func TestFundFlow_compute(t *testing.T) {
+	dates := []string{"2024-06-17", "2024-06-18", "2024-06-19", "2024-06-20", "2024-06-21", "2024-06-24"}
+	mains := []float64{-300, 100, -50, 200, 300, 400}
+	var list []east_money.FundFlow
+	for i, date := range dates {
+		list = append(list, east_money.FundFlow{Date: date, Main: mains[i], SuperLarge: mains[i] / 2, Small: -mains[i]})
+	}
+	v := NewFundFlow("2024-06-21", "sh600600")
+	if !v.compute(list, "2024-06-21") {
+		t.Fatal("2024-06-21 not found")
+	}
+	if v.Main != 300 || v.Main3 != 450 || v.Main5 != 250 || v.Main10 != 250 {
+		t.Errorf("main=%f, main3=%f, main5=%f, main10=%f", v.Main, v.Main3, v.Main5, v.Main10)
+	}
+	if v.SuperLarge3 != 225 || v.Small5 != -250 {
+		t.Errorf("super_large3=%f, small5=%f", v.SuperLarge3, v.Small5)
+	}
+	if v.MainInflowDays != 2 {
+		t.Errorf("main_inflow_days=%d", v.MainInflowDays)
+	}
+	if v.compute(list, "2024-06-22") {
+		t.Error("2024-06-22 is not a trading day")
+	}
+}
+
+func TestFundFlow_basic(t *testing.T) {
+	code := "600600"
+	date := "2024-06-24"
+	cacheDate, featureDate := cache.CorrectDate(date)
+	code = exchange.CorrectSecurityCode(code)
+	v := NewFundFlow(featureDate, code)
+	v.Update(code, cacheDate, featureDate, true)
+	fmt.Printf("%+v\n", *v)
+	if err := v.Check(featureDate); err != nil {
+		fmt.Println(err)
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"gitee.com/quant1x/pandas"
 	. "gitee.com/quant1x/pandas/formula"
 	"xquant/pkg/cache"
-	"xquant/pkg/datasource/base"
 	"xquant/pkg/utils"
 )
 
@@ -95,7 +94,7 @@ func (this *History) Update(code, cacheDate, featureDate string, complete bool)
 func (this *History) Repair(code, cacheDate, featureDate string, complete bool) {
 	securityCode := exchange.CorrectSecurityCode(this.Code)
 	tradeDate := exchange.FixTradeDate(featureDate)
-	klines := base.CheckoutKLines(securityCode, tradeDate)
+	klines := checkoutKLines(FeatureHistory, securityCode, tradeDate)
 	if len(klines) < cache.KLineMin {
 		return
 	}
@@ -206,6 +205,11 @@ func (this *History) ValidateSample() error {
 	return ErrInvalidFeatureSample
 }
 
+// Check 实现 cache.Validator 接口, 按特征的校验策略检查数据
+func (this *History) Check(featureDate string) error {
+	return CheckFeature(this, featureDate)
+}
+
 // GetMV5 前5日分钟均量
 func (this *History) GetMV5() float64 {
 	//minutes := trading.Minutes(this.GetDate())
@@ -3,11 +3,9 @@ package factors
 import (
 	"context"
 	"gitee.com/quant1x/exchange"
-	"gitee.com/quant1x/num"
 	"gitee.com/quant1x/pandas"
 	. "gitee.com/quant1x/pandas/formula"
 	"xquant/pkg/cache"
-	"xquant/pkg/datasource/base"
 	"xquant/pkg/utils"
 )
 
@@ -71,6 +69,11 @@ func (this *InvestmentSentimentMaster) GetSecurityCode() string {
 	return this.Code
 }
 
+// DependOn 实现 cache.Depend 接口, 涨停价依赖基本面数据的证券名称和上市日期
+func (this *InvestmentSentimentMaster) DependOn() []cache.Kind {
+	return []cache.Kind{FeatureF10}
+}
+
 func (this *InvestmentSentimentMaster) Init(ctx context.Context, date string) error {
 	_ = ctx
 	_ = date
@@ -82,7 +85,7 @@ func (this *InvestmentSentimentMaster) Update(code, cacheDate, featureDate strin
 	this.Date = exchange.FixTradeDate(cacheDate)
 	this.Code = securityCode
 	tradeDate := exchange.FixTradeDate(featureDate)
-	klines := base.CheckoutKLines(securityCode, tradeDate)
+	klines := checkoutKLines(FeatureInvestmentSentimentMaster, securityCode, tradeDate)
 	if len(klines) < cache.KLineMin {
 		return
 	}
@@ -101,12 +104,11 @@ func (this *InvestmentSentimentMaster) Update(code, cacheDate, featureDate strin
 	R1CLOSE := REF(CLOSE, 1)
 	//CST:=NOT(NAMELIKE('S') OR NAMELIKE('*S')) AND VOL>1;
 	//ZDF:=IFF(INBLOCK('创业板'), 0.2, IFF(INBLOCK('科创板'),0.2, IFF(INBLOCK('ST板块'), 0.05, IFF(INBLOCK('北证A股'),0.3,0.1))));
-	ZDF := exchange.MarketLimit(securityCode)
+	// 涨跌幅按板块、风险警示和上市天数逐日确定, 不设涨跌幅限制的交易日涨停价为NaN
+	limitUps, _ := PriceLimitSeries(securityCode, klines)
+	ZDF := CheckoutPriceLimit(securityCode, tradeDate, klines[len(klines)-2].Close).UpRatio
 	//ZTJ:=ZTPRICE(R1CLOSE,ZDF);
-	ZTJ := R1CLOSE.Mul(1.00+ZDF).Apply2(func(idx int, v any) any {
-		f := v.(float64)
-		return num.Decimal(f)
-	}, true)
+	ZTJ := pandas.ToSeries(limitUps...)
 	//CZT:=CLOSE=ZTJ;
 	CZT := CLOSE.Gte(ZTJ)
 	//BN:COUNT(CZT,PN);
@@ -215,3 +217,8 @@ func (this *InvestmentSentimentMaster) ValidateSample() error {
 	}
 	return ErrInvalidFeatureSample
 }
+
+// Check 实现 cache.Validator 接口, 按特征的校验策略检查数据
+func (this *InvestmentSentimentMaster) Check(featureDate string) error {
+	return CheckFeature(this, featureDate)
+}
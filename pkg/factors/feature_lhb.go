@@ -0,0 +1,163 @@
+package factors
+
+import (
+	"context"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/gox/logger"
+	"gitee.com/quant1x/num"
+	"xquant/pkg/cache"
+)
+
+const (
+	cacheL5KeyBillBoard = "lhb"
+)
+
+// BillBoard 龙虎榜特征
+//
+//	个股当日未上榜时, 除OnList外的字段均为0
+type BillBoard struct {
+	cache.DataSummary `dataframe:"-"`
+	Date              string  `name:"日期" dataframe:"date"`                        // 数据日期
+	Code              string  `name:"证券代码" dataframe:"code"`                      // 证券代码
+	OnList            bool    `name:"是否上榜" dataframe:"on_list"`                   // 当日是否上榜
+	SeatNum           int     `name:"席位数" dataframe:"seat_num"`                   // 上榜席位数
+	BuyAmount         float64 `name:"买入金额" dataframe:"buy_amount"`                // 全部席位买入金额
+	SellAmount        float64 `name:"卖出金额" dataframe:"sell_amount"`               // 全部席位卖出金额
+	NetAmount         float64 `name:"净买入" dataframe:"net_amount"`                 // 全部席位净买入金额
+	NetRatio          float64 `name:"净买入占比%" dataframe:"net_ratio"`               // 净买入金额/当日成交金额
+	InstitutionNum    int     `name:"机构席位数" dataframe:"institution_num"`          // 机构专用席位数
+	InstitutionBuy    float64 `name:"机构买入" dataframe:"institution_buy"`           // 机构买入金额
+	InstitutionSell   float64 `name:"机构卖出" dataframe:"institution_sell"`          // 机构卖出金额
+	InstitutionNet    float64 `name:"机构净买入" dataframe:"institution_net"`          // 机构净买入金额
+	ConnectNet        float64 `name:"沪深股通净买入" dataframe:"connect_net"`            // 沪股通和深股通专用席位的净买入金额
+	HotMoneyNum       int     `name:"游资席位数" dataframe:"hot_money_num"`            // 知名游资席位数
+	HotMoneyBuy       float64 `name:"游资买入" dataframe:"hot_money_buy"`             // 知名游资买入金额
+	HotMoneyNet       float64 `name:"游资净买入" dataframe:"hot_money_net"`            // 知名游资净买入金额
+	HotMoney          bool    `name:"游资参与" dataframe:"hot_money"`                 // 是否有知名游资参与
+	RiseProbability3D float64 `name:"上榜后3日上涨概率%" dataframe:"rise_probability_3d"` // 买入席位上榜后3日上涨概率, 按买入金额加权
+	UpdateTime        string  `name:"更新时间" dataframe:"update_time"`               // 更新时间
+	State             uint64  `name:"样本状态" dataframe:"样本状态"`                      // 样本状态
+}
+
+func NewBillBoard(date, code string) *BillBoard {
+	summary := __mapFeatures[FeatureBillBoard]
+	v := BillBoard{
+		DataSummary: summary,
+		Date:        date,
+		Code:        code,
+	}
+	return &v
+}
+
+func (this *BillBoard) GetDate() string {
+	return this.Date
+}
+
+func (this *BillBoard) GetSecurityCode() string {
+	return this.Code
+}
+
+func (this *BillBoard) Factory(date string, code string) Feature {
+	v := NewBillBoard(date, code)
+	return v
+}
+
+func (this *BillBoard) Init(ctx context.Context, date string) error {
+	_ = ctx
+	_ = date
+	return nil
+}
+
+func (this *BillBoard) FromHistory(history History) Feature {
+	_ = history
+	return this
+}
+
+func (this *BillBoard) Update(code, cacheDate, featureDate string, complete bool) {
+	securityCode := exchange.CorrectSecurityCode(code)
+	tradeDate := exchange.FixTradeDate(featureDate)
+	seats, err := GetBillBoardSeats(securityCode, tradeDate)
+	if err != nil {
+		// 拉取失败时不能当作未上榜, 样本无效
+		logger.Errorf("code[%s, %s] 龙虎榜拉取失败: %+v", code, featureDate, err)
+		this.State &^= this.Kind()
+		return
+	}
+	this.Date = tradeDate
+	this.aggregate(seats)
+	// 样本状态
+	this.State |= this.Kind()
+	this.UpdateTime = GetTimestamp()
+	_ = cacheDate
+	_ = complete
+}
+
+// aggregate 汇总个股的席位明细
+func (this *BillBoard) aggregate(seats []BillBoardSeat) {
+	*this = BillBoard{
+		DataSummary: this.DataSummary,
+		Date:        this.Date,
+		Code:        this.Code,
+		State:       this.State,
+	}
+	this.OnList = len(seats) > 0
+	this.SeatNum = len(seats)
+	var (
+		amount      float64
+		probability float64
+		weight      float64
+	)
+	for _, v := range seats {
+		this.BuyAmount += v.Buy
+		this.SellAmount += v.Sell
+		amount = max(amount, v.Amount)
+		switch v.SeatType {
+		case SeatInstitution:
+			this.InstitutionNum++
+			this.InstitutionBuy += v.Buy
+			this.InstitutionSell += v.Sell
+		case SeatStockConnect:
+			this.ConnectNet += v.Buy - v.Sell
+		case SeatHotMoney:
+			this.HotMoneyNum++
+			this.HotMoneyBuy += v.Buy
+			this.HotMoneyNet += v.Buy - v.Sell
+		}
+		if v.Buy > 0 {
+			probability += v.RiseProbability3D * v.Buy
+			weight += v.Buy
+		}
+	}
+	this.NetAmount = this.BuyAmount - this.SellAmount
+	this.InstitutionNet = this.InstitutionBuy - this.InstitutionSell
+	this.HotMoney = this.HotMoneyNum > 0
+	if amount > 0 {
+		this.NetRatio = num.Decimal(this.NetAmount / amount * 100)
+	}
+	if weight > 0 {
+		this.RiseProbability3D = num.Decimal(probability / weight)
+	}
+}
+
+func (this *BillBoard) Repair(code, cacheDate, featureDate string, complete bool) {
+	this.Update(code, cacheDate, featureDate, complete)
+}
+
+func (this *BillBoard) Increase(snapshot QuoteSnapshot) Feature {
+	_ = snapshot
+	return this
+}
+
+// ValidateSample 验证样本数据
+func (this *BillBoard) ValidateSample() error {
+	if this.State > 0 {
+		return nil
+	}
+	return ErrInvalidFeatureSample
+}
+
+// Check 实现 cache.Validator 接口, 按特征的校验策略检查数据
+func (this *BillBoard) Check(featureDate string) error {
+	return CheckFeature(this, featureDate)
+}
@@ -0,0 +1,70 @@
+package factors
+
+import (
+	"fmt"
+	"testing"
+
+	"gitee.com/quant1x/exchange"
+	"xquant/pkg/cache"
+)
+
+func TestClassifySeat(t *testing.T) {
+	hotMoneySeats := []string{"上海溧阳路", "杭州上塘路"}
+	tests := []struct {
+		name string
+		want SeatType
+	}{
+		{"机构专用", SeatInstitution},
+		{"沪股通专用", SeatStockConnect},
+		{"深股通专用", SeatStockConnect},
+		{"中国银河证券股份有限公司上海溧阳路证券营业部", SeatHotMoney},
+		{"财通证券股份有限公司杭州上塘路证券营业部", SeatHotMoney},
+		{"东方证券股份有限公司上海浦东新区源深路证券营业部", SeatOrdinary},
+	}
+	for _, tt := range tests {
+		if got := ClassifySeat(tt.name, hotMoneySeats); got != tt.want {
+			t.Errorf("ClassifySeat(%s) = %s, want %s", tt.name, SeatTypeName(got), SeatTypeName(tt.want))
+		}
+	}
+}
+
+func TestBillBoard_aggregate(t *testing.T) {
+	seats := []BillBoardSeat{
+		{SeatType: SeatInstitution, Buy: 3000, Sell: 1000, RiseProbability3D: 60, Amount: 100000},
+		{SeatType: SeatStockConnect, Buy: 500, Sell: 1500, Amount: 100000},
+		{SeatType: SeatHotMoney, Buy: 1000, RiseProbability3D: 40, Amount: 100000},
+		{SeatType: SeatOrdinary, Sell: 2000, RiseProbability3D: 90, Amount: 100000},
+	}
+	v := NewBillBoard("2024-07-16", "sh600611")
+	v.aggregate(seats)
+	if !v.OnList || v.SeatNum != 4 {
+		t.Fatalf("on_list=%t, seat_num=%d", v.OnList, v.SeatNum)
+	}
+	if v.InstitutionNet != 2000 || v.ConnectNet != -1000 || v.HotMoneyNet != 1000 || !v.HotMoney {
+		t.Errorf("institution_net=%f, connect_net=%f, hot_money_net=%f", v.InstitutionNet, v.ConnectNet, v.HotMoneyNet)
+	}
+	if v.NetAmount != 0 || v.NetRatio != 0 {
+		t.Errorf("net_amount=%f, net_ratio=%f", v.NetAmount, v.NetRatio)
+	}
+	// 只按买入席位的买入金额加权: (60*3000+0*500+40*1000)/4500
+	if v.RiseProbability3D != 48.89 {
+		t.Errorf("rise_probability_3d=%f", v.RiseProbability3D)
+	}
+	v.aggregate(nil)
+	if v.OnList || v.BuyAmount != 0 || v.HotMoney {
+		t.Errorf("not on list: %+v", *v)
+	}
+}
+
+func TestBillBoard_basic(t *testing.T) {
+	code := "600611"
+	date := "2024-07-16"
+	cacheDate, featureDate := cache.CorrectDate(date)
+	code = exchange.CorrectSecurityCode(code)
+	v := NewBillBoard(featureDate, code)
+	v.Update(code, cacheDate, featureDate, true)
+	fmt.Printf("%+v\n", *v)
+	if err := v.Check(featureDate); err != nil {
+		fmt.Println(err)
+	}
+}
@@ -3,7 +3,6 @@ package factors
 import (
 	"context"
 	"gitee.com/quant1x/exchange"
-	"gitee.com/quant1x/gox/api"
 	"gitee.com/quant1x/gox/logger"
 	"gitee.com/quant1x/num"
 	"gitee.com/quant1x/pandas"
@@ -11,7 +10,6 @@ import (
 	"xquant/pkg/cache"
 	"xquant/pkg/config"
 	"xquant/pkg/datasource/base"
-	"xquant/pkg/datasource/east_money"
 	"xquant/pkg/market"
 	"xquant/pkg/utils"
 )
@@ -32,7 +30,7 @@ type Misc struct {
 	MA5                   float64 `name:"5日均线" dataframe:"5日均线"`            // 5日均价
 	MA10                  float64 `name:"10日均线" dataframe:"10日均线"`          // 10日均价
 	MA20                  float64 `name:"20日均线" dataframe:"20日均线"`          // 生命线(MA20)/20日线
-	FundFlow              float64 `name:"资金流向" dataframe:"资金流向"`            // 资金流向, 暂时无用
+	FundFlow              float64 `name:"资金流向" dataframe:"资金流向"`            // 资金流向, 主力净流入, 分档的资金流向见FundFlow特征
 	RZYEZB                float64 `name:"融资余额占流通市值比(%)" dataframe:"RZYEZB"` // 融资余额占流通市值比
 	VolumeRatio           float64 `name:"成交量比" dataframe:"成交量比"`            // 成交量放大比例, 相邻的两个交易日进行比对
 	TurnoverRate          float64 `name:"换手率" dataframe:"换手率"`              // 换手率
@@ -110,6 +108,11 @@ func (this *Misc) Init(ctx context.Context, date string) error {
 	return nil
 }
 
+// DependOn 实现 cache.Depend 接口, 换手率Z依赖基本面数据
+func (this *Misc) DependOn() []cache.Kind {
+	return []cache.Kind{FeatureF10}
+}
+
 func (this *Misc) FromHistory(history History) Feature {
 	_ = history
 	return this
@@ -170,6 +173,11 @@ func (this *Misc) ValidateSample() error {
 	return ErrInvalidFeatureSample
 }
 
+// Check 实现 cache.Validator 接口, 按特征的校验策略检查数据
+func (this *Misc) Check(featureDate string) error {
+	return CheckFeature(this, featureDate)
+}
+
 // ExchangeKLineExtend 更新Exchange K线相关数据
 func miscKLineExtend(info *Misc, securityCode string, featureDate string) {
 	cover := NewMiscKLine(securityCode, featureDate)
@@ -254,26 +262,17 @@ func miscFundFlow(info *Misc, securityCode string, cacheDate, featureDate string
 	if !exchange.AssertStockBySecurityCode(securityCode) {
 		return
 	}
-	beginDate := exchange.MARKET_CH_FIRST_LISTTIME
-	filename := cache.FundFlowFilename(securityCode)
-	cacheList := []east_money.FundFlow{}
-	err := api.CsvToSlices(filename, &cacheList)
-	cacheLength := len(cacheList)
-	if err == nil && cacheLength > 0 {
-		beginDate = cacheList[cacheLength-1].Date
-		cacheList = cacheList[0 : cacheLength-1]
-	}
-	newList := east_money.IndividualStocksFundFlow(securityCode, beginDate)
-	if len(newList) == 0 {
-		return
+	tradeDate := exchange.FixTradeDate(featureDate)
+	list := CheckoutFundFlow(securityCode, tradeDate)
+	for i := len(list) - 1; i >= 0; i-- {
+		if list[i].Date == tradeDate {
+			info.FundFlow = list[i].Main
+			break
+		} else if list[i].Date < tradeDate {
+			break
+		}
 	}
-	list := append(cacheList, newList...)
-	_ = api.SlicesToCsv(filename, list)
-	last := list[len(list)-1]
-	cover := last.Medium
-	info.FundFlow = cover
 	_ = cacheDate
-	_ = featureDate
 }
 
 type PowerTrend = int
@@ -7,7 +7,6 @@ import (
 	"gitee.com/quant1x/pandas"
 	. "gitee.com/quant1x/pandas/formula"
 	"xquant/pkg/cache"
-	"xquant/pkg/datasource/base"
 	"xquant/pkg/market"
 	"xquant/pkg/utils"
 )
@@ -42,7 +41,7 @@ type MiscKLine struct {
 // NewMiscKLine 构建制定日期的K线数据
 func NewMiscKLine(code, date string) *MiscKLine {
 	securityCode := exchange.CorrectSecurityCode(code)
-	klines := base.CheckoutKLines(securityCode, date)
+	klines := checkoutKLines(FeatureMisc, securityCode, date)
 	if len(klines) < cache.KLineMin {
 		return nil
 	}
@@ -3,7 +3,6 @@ package factors
 import (
 	"math"
 
-	"gitee.com/quant1x/exchange"
 	"gitee.com/quant1x/num"
 	"gitee.com/quant1x/pandas"
 	. "gitee.com/quant1x/pandas/formula"
@@ -98,23 +97,18 @@ func KLineShape(df pandas.DataFrame, securityCode string) (shape ShapeType) {
 		shape |= KLineShapeYiZi
 	}
 	// 4 判断是否涨跌停板
-	limitRate := exchange.MarketLimit(securityCode)
-	lastClose := num.Decimal(LAST_CLOSE)
-	lastHigh := num.Decimal(HIGH)
-	lastLow := num.Decimal(LOW)
-	priceLimitUp := num.Decimal(lastClose * (1.000 + limitRate))
-	priceLimitDown := num.Decimal(lastClose * (1.000 - limitRate))
-	price := num.Decimal(CLOSE)
-	if price == priceLimitUp {
+	date := utils.StringIndexOf(df.Col("date"), -1)
+	limit := CheckoutPriceLimit(securityCode, date, LAST_CLOSE)
+	if limit.IsLimitUp(CLOSE) {
 		// 4.1 涨停板
 		shape |= KLineShapeLimitUp
-	} else if lastHigh == priceLimitUp {
+	} else if limit.IsLimitUp(HIGH) {
 		// 4.2 炸板
 		shape |= KLineShapeNotLimitUp
-	} else if price == priceLimitDown {
+	} else if limit.IsLimitDown(CLOSE) {
 		// 4.2 跌停板
 		shape |= KLineShapeLimitDown
-	} else if lastLow == priceLimitDown {
+	} else if limit.IsLimitDown(LOW) {
 		// 4.4 曾跌停
 		shape |= KLineShapeNotLimitDown
 	}
@@ -6,7 +6,6 @@ import (
 	"gitee.com/quant1x/pandas"
 	. "gitee.com/quant1x/pandas/formula"
 	"xquant/pkg/cache"
-	"xquant/pkg/datasource/base"
 	"xquant/pkg/utils"
 )
 
@@ -49,7 +48,7 @@ func (this *HousNo1) Update(code, cacheDate, featureDate string, complete bool)
 func (this *HousNo1) Repair(code, cacheDate, featureDate string, complete bool) {
 	securityCode := exchange.CorrectSecurityCode(code)
 	tradeDate := exchange.FixTradeDate(featureDate)
-	klines := base.CheckoutKLines(securityCode, tradeDate)
+	klines := checkoutKLines(FeatureNo1, securityCode, tradeDate)
 	if len(klines) < cache.KLineMin {
 		return
 	}
@@ -90,3 +89,8 @@ func (this *HousNo1) ValidateSample() error {
 	}
 	return ErrInvalidFeatureSample
 }
+
+// Check 实现 cache.Validator 接口, 按特征的校验策略检查数据
+func (this *HousNo1) Check(featureDate string) error {
+	return CheckFeature(this, featureDate)
+}
@@ -0,0 +1,127 @@
+package factors
+
+import (
+	"context"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/gox/logger"
+	"xquant/pkg/cache"
+)
+
+const (
+	cacheL5KeyOrderFlow = "orderflow"
+)
+
+// OrderFlow 成交分析特征, 取自成交分析数据集
+//
+//	金额单位为元
+type OrderFlow struct {
+	cache.DataSummary `dataframe:"-"`
+	Date              string  `name:"日期" dataframe:"date"`                  // 数据日期
+	Code              string  `name:"证券代码" dataframe:"code"`                // 证券代码
+	Imbalance         float64 `name:"订单流失衡%" dataframe:"imbalance"`         // (主动买入-主动卖出)/(主动买入+主动卖出)
+	SmallNet          float64 `name:"小单净买入" dataframe:"small_net"`          // 小单净买入金额
+	MediumNet         float64 `name:"中单净买入" dataframe:"medium_net"`         // 中单净买入金额
+	LargeNet          float64 `name:"大单净买入" dataframe:"large_net"`          // 大单和超大单的净买入金额
+	SuperLargeNet     float64 `name:"超大单净买入" dataframe:"super_large_net"`   // 超大单净买入金额
+	LargeNetRatio     float64 `name:"大单净买入占比%" dataframe:"large_net_ratio"` // 大单净买入/成交金额
+	BuyVWAP           float64 `name:"主买均价" dataframe:"buy_vwap"`            // 主动买入的成交量加权均价
+	SellVWAP          float64 `name:"主卖均价" dataframe:"sell_vwap"`           // 主动卖出的成交量加权均价
+	VWAPSpread        float64 `name:"买卖均价差%" dataframe:"vwap_spread"`       // 主买均价/主卖均价-1
+	TailNet           float64 `name:"尾盘净买入" dataframe:"tail_net"`           // 尾盘30分钟净买入金额
+	TailImbalance     float64 `name:"尾盘订单流失衡%" dataframe:"tail_imbalance"`  // 尾盘30分钟的订单流失衡
+	TailRatio         float64 `name:"尾盘成交占比%" dataframe:"tail_ratio"`       // 尾盘30分钟成交金额/全天成交金额
+	UpdateTime        string  `name:"更新时间" dataframe:"update_time"`         // 更新时间
+	State             uint64  `name:"样本状态" dataframe:"样本状态"`                // 样本状态
+}
+
+func NewOrderFlow(date, code string) *OrderFlow {
+	summary := __mapFeatures[FeatureOrderFlow]
+	v := OrderFlow{
+		DataSummary: summary,
+		Date:        date,
+		Code:        code,
+	}
+	return &v
+}
+
+func (this *OrderFlow) GetDate() string {
+	return this.Date
+}
+
+func (this *OrderFlow) GetSecurityCode() string {
+	return this.Code
+}
+
+func (this *OrderFlow) Factory(date string, code string) Feature {
+	v := NewOrderFlow(date, code)
+	return v
+}
+
+func (this *OrderFlow) Init(ctx context.Context, date string) error {
+	_ = ctx
+	_ = date
+	return nil
+}
+
+func (this *OrderFlow) FromHistory(history History) Feature {
+	_ = history
+	return this
+}
+
+func (this *OrderFlow) Update(code, cacheDate, featureDate string, complete bool) {
+	securityCode := exchange.CorrectSecurityCode(code)
+	if !exchange.AssertStockBySecurityCode(securityCode) {
+		return
+	}
+	tradeDate := exchange.FixTradeDate(featureDate)
+	summary := CheckoutOrderFlow(securityCode, tradeDate)
+	if summary == nil {
+		logger.Errorf("code[%s, %s] order flow not found", code, featureDate)
+		return
+	}
+	if summary.Count == 0 {
+		// 没有成交数据, 如停牌
+		return
+	}
+	this.Date = summary.Date
+	this.Imbalance = summary.Imbalance
+	this.SmallNet = summary.SmallBuy - summary.SmallSell
+	this.MediumNet = summary.MediumBuy - summary.MediumSell
+	this.LargeNet = summary.LargeNet
+	this.SuperLargeNet = summary.SuperLargeBuy - summary.SuperLargeSell
+	this.LargeNetRatio = summary.LargeNetRatio
+	this.BuyVWAP = summary.BuyVWAP
+	this.SellVWAP = summary.SellVWAP
+	this.VWAPSpread = summary.VWAPSpread
+	this.TailNet = summary.TailNet
+	this.TailImbalance = summary.TailImbalance
+	this.TailRatio = summary.TailRatio
+	// 样本状态
+	this.State |= this.Kind()
+	this.UpdateTime = GetTimestamp()
+	_ = cacheDate
+	_ = complete
+}
+
+func (this *OrderFlow) Repair(code, cacheDate, featureDate string, complete bool) {
+	this.Update(code, cacheDate, featureDate, complete)
+}
+
+func (this *OrderFlow) Increase(snapshot QuoteSnapshot) Feature {
+	_ = snapshot
+	return this
+}
+
+// ValidateSample 验证样本数据
+func (this *OrderFlow) ValidateSample() error {
+	if this.State > 0 {
+		return nil
+	}
+	return ErrInvalidFeatureSample
+}
+
+// Check 实现 cache.Validator 接口, 按特征的校验策略检查数据
+func (this *OrderFlow) Check(featureDate string) error {
+	return CheckFeature(this, featureDate)
+}
@@ -0,0 +1,369 @@
+package factors
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/gox/api"
+	"gitee.com/quant1x/pandas"
+	. "gitee.com/quant1x/pandas/formula"
+	"xquant/pkg/cache"
+	"xquant/pkg/utils"
+)
+
+const (
+	cacheL5KeyWeekly  = "weekly"
+	cacheL5KeyMonthly = "monthly"
+)
+
+const (
+	PeriodWeekly  = "W" // 周线
+	PeriodMonthly = "M" // 月线
+)
+
+// PeriodHistory 日线以上级别的历史整合数据
+//
+//	记录截止上一个交易日的周线或月线数据, 当前周期的K线可能尚未走完,
+//	盘中通过Increase用快照合并出实时的周期K线和均线
+type PeriodHistory struct {
+	cache.DataSummary `dataframe:"-"`
+	Date              string  `name:"日期" dataframe:"date"`                  // 日期, 数据落地的日期
+	Code              string  `name:"代码" dataframe:"code"`                  // 代码
+	Period            string  `name:"周期" dataframe:"period"`                // 周期, W-周线, M-月线
+	PeriodDate        string  `name:"周期日期" dataframe:"period_date"`         // 当前周期K线的日期
+	Closed            bool    `name:"周期结束" dataframe:"closed"`              // 当前周期是否已经走完
+	Days              int     `name:"周期天数" dataframe:"days"`                // 当前周期已经走过的交易日数
+	OPEN              float64 `name:"开盘" dataframe:"open"`                  // 当前周期开盘
+	CLOSE             float64 `name:"收盘" dataframe:"close"`                 // 当前周期收盘
+	HIGH              float64 `name:"最高" dataframe:"high"`                  // 当前周期最高
+	LOW               float64 `name:"最低" dataframe:"low"`                   // 当前周期最低
+	VOL               float64 `name:"成交量" dataframe:"vol"`                  // 当前周期成交量
+	AMOUNT            float64 `name:"成交额" dataframe:"amount"`               // 当前周期成交额
+	LastClose         float64 `name:"上周期收盘" dataframe:"last_close"`         // 上一个周期收盘
+	LastHigh          float64 `name:"上周期最高" dataframe:"last_high"`          // 上一个周期最高
+	LastLow           float64 `name:"上周期最低" dataframe:"last_low"`           // 上一个周期最低
+	MA4               float64 `name:"4周期均价" dataframe:"ma4"`                // 已走完周期的4周期均价, 用于增量计算MA5
+	MA9               float64 `name:"9周期均价" dataframe:"ma9"`                // 已走完周期的9周期均价, 用于增量计算MA10
+	MA19              float64 `name:"19周期均价" dataframe:"ma19"`              // 已走完周期的19周期均价, 用于增量计算MA20
+	MV4               float64 `name:"4周期均量" dataframe:"mv4"`                // 已走完周期的4周期均量
+	HHV19             float64 `name:"19周期最高" dataframe:"hhv19"`             // 已走完周期的19周期最高价, 用于增量计算HHV20
+	LLV19             float64 `name:"19周期最低" dataframe:"llv19"`             // 已走完周期的19周期最低价, 用于增量计算LLV20
+	MA5               float64 `name:"5周期均价" dataframe:"ma5"`                // 5周期均价
+	MA10              float64 `name:"10周期均价" dataframe:"ma10"`              // 10周期均价
+	MA20              float64 `name:"20周期均价" dataframe:"ma20"`              // 20周期均价
+	MV5               float64 `name:"5周期均量" dataframe:"mv5"`                // 5周期均量
+	HHV20             float64 `name:"20周期最高" dataframe:"hhv20"`             // 20周期最高价
+	LLV20             float64 `name:"20周期最低" dataframe:"llv20"`             // 20周期最低价
+	BullN             int     `name:"多头排列周期" dataframe:"bull_n"`            // 多头周期数
+	UpwardN           int     `name:"向上跳空周期数" dataframe:"upward_n"`         // 向上跳空缺口到现在的周期数
+	NewHighN          int     `name:"新高次数" dataframe:"new_high_n"`          // 新高次数
+	NewLowN           int     `name:"新低次数" dataframe:"new_low_n"`           // 新低次数
+	LastUpwardN       int     `name:"已走完向上跳空周期数" dataframe:"last_upward_n"` // 已走完周期的UpwardN, 用于增量计算
+	LastNewHighN      int     `name:"已走完新高次数" dataframe:"last_new_high_n"`  // 已走完周期的NewHighN, 用于增量计算
+	LastNewLowN       int     `name:"已走完新低次数" dataframe:"last_new_low_n"`   // 已走完周期的NewLowN, 用于增量计算
+	UpdateTime        string  `name:"更新时间" dataframe:"update_time"`         // 更新时间
+	State             uint64  `name:"样本状态" dataframe:"样本状态"`                // 样本状态
+}
+
+// NewWeeklyHistory 创建周线历史数据
+func NewWeeklyHistory(date, code string) *PeriodHistory {
+	return newPeriodHistory(FeatureWeeklyHistory, PeriodWeekly, date, code)
+}
+
+// NewMonthlyHistory 创建月线历史数据
+func NewMonthlyHistory(date, code string) *PeriodHistory {
+	return newPeriodHistory(FeatureMonthlyHistory, PeriodMonthly, date, code)
+}
+
+func newPeriodHistory(kind cache.Kind, period, date, code string) *PeriodHistory {
+	summary := __mapFeatures[kind]
+	v := PeriodHistory{
+		DataSummary: summary,
+		Date:        date,
+		Code:        code,
+		Period:      period,
+	}
+	return &v
+}
+
+func (this *PeriodHistory) GetDate() string {
+	return this.Date
+}
+
+func (this *PeriodHistory) GetSecurityCode() string {
+	return this.Code
+}
+
+func (this *PeriodHistory) Factory(date string, code string) Feature {
+	if this.Period == PeriodMonthly {
+		return NewMonthlyHistory(date, code)
+	}
+	return NewWeeklyHistory(date, code)
+}
+
+func (this *PeriodHistory) Init(ctx context.Context, date string) error {
+	_ = ctx
+	_ = date
+	return nil
+}
+
+// checkPeriod 周期范围的计算函数
+func (this *PeriodHistory) checkPeriod() func(date ...string) (s, e string) {
+	if this.Period == PeriodMonthly {
+		return api.GetMonthDay
+	}
+	return api.GetWeekDay
+}
+
+func (this *PeriodHistory) Update(code, cacheDate, featureDate string, complete bool) {
+	this.Repair(code, cacheDate, featureDate, complete)
+}
+
+func (this *PeriodHistory) Repair(code, cacheDate, featureDate string, complete bool) {
+	securityCode := exchange.CorrectSecurityCode(this.Code)
+	tradeDate := exchange.FixTradeDate(featureDate)
+	klines := checkoutKLines(this.Kind(), securityCode, tradeDate)
+	if len(klines) < cache.KLineMin {
+		return
+	}
+	checkPeriod := this.checkPeriod()
+	df := periodKLine(checkPeriod, securityCode, klines)
+	rows := df.Nrow()
+	if rows < 2 {
+		return
+	}
+	var (
+		DATE   = df.Col("date")
+		OPEN   = df.ColAsNDArray("open")
+		CLOSE  = df.ColAsNDArray("close")
+		HIGH   = df.ColAsNDArray("high")
+		LOW    = df.ColAsNDArray("low")
+		VOL    = df.ColAsNDArray("volume")
+		AMOUNT = df.ColAsNDArray("amount")
+	)
+	// 当前周期的K线
+	this.PeriodDate = utils.StringIndexOf(DATE, -1)
+	this.OPEN = utils.Float64IndexOf(OPEN, -1)
+	this.CLOSE = utils.Float64IndexOf(CLOSE, -1)
+	this.HIGH = utils.Float64IndexOf(HIGH, -1)
+	this.LOW = utils.Float64IndexOf(LOW, -1)
+	this.VOL = utils.Float64IndexOf(VOL, -1)
+	this.AMOUNT = utils.Float64IndexOf(AMOUNT, -1)
+	// 上一个周期的K线
+	this.LastClose = utils.Float64IndexOf(CLOSE, -2)
+	this.LastHigh = utils.Float64IndexOf(HIGH, -2)
+	this.LastLow = utils.Float64IndexOf(LOW, -2)
+	// 下一个交易日不在当前周期内, 当前周期已走完
+	_, periodEnd := checkPeriod(tradeDate)
+	this.Closed = exchange.NextTradeDate(tradeDate) > periodEnd
+	startDate, _ := checkPeriod(tradeDate)
+	this.Days = 0
+	for i := len(klines) - 1; i >= 0 && klines[i].Date >= startDate; i-- {
+		this.Days++
+	}
+	// 已走完的周期, 用于盘中增量计算
+	completed := CLOSE
+	completedVol := VOL
+	completedHigh, completedLow := HIGH, LOW
+	if !this.Closed {
+		completed = REF(CLOSE, 1)
+		completedVol = REF(VOL, 1)
+		completedHigh, completedLow = REF(HIGH, 1), REF(LOW, 1)
+	}
+	this.MA4 = utils.Float64IndexOf(MA(completed, 4), -1)
+	this.MA9 = utils.Float64IndexOf(MA(completed, 9), -1)
+	this.MA19 = utils.Float64IndexOf(MA(completed, 19), -1)
+	this.MV4 = utils.Float64IndexOf(MA(completedVol, 4), -1)
+	this.HHV19 = utils.Float64IndexOf(HHV(completedHigh, 19), -1)
+	this.LLV19 = utils.Float64IndexOf(LLV(completedLow, 19), -1)
+	// 截止featureDate的均线
+	ma5 := MA(CLOSE, 5)
+	ma10 := MA(CLOSE, 10)
+	ma20 := MA(CLOSE, 20)
+	this.MA5 = utils.Float64IndexOf(ma5, -1)
+	this.MA10 = utils.Float64IndexOf(ma10, -1)
+	this.MA20 = utils.Float64IndexOf(ma20, -1)
+	this.MV5 = utils.Float64IndexOf(MA(VOL, 5), -1)
+	this.HHV20 = utils.Float64IndexOf(HHV(HIGH, 20), -1)
+	this.LLV20 = utils.Float64IndexOf(LLV(LOW, 20), -1)
+	// 多头排列周期数
+	bullC := ma5.Gt(ma10).And(ma10.Gt(ma20))
+	this.BullN = utils.IntegerIndexOf(BARSLASTCOUNT(bullC), -1)
+	// 已走完的周期, 当前周期未走完时取上一个周期
+	last := -1
+	if !this.Closed {
+		last = -2
+	}
+	// 最近一次向上的跳空缺口到现在的周期数
+	gapUpward := LOW.Gt(REF(HIGH, 1))
+	upwardN := BARSLAST(gapUpward)
+	this.UpwardN = utils.IntegerIndexOf(upwardN, -1)
+	this.LastUpwardN = utils.IntegerIndexOf(upwardN, last)
+	// 收盘价和最高价连续走高
+	newHigh := CLOSE.Gt(REF(CLOSE, 1)).And(HIGH.Gt(REF(HIGH, 1)))
+	newHighN := BARSLASTCOUNT(newHigh)
+	this.NewHighN = utils.IntegerIndexOf(newHighN, -1)
+	this.LastNewHighN = utils.IntegerIndexOf(newHighN, last)
+	// 最低价连续走低
+	newLow := LOW.Lt(REF(LOW, 1))
+	newLowN := BARSLASTCOUNT(newLow)
+	this.NewLowN = utils.IntegerIndexOf(newLowN, -1)
+	this.LastNewLowN = utils.IntegerIndexOf(newLowN, last)
+
+	this.UpdateTime = GetTimestamp()
+	this.State |= this.Kind()
+}
+
+func (this *PeriodHistory) FromHistory(history History) Feature {
+	_ = history
+	return this
+}
+
+// Increase 用快照增量计算当前周期的K线、均线和趋势计数
+//
+//	返回新的对象, 不修改缓存中的数据
+func (this *PeriodHistory) Increase(snapshot QuoteSnapshot) Feature {
+	v := *this
+	if snapshot.Price <= 0 || snapshot.Date <= this.PeriodDate {
+		return &v
+	}
+	vol := float64(snapshot.Vol)
+	if this.Closed {
+		// 新周期开始
+		v.LastClose, v.LastHigh, v.LastLow = this.CLOSE, this.HIGH, this.LOW
+		v.OPEN, v.HIGH, v.LOW = snapshot.Open, snapshot.High, snapshot.Low
+		v.VOL, v.AMOUNT = vol, snapshot.Amount
+		v.Days = 1
+	} else {
+		v.HIGH = max(this.HIGH, snapshot.High)
+		v.LOW = min(this.LOW, snapshot.Low)
+		v.VOL = this.VOL + vol
+		v.AMOUNT = this.AMOUNT + snapshot.Amount
+		v.Days = this.Days + 1
+	}
+	v.Date = snapshot.Date
+	v.PeriodDate = snapshot.Date
+	v.CLOSE = snapshot.Price
+	v.Closed = false
+	// 增量均线
+	v.MA5 = incrementalAverage(this.MA4, 5, v.CLOSE)
+	v.MA10 = incrementalAverage(this.MA9, 10, v.CLOSE)
+	v.MA20 = incrementalAverage(this.MA19, 20, v.CLOSE)
+	v.MV5 = incrementalAverage(this.MV4, 5, v.VOL)
+	// 20周期的窗口由已走完的19个周期和当前周期组成, 新周期开始时最早的周期移出窗口
+	v.HHV20 = max(this.HHV19, v.HIGH)
+	if v.LOW > 0 {
+		v.LLV20 = v.LOW
+		if this.LLV19 > 0 {
+			v.LLV20 = min(this.LLV19, v.LOW)
+		}
+	}
+	// 趋势计数
+	if v.MA5 > v.MA10 && v.MA10 > v.MA20 {
+		if this.Closed {
+			v.BullN = this.BullN + 1
+		} else if v.BullN == 0 {
+			v.BullN = 1
+		}
+	} else {
+		v.BullN = 0
+	}
+	// 新周期开始时, 刚走完的周期就是已走完周期的最后一个
+	lastUpwardN, lastNewHighN, lastNewLowN := this.LastUpwardN, this.LastNewHighN, this.LastNewLowN
+	if this.Closed {
+		lastUpwardN, lastNewHighN, lastNewLowN = this.UpwardN, this.NewHighN, this.NewLowN
+	}
+	// v.LastClose, v.LastHigh, v.LastLow已经是上一个周期的K线
+	v.UpwardN = lastUpwardN + 1
+	if v.LOW > v.LastHigh {
+		v.UpwardN = 0
+	}
+	v.NewHighN = 0
+	if v.CLOSE > v.LastClose && v.HIGH > v.LastHigh {
+		v.NewHighN = lastNewHighN + 1
+	}
+	v.NewLowN = 0
+	if v.LOW < v.LastLow {
+		v.NewLowN = lastNewLowN + 1
+	}
+	return &v
+}
+
+// incrementalAverage 由前n-1个周期的均值和最新值计算n周期均值
+func incrementalAverage(lastAverage float64, n int, value float64) float64 {
+	if math.IsNaN(lastAverage) {
+		return math.NaN()
+	}
+	return (lastAverage*float64(n-1) + value) / float64(n)
+}
+
+// IsBull 是否多头排列
+func (this *PeriodHistory) IsBull() bool {
+	return this.MA5 > this.MA10 && this.MA10 > this.MA20
+}
+
+// IsRising 均线是否向上, 当前价格在MA5之上并且MA5不低于MA10
+func (this *PeriodHistory) IsRising() bool {
+	return this.CLOSE > this.MA5 && this.MA5 >= this.MA10
+}
+
+func (this *PeriodHistory) ValidateSample() error {
+	if this.State > 0 {
+		return nil
+	}
+	return ErrInvalidFeatureSample
+}
+
+// Check 实现 cache.Validator 接口, 按特征的校验策略检查数据
+func (this *PeriodHistory) Check(featureDate string) error {
+	return CheckFeature(this, featureDate)
+}
+
+// periodKLineDates 周期K线缓存的最大日期数, 实时跟踪和回测可能同时使用不同的日期
+const periodKLineDates = 4
+
+var (
+	__periodKLineMutex sync.RWMutex
+	__periodKLineDates []string                                   // 已缓存的日期, 按缓存的先后顺序
+	__periodKLines     = map[string]map[string]pandas.DataFrame{} // 日期 -> 周期/证券代码 -> K线
+)
+
+// CheckoutPeriodKLines 获取截止指定日期的周线或月线, 按日期缓存
+func CheckoutPeriodKLines(period, securityCode string, date ...string) pandas.DataFrame {
+	securityCode = exchange.CorrectSecurityCode(securityCode)
+	tradeDate := cache.DefaultCanReadDate()
+	if len(date) > 0 && len(date[0]) > 0 {
+		tradeDate = exchange.FixTradeDate(date[0])
+	}
+	key := period + "/" + securityCode
+	__periodKLineMutex.RLock()
+	df, ok := __periodKLines[tradeDate][key]
+	__periodKLineMutex.RUnlock()
+	if ok {
+		return df
+	}
+	kind := FeatureWeeklyHistory
+	if period == PeriodMonthly {
+		kind = FeatureMonthlyHistory
+	}
+	klines := checkoutKLines(kind, securityCode, tradeDate)
+	v := PeriodHistory{Period: period}
+	df = periodKLine(v.checkPeriod(), securityCode, klines)
+	__periodKLineMutex.Lock()
+	defer __periodKLineMutex.Unlock()
+	mapKLines, ok := __periodKLines[tradeDate]
+	if !ok {
+		// 超过缓存的日期数, 清除最早的日期
+		if len(__periodKLineDates) >= periodKLineDates {
+			delete(__periodKLines, __periodKLineDates[0])
+			__periodKLineDates = __periodKLineDates[1:]
+		}
+		mapKLines = map[string]pandas.DataFrame{}
+		__periodKLines[tradeDate] = mapKLines
+		__periodKLineDates = append(__periodKLineDates, tradeDate)
+	}
+	mapKLines[key] = df
+	return df
+}
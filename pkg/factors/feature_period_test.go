@@ -0,0 +1,83 @@
+package factors
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/gox/api"
+	"xquant/pkg/cache"
+)
+
+func TestPeriodHistory(t *testing.T) {
+	code := "600600"
+	date := "2024-05-17"
+	cacheDate, featureDate := cache.CorrectDate(date)
+	code = exchange.CorrectSecurityCode(code)
+	weekly := NewWeeklyHistory(cacheDate, code)
+	weekly.Update(code, cacheDate, featureDate, true)
+	data, _ := json.Marshal(weekly)
+	fmt.Println(api.Bytes2String(data))
+	monthly := NewMonthlyHistory(cacheDate, code)
+	monthly.Update(code, cacheDate, featureDate, true)
+	data, _ = json.Marshal(monthly)
+	fmt.Println(api.Bytes2String(data))
+	snapshot := QuoteSnapshot{Date: cacheDate, Price: weekly.CLOSE * 1.02, Open: weekly.CLOSE, High: weekly.CLOSE * 1.03, Low: weekly.CLOSE * 0.99}
+	v := weekly.Increase(snapshot)
+	data, _ = json.Marshal(v)
+	fmt.Println(api.Bytes2String(data))
+}
+
+func TestPeriodHistory_IncreaseTrend(t *testing.T) {
+	// 周期未走完, 当前周期尚未创新高, 上一个周期之前已经连续2个周期新高
+	weekly := PeriodHistory{
+		Period:       PeriodWeekly,
+		PeriodDate:   "2024-05-15",
+		CLOSE:        10,
+		HIGH:         10.2,
+		LOW:          9.8,
+		LastClose:    10.1,
+		LastHigh:     10.3,
+		LastLow:      9.6,
+		UpwardN:      3,
+		LastUpwardN:  2,
+		LastNewHighN: 2,
+	}
+	// 盘中突破上一个周期的高点
+	v := weekly.Increase(QuoteSnapshot{Date: "2024-05-16", Price: 10.5, Open: 10, High: 10.6, Low: 9.9}).(*PeriodHistory)
+	if v.NewHighN != 3 || v.NewLowN != 0 || v.UpwardN != 3 {
+		t.Errorf("new_high_n=%d, new_low_n=%d, upward_n=%d", v.NewHighN, v.NewLowN, v.UpwardN)
+	}
+	// 周期已走完, 新周期向上跳空
+	weekly.Closed = true
+	v = weekly.Increase(QuoteSnapshot{Date: "2024-05-20", Price: 10.6, Open: 10.4, High: 10.7, Low: 10.3}).(*PeriodHistory)
+	if v.NewHighN != 1 || v.UpwardN != 0 || v.LastHigh != 10.2 {
+		t.Errorf("new_high_n=%d, upward_n=%d, last_high=%f", v.NewHighN, v.UpwardN, v.LastHigh)
+	}
+}
+
+func TestPeriodHistory_IncreaseWindow(t *testing.T) {
+	// 周期已走完, 20周期的最高价和最低价都在最早的周期, 新周期开始后移出窗口
+	weekly := PeriodHistory{
+		Period:     PeriodWeekly,
+		PeriodDate: "2024-05-17",
+		Closed:     true,
+		CLOSE:      10,
+		HIGH:       10.2,
+		LOW:        9.8,
+		HHV20:      12,
+		LLV20:      8,
+		HHV19:      10.8,
+		LLV19:      9.2,
+	}
+	v := weekly.Increase(QuoteSnapshot{Date: "2024-05-20", Price: 10.1, Open: 10, High: 10.3, Low: 9.9}).(*PeriodHistory)
+	if v.HHV20 != 10.8 || v.LLV20 != 9.2 {
+		t.Errorf("hhv20=%f, llv20=%f", v.HHV20, v.LLV20)
+	}
+	// 当前周期突破窗口内的最高价
+	v = weekly.Increase(QuoteSnapshot{Date: "2024-05-20", Price: 11, Open: 10, High: 11.2, Low: 9.9}).(*PeriodHistory)
+	if v.HHV20 != 11.2 || v.LLV20 != 9.2 {
+		t.Errorf("hhv20=%f, llv20=%f", v.HHV20, v.LLV20)
+	}
+}
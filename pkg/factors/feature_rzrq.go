@@ -126,3 +126,8 @@ func (this *SecuritiesMarginTrading) ValidateSample() error {
 	}
 	return ErrInvalidFeatureSample
 }
+
+// Check 实现 cache.Validator 接口, 按特征的校验策略检查数据
+func (this *SecuritiesMarginTrading) Check(featureDate string) error {
+	return CheckFeature(this, featureDate)
+}
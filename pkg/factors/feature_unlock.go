@@ -0,0 +1,137 @@
+package factors
+
+import (
+	"context"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/num"
+	"xquant/pkg/cache"
+)
+
+const (
+	cacheL5KeyUnlock = "unlock"
+)
+
+// Unlock 限售解禁特征
+//
+//	特征在缓存日期使用, 下一个解禁日和UnlockDays都相对缓存日期计算, 没有待解禁的批次时UnlockDays为-1
+type Unlock struct {
+	cache.DataSummary `dataframe:"-"`
+	Date              string  `name:"日期" dataframe:"date"`             // 数据日期
+	Code              string  `name:"证券代码" dataframe:"code"`           // 证券代码
+	NextDate          string  `name:"下一解禁日" dataframe:"next_date"`     // 下一个解禁日期
+	UnlockDays        int     `name:"距解禁交易日数" dataframe:"unlock_days"` // 缓存日期距离下一个解禁日的交易日数, 当日解禁为0
+	LiftNum           float64 `name:"解禁数量" dataframe:"lift_num"`       // 下一个解禁日的解禁数量, 单位股
+	LiftType          string  `name:"限售股类型" dataframe:"lift_type"`     // 下一个解禁日的限售股类型
+	TotalRatio        float64 `name:"占总股本比例%" dataframe:"total_ratio"` // 解禁数量占总股本的比例
+	FloatRatio        float64 `name:"占流通股比例%" dataframe:"float_ratio"` // 解禁数量占流通股本的比例
+	UpdateTime        string  `name:"更新时间" dataframe:"update_time"`    // 更新时间
+	State             uint64  `name:"样本状态" dataframe:"样本状态"`           // 样本状态
+}
+
+func NewUnlock(date, code string) *Unlock {
+	summary := __mapFeatures[FeatureUnlock]
+	v := Unlock{
+		DataSummary: summary,
+		Date:        date,
+		Code:        code,
+		UnlockDays:  -1,
+	}
+	return &v
+}
+
+func (this *Unlock) GetDate() string {
+	return this.Date
+}
+
+func (this *Unlock) GetSecurityCode() string {
+	return this.Code
+}
+
+func (this *Unlock) Factory(date string, code string) Feature {
+	v := NewUnlock(date, code)
+	return v
+}
+
+func (this *Unlock) Init(ctx context.Context, date string) error {
+	_ = ctx
+	_ = date
+	return nil
+}
+
+// DependOn 实现 cache.Depend 接口, 占流通股比例依赖基本面的流通股本
+func (this *Unlock) DependOn() []cache.Kind {
+	return []cache.Kind{FeatureF10}
+}
+
+func (this *Unlock) FromHistory(history History) Feature {
+	_ = history
+	return this
+}
+
+func (this *Unlock) Update(code, cacheDate, featureDate string, complete bool) {
+	securityCode := exchange.CorrectSecurityCode(code)
+	if !exchange.AssertStockBySecurityCode(securityCode) {
+		return
+	}
+	tradeDate := exchange.FixTradeDate(featureDate)
+	this.Date = tradeDate
+	capital := 0.00
+	if f10 := GetL5F10(securityCode, tradeDate); f10 != nil {
+		capital = f10.Capital
+	}
+	this.Compute(CheckoutUnlocks(securityCode), capital, exchange.FixTradeDate(cacheDate))
+	// 样本状态
+	this.State |= this.Kind()
+	this.UpdateTime = GetTimestamp()
+	_ = complete
+}
+
+// Compute 计算下一个解禁日, capital为流通股本, 数据源没有占流通股比例时用流通股本计算, cacheDate为使用特征的交易日
+func (this *Unlock) Compute(list []UnlockRecord, capital float64, cacheDate string) {
+	this.NextDate = ""
+	this.UnlockDays = -1
+	this.LiftNum = 0
+	this.LiftType = ""
+	this.TotalRatio = 0
+	this.FloatRatio = 0
+	// 特征日期当天解禁的已经流通, 缓存日期才是买入的交易日
+	next, ok := NextUnlock(list, cacheDate)
+	if !ok {
+		return
+	}
+	this.NextDate = next.LiftDate
+	this.UnlockDays = 0
+	if days := len(exchange.TradingDateRange(cacheDate, next.LiftDate)) - 1; days > 0 {
+		this.UnlockDays = days
+	}
+	this.LiftNum = next.LiftNum
+	this.LiftType = next.LiftType
+	this.TotalRatio = num.Decimal(next.TotalRatio)
+	this.FloatRatio = num.Decimal(next.FloatRatio)
+	if this.FloatRatio == 0 && capital > 0 {
+		this.FloatRatio = num.Decimal(next.LiftNum / capital * 100)
+	}
+}
+
+func (this *Unlock) Repair(code, cacheDate, featureDate string, complete bool) {
+	this.Update(code, cacheDate, featureDate, complete)
+}
+
+func (this *Unlock) Increase(snapshot QuoteSnapshot) Feature {
+	_ = snapshot
+	return this
+}
+
+// ValidateSample 验证样本数据
+func (this *Unlock) ValidateSample() error {
+	if this.State > 0 {
+		return nil
+	}
+	return ErrInvalidFeatureSample
+}
+
+// Check 实现 cache.Validator 接口, 按特征的校验策略检查数据
+func (this *Unlock) Check(featureDate string) error {
+	return CheckFeature(this, featureDate)
+}
@@ -0,0 +1,89 @@
+package factors
+
+import (
+	"fmt"
+	"testing"
+
+	"gitee.com/quant1x/exchange"
+	"xquant/pkg/cache"
+)
+
+func TestNextUnlock(t *testing.T) {
+	list := []UnlockRecord{
+		{Code: "sh688981", LiftDate: "2024-06-03", LiftNum: 1000, LiftType: "首发原股东限售股份", TotalRatio: 1, FloatRatio: 2},
+		{Code: "sh688981", LiftDate: "2024-07-15", LiftNum: 2000, LiftType: "定向增发机构配售股份", TotalRatio: 2, FloatRatio: 4},
+		{Code: "sh688981", LiftDate: "2024-07-15", LiftNum: 500, LiftType: "股权激励限售股份", TotalRatio: 0.5, FloatRatio: 1},
+		{Code: "sh688981", LiftDate: "2025-01-06", LiftNum: 3000, TotalRatio: 3, FloatRatio: 6},
+	}
+	v, ok := NextUnlock(list, "2024-06-04")
+	if !ok || v.LiftDate != "2024-07-15" {
+		t.Fatalf("ok=%t, lift_date=%s", ok, v.LiftDate)
+	}
+	if v.LiftNum != 2500 || v.TotalRatio != 2.5 || v.FloatRatio != 5 || v.LiftType != "定向增发机构配售股份,股权激励限售股份" {
+		t.Errorf("%+v", v)
+	}
+	v, ok = NextUnlock(list, "2024-06-03")
+	if !ok || v.LiftDate != "2024-06-03" || v.LiftNum != 1000 {
+		t.Errorf("%+v", v)
+	}
+	if _, ok = NextUnlock(list, "2025-01-07"); ok {
+		t.Errorf("2025-01-07 should have no unlock")
+	}
+}
+
+func Test_mergeUnlocks(t *testing.T) {
+	cacheList := []UnlockRecord{
+		{Code: "sh688981", LiftDate: "2024-06-03", LiftNum: 1000, LiftType: "首发原股东限售股份"},
+		{Code: "sh688981", LiftDate: "2024-07-15", LiftNum: 2000, LiftType: "定向增发机构配售股份"},
+	}
+	// 接口只返回了最近的批次, 并修正了已缓存批次的解禁数量
+	list := []UnlockRecord{
+		{Code: "sh688981", LiftDate: "2025-01-06", LiftNum: 3000, LiftType: "首发原股东限售股份"},
+		{Code: "sh688981", LiftDate: "2024-07-15", LiftNum: 2100, LiftType: "定向增发机构配售股份"},
+	}
+	merged := mergeUnlocks(cacheList, list)
+	if len(merged) != 3 {
+		t.Fatalf("len=%d, %+v", len(merged), merged)
+	}
+	if merged[0].LiftDate != "2024-06-03" || merged[1].LiftNum != 2100 || merged[2].LiftDate != "2025-01-06" {
+		t.Errorf("%+v", merged)
+	}
+}
+
+func TestUnlock_Compute(t *testing.T) {
+	list := []UnlockRecord{
+		{Code: "sh688981", LiftDate: "2024-07-15", LiftNum: 2000, FloatRatio: 4},
+		{Code: "sh688981", LiftDate: "2024-07-18", LiftNum: 3000, FloatRatio: 6},
+	}
+	tests := []struct {
+		date       string
+		nextDate   string
+		unlockDays int
+	}{
+		{"2024-07-11", "2024-07-15", 2},
+		{"2024-07-15", "2024-07-15", 0},
+		// 特征日期当天已经解禁, 缓存日期看下一批
+		{"2024-07-16", "2024-07-18", 2},
+	}
+	for _, tt := range tests {
+		cacheDate, featureDate := cache.CorrectDate(tt.date)
+		v := NewUnlock(featureDate, "sh688981")
+		v.Compute(list, 0, cacheDate)
+		if v.NextDate != tt.nextDate || v.UnlockDays != tt.unlockDays {
+			t.Errorf("%s: next_date=%s, unlock_days=%d", tt.date, v.NextDate, v.UnlockDays)
+		}
+	}
+}
+
+func TestUnlock_basic(t *testing.T) {
+	code := "688981"
+	date := "2024-07-10"
+	cacheDate, featureDate := cache.CorrectDate(date)
+	code = exchange.CorrectSecurityCode(code)
+	v := NewUnlock(featureDate, code)
+	v.Update(code, cacheDate, featureDate, true)
+	fmt.Printf("%+v\n", *v)
+	if err := v.Check(featureDate); err != nil {
+		fmt.Println(err)
+	}
+}
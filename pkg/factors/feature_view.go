@@ -0,0 +1,275 @@
+package factors
+
+import (
+	"container/list"
+	"reflect"
+	"sync"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/gox/logger"
+)
+
+const (
+	// DefaultViewCapacity 默认缓存的日期视图数
+	DefaultViewCapacity = 8
+)
+
+// viewFeature 视图中的一个特征, 第一次访问时加载
+type viewFeature struct {
+	once sync.Once
+	data map[string]Feature
+}
+
+// dateView 一个日期的特征数据
+type dateView struct {
+	date     string
+	refs     int // 引用计数, 大于0时不会被淘汰
+	m        sync.Mutex
+	features map[string]*viewFeature
+}
+
+func (this *dateView) feature(key string) map[string]Feature {
+	this.m.Lock()
+	vf, ok := this.features[key]
+	if !ok {
+		vf = &viewFeature{}
+		this.features[key] = vf
+	}
+	this.m.Unlock()
+	vf.once.Do(func() {
+		vf.data = map[string]Feature{}
+		slice, err := LoadFeatureSlices(key, this.date)
+		if err != nil {
+			logger.Errorf("加载特征[%s]失败, date=%s, error=%+v", key, this.date, err)
+			return
+		}
+		rv := reflect.ValueOf(slice)
+		for i := 0; i < rv.Len(); i++ {
+			if v, ok := rv.Index(i).Interface().(Feature); ok {
+				vf.data[v.GetSecurityCode()] = v
+			}
+		}
+	})
+	return vf.data
+}
+
+// viewStore 按日期缓存的特征视图, LRU淘汰没有被引用的日期
+type viewStore struct {
+	m        sync.Mutex
+	capacity int
+	lru      *list.List
+	views    map[string]*list.Element
+}
+
+var (
+	__views = &viewStore{
+		capacity: DefaultViewCapacity,
+		lru:      list.New(),
+		views:    map[string]*list.Element{},
+	}
+)
+
+func (this *viewStore) get(date string, ref bool) *dateView {
+	this.m.Lock()
+	defer this.m.Unlock()
+	var view *dateView
+	if elem, ok := this.views[date]; ok {
+		this.lru.MoveToFront(elem)
+		view = elem.Value.(*dateView)
+	} else {
+		view = &dateView{date: date, features: map[string]*viewFeature{}}
+		this.views[date] = this.lru.PushFront(view)
+	}
+	if ref {
+		view.refs++
+	}
+	this.evict()
+	return view
+}
+
+func (this *viewStore) release(view *dateView) {
+	this.m.Lock()
+	defer this.m.Unlock()
+	if view.refs > 0 {
+		view.refs--
+	}
+	this.evict()
+}
+
+// evict 超出容量时从最久未使用的日期开始淘汰, 跳过被引用的日期
+func (this *viewStore) evict() {
+	elem := this.lru.Back()
+	for this.lru.Len() > this.capacity && elem != nil {
+		prev := elem.Prev()
+		view := elem.Value.(*dateView)
+		if view.refs == 0 {
+			this.lru.Remove(elem)
+			delete(this.views, view.date)
+		}
+		elem = prev
+	}
+}
+
+// invalidate 特征缓存文件更新后, 丢弃视图中已加载的数据
+func (this *viewStore) invalidate(key, date string) {
+	this.m.Lock()
+	elem, ok := this.views[date]
+	this.m.Unlock()
+	if !ok {
+		return
+	}
+	view := elem.Value.(*dateView)
+	view.m.Lock()
+	delete(view.features, key)
+	view.m.Unlock()
+}
+
+// SetViewCapacity 设置缓存的日期视图数
+func SetViewCapacity(capacity int) {
+	if capacity < 1 {
+		capacity = DefaultViewCapacity
+	}
+	__views.m.Lock()
+	defer __views.m.Unlock()
+	__views.capacity = capacity
+	__views.evict()
+}
+
+// View 指定日期的特征视图
+//
+//	视图只读取缓存文件, 不切换Cache1D当前的日期, 不同日期的视图可以并发使用
+type View struct {
+	view     *dateView
+	released bool
+}
+
+// At 获取指定日期的特征视图
+//
+//	不持有引用, 适合一次性的读取, 长时间使用需要调用Acquire
+func At(date string) *View {
+	return &View{view: __views.get(exchange.FixTradeDate(date), false)}
+}
+
+// Acquire 获取指定日期的特征视图并持有引用, 使用完需要调用Release
+func Acquire(date string) *View {
+	return &View{view: __views.get(exchange.FixTradeDate(date), true)}
+}
+
+// Release 释放视图的引用
+func (this *View) Release() {
+	if this.released {
+		return
+	}
+	this.released = true
+	__views.release(this.view)
+}
+
+// Date 视图的日期
+func (this *View) Date() string {
+	return this.view.date
+}
+
+// Element 获取指定特征的证券数据, key为特征的缓存关键字
+func (this *View) Element(key, securityCode string) Feature {
+	securityCode = exchange.CorrectSecurityCode(securityCode)
+	v, ok := this.view.feature(key)[securityCode]
+	if !ok {
+		return nil
+	}
+	return v
+}
+
+// Filter 过滤指定特征的全部证券数据
+func (this *View) Filter(key string, f func(v Feature) bool) []Feature {
+	var list []Feature
+	for _, v := range this.view.feature(key) {
+		if f == nil || f(v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+func viewElement[T Feature](view *View, key, securityCode string) T {
+	var zero T
+	v, ok := view.Element(key, securityCode).(T)
+	if !ok {
+		return zero
+	}
+	return v
+}
+
+// History 历史数据
+func (this *View) History(securityCode string) *History {
+	return viewElement[*History](this, cacheL5KeyHistory, securityCode)
+}
+
+// F10 基本面
+func (this *View) F10(securityCode string) *F10 {
+	return viewElement[*F10](this, cacheL5KeyF10, securityCode)
+}
+
+// Misc 扩展交易特征
+func (this *View) Misc(securityCode string) *Misc {
+	return viewElement[*Misc](this, cacheL5KeyMisc, securityCode)
+}
+
+// Box 平台
+func (this *View) Box(securityCode string) *Box {
+	return viewElement[*Box](this, cacheL5KeyBox, securityCode)
+}
+
+// InvestmentSentimentMaster 情绪大师
+func (this *View) InvestmentSentimentMaster(securityCode string) *InvestmentSentimentMaster {
+	return viewElement[*InvestmentSentimentMaster](this, cacheL5KeyInvestmentSentimentMaster, securityCode)
+}
+
+// SecuritiesMarginTrading 融资融券
+func (this *View) SecuritiesMarginTrading(securityCode string) *SecuritiesMarginTrading {
+	return viewElement[*SecuritiesMarginTrading](this, cacheL5KeySecuritiesMarginTrading, securityCode)
+}
+
+// WeeklyHistory 周线历史
+func (this *View) WeeklyHistory(securityCode string) *PeriodHistory {
+	return viewElement[*PeriodHistory](this, cacheL5KeyWeekly, securityCode)
+}
+
+// MonthlyHistory 月线历史
+func (this *View) MonthlyHistory(securityCode string) *PeriodHistory {
+	return viewElement[*PeriodHistory](this, cacheL5KeyMonthly, securityCode)
+}
+
+// Chip 筹码分布
+func (this *View) Chip(securityCode string) *Chip {
+	return viewElement[*Chip](this, cacheL5KeyChips, securityCode)
+}
+
+// Alpha 量价因子
+func (this *View) Alpha(securityCode string) *Alpha {
+	return viewElement[*Alpha](this, cacheL5KeyAlpha, securityCode)
+}
+
+// BillBoard 龙虎榜
+func (this *View) BillBoard(securityCode string) *BillBoard {
+	return viewElement[*BillBoard](this, cacheL5KeyBillBoard, securityCode)
+}
+
+// FundFlow 资金流向
+func (this *View) FundFlow(securityCode string) *FundFlow {
+	return viewElement[*FundFlow](this, cacheL5KeyFundFlow, securityCode)
+}
+
+// OrderFlow 成交分析
+func (this *View) OrderFlow(securityCode string) *OrderFlow {
+	return viewElement[*OrderFlow](this, cacheL5KeyOrderFlow, securityCode)
+}
+
+// Unlock 限售解禁
+func (this *View) Unlock(securityCode string) *Unlock {
+	return viewElement[*Unlock](this, cacheL5KeyUnlock, securityCode)
+}
+
+// Dividend 除权除息
+func (this *View) Dividend(securityCode string) *Dividend {
+	return viewElement[*Dividend](this, cacheL5KeyDividend, securityCode)
+}
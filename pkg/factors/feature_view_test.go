@@ -0,0 +1,28 @@
+package factors
+
+import (
+	"container/list"
+	"testing"
+)
+
+func TestViewStoreEvict(t *testing.T) {
+	store := &viewStore{capacity: 2, lru: list.New(), views: map[string]*list.Element{}}
+	pinned := store.get("2024-01-02", true)
+	_ = store.get("2024-01-03", false)
+	_ = store.get("2024-01-04", false)
+	// 被引用的日期不会被淘汰
+	if _, ok := store.views["2024-01-02"]; !ok {
+		t.Error("pinned view evicted")
+	}
+	if _, ok := store.views["2024-01-03"]; ok {
+		t.Error("least recently used view not evicted")
+	}
+	store.release(pinned)
+	_ = store.get("2024-01-05", false)
+	if _, ok := store.views["2024-01-02"]; ok {
+		t.Error("released view not evicted")
+	}
+	if store.lru.Len() != 2 {
+		t.Errorf("len = %d", store.lru.Len())
+	}
+}
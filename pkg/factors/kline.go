@@ -10,19 +10,25 @@ import (
 )
 
 // BasicKLine 基础日K线
-func BasicKLine(securityCode string) pandas.DataFrame {
+//
+//	本地缓存是前复权的K线, adjustment指定其它复权方式时按复权方式转换
+func BasicKLine(securityCode string, adjustment ...base.Adjustment) pandas.DataFrame {
 	securityCode = exchange.CorrectSecurityCode(securityCode)
-	filename := cache.KLineFilename(securityCode)
-	df := pandas.ReadCSV(filename)
-	return df
+	if len(adjustment) > 0 && adjustment[0] != base.ForwardAdjusted {
+		klines := base.LoadAdjustedKLines(securityCode, adjustment[0])
+		return pandas.LoadStructs(klines)
+	}
+	klines := base.LoadBasicKline(securityCode)
+	return pandas.LoadStructs(klines)
 }
 
 // KLine 加载日K线宽表
 func KLine(securityCode string) pandas.DataFrame {
 	securityCode = exchange.CorrectSecurityCode(securityCode)
 	filename := cache.WideFilename(securityCode)
-	df := pandas.ReadCSV(filename)
-	return df
+	var list []SecurityFeature
+	_ = cache.LoadSlices(filename, &list)
+	return pandas.LoadStructs(list)
 }
 
 // KLineToWeekly 日线转周线
@@ -0,0 +1,147 @@
+package factors
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/gox/api"
+	"gitee.com/quant1x/gox/logger"
+
+	"xquant/pkg/cache"
+	"xquant/pkg/columnar"
+	"xquant/pkg/datasource/base"
+	"xquant/pkg/market"
+)
+
+// columnarMigrator 支持把csv缓存转换成列式格式的特征
+type columnarMigrator interface {
+	migrateToColumnar(removeSource bool) (int, error)
+}
+
+// migrateFileToColumnar 转换单个csv文件, 返回是否转换
+func migrateFileToColumnar[T any](filename string, removeSource bool) (bool, error) {
+	var list []T
+	err := api.CsvToSlices(filename, &list)
+	if err != nil || len(list) == 0 {
+		return false, err
+	}
+	if err = cache.WriteColumnar(filename, list); err != nil {
+		return false, err
+	}
+	if removeSource {
+		_ = os.Remove(filename)
+	}
+	return true, nil
+}
+
+// migrateToColumnar 把所有日期的csv缓存文件转换成列式格式
+func (this *Cache1D[T]) migrateToColumnar(removeSource bool) (int, error) {
+	// 用一个固定日期反推缓存文件的目录和前缀
+	const sampleDate = "19700101"
+	sample := getCache1DFilepath(this.cacheKey, sampleDate)
+	prefix := strings.TrimSuffix(filepath.Base(sample), sampleDate)
+	pattern := filepath.Join(filepath.Dir(filepath.Dir(sample)), "*", prefix+"*")
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, filename := range files {
+		if strings.HasSuffix(filename, columnar.FileExtension) || strings.HasSuffix(filename, ".tmp") {
+			continue
+		}
+		ok, err := migrateFileToColumnar[T](filename, removeSource)
+		if err != nil {
+			logger.Errorf("%s 转换失败, error=%+v", filename, err)
+			continue
+		}
+		if ok {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// MigrateFeaturesToColumnar 把特征缓存转换成列式格式, 返回转换的文件数
+//
+//	keywords为空时转换全部特征, 否则只转换关键字或名称匹配的特征
+func MigrateFeaturesToColumnar(keywords []string, removeSource bool) (int, error) {
+	__mutexFeatureRotationAdapters.Lock()
+	adapters := make(map[string]FeatureRotationAdapter, len(__mapFeatureRotationAdapters))
+	for k, v := range __mapFeatureRotationAdapters {
+		adapters[k] = v
+	}
+	__mutexFeatureRotationAdapters.Unlock()
+	total := 0
+	for key, adapter := range adapters {
+		if len(keywords) > 0 && !slicesContains(keywords, key, adapter.Name()) {
+			continue
+		}
+		migrator, ok := adapter.(columnarMigrator)
+		if !ok {
+			continue
+		}
+		count, err := migrator.migrateToColumnar(removeSource)
+		if err != nil {
+			return total, err
+		}
+		logger.Infof("特征[%s]转换列式存储, 文件数=%d", key, count)
+		total += count
+	}
+	return total, nil
+}
+
+// MigrateWideTableToColumnar 把宽表缓存转换成列式格式, 返回转换的文件数
+func MigrateWideTableToColumnar(removeSource bool) (int, error) {
+	count := 0
+	for _, securityCode := range market.GetCodeList() {
+		securityCode = exchange.CorrectSecurityCode(securityCode)
+		filename := cache.WideFilename(securityCode)
+		if !api.FileExist(filename) {
+			continue
+		}
+		ok, err := migrateFileToColumnar[SecurityFeature](filename, removeSource)
+		if err != nil {
+			logger.Errorf("%s 转换失败, error=%+v", filename, err)
+			continue
+		}
+		if ok {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// MigrateKLineToColumnar 把日K线缓存转换成列式格式, 返回转换的文件数
+func MigrateKLineToColumnar(removeSource bool) (int, error) {
+	count := 0
+	for _, securityCode := range market.GetCodeList() {
+		securityCode = exchange.CorrectSecurityCode(securityCode)
+		filename := cache.KLineFilename(securityCode)
+		if !api.FileExist(filename) {
+			continue
+		}
+		ok, err := migrateFileToColumnar[base.KLine](filename, removeSource)
+		if err != nil {
+			logger.Errorf("%s 转换失败, error=%+v", filename, err)
+			continue
+		}
+		if ok {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func slicesContains(keywords []string, values ...string) bool {
+	for _, keyword := range keywords {
+		for _, v := range values {
+			if strings.EqualFold(keyword, v) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,379 @@
+package factors
+
+import (
+	"encoding/csv"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/gox/api"
+	"gitee.com/quant1x/gox/logger"
+	"gitee.com/quant1x/num"
+	"xquant/pkg/cache"
+	"xquant/pkg/config"
+	"xquant/pkg/market"
+)
+
+const (
+	OrderBookLevels = 5 // 盘口档位数
+)
+
+// 封单状态
+const (
+	LimitSealNone = 0  // 未封板
+	LimitSealUp   = 1  // 封涨停
+	LimitSealDown = -1 // 封跌停
+)
+
+// 封单异动
+const (
+	SealAlertNone     = 0  // 无异动
+	SealAlertIncrease = 1  // 封单骤增
+	SealAlertDecrease = -1 // 封单骤减, 包括撤单和炸板
+)
+
+// OrderBook 盘口分析, 由即时行情快照的五档买卖盘计算
+//
+//	成交量单位为手, 金额单位为元
+type OrderBook struct {
+	Date             string  `name:"日期" dataframe:"date"`                  // 交易日期
+	ServerTime       string  `name:"时间" dataframe:"server_time"`           // 快照时间
+	Code             string  `name:"证券代码" dataframe:"code"`                // 证券代码
+	Price            float64 `name:"现价" dataframe:"price"`                 // 现价
+	Bid1             float64 `name:"买一价" dataframe:"bid1"`                 // 买一价
+	Ask1             float64 `name:"卖一价" dataframe:"ask1"`                 // 卖一价
+	BidVolume        int     `name:"买盘量" dataframe:"bid_volume"`           // 五档买盘合计
+	AskVolume        int     `name:"卖盘量" dataframe:"ask_volume"`           // 五档卖盘合计
+	Imbalance        float64 `name:"委比%" dataframe:"imbalance"`            // (买盘量-卖盘量)/(买盘量+卖盘量)
+	WeightedMidPrice float64 `name:"加权中间价" dataframe:"weighted_mid_price"` // 按买一卖一的挂单量加权的中间价
+	SpreadTicks      int     `name:"价差档数" dataframe:"spread_ticks"`        // 卖一价和买一价相差的最小变动单位数
+	Pressure         float64 `name:"盘口压力%" dataframe:"pressure"`           // 按档位距离加权的委比, 第N档权重为1/N
+	LimitStatus      int     `name:"封板状态" dataframe:"limit_status"`        // 1-封涨停, -1-封跌停, 0-未封板
+	SealVolume       int     `name:"封单量" dataframe:"seal_volume"`          // 涨停价买一或跌停价卖一的挂单量
+	SealAmount       float64 `name:"封单金额" dataframe:"seal_amount"`         // 封单金额
+	SealRatio        float64 `name:"封成比%" dataframe:"seal_ratio"`          // 封单金额/当日成交金额
+	SealChange       int     `name:"封单变化" dataframe:"seal_change"`         // 封单量较上一个快照的变化
+	SealChangeRate   float64 `name:"封单变化率%" dataframe:"seal_change_rate"`  // 封单量较上一个快照的变化率
+	SealAlert        int     `name:"封单异动" dataframe:"seal_alert"`          // 1-骤增, -1-骤减, 0-无异动
+	UpdateTime       string  `name:"更新时间" dataframe:"update_time"`         // 本地时间戳
+}
+
+var (
+	// 盘口记录的表头, 顺序和OrderBook的字段一致
+	orderBookHeaders = []string{"date", "server_time", "code", "price", "bid1", "ask1", "bid_volume", "ask_volume",
+		"imbalance", "weighted_mid_price", "spread_ticks", "pressure", "limit_status", "seal_volume", "seal_amount",
+		"seal_ratio", "seal_change", "seal_change_rate", "seal_alert", "update_time"}
+)
+
+// record 盘口记录的一行
+func (this OrderBook) record() []string {
+	f := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return []string{this.Date, this.ServerTime, this.Code, f(this.Price), f(this.Bid1), f(this.Ask1),
+		strconv.Itoa(this.BidVolume), strconv.Itoa(this.AskVolume), f(this.Imbalance), f(this.WeightedMidPrice),
+		strconv.Itoa(this.SpreadTicks), f(this.Pressure), strconv.Itoa(this.LimitStatus), strconv.Itoa(this.SealVolume),
+		f(this.SealAmount), f(this.SealRatio), strconv.Itoa(this.SealChange), f(this.SealChangeRate),
+		strconv.Itoa(this.SealAlert), this.UpdateTime}
+}
+
+// bookLevels 五档买卖盘的价格和挂单量
+func bookLevels(q QuoteSnapshot) (bids, asks [OrderBookLevels]float64, bidVols, askVols [OrderBookLevels]int) {
+	bids = [OrderBookLevels]float64{q.Bid1, q.Bid2, q.Bid3, q.Bid4, q.Bid5}
+	asks = [OrderBookLevels]float64{q.Ask1, q.Ask2, q.Ask3, q.Ask4, q.Ask5}
+	bidVols = [OrderBookLevels]int{q.BidVol1, q.BidVol2, q.BidVol3, q.BidVol4, q.BidVol5}
+	askVols = [OrderBookLevels]int{q.AskVol1, q.AskVol2, q.AskVol3, q.AskVol4, q.AskVol5}
+	return
+}
+
+// ComputeOrderBook 计算盘口分析
+//
+//	limit为当日的涨跌停价格, volUnit为每手的股数, last为同一证券上一个快照的盘口分析, 没有时传nil.
+//	封单量较上一个快照的变化率绝对值不低于alertRate(%)时视为封单异动
+func ComputeOrderBook(q QuoteSnapshot, limit market.Limit, volUnit int, last *OrderBook, alertRate float64) OrderBook {
+	if volUnit <= 0 {
+		volUnit = 100
+	}
+	book := OrderBook{
+		Date:       q.Date,
+		ServerTime: q.ServerTime,
+		Code:       q.SecurityCode,
+		Price:      q.Price,
+		Bid1:       q.Bid1,
+		Ask1:       q.Ask1,
+	}
+	bids, asks, bidVols, askVols := bookLevels(q)
+	var bidWeighted, askWeighted float64
+	for i := 0; i < OrderBookLevels; i++ {
+		weight := 1 / float64(i+1)
+		if bids[i] > 0 {
+			book.BidVolume += bidVols[i]
+			bidWeighted += float64(bidVols[i]) * weight
+		}
+		if asks[i] > 0 {
+			book.AskVolume += askVols[i]
+			askWeighted += float64(askVols[i]) * weight
+		}
+	}
+	if total := book.BidVolume + book.AskVolume; total > 0 {
+		book.Imbalance = num.Decimal(float64(book.BidVolume-book.AskVolume) / float64(total) * 100)
+	}
+	if total := bidWeighted + askWeighted; total > 0 {
+		book.Pressure = num.Decimal((bidWeighted - askWeighted) / total * 100)
+	}
+	// 买一卖一都有挂单时才有中间价和价差
+	if q.Bid1 > 0 && q.Ask1 > 0 && q.BidVol1+q.AskVol1 > 0 {
+		// 买盘越厚, 中间价越靠近卖一
+		book.WeightedMidPrice = (q.Bid1*float64(q.AskVol1) + q.Ask1*float64(q.BidVol1)) / float64(q.BidVol1+q.AskVol1)
+		tick := limit.Tick
+		if tick <= 0 {
+			tick = market.PriceTick(q.SecurityCode)
+		}
+		book.SpreadTicks = int(math.Round((q.Ask1 - q.Bid1) / tick))
+	}
+	// 封单: 涨停价买一且没有卖盘, 或者跌停价卖一且没有买盘
+	switch {
+	case book.AskVolume == 0 && q.BidVol1 > 0 && limit.IsLimitUp(q.Bid1):
+		book.LimitStatus = LimitSealUp
+		book.SealVolume = q.BidVol1
+		book.SealAmount = float64(q.BidVol1*volUnit) * q.Bid1
+	case book.BidVolume == 0 && q.AskVol1 > 0 && limit.IsLimitDown(q.Ask1):
+		book.LimitStatus = LimitSealDown
+		book.SealVolume = q.AskVol1
+		book.SealAmount = float64(q.AskVol1*volUnit) * q.Ask1
+	}
+	if book.SealAmount > 0 && q.Amount > 0 {
+		book.SealRatio = num.Decimal(book.SealAmount / q.Amount * 100)
+	}
+	// 封单变化只和同一交易日、同一方向的封单比较, 炸板视为封单全部撤掉
+	if last != nil && last.Date == book.Date && last.LimitStatus != LimitSealNone && last.SealVolume > 0 {
+		lastSeal := last.SealVolume
+		if book.LimitStatus == last.LimitStatus {
+			book.SealChange = book.SealVolume - lastSeal
+		} else {
+			book.SealChange = -lastSeal
+		}
+		book.SealChangeRate = num.Decimal(float64(book.SealChange) / float64(lastSeal) * 100)
+		if alertRate > 0 && math.Abs(book.SealChangeRate) >= alertRate {
+			if book.SealChange > 0 {
+				book.SealAlert = SealAlertIncrease
+			} else {
+				book.SealAlert = SealAlertDecrease
+			}
+		}
+	}
+	return book
+}
+
+// NewOrderBook 计算即时行情快照的盘口分析, last为同一证券上一个快照的盘口分析
+func NewOrderBook(q QuoteSnapshot, last *OrderBook) OrderBook {
+	var limit market.Limit
+	// 只有单边挂单时才可能封板, 避免每个快照都计算涨跌停价
+	if q.Ask1 == 0 || q.Bid1 == 0 {
+		limit = CheckoutPriceLimit(q.SecurityCode, q.Date, q.LastClose)
+	}
+	volUnit := 100
+	if f10 := GetL5F10(q.SecurityCode, q.Date); f10 != nil && f10.VolUnit > 0 {
+		volUnit = f10.VolUnit
+	}
+	alertRate := config.GetDataConfig().Snapshot.SealAlertRate
+	book := ComputeOrderBook(q, limit, volUnit, last, alertRate)
+	book.UpdateTime = GetTimestamp()
+	return book
+}
+
+// SetOrderBook 更新快照的盘口分析字段
+func (q *QuoteSnapshot) SetOrderBook(book OrderBook) {
+	q.BookImbalance = book.Imbalance
+	q.WeightedMidPrice = book.WeightedMidPrice
+	q.SpreadTicks = book.SpreadTicks
+	q.BookPressure = book.Pressure
+	q.LimitStatus = book.LimitStatus
+	q.SealVolume = book.SealVolume
+	q.SealAmount = book.SealAmount
+	q.SealRatio = book.SealRatio
+	q.SealChange = book.SealChange
+	q.SealChangeRate = book.SealChangeRate
+	q.SealAlert = book.SealAlert
+}
+
+const (
+	orderBookFlushRows     = 100000          // 缓冲的盘口记录达到行数时落盘
+	orderBookFlushInterval = 5 * time.Minute // 距离上一次落盘超过间隔时落盘
+)
+
+var (
+	__mutexOrderBook  sync.Mutex
+	__orderBookBuffer orderBookBuffer
+	__orderBookDaily  orderBookSummary // 最近一次读取的盘口每日汇总
+)
+
+// 盘口记录的写缓冲
+type orderBookBuffer struct {
+	date    string                 // 缓冲数据的交易日期
+	rows    map[string][]OrderBook // 按证券代码缓冲未落盘的盘口记录
+	last    map[string]OrderBook   // 每个证券最后一次记录的盘口分析, 落盘时写入每日汇总
+	pending int                    // 未落盘的行数
+	flushed time.Time              // 上一次落盘的时间
+}
+
+// 盘口分析的每日汇总
+type orderBookSummary struct {
+	date  string
+	books map[string]OrderBook
+}
+
+// 切换交易日期, 用已有的每日汇总初始化每个证券最后一次记录的盘口分析
+func (this *orderBookBuffer) reset(date string) {
+	this.date = date
+	this.rows = map[string][]OrderBook{}
+	this.last = loadOrderBookSummary(date)
+	this.pending = 0
+	this.flushed = time.Now()
+}
+
+// 按证券追加缓冲的盘口记录, 并覆盖每日汇总
+func (this *orderBookBuffer) flush() {
+	if this.pending == 0 {
+		return
+	}
+	for code, rows := range this.rows {
+		if err := appendOrderBooks(code, this.date, rows); err != nil {
+			logger.Errorf("code[%s, %s] 盘口记录失败: %+v", code, this.date, err)
+		}
+	}
+	if err := saveOrderBookSummary(this.date, this.last); err != nil {
+		logger.Errorf("盘口汇总[%s]保存失败: %+v", this.date, err)
+	}
+	this.rows = map[string][]OrderBook{}
+	this.pending = 0
+	this.flushed = time.Now()
+}
+
+// SaveOrderBooks 缓冲盘口分析, 定期追加到每个证券当日的快照文件, 每个快照一行
+//
+//	快照时间和上一次记录相同的盘口分析不再记录. 缓冲的行数或时间超过阀值时落盘, 交易日期切换时落盘前一日的数据
+func SaveOrderBooks(books []OrderBook) {
+	__mutexOrderBook.Lock()
+	defer __mutexOrderBook.Unlock()
+	buf := &__orderBookBuffer
+	for _, v := range books {
+		if v.Date != buf.date {
+			buf.flush()
+			buf.reset(v.Date)
+		}
+		if last, ok := buf.last[v.Code]; ok && last.ServerTime == v.ServerTime {
+			continue
+		}
+		buf.rows[v.Code] = append(buf.rows[v.Code], v)
+		buf.last[v.Code] = v
+		buf.pending++
+	}
+	if buf.pending >= orderBookFlushRows || time.Since(buf.flushed) >= orderBookFlushInterval {
+		buf.flush()
+	}
+}
+
+// FlushOrderBooks 缓冲的盘口记录落盘, 同时保存每日汇总, 收盘后和进程退出前调用
+func FlushOrderBooks() {
+	__mutexOrderBook.Lock()
+	defer __mutexOrderBook.Unlock()
+	__orderBookBuffer.flush()
+}
+
+func appendOrderBooks(securityCode, date string, books []OrderBook) error {
+	filename := cache.SnapshotFilename(securityCode, date)
+	exist := api.FileExist(filename)
+	if !exist {
+		if err := os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer api.CloseQuietly(f)
+	w := csv.NewWriter(f)
+	if !exist {
+		_ = w.Write(orderBookHeaders)
+	}
+	for _, v := range books {
+		_ = w.Write(v.record())
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// saveOrderBookSummary 保存盘口分析的每日汇总, 按证券代码排序
+func saveOrderBookSummary(date string, books map[string]OrderBook) error {
+	codes := api.Keys(books)
+	slices.Sort(codes)
+	list := make([]OrderBook, 0, len(codes))
+	for _, code := range codes {
+		list = append(list, books[code])
+	}
+	filename := cache.OrderBookSummaryFilename(date)
+	if err := os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+		return err
+	}
+	if err := api.SlicesToCsv(filename, list); err != nil {
+		return err
+	}
+	// 内存中的汇总失效, 调用方已持有__mutexOrderBook
+	if __orderBookDaily.date == date {
+		__orderBookDaily = orderBookSummary{}
+	}
+	return nil
+}
+
+// loadOrderBookSummary 读取盘口分析的每日汇总, 没有汇总文件时返回nil
+func loadOrderBookSummary(date string) map[string]OrderBook {
+	var list []OrderBook
+	_ = api.CsvToSlices(cache.OrderBookSummaryFilename(date), &list)
+	books := make(map[string]OrderBook, len(list))
+	for _, v := range list {
+		books[v.Code] = v
+	}
+	return books
+}
+
+// LoadOrderBooks 读取证券在指定交易日记录的全部盘口分析, 按时间升序
+func LoadOrderBooks(securityCode, date string) []OrderBook {
+	securityCode = exchange.CorrectSecurityCode(securityCode)
+	filename := cache.SnapshotFilename(securityCode, date)
+	var list []OrderBook
+	_ = api.CsvToSlices(filename, &list)
+	return list
+}
+
+// GetOrderBook 证券在指定交易日最后一个快照的盘口分析, 用于回测, 没有记录时返回nil
+//
+//	优先读取每日汇总, 没有汇总的历史数据读取当日全部的盘口记录
+func GetOrderBook(securityCode, date string) *OrderBook {
+	securityCode = exchange.CorrectSecurityCode(securityCode)
+	date = exchange.FixTradeDate(date)
+	if api.FileExist(cache.OrderBookSummaryFilename(date)) {
+		__mutexOrderBook.Lock()
+		if __orderBookDaily.date != date {
+			__orderBookDaily = orderBookSummary{date: date, books: loadOrderBookSummary(date)}
+		}
+		v, ok := __orderBookDaily.books[securityCode]
+		__mutexOrderBook.Unlock()
+		if !ok {
+			return nil
+		}
+		return &v
+	}
+	list := LoadOrderBooks(securityCode, date)
+	if len(list) == 0 {
+		return nil
+	}
+	v := list[len(list)-1]
+	return &v
+}
@@ -0,0 +1,72 @@
+package factors
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"xquant/pkg/market"
+)
+
+func TestComputeOrderBook(t *testing.T) {
+	limit := market.Limit{Tick: 0.01, LimitUp: 11.00, LimitDown: 9.00}
+	q := QuoteSnapshot{Date: "2024-06-03", SecurityCode: "sh600000", LastClose: 10.00, Price: 10.01, Amount: 1e7,
+		Bid1: 10.00, BidVol1: 300, Bid2: 9.99, BidVol2: 200,
+		Ask1: 10.02, AskVol1: 100, Ask2: 10.03, AskVol2: 100}
+	book := ComputeOrderBook(q, limit, 100, nil, 30)
+	if book.BidVolume != 500 || book.AskVolume != 200 {
+		t.Fatalf("bid_volume=%d, ask_volume=%d", book.BidVolume, book.AskVolume)
+	}
+	if book.Imbalance != 42.86 || book.Pressure != 45.45 {
+		t.Errorf("imbalance=%f, pressure=%f", book.Imbalance, book.Pressure)
+	}
+	if math.Abs(book.WeightedMidPrice-10.015) > 1e-9 || book.SpreadTicks != 2 {
+		t.Errorf("weighted_mid_price=%f, spread_ticks=%d", book.WeightedMidPrice, book.SpreadTicks)
+	}
+	if book.LimitStatus != LimitSealNone || book.SealVolume != 0 {
+		t.Errorf("limit_status=%d, seal_volume=%d", book.LimitStatus, book.SealVolume)
+	}
+
+	// 封涨停, 封单较上一个快照减少一半
+	sealed := QuoteSnapshot{Date: "2024-06-03", SecurityCode: "sh600000", LastClose: 10.00, Price: 11.00, Amount: 1e7,
+		Bid1: 11.00, BidVol1: 5000, Bid2: 10.99, BidVol2: 100}
+	last := OrderBook{Date: "2024-06-03", LimitStatus: LimitSealUp, SealVolume: 10000}
+	book = ComputeOrderBook(sealed, limit, 100, &last, 30)
+	if book.LimitStatus != LimitSealUp || book.SealVolume != 5000 || book.SealAmount != 5.5e6 || book.SealRatio != 55 {
+		t.Errorf("limit_status=%d, seal_volume=%d, seal_amount=%f, seal_ratio=%f", book.LimitStatus, book.SealVolume, book.SealAmount, book.SealRatio)
+	}
+	if book.SealChange != -5000 || book.SealChangeRate != -50 || book.SealAlert != SealAlertDecrease {
+		t.Errorf("seal_change=%d, seal_change_rate=%f, seal_alert=%d", book.SealChange, book.SealChangeRate, book.SealAlert)
+	}
+	if book.WeightedMidPrice != 0 || book.SpreadTicks != 0 {
+		t.Errorf("weighted_mid_price=%f, spread_ticks=%d", book.WeightedMidPrice, book.SpreadTicks)
+	}
+
+	// 炸板, 封单视为全部撤掉
+	book = ComputeOrderBook(q, limit, 100, &last, 30)
+	if book.SealChange != -10000 || book.SealChangeRate != -100 || book.SealAlert != SealAlertDecrease {
+		t.Errorf("seal_change=%d, seal_change_rate=%f, seal_alert=%d", book.SealChange, book.SealChangeRate, book.SealAlert)
+	}
+
+	// 不同交易日的封单不比较
+	last.Date = "2024-05-31"
+	book = ComputeOrderBook(sealed, limit, 100, &last, 30)
+	if book.SealChange != 0 || book.SealAlert != SealAlertNone {
+		t.Errorf("seal_change=%d, seal_alert=%d", book.SealChange, book.SealAlert)
+	}
+}
+
+func TestOrderBook_record(t *testing.T) {
+	typ := reflect.TypeOf(OrderBook{})
+	if typ.NumField() != len(orderBookHeaders) {
+		t.Fatalf("fields=%d, headers=%d", typ.NumField(), len(orderBookHeaders))
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		if tag := typ.Field(i).Tag.Get("dataframe"); tag != orderBookHeaders[i] {
+			t.Errorf("field[%d]: tag=%s, header=%s", i, tag, orderBookHeaders[i])
+		}
+	}
+	if n := len(OrderBook{}.record()); n != len(orderBookHeaders) {
+		t.Errorf("record=%d, headers=%d", n, len(orderBookHeaders))
+	}
+}
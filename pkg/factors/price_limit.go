@@ -0,0 +1,136 @@
+package factors
+
+import (
+	"math"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/gotdx/securities"
+	"xquant/pkg/datasource/base"
+	"xquant/pkg/market"
+)
+
+// CheckoutPriceLimit 证券在指定交易日的涨跌停价格
+//
+//	风险警示状态取当日的基本面数据中的证券名称, 基本面数据不存在时取当前的证券名称.
+//	上市天数按上市日期到交易日的交易日数计算. lastClose不大于0时, 取当日历史特征中的昨收
+func CheckoutPriceLimit(securityCode, date string, lastClose float64) market.Limit {
+	securityCode = exchange.CorrectSecurityCode(securityCode)
+	if len(date) == 0 {
+		date = exchange.LastTradeDate()
+	}
+	date = exchange.FixTradeDate(date)
+	if lastClose <= 0 {
+		if history := GetL5History(securityCode, date); history != nil {
+			lastClose = history.CLOSE
+		}
+	}
+	status := checkoutSecurityStatus(securityCode, date)
+	return market.CalculatePriceLimit(securityCode, date, lastClose, status)
+}
+
+// PriceLimitSeries 按K线逐日计算涨停价和跌停价, 昨收取前一根K线的收盘价
+//
+//	风险警示状态按K线日期分段确定, 上市天数按K线的序号递推.
+//	首根K线和不设涨跌幅限制的交易日为NaN
+func PriceLimitSeries(securityCode string, klines []base.KLine) (limitUp, limitDown []float64) {
+	n := len(klines)
+	limitUp, limitDown = make([]float64, n), make([]float64, n)
+	if n == 0 {
+		return
+	}
+	securityCode = exchange.CorrectSecurityCode(securityCode)
+	status := checkoutSecurityStatus(securityCode, klines[n-1].Date)
+	listingDays := status.ListingDays
+	st := specialTreatmentSeries(securityCode, klines)
+	for i := 0; i < n; i++ {
+		lastClose := 0.00
+		if i > 0 {
+			lastClose = klines[i-1].Close
+		}
+		status.ST = st[i]
+		status.ListingDays = 0
+		if listingDays > 0 {
+			status.ListingDays = max(listingDays-(n-1-i), 0)
+		}
+		limit := market.CalculatePriceLimit(securityCode, klines[i].Date, lastClose, status)
+		if limit.NoLimit || limit.LimitUp <= 0 {
+			limitUp[i], limitDown[i] = math.NaN(), math.NaN()
+			continue
+		}
+		limitUp[i], limitDown[i] = limit.LimitUp, limit.LimitDown
+	}
+	return
+}
+
+// stProbeBars 风险警示状态抽样的最大间隔
+const stProbeBars = 60
+
+// specialTreatmentSeries 按K线逐日确定风险警示状态
+func specialTreatmentSeries(securityCode string, klines []base.KLine) []bool {
+	return segmentSeries(len(klines), func(i int) bool {
+		return isSpecialTreatment(securityCode, klines[i].Date)
+	})
+}
+
+// segmentSeries 按分段抽样确定n个交易日的状态
+//
+//	状态只在少数交易日变化, 逐日读取基本面数据的代价太大. 区间首尾的状态不一致时二分查找变化的交易日,
+//	首尾一致且区间不超过stProbeBars根K线时视为状态不变
+func segmentSeries(n int, probe func(i int) bool) []bool {
+	series := make([]bool, n)
+	if n == 0 {
+		return series
+	}
+	var split func(lo, hi int, vLo, vHi bool)
+	split = func(lo, hi int, vLo, vHi bool) {
+		if hi-lo <= 1 || (vLo == vHi && hi-lo <= stProbeBars) {
+			for i := lo; i < hi; i++ {
+				series[i] = vLo
+			}
+			series[hi] = vHi
+			return
+		}
+		mid := (lo + hi) / 2
+		vMid := probe(mid)
+		split(lo, mid, vLo, vMid)
+		split(mid, hi, vMid, vHi)
+	}
+	split(0, n-1, probe(0), probe(n-1))
+	return series
+}
+
+// isSpecialTreatment 证券在指定交易日是否处于风险警示状态
+//
+//	取当日的基本面数据中的证券名称, 基本面数据不存在时取当前的证券名称
+func isSpecialTreatment(securityCode, date string) bool {
+	name := ""
+	if f10 := GetL5F10(securityCode, date); f10 != nil {
+		name = f10.SecurityName
+	}
+	if len(name) == 0 {
+		if info, ok := securities.CheckoutSecurityInfo(securityCode); ok {
+			name = info.Name
+		}
+	}
+	return market.IsSpecialTreatment(name)
+}
+
+// checkoutSecurityStatus 证券在指定交易日的风险警示状态和上市天数
+func checkoutSecurityStatus(securityCode, date string) market.SecurityStatus {
+	var status market.SecurityStatus
+	status.ST = isSpecialTreatment(securityCode, date)
+	ipoDate := ""
+	if f10 := GetL5F10(securityCode, date); f10 != nil {
+		ipoDate = f10.IpoDate
+	}
+	if len(ipoDate) == 0 {
+		ipoDate = getIpoDate(securityCode, date)
+	}
+	if len(ipoDate) > 0 {
+		ipoDate = exchange.FixTradeDate(ipoDate)
+		if ipoDate <= date {
+			status.ListingDays = len(exchange.TradingDateRange(ipoDate, date))
+		}
+	}
+	return status
+}
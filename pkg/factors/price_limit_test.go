@@ -0,0 +1,40 @@
+package factors
+
+import (
+	"fmt"
+	"testing"
+
+	"gitee.com/quant1x/exchange"
+	"xquant/pkg/datasource/base"
+)
+
+func TestCheckoutPriceLimit(t *testing.T) {
+	code := "sz300956"
+	date := exchange.LastTradeDate()
+	limit := CheckoutPriceLimit(code, date, 0)
+	fmt.Printf("%+v\n", limit)
+	klines := base.CheckoutKLines(code, date)
+	up, down := PriceLimitSeries(code, klines)
+	if len(up) != len(klines) || len(down) != len(klines) {
+		t.Fatalf("length: %d %d %d", len(klines), len(up), len(down))
+	}
+}
+
+func Test_segmentSeries(t *testing.T) {
+	n := 1000
+	// 第300到第520个交易日处于风险警示状态
+	want := func(i int) bool { return i >= 300 && i <= 520 }
+	probes := 0
+	got := segmentSeries(n, func(i int) bool {
+		probes++
+		return want(i)
+	})
+	for i := 0; i < n; i++ {
+		if got[i] != want(i) {
+			t.Fatalf("i=%d, got=%t, want=%t", i, got[i], want(i))
+		}
+	}
+	if probes >= n/2 {
+		t.Errorf("probes=%d", probes)
+	}
+}
@@ -53,6 +53,37 @@ type QuoteSnapshot struct {
 	QuantityRatio         float64              `name:"量比"`
 	ChangePower           float64              `name:"涨跌力度"` // 开盘金额除以开盘涨幅
 	AverageBiddingVolume  int                  `name:"委托均量"` // 委托均量
+	Bid1                  float64              // 买一价
+	Ask1                  float64              // 卖一价
+	BidVol1               int                  // 买一量
+	AskVol1               int                  // 卖一量
+	Bid2                  float64              // 买二价
+	Ask2                  float64              // 卖二价
+	BidVol2               int                  // 买二量
+	AskVol2               int                  // 卖二量
+	Bid3                  float64              // 买三价
+	Ask3                  float64              // 卖三价
+	BidVol3               int                  // 买三量
+	AskVol3               int                  // 卖三量
+	Bid4                  float64              // 买四价
+	Ask4                  float64              // 卖四价
+	BidVol4               int                  // 买四量
+	AskVol4               int                  // 卖四量
+	Bid5                  float64              // 买五价
+	Ask5                  float64              // 卖五价
+	BidVol5               int                  // 买五量
+	AskVol5               int                  // 卖五量
+	BookImbalance         float64              `name:"委比%"`    // 五档买卖盘的委比
+	WeightedMidPrice      float64              `name:"加权中间价"`  // 按买一卖一挂单量加权的中间价
+	SpreadTicks           int                  `name:"价差档数"`   // 卖一价和买一价相差的最小变动单位数
+	BookPressure          float64              `name:"盘口压力%"`  // 按档位距离加权的委比
+	LimitStatus           int                  `name:"封板状态"`   // 1-封涨停, -1-封跌停, 0-未封板
+	SealVolume            int                  `name:"封单量"`    // 封单量, 单位是手
+	SealAmount            float64              `name:"封单金额"`   // 封单金额
+	SealRatio             float64              `name:"封成比%"`   // 封单金额/成交金额
+	SealChange            int                  `name:"封单变化"`   // 封单量较上一个快照的变化
+	SealChangeRate        float64              `name:"封单变化率%"` // 封单量较上一个快照的变化率
+	SealAlert             int                  `name:"封单异动"`   // 1-骤增, -1-骤减, 0-无异动
 	NextOpen              float64              // 仅回测有效: 下一个交易日开盘价
 	NextClose             float64              // 仅回测有效: 下一个交易日收盘价
 	NextHigh              float64              // 仅回测有效: 下一个交易日最高价
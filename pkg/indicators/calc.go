@@ -0,0 +1,282 @@
+package indicators
+
+import (
+	"math"
+)
+
+// 技术指标的计算, 公式和默认参数与通达信保持一致
+
+// calcMACD DIF:EMA(C,SHORT)-EMA(C,LONG); DEA:EMA(DIF,MID); MACD:(DIF-DEA)*2
+func calcMACD(b Bars, p Params) map[string][]float64 {
+	dif := zip(EMA(b.Close, p.Int("SHORT")), EMA(b.Close, p.Int("LONG")), sub)
+	dea := EMA(dif, p.Int("MID"))
+	macd := apply(zip(dif, dea, sub), func(v float64) float64 { return v * 2 })
+	return map[string][]float64{"DIF": dif, "DEA": dea, "MACD": macd}
+}
+
+// calcBOLL MB:MA(C,N); UP:MB+P*STD(C,N); DN:MB-P*STD(C,N)
+func calcBOLL(b Bars, p Params) map[string][]float64 {
+	mb := MA(b.Close, p.Int("N"))
+	width := apply(STD(b.Close, p.Int("N")), func(v float64) float64 { return v * p.Float("P") })
+	return map[string][]float64{"MB": mb, "UP": zip(mb, width, add), "DN": zip(mb, width, sub)}
+}
+
+// calcRSI RSI:SMA(MAX(C-LC,0),N,1)/SMA(ABS(C-LC),N,1)*100
+func calcRSI(b Bars, p Params) map[string][]float64 {
+	change := zip(b.Close, REF(b.Close, 1), sub)
+	up := apply(change, func(v float64) float64 { return max(v, 0) })
+	abs := apply(change, math.Abs)
+	rsi := func(n int) []float64 {
+		return apply(zip(SMA(up, n, 1), SMA(abs, n, 1), div), func(v float64) float64 { return v * 100 })
+	}
+	return map[string][]float64{"RSI1": rsi(p.Int("N1")), "RSI2": rsi(p.Int("N2")), "RSI3": rsi(p.Int("N3"))}
+}
+
+// calcKDJ RSV:(C-LLV(L,N))/(HHV(H,N)-LLV(L,N))*100; K:SMA(RSV,M1,1); D:SMA(K,M2,1); J:3*K-2*D
+func calcKDJ(b Bars, p Params) map[string][]float64 {
+	n := p.Int("N")
+	llv := LLV(b.Low, n)
+	rsv := apply(zip(zip(b.Close, llv, sub), zip(HHV(b.High, n), llv, sub), div), func(v float64) float64 { return v * 100 })
+	k := SMA(rsv, p.Int("M1"), 1)
+	d := SMA(k, p.Int("M2"), 1)
+	j := zip(k, d, func(k, d float64) float64 { return 3*k - 2*d })
+	return map[string][]float64{"K": k, "D": d, "J": j}
+}
+
+// trueRange TR:MAX(MAX(H-L,ABS(REF(C,1)-H)),ABS(REF(C,1)-L))
+func trueRange(b Bars) []float64 {
+	lc := REF(b.Close, 1)
+	tr := make([]float64, b.Len())
+	for i := range tr {
+		tr[i] = b.High[i] - b.Low[i]
+		if !math.IsNaN(lc[i]) {
+			tr[i] = max(tr[i], math.Abs(lc[i]-b.High[i]), math.Abs(lc[i]-b.Low[i]))
+		}
+	}
+	return tr
+}
+
+// calcATR TR:真实波幅; ATR:MA(TR,N)
+func calcATR(b Bars, p Params) map[string][]float64 {
+	tr := trueRange(b)
+	return map[string][]float64{"TR": tr, "ATR": MA(tr, p.Int("N"))}
+}
+
+// calcOBV VA:IF(C>REF(C,1),V,IF(C<REF(C,1),-V,0)); OBV:SUM(VA,0); MAOBV:MA(OBV,M)
+func calcOBV(b Bars, p Params) map[string][]float64 {
+	lc := REF(b.Close, 1)
+	va := make([]float64, b.Len())
+	for i := range va {
+		switch {
+		case b.Close[i] > lc[i]:
+			va[i] = b.Volume[i]
+		case b.Close[i] < lc[i]:
+			va[i] = -b.Volume[i]
+		}
+	}
+	obv := SUM(va, 0)
+	return map[string][]float64{"OBV": obv, "MAOBV": MA(obv, p.Int("M"))}
+}
+
+// calcDMI 趋向指标
+//
+//	MTR:SUM(TR,N); HD:H-REF(H,1); LD:REF(L,1)-L;
+//	DMP:SUM(IF(HD>0&&HD>LD,HD,0),N); DMM:SUM(IF(LD>0&&LD>HD,LD,0),N);
+//	PDI:DMP*100/MTR; MDI:DMM*100/MTR; ADX:MA(ABS(MDI-PDI)/(MDI+PDI)*100,M); ADXR:(ADX+REF(ADX,M))/2
+func calcDMI(b Bars, p Params) map[string][]float64 {
+	n, m := p.Int("N"), p.Int("M")
+	size := b.Len()
+	dmp, dmm := make([]float64, size), make([]float64, size)
+	for i := 1; i < size; i++ {
+		hd := b.High[i] - b.High[i-1]
+		ld := b.Low[i-1] - b.Low[i]
+		if hd > 0 && hd > ld {
+			dmp[i] = hd
+		}
+		if ld > 0 && ld > hd {
+			dmm[i] = ld
+		}
+	}
+	mtr := SUM(trueRange(b), n)
+	pdi := apply(zip(SUM(dmp, n), mtr, div), func(v float64) float64 { return v * 100 })
+	mdi := apply(zip(SUM(dmm, n), mtr, div), func(v float64) float64 { return v * 100 })
+	dx := zip(mdi, pdi, func(m, p float64) float64 { return div(math.Abs(m-p), m+p) * 100 })
+	adx := MA(dx, m)
+	adxr := apply(zip(adx, REF(adx, m), add), func(v float64) float64 { return v / 2 })
+	return map[string][]float64{"PDI": pdi, "MDI": mdi, "ADX": adx, "ADXR": adxr}
+}
+
+// typical TYP:(H+L+C)/3
+func typical(b Bars) []float64 {
+	typ := make([]float64, b.Len())
+	for i := range typ {
+		typ[i] = (b.High[i] + b.Low[i] + b.Close[i]) / 3
+	}
+	return typ
+}
+
+// calcCCI CCI:(TYP-MA(TYP,N))*1000/(15*AVEDEV(TYP,N))
+func calcCCI(b Bars, p Params) map[string][]float64 {
+	n := p.Int("N")
+	typ := typical(b)
+	cci := zip(zip(typ, MA(typ, n), sub), AVEDEV(typ, n), func(d, dev float64) float64 { return div(d*1000, 15*dev) })
+	return map[string][]float64{"CCI": cci}
+}
+
+// calcWR WR1:100*(HHV(H,N)-C)/(HHV(H,N)-LLV(L,N)); WR2同N1
+func calcWR(b Bars, p Params) map[string][]float64 {
+	wr := func(n int) []float64 {
+		hhv := HHV(b.High, n)
+		return apply(zip(zip(hhv, b.Close, sub), zip(hhv, LLV(b.Low, n), sub), div), func(v float64) float64 { return v * 100 })
+	}
+	return map[string][]float64{"WR1": wr(p.Int("N")), "WR2": wr(p.Int("N1"))}
+}
+
+// calcSAR 抛物线转向, STEP为加速因子步长, MAX为加速因子上限
+//
+//	BULL为1时多头, -1时空头
+func calcSAR(b Bars, p Params) map[string][]float64 {
+	size := b.Len()
+	sar, bull := nans(size), nans(size)
+	if size < 2 {
+		return map[string][]float64{"SAR": sar, "BULL": bull}
+	}
+	step, limit := p.Float("STEP"), p.Float("MAX")
+	isBull := b.Close[1] >= b.Close[0]
+	af := step
+	ep, value := b.High[0], b.Low[0]
+	if !isBull {
+		ep, value = b.Low[0], b.High[0]
+	}
+	for i := 1; i < size; i++ {
+		value = value + af*(ep-value)
+		if isBull {
+			// SAR不能高于前两根K线的最低价
+			value = min(value, b.Low[i-1])
+			if i >= 2 {
+				value = min(value, b.Low[i-2])
+			}
+			if b.Low[i] < value {
+				isBull, value, ep, af = false, ep, b.Low[i], step
+			} else if b.High[i] > ep {
+				ep, af = b.High[i], min(af+step, limit)
+			}
+		} else {
+			value = max(value, b.High[i-1])
+			if i >= 2 {
+				value = max(value, b.High[i-2])
+			}
+			if b.High[i] > value {
+				isBull, value, ep, af = true, ep, b.High[i], step
+			} else if b.Low[i] < ep {
+				ep, af = b.Low[i], min(af+step, limit)
+			}
+		}
+		sar[i] = value
+		bull[i] = -1
+		if isBull {
+			bull[i] = 1
+		}
+	}
+	return map[string][]float64{"SAR": sar, "BULL": bull}
+}
+
+// calcVWAP VWAP:SUM(AMOUNT,N)/SUM(VOL,N); AVGPRICE:AMOUNT/VOL
+func calcVWAP(b Bars, p Params) map[string][]float64 {
+	n := p.Int("N")
+	return map[string][]float64{
+		"VWAP":     zip(SUM(b.Amount, n), SUM(b.Volume, n), div),
+		"AVGPRICE": zip(b.Amount, b.Volume, div),
+	}
+}
+
+// calcTRIX MTR:EMA(EMA(EMA(C,N),N),N); TRIX:(MTR-REF(MTR,1))/REF(MTR,1)*100; MATRIX:MA(TRIX,M)
+func calcTRIX(b Bars, p Params) map[string][]float64 {
+	n := p.Int("N")
+	mtr := EMA(EMA(EMA(b.Close, n), n), n)
+	lmtr := REF(mtr, 1)
+	trix := apply(zip(zip(mtr, lmtr, sub), lmtr, div), func(v float64) float64 { return v * 100 })
+	return map[string][]float64{"TRIX": trix, "MATRIX": MA(trix, p.Int("M"))}
+}
+
+// calcEMV 简易波动指标
+//
+//	VOLUME:MA(V,N)/V; MID:100*(H+L-REF(H+L,1))/(H+L); EMV:MA(MID*VOLUME*(H-L)/MA(H-L,N),N); MAEMV:MA(EMV,M)
+func calcEMV(b Bars, p Params) map[string][]float64 {
+	n := p.Int("N")
+	volume := zip(MA(b.Volume, n), b.Volume, div)
+	hl := zip(b.High, b.Low, add)
+	mid := zip(zip(hl, REF(hl, 1), sub), hl, func(d, s float64) float64 { return div(100*d, s) })
+	spread := zip(b.High, b.Low, sub)
+	x := zip(zip(zip(mid, volume, mul), spread, mul), MA(spread, n), div)
+	emv := MA(x, n)
+	return map[string][]float64{"EMV": emv, "MAEMV": MA(emv, p.Int("M"))}
+}
+
+// calcBIAS BIAS:(C-MA(C,N))/MA(C,N)*100
+func calcBIAS(b Bars, p Params) map[string][]float64 {
+	bias := func(n int) []float64 {
+		ma := MA(b.Close, n)
+		return apply(zip(zip(b.Close, ma, sub), ma, div), func(v float64) float64 { return v * 100 })
+	}
+	return map[string][]float64{"BIAS1": bias(p.Int("N1")), "BIAS2": bias(p.Int("N2")), "BIAS3": bias(p.Int("N3"))}
+}
+
+// calcROC ROC:100*(C-REF(C,N))/REF(C,N); MAROC:MA(ROC,M)
+func calcROC(b Bars, p Params) map[string][]float64 {
+	lc := REF(b.Close, p.Int("N"))
+	roc := apply(zip(zip(b.Close, lc, sub), lc, div), func(v float64) float64 { return v * 100 })
+	return map[string][]float64{"ROC": roc, "MAROC": MA(roc, p.Int("M"))}
+}
+
+// calcMTM MTM:C-REF(C,N); MTMMA:MA(MTM,M)
+func calcMTM(b Bars, p Params) map[string][]float64 {
+	mtm := zip(b.Close, REF(b.Close, p.Int("N")), sub)
+	return map[string][]float64{"MTM": mtm, "MTMMA": MA(mtm, p.Int("M"))}
+}
+
+// calcPSY PSY:COUNT(C>REF(C,1),N)/N*100; PSYMA:MA(PSY,M)
+func calcPSY(b Bars, p Params) map[string][]float64 {
+	n := p.Int("N")
+	lc := REF(b.Close, 1)
+	up := make([]float64, b.Len())
+	for i := range up {
+		if math.IsNaN(lc[i]) {
+			up[i] = math.NaN()
+		} else if b.Close[i] > lc[i] {
+			up[i] = 1
+		}
+	}
+	psy := apply(SUM(up, n), func(v float64) float64 { return v / float64(n) * 100 })
+	return map[string][]float64{"PSY": psy, "PSYMA": MA(psy, p.Int("M"))}
+}
+
+// calcMFI 资金流量指标
+//
+//	TYP:(H+L+C)/3; V1:SUM(IF(TYP>REF(TYP,1),TYP*V,0),N)/SUM(IF(TYP<REF(TYP,1),TYP*V,0),N); MFI:100-(100/(1+V1))
+func calcMFI(b Bars, p Params) map[string][]float64 {
+	n := p.Int("N")
+	typ := typical(b)
+	ltyp := REF(typ, 1)
+	in, out := make([]float64, b.Len()), make([]float64, b.Len())
+	for i := range typ {
+		switch {
+		case math.IsNaN(ltyp[i]):
+			in[i], out[i] = math.NaN(), math.NaN()
+		case typ[i] > ltyp[i]:
+			in[i] = typ[i] * b.Volume[i]
+		case typ[i] < ltyp[i]:
+			out[i] = typ[i] * b.Volume[i]
+		}
+	}
+	mfi := zip(SUM(in, n), SUM(out, n), func(a, b float64) float64 {
+		if b == 0 {
+			if math.IsNaN(a) {
+				return math.NaN()
+			}
+			return 100
+		}
+		return 100 - 100/(1+a/b)
+	})
+	return map[string][]float64{"MFI": mfi}
+}
@@ -0,0 +1,88 @@
+package indicators
+
+import (
+	"fmt"
+	"strconv"
+
+	"gitee.com/quant1x/pandas"
+)
+
+// BarsFromDataFrame 从K线DataFrame中提取序列, 列名为date,open,close,high,low,volume,amount
+func BarsFromDataFrame(df pandas.DataFrame) Bars {
+	float64s := func(name string) []float64 {
+		return df.ColAsNDArray(name).Float64s()
+	}
+	return Bars{
+		Date:   df.Col("date").Strings(),
+		Open:   float64s("open"),
+		High:   float64s("high"),
+		Low:    float64s("low"),
+		Close:  float64s("close"),
+		Volume: float64s("volume"),
+		Amount: float64s("amount"),
+	}
+}
+
+// registeredIndicator 已注册指标的Indicator接口实现
+type registeredIndicator struct {
+	BaseIndicator
+	definition Definition
+	params     map[string]float64
+}
+
+// New 按名称创建指标, 未指定的参数取默认值
+func New(name string, params map[string]interface{}) (Indicator, error) {
+	def, ok := Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIndicatorNotFound, name)
+	}
+	values := make(map[string]float64, len(params))
+	for k, v := range params {
+		f, err := paramValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s.%s=%v", ErrIndicatorParam, def.Name, k, v)
+		}
+		values[k] = f
+	}
+	if _, err := def.Resolve(values); err != nil {
+		return nil, err
+	}
+	return &registeredIndicator{
+		BaseIndicator: NewBaseIndicator(def.Name, params),
+		definition:    def,
+		params:        values,
+	}, nil
+}
+
+// Calculate 计算指标, 结果包含date、close和指标的输出列
+func (this *registeredIndicator) Calculate(df pandas.DataFrame) pandas.DataFrame {
+	result := pandas.NewDataFrame(df.Col("date"), df.Col("close"))
+	values, err := this.definition.Compute(BarsFromDataFrame(df), this.params)
+	if err != nil {
+		return result
+	}
+	series := make([]pandas.Series, 0, len(this.definition.Columns))
+	for _, col := range this.definition.Columns {
+		series = append(series, pandas.NewSeriesWithType(pandas.SERIES_TYPE_FLOAT64, col, values[col]))
+	}
+	return result.Join(series...)
+}
+
+func paramValue(v interface{}) (float64, error) {
+	switch x := v.(type) {
+	case int:
+		return float64(x), nil
+	case int32:
+		return float64(x), nil
+	case int64:
+		return float64(x), nil
+	case float32:
+		return float64(x), nil
+	case float64:
+		return x, nil
+	case string:
+		return strconv.ParseFloat(x, 64)
+	default:
+		return 0, ErrIndicatorParam
+	}
+}
@@ -0,0 +1,41 @@
+package indicators
+
+import (
+	"xquant/pkg/datasource/base"
+)
+
+// BarsFromKLines K线转序列
+func BarsFromKLines(klines []base.KLine) Bars {
+	n := len(klines)
+	b := Bars{
+		Date:   make([]string, n),
+		Open:   make([]float64, n),
+		High:   make([]float64, n),
+		Low:    make([]float64, n),
+		Close:  make([]float64, n),
+		Volume: make([]float64, n),
+		Amount: make([]float64, n),
+	}
+	for i, v := range klines {
+		b.Date[i] = v.Date
+		b.Open[i] = v.Open
+		b.High[i] = v.High
+		b.Low[i] = v.Low
+		b.Close[i] = v.Close
+		b.Volume[i] = v.Volume
+		b.Amount[i] = v.Amount
+	}
+	return b
+}
+
+// Append 追加一根K线, 用于盘中以快照作为最新的K线
+func (b Bars) Append(date string, open, high, low, close, volume, amount float64) Bars {
+	b.Date = append(b.Date, date)
+	b.Open = append(b.Open, open)
+	b.High = append(b.High, high)
+	b.Low = append(b.Low, low)
+	b.Close = append(b.Close, close)
+	b.Volume = append(b.Volume, volume)
+	b.Amount = append(b.Amount, amount)
+	return b
+}
@@ -0,0 +1,199 @@
+package indicators
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrIndicatorNotFound = errors.New("指标不存在")
+	ErrIndicatorParam    = errors.New("指标参数无效")
+)
+
+// Param 指标参数
+type Param struct {
+	Name        string  `json:"name"`        // 参数名, 大写
+	Default     float64 `json:"default"`     // 默认值
+	Integer     bool    `json:"integer"`     // 整数参数, 周期类参数
+	Description string  `json:"description"` // 说明
+}
+
+// Params 指标参数值
+type Params map[string]float64
+
+// Int 整数参数
+func (this Params) Int(name string) int {
+	return int(this[name])
+}
+
+// Float 浮点参数
+func (this Params) Float(name string) float64 {
+	return this[name]
+}
+
+// CalculateFunc 指标计算函数, 返回列名到序列的映射, 序列长度与K线一致
+type CalculateFunc func(b Bars, p Params) map[string][]float64
+
+// Definition 指标定义
+type Definition struct {
+	Name        string        `json:"name"`        // 指标名称, 大写
+	Description string        `json:"description"` // 说明
+	Params      []Param       `json:"params"`      // 参数列表
+	Columns     []string      `json:"columns"`     // 输出列
+	Calculate   CalculateFunc `json:"-"`           // 计算函数
+}
+
+// Resolve 用默认值补全参数, 并检查参数的合法性
+func (this Definition) Resolve(values map[string]float64) (Params, error) {
+	params := make(Params, len(this.Params))
+	for _, p := range this.Params {
+		params[p.Name] = p.Default
+	}
+	for k, v := range values {
+		name := strings.ToUpper(strings.TrimSpace(k))
+		i := slices.IndexFunc(this.Params, func(p Param) bool { return p.Name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("%w: %s不支持参数%s", ErrIndicatorParam, this.Name, k)
+		}
+		if math.IsNaN(v) || math.IsInf(v, 0) || v <= 0 {
+			return nil, fmt.Errorf("%w: %s.%s=%v, 必须为正数", ErrIndicatorParam, this.Name, name, v)
+		}
+		if this.Params[i].Integer && v != math.Trunc(v) {
+			return nil, fmt.Errorf("%w: %s.%s=%v, 必须为整数", ErrIndicatorParam, this.Name, name, v)
+		}
+		params[name] = v
+	}
+	return params, nil
+}
+
+// Compute 计算指标, 返回的列按Columns补齐
+func (this Definition) Compute(b Bars, values map[string]float64) (map[string][]float64, error) {
+	params, err := this.Resolve(values)
+	if err != nil {
+		return nil, err
+	}
+	result := this.Calculate(b, params)
+	for _, col := range this.Columns {
+		if _, ok := result[col]; !ok {
+			result[col] = nans(b.Len())
+		}
+	}
+	return result, nil
+}
+
+var (
+	__mutexIndicators sync.RWMutex
+	__mapIndicators   = map[string]Definition{}
+)
+
+// Register 注册指标, 同名指标会被覆盖
+func Register(def Definition) {
+	def.Name = strings.ToUpper(def.Name)
+	__mutexIndicators.Lock()
+	defer __mutexIndicators.Unlock()
+	__mapIndicators[def.Name] = def
+}
+
+// Lookup 按名称查找指标, 不区分大小写
+func Lookup(name string) (Definition, bool) {
+	__mutexIndicators.RLock()
+	defer __mutexIndicators.RUnlock()
+	def, ok := __mapIndicators[strings.ToUpper(strings.TrimSpace(name))]
+	return def, ok
+}
+
+// List 全部已注册的指标, 按名称排序
+func List() []Definition {
+	__mutexIndicators.RLock()
+	defer __mutexIndicators.RUnlock()
+	list := make([]Definition, 0, len(__mapIndicators))
+	for _, def := range __mapIndicators {
+		list = append(list, def)
+	}
+	slices.SortFunc(list, func(a, b Definition) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return list
+}
+
+// Compute 按名称计算指标, 未指定的参数取默认值
+func Compute(name string, b Bars, params map[string]float64) (map[string][]float64, error) {
+	def, ok := Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIndicatorNotFound, name)
+	}
+	return def.Compute(b, params)
+}
+
+// ParseParams 解析参数字符串, 格式为"N=14,M=6"
+func ParseParams(s string) (map[string]float64, error) {
+	params := map[string]float64{}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		k, v, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrIndicatorParam, item)
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrIndicatorParam, item)
+		}
+		params[strings.ToUpper(strings.TrimSpace(k))] = f
+	}
+	return params, nil
+}
+
+func period(name string, value int, description string) Param {
+	return Param{Name: name, Default: float64(value), Integer: true, Description: description}
+}
+
+func init() {
+	for _, def := range []Definition{
+		{Name: "MACD", Description: "指数平滑异同平均线", Calculate: calcMACD, Columns: []string{"DIF", "DEA", "MACD"},
+			Params: []Param{period("SHORT", 12, "短周期"), period("LONG", 26, "长周期"), period("MID", 9, "DEA周期")}},
+		{Name: "BOLL", Description: "布林带", Calculate: calcBOLL, Columns: []string{"MB", "UP", "DN"},
+			Params: []Param{period("N", 20, "周期"), {Name: "P", Default: 2, Description: "标准差倍数"}}},
+		{Name: "RSI", Description: "相对强弱指标", Calculate: calcRSI, Columns: []string{"RSI1", "RSI2", "RSI3"},
+			Params: []Param{period("N1", 6, "周期1"), period("N2", 12, "周期2"), period("N3", 24, "周期3")}},
+		{Name: "KDJ", Description: "随机指标", Calculate: calcKDJ, Columns: []string{"K", "D", "J"},
+			Params: []Param{period("N", 9, "RSV周期"), period("M1", 3, "K平滑周期"), period("M2", 3, "D平滑周期")}},
+		{Name: "ATR", Description: "真实波幅", Calculate: calcATR, Columns: []string{"TR", "ATR"},
+			Params: []Param{period("N", 14, "周期")}},
+		{Name: "OBV", Description: "累积能量线", Calculate: calcOBV, Columns: []string{"OBV", "MAOBV"},
+			Params: []Param{period("M", 30, "均线周期")}},
+		{Name: "DMI", Description: "趋向指标", Calculate: calcDMI, Columns: []string{"PDI", "MDI", "ADX", "ADXR"},
+			Params: []Param{period("N", 14, "周期"), period("M", 6, "ADX周期")}},
+		{Name: "CCI", Description: "商品路径指标", Calculate: calcCCI, Columns: []string{"CCI"},
+			Params: []Param{period("N", 14, "周期")}},
+		{Name: "WR", Description: "威廉指标", Calculate: calcWR, Columns: []string{"WR1", "WR2"},
+			Params: []Param{period("N", 10, "周期1"), period("N1", 6, "周期2")}},
+		{Name: "SAR", Description: "抛物线转向", Calculate: calcSAR, Columns: []string{"SAR", "BULL"},
+			Params: []Param{{Name: "STEP", Default: 0.02, Description: "加速因子步长"}, {Name: "MAX", Default: 0.2, Description: "加速因子上限"}}},
+		{Name: "VWAP", Description: "成交量加权均价", Calculate: calcVWAP, Columns: []string{"VWAP", "AVGPRICE"},
+			Params: []Param{period("N", 20, "周期")}},
+		{Name: "TRIX", Description: "三重指数平滑平均线", Calculate: calcTRIX, Columns: []string{"TRIX", "MATRIX"},
+			Params: []Param{period("N", 12, "周期"), period("M", 9, "均线周期")}},
+		{Name: "EMV", Description: "简易波动指标", Calculate: calcEMV, Columns: []string{"EMV", "MAEMV"},
+			Params: []Param{period("N", 14, "周期"), period("M", 9, "均线周期")}},
+		{Name: "BIAS", Description: "乖离率", Calculate: calcBIAS, Columns: []string{"BIAS1", "BIAS2", "BIAS3"},
+			Params: []Param{period("N1", 6, "周期1"), period("N2", 12, "周期2"), period("N3", 24, "周期3")}},
+		{Name: "ROC", Description: "变动率", Calculate: calcROC, Columns: []string{"ROC", "MAROC"},
+			Params: []Param{period("N", 12, "周期"), period("M", 6, "均线周期")}},
+		{Name: "MTM", Description: "动量线", Calculate: calcMTM, Columns: []string{"MTM", "MTMMA"},
+			Params: []Param{period("N", 12, "周期"), period("M", 6, "均线周期")}},
+		{Name: "PSY", Description: "心理线", Calculate: calcPSY, Columns: []string{"PSY", "PSYMA"},
+			Params: []Param{period("N", 12, "周期"), period("M", 6, "均线周期")}},
+		{Name: "MFI", Description: "资金流量指标", Calculate: calcMFI, Columns: []string{"MFI"},
+			Params: []Param{period("N", 14, "周期")}},
+	} {
+		Register(def)
+	}
+}
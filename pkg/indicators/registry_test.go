@@ -0,0 +1,96 @@
+package indicators
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func testBars(n int) Bars {
+	b := Bars{}
+	for i := 0; i < n; i++ {
+		c := 10 + math.Sin(float64(i)/5)*2 + float64(i)*0.05
+		b.Open = append(b.Open, c-0.1)
+		b.High = append(b.High, c+0.3)
+		b.Low = append(b.Low, c-0.3)
+		b.Close = append(b.Close, c)
+		b.Volume = append(b.Volume, 1000+float64(i%7)*100)
+		b.Amount = append(b.Amount, c*(1000+float64(i%7)*100))
+	}
+	return b
+}
+
+func TestRegistry(t *testing.T) {
+	b := testBars(120)
+	for _, def := range List() {
+		result, err := Compute(def.Name, b, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", def.Name, err)
+		}
+		for _, col := range def.Columns {
+			values := result[col]
+			if len(values) != b.Len() {
+				t.Fatalf("%s.%s: length=%d", def.Name, col, len(values))
+			}
+			if last := values[len(values)-1]; math.IsNaN(last) || math.IsInf(last, 0) {
+				t.Errorf("%s.%s: last=%v", def.Name, col, last)
+			}
+		}
+	}
+}
+
+func TestComputeParams(t *testing.T) {
+	b := testBars(60)
+	if _, err := Compute("unknown", b, nil); !errors.Is(err, ErrIndicatorNotFound) {
+		t.Errorf("unknown: %v", err)
+	}
+	if _, err := Compute("rsi", b, map[string]float64{"N": 6}); !errors.Is(err, ErrIndicatorParam) {
+		t.Errorf("rsi.N: %v", err)
+	}
+	if _, err := Compute("kdj", b, map[string]float64{"n": 9.5}); !errors.Is(err, ErrIndicatorParam) {
+		t.Errorf("kdj.N=9.5: %v", err)
+	}
+	params, err := ParseParams("n=5, m1=2")
+	if err != nil || params["N"] != 5 || params["M1"] != 2 {
+		t.Fatalf("ParseParams: %v %v", params, err)
+	}
+	result, err := Compute("KDJ", b, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 前N-1个周期RSV数据不足
+	if !math.IsNaN(result["K"][3]) || math.IsNaN(result["K"][4]) {
+		t.Errorf("K: %v", result["K"][:6])
+	}
+}
+
+func TestIndicatorValues(t *testing.T) {
+	b := Bars{
+		High:   []float64{10, 11, 12, 11, 13},
+		Low:    []float64{9, 10, 11, 10, 11},
+		Close:  []float64{9.5, 10.5, 11.5, 10.5, 12.5},
+		Volume: []float64{100, 200, 300, 400, 500},
+		Amount: []float64{950, 2100, 3450, 4200, 6250},
+	}
+	obv, _ := Compute("OBV", b, nil)
+	want := []float64{0, 200, 500, 100, 600}
+	for i, v := range want {
+		if obv["OBV"][i] != v {
+			t.Fatalf("OBV=%v, want %v", obv["OBV"], want)
+		}
+	}
+	atr, _ := Compute("ATR", b, map[string]float64{"N": 2})
+	// TR=[1, 1.5, 1.5, 1.5, 2.5]
+	if got := atr["ATR"][4]; math.Abs(got-2) > 1e-9 {
+		t.Errorf("ATR=%v", atr["ATR"])
+	}
+	vwap, _ := Compute("VWAP", b, map[string]float64{"N": 2})
+	if got := vwap["VWAP"][4]; math.Abs(got-(4200+6250)/900.0) > 1e-9 {
+		t.Errorf("VWAP=%v", vwap["VWAP"])
+	}
+	wr, _ := Compute("WR", b, map[string]float64{"N": 3, "N1": 2})
+	// HHV(H,3)=13, LLV(L,3)=10
+	if got := wr["WR1"][4]; math.Abs(got-100*(13-12.5)/3) > 1e-9 {
+		t.Errorf("WR1=%v", wr["WR1"])
+	}
+}
@@ -0,0 +1,233 @@
+package indicators
+
+import (
+	"math"
+)
+
+// 通达信公式语义的序列函数, 输入输出按时间升序对齐, 数据不足的位置为NaN
+
+// Bars 按时间升序排列的K线序列
+type Bars struct {
+	Date   []string
+	Open   []float64
+	High   []float64
+	Low    []float64
+	Close  []float64
+	Volume []float64
+	Amount []float64
+}
+
+// Len K线数量
+func (b Bars) Len() int {
+	return len(b.Close)
+}
+
+// Slice 截取[begin, end)区间的K线
+func (b Bars) Slice(begin, end int) Bars {
+	sub := func(x []float64) []float64 {
+		if len(x) < end {
+			return x
+		}
+		return x[begin:end]
+	}
+	v := Bars{
+		Open:   sub(b.Open),
+		High:   sub(b.High),
+		Low:    sub(b.Low),
+		Close:  sub(b.Close),
+		Volume: sub(b.Volume),
+		Amount: sub(b.Amount),
+	}
+	if len(b.Date) >= end {
+		v.Date = b.Date[begin:end]
+	}
+	return v
+}
+
+func nans(n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	return out
+}
+
+// REF n周期前的值
+func REF(x []float64, n int) []float64 {
+	out := nans(len(x))
+	for i := n; i < len(x); i++ {
+		out[i] = x[i-n]
+	}
+	return out
+}
+
+// window 滑动窗口计算, 窗口内有NaN时输出NaN
+func window(x []float64, n int, f func(w []float64) float64) []float64 {
+	out := nans(len(x))
+	if n < 1 {
+		return out
+	}
+	for i := n - 1; i < len(x); i++ {
+		w := x[i-n+1 : i+1]
+		valid := true
+		for _, v := range w {
+			if math.IsNaN(v) {
+				valid = false
+				break
+			}
+		}
+		if valid {
+			out[i] = f(w)
+		}
+	}
+	return out
+}
+
+// SUM n周期求和, n为0时累计求和
+func SUM(x []float64, n int) []float64 {
+	if n > 0 {
+		return window(x, n, func(w []float64) float64 {
+			s := 0.00
+			for _, v := range w {
+				s += v
+			}
+			return s
+		})
+	}
+	out := nans(len(x))
+	s := 0.00
+	for i, v := range x {
+		if !math.IsNaN(v) {
+			s += v
+		}
+		out[i] = s
+	}
+	return out
+}
+
+// MA 简单移动平均
+func MA(x []float64, n int) []float64 {
+	return window(x, n, func(w []float64) float64 {
+		s := 0.00
+		for _, v := range w {
+			s += v
+		}
+		return s / float64(len(w))
+	})
+}
+
+// STD n周期样本标准差
+func STD(x []float64, n int) []float64 {
+	return window(x, n, func(w []float64) float64 {
+		if len(w) < 2 {
+			return 0
+		}
+		m := 0.00
+		for _, v := range w {
+			m += v
+		}
+		m /= float64(len(w))
+		s := 0.00
+		for _, v := range w {
+			s += (v - m) * (v - m)
+		}
+		return math.Sqrt(s / float64(len(w)-1))
+	})
+}
+
+// AVEDEV n周期平均绝对偏差
+func AVEDEV(x []float64, n int) []float64 {
+	return window(x, n, func(w []float64) float64 {
+		m := 0.00
+		for _, v := range w {
+			m += v
+		}
+		m /= float64(len(w))
+		s := 0.00
+		for _, v := range w {
+			s += math.Abs(v - m)
+		}
+		return s / float64(len(w))
+	})
+}
+
+// HHV n周期最高值
+func HHV(x []float64, n int) []float64 {
+	return window(x, n, func(w []float64) float64 {
+		v := w[0]
+		for _, x := range w[1:] {
+			v = max(v, x)
+		}
+		return v
+	})
+}
+
+// LLV n周期最低值
+func LLV(x []float64, n int) []float64 {
+	return window(x, n, func(w []float64) float64 {
+		v := w[0]
+		for _, x := range w[1:] {
+			v = min(v, x)
+		}
+		return v
+	})
+}
+
+// EMA 指数移动平均, 从第一个有效值开始递推
+func EMA(x []float64, n int) []float64 {
+	return recursive(x, 2/float64(n+1))
+}
+
+// SMA 通达信的SMA(X,N,M), Y=(M*X+(N-M)*Y')/N
+func SMA(x []float64, n, m int) []float64 {
+	return recursive(x, float64(m)/float64(n))
+}
+
+func recursive(x []float64, alpha float64) []float64 {
+	out := nans(len(x))
+	prev := math.NaN()
+	for i, v := range x {
+		switch {
+		case math.IsNaN(v):
+			out[i] = prev
+			continue
+		case math.IsNaN(prev):
+			prev = v
+		default:
+			prev = alpha*v + (1-alpha)*prev
+		}
+		out[i] = prev
+	}
+	return out
+}
+
+// zip 两个序列逐元素计算
+func zip(x, y []float64, f func(a, b float64) float64) []float64 {
+	n := min(len(x), len(y))
+	out := make([]float64, n)
+	for i := 0; i < n; i++ {
+		out[i] = f(x[i], y[i])
+	}
+	return out
+}
+
+// apply 序列逐元素计算
+func apply(x []float64, f func(v float64) float64) []float64 {
+	out := make([]float64, len(x))
+	for i, v := range x {
+		out[i] = f(v)
+	}
+	return out
+}
+
+// div 除法, 除数为0时输出NaN
+func div(a, b float64) float64 {
+	if b == 0 {
+		return math.NaN()
+	}
+	return a / b
+}
+
+func add(a, b float64) float64 { return a + b }
+func sub(a, b float64) float64 { return a - b }
+func mul(a, b float64) float64 { return a * b }
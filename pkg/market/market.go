@@ -95,10 +95,9 @@ func roundTo2Decimal(num float64) float64 {
 }
 
 // PriceLimit 计算涨停板和跌停板的价格
+//
+//	按当前的证券名称判断风险警示, 不考虑上市天数和历史规则, 指定日期的涨跌停价格使用 CalculatePriceLimit
 func PriceLimit(securityCode string, lastClose float64) (limitUp, limitDown float64) {
-	limitRate := exchange.MarketLimit(securityCode)
-	// 计算涨跌停价并保留2位小数
-	limitUp = roundTo2Decimal(lastClose * (1.0 + limitRate))
-	limitDown = roundTo2Decimal(lastClose * (1.0 - limitRate))
-	return limitUp, limitDown
+	limit := CalculatePriceLimit(securityCode, exchange.GetCurrentlyDay(), lastClose, currentStatus(securityCode))
+	return limit.LimitUp, limit.LimitDown
 }
@@ -0,0 +1,208 @@
+package market
+
+import (
+	"math"
+	"strings"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/gotdx/securities"
+)
+
+// Board 涨跌幅规则适用的板块
+type Board int
+
+const (
+	BoardOther   Board = iota // 其它, 基金、债券等, 沿用交易所默认的涨跌幅
+	BoardMain                 // 沪深主板
+	BoardChiNext              // 创业板
+	BoardSTAR                 // 科创板
+	BoardBJ                   // 北交所
+)
+
+// String 板块名称
+func (b Board) String() string {
+	switch b {
+	case BoardMain:
+		return "主板"
+	case BoardChiNext:
+		return "创业板"
+	case BoardSTAR:
+		return "科创板"
+	case BoardBJ:
+		return "北交所"
+	default:
+		return "其它"
+	}
+}
+
+// BoardOf 证券代码所属的板块
+func BoardOf(securityCode string) Board {
+	_, flag, symbol := exchange.DetectMarket(securityCode)
+	switch {
+	case flag == "bj":
+		return BoardBJ
+	case flag == "sh" && (strings.HasPrefix(symbol, "688") || strings.HasPrefix(symbol, "689")):
+		return BoardSTAR
+	case flag == "sh" && strings.HasPrefix(symbol, "60"):
+		return BoardMain
+	case flag == "sz" && (strings.HasPrefix(symbol, "300") || strings.HasPrefix(symbol, "301")):
+		return BoardChiNext
+	case flag == "sz" && (strings.HasPrefix(symbol, "000") || strings.HasPrefix(symbol, "001") ||
+		strings.HasPrefix(symbol, "002") || strings.HasPrefix(symbol, "003")):
+		return BoardMain
+	default:
+		return BoardOther
+	}
+}
+
+// limitRule 板块的涨跌幅规则, 从since开始生效, 直到同板块的下一条规则生效
+type limitRule struct {
+	board        Board
+	since        string  // 生效日期
+	ratio        float64 // 涨跌幅限制
+	stRatio      float64 // 风险警示股票的涨跌幅限制
+	freeDays     int     // 上市后前N个交易日不设涨跌幅限制
+	ipoUpRatio   float64 // 上市首日的涨幅限制, 相对发行价, freeDays为0时有效
+	ipoDownRatio float64 // 上市首日的跌幅限制, 相对发行价, freeDays为0时有效
+}
+
+// 规则按板块和生效日期升序排列
+var limitRules = []limitRule{
+	{board: BoardMain, since: "1996-12-16", ratio: 0.10, stRatio: 0.05, ipoUpRatio: 0.44, ipoDownRatio: 0.36},
+	// 主板注册制, 新股上市前5日不设涨跌幅限制
+	{board: BoardMain, since: "2023-04-10", ratio: 0.10, stRatio: 0.05, freeDays: 5},
+	{board: BoardChiNext, since: "2009-10-30", ratio: 0.10, stRatio: 0.05, ipoUpRatio: 0.44, ipoDownRatio: 0.36},
+	// 创业板注册制改革, 涨跌幅调整为20%, 风险警示股票同样为20%
+	{board: BoardChiNext, since: "2020-08-24", ratio: 0.20, stRatio: 0.20, freeDays: 5},
+	{board: BoardSTAR, since: "2019-07-22", ratio: 0.20, stRatio: 0.20, freeDays: 5},
+	// 北交所上市首日不设涨跌幅限制
+	{board: BoardBJ, since: "2021-11-15", ratio: 0.30, stRatio: 0.30, freeDays: 1},
+}
+
+// lookupLimitRule 查找板块在指定日期生效的规则
+func lookupLimitRule(board Board, date string) (limitRule, bool) {
+	var rule limitRule
+	found := false
+	for _, v := range limitRules {
+		if v.board != board {
+			continue
+		}
+		// 早于最早规则的日期按最早的规则处理
+		if !found || v.since <= date {
+			rule, found = v, true
+		}
+	}
+	return rule, found
+}
+
+// SecurityStatus 影响涨跌幅的证券状态
+type SecurityStatus struct {
+	ST          bool // 是否风险警示
+	ListingDays int  // 上市第几个交易日, 1为上市首日, 0为未知, 未知时按常规涨跌幅处理
+}
+
+// IsSpecialTreatment 证券名称是否风险警示, 包括ST、*ST、SST和S*ST
+func IsSpecialTreatment(name string) bool {
+	return strings.Contains(strings.ToUpper(name), keywordST)
+}
+
+// Limit 证券在某个交易日的涨跌停价格
+type Limit struct {
+	SecurityCode string  `json:"code"`        // 证券代码
+	Date         string  `json:"date"`        // 交易日期
+	Board        Board   `json:"board"`       // 板块
+	ST           bool    `json:"st"`          // 是否风险警示
+	ListingDays  int     `json:"listingDays"` // 上市第几个交易日
+	LastClose    float64 `json:"lastClose"`   // 昨收, 上市首日为发行价
+	Tick         float64 `json:"tick"`        // 最小价格变动单位
+	UpRatio      float64 `json:"upRatio"`     // 涨幅限制
+	DownRatio    float64 `json:"downRatio"`   // 跌幅限制
+	NoLimit      bool    `json:"noLimit"`     // 不设涨跌幅限制
+	LimitUp      float64 `json:"limitUp"`     // 涨停价, 不设涨跌幅限制时为0
+	LimitDown    float64 `json:"limitDown"`   // 跌停价, 不设涨跌幅限制时为0
+}
+
+// IsLimitUp 价格是否达到涨停价
+func (l Limit) IsLimitUp(price float64) bool {
+	return !l.NoLimit && l.LimitUp > 0 && roundTick(price, l.Tick) >= l.LimitUp
+}
+
+// IsLimitDown 价格是否达到跌停价
+func (l Limit) IsLimitDown(price float64) bool {
+	return !l.NoLimit && l.LimitDown > 0 && roundTick(price, l.Tick) <= l.LimitDown
+}
+
+// Clamp 委托价格限制在涨跌停价之间, 超出涨跌停价的委托会被交易所拒绝
+func (l Limit) Clamp(price float64) float64 {
+	if l.NoLimit || l.LimitUp <= 0 {
+		return price
+	}
+	return min(max(price, l.LimitDown), l.LimitUp)
+}
+
+// CalculatePriceLimit 按板块规则、风险警示状态和上市天数计算涨跌停价格
+//
+//	涨跌停价按最小价格变动单位四舍五入
+func CalculatePriceLimit(securityCode, date string, lastClose float64, status SecurityStatus) Limit {
+	securityCode = exchange.CorrectSecurityCode(securityCode)
+	date = exchange.FixTradeDate(date)
+	limit := Limit{
+		SecurityCode: securityCode,
+		Date:         date,
+		Board:        BoardOf(securityCode),
+		ST:           status.ST,
+		ListingDays:  status.ListingDays,
+		LastClose:    lastClose,
+		Tick:         PriceTick(securityCode),
+	}
+	rule, ok := lookupLimitRule(limit.Board, date)
+	switch {
+	case !ok:
+		limit.UpRatio = exchange.MarketLimit(securityCode)
+		limit.DownRatio = limit.UpRatio
+	case status.ListingDays > 0 && status.ListingDays <= rule.freeDays:
+		limit.NoLimit = true
+	case status.ListingDays == 1 && rule.ipoUpRatio > 0:
+		limit.UpRatio, limit.DownRatio = rule.ipoUpRatio, rule.ipoDownRatio
+	case status.ST:
+		limit.UpRatio, limit.DownRatio = rule.stRatio, rule.stRatio
+	default:
+		limit.UpRatio, limit.DownRatio = rule.ratio, rule.ratio
+	}
+	if limit.NoLimit || lastClose <= 0 || math.IsNaN(lastClose) {
+		return limit
+	}
+	limit.LimitUp = roundTick(lastClose*(1+limit.UpRatio), limit.Tick)
+	limit.LimitDown = roundTick(lastClose*(1-limit.DownRatio), limit.Tick)
+	return limit
+}
+
+// PriceTick 最小价格变动单位, 股票为0.01元, 基金为0.001元
+func PriceTick(securityCode string) float64 {
+	if BoardOf(securityCode) == BoardOther && !exchange.AssertStockBySecurityCode(securityCode) {
+		return 0.001
+	}
+	return 0.01
+}
+
+// roundTick 按最小价格变动单位四舍五入
+func roundTick(price, tick float64) float64 {
+	if tick <= 0 {
+		tick = 0.01
+	}
+	// 加上极小值, 修正浮点误差导致的x.xx5舍入错误
+	v := math.Floor(price/tick+0.5+1e-9) * tick
+	if tick < 0.01 {
+		return math.Round(v*1000) / 1000
+	}
+	return roundTo2Decimal(v)
+}
+
+// currentStatus 证券当前的状态, 只能判断风险警示, 上市天数未知
+func currentStatus(securityCode string) SecurityStatus {
+	var status SecurityStatus
+	if info, ok := securities.CheckoutSecurityInfo(securityCode); ok {
+		status.ST = IsSpecialTreatment(info.Name)
+	}
+	return status
+}
@@ -0,0 +1,47 @@
+package market
+
+import (
+	"testing"
+)
+
+func TestCalculatePriceLimit(t *testing.T) {
+	tests := []struct {
+		code      string
+		date      string
+		lastClose float64
+		status    SecurityStatus
+		up, down  float64
+		noLimit   bool
+	}{
+		{code: "sh600000", date: "2024-01-02", lastClose: 10.00, up: 11.00, down: 9.00},
+		{code: "sh600000", date: "2024-01-02", lastClose: 3.35, up: 3.69, down: 3.02},
+		{code: "sh600000", date: "2024-01-02", lastClose: 10.00, status: SecurityStatus{ST: true}, up: 10.50, down: 9.50},
+		{code: "sz300750", date: "2020-08-21", lastClose: 10.00, up: 11.00, down: 9.00},
+		{code: "sz300750", date: "2020-08-24", lastClose: 10.00, up: 12.00, down: 8.00},
+		{code: "sz300750", date: "2024-01-02", lastClose: 10.00, status: SecurityStatus{ST: true}, up: 12.00, down: 8.00},
+		{code: "sh688981", date: "2024-01-02", lastClose: 10.00, status: SecurityStatus{ListingDays: 3}, noLimit: true},
+		{code: "sh688981", date: "2024-01-02", lastClose: 10.00, status: SecurityStatus{ListingDays: 6}, up: 12.00, down: 8.00},
+		{code: "sz002594", date: "2019-01-02", lastClose: 10.00, status: SecurityStatus{ListingDays: 1}, up: 14.40, down: 6.40},
+		{code: "sz001389", date: "2023-06-01", lastClose: 10.00, status: SecurityStatus{ListingDays: 5}, noLimit: true},
+		{code: "bj830799", date: "2024-01-02", lastClose: 10.00, up: 13.00, down: 7.00},
+	}
+	for _, tt := range tests {
+		limit := CalculatePriceLimit(tt.code, tt.date, tt.lastClose, tt.status)
+		if limit.NoLimit != tt.noLimit || limit.LimitUp != tt.up || limit.LimitDown != tt.down {
+			t.Errorf("%s %s %+v: got %+v", tt.code, tt.date, tt.status, limit)
+		}
+	}
+}
+
+func TestLimitClamp(t *testing.T) {
+	limit := CalculatePriceLimit("sh600000", "2024-01-02", 10.00, SecurityStatus{})
+	if !limit.IsLimitUp(11.00) || limit.IsLimitUp(10.99) || !limit.IsLimitDown(9.00) {
+		t.Errorf("limit: %+v", limit)
+	}
+	if v := limit.Clamp(11.20); v != 11.00 {
+		t.Errorf("Clamp(11.20)=%v", v)
+	}
+	if v := limit.Clamp(8.50); v != 9.00 {
+		t.Errorf("Clamp(8.50)=%v", v)
+	}
+}
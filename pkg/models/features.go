@@ -97,5 +97,11 @@ func FeatureToSnapshot(feature factors.SecurityFeature, securityCode string) fac
 		lastMinuteVolume := history.GetMV5()
 		qs.OpenQuantityRatio = num.ChangeRate(lastMinuteVolume, qs.OpenVolume)
 	}
+	// 盘口分析取当日最后一次记录的快照, 没有记录时为0
+	if book := factors.GetOrderBook(securityCode, qs.Date); book != nil {
+		qs.Bid1 = book.Bid1
+		qs.Ask1 = book.Ask1
+		qs.SetOrderBook(*book)
+	}
 	return qs
 }
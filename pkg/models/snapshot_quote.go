@@ -38,6 +38,8 @@ func QuoteSnapshotFromProtocol(v quotes.Snapshot) factors.QuoteSnapshot {
 		minuteVolume := float64(snapshot.Vol) / float64(exchange.Minutes(snapshot.Date))
 		snapshot.QuantityRatio = minuteVolume / lastMinuteVolume
 	}
+	// 盘口分析, 封单变化和最近一次同步的盘口比较
+	snapshot.SetOrderBook(factors.NewOrderBook(snapshot, SnapshotMgr.GetOrderBookFromMemory(securityCode)))
 	return snapshot
 }
 
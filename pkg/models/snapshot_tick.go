@@ -28,6 +28,7 @@ func init() {
 type SnapshotManager struct {
 	mu     sync.RWMutex
 	cache  map[string]quotes.Snapshot
+	books  map[string]factors.OrderBook // 最近一次同步的个股盘口分析
 	tdxAPI *quotes.StdApi
 	config config.DataParameter
 }
@@ -36,6 +37,7 @@ type SnapshotManager struct {
 func NewSnapshotManager() *SnapshotManager {
 	return &SnapshotManager{
 		cache:  make(map[string]quotes.Snapshot),
+		books:  make(map[string]factors.OrderBook),
 		tdxAPI: gotdx.GetTdxApi(),
 		config: config.GetDataConfig(),
 	}
@@ -52,6 +54,17 @@ func (sm *SnapshotManager) GetTickFromMemory(securityCode string) *quotes.Snapsh
 	return nil
 }
 
+// GetOrderBookFromMemory 从缓存获取最近一次同步的盘口分析
+func (sm *SnapshotManager) GetOrderBookFromMemory(securityCode string) *factors.OrderBook {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	if book, exists := sm.books[securityCode]; exists {
+		return &book
+	}
+	return nil
+}
+
 // GetStrategySnapshot 获取增强的策略快照
 func (sm *SnapshotManager) GetStrategySnapshot(securityCode string) *factors.QuoteSnapshot {
 	baseSnapshot := sm.GetTickFromMemory(securityCode)
@@ -76,6 +89,12 @@ func (sm *SnapshotManager) enhanceSnapshot(v *quotes.Snapshot) *factors.QuoteSna
 
 	snapshot.OpenBiddingDirection, snapshot.OpenVolumeDirection = v.CheckDirection()
 
+	if book := sm.GetOrderBookFromMemory(v.SecurityCode); book != nil && book.ServerTime == snapshot.ServerTime {
+		snapshot.SetOrderBook(*book)
+	} else {
+		snapshot.SetOrderBook(factors.NewOrderBook(snapshot, book))
+	}
+
 	return &snapshot
 }
 
@@ -180,13 +199,37 @@ func (sm *SnapshotManager) SyncAllSnapshots(ctx context.Context, barIndex *int)
 		bar.Wait()
 	}
 
+	books := sm.updateOrderBooks(snapshots)
+
 	sm.mu.Lock()
 	for _, v := range snapshots {
 		sm.cache[v.SecurityCode] = v
 	}
+	for _, v := range books {
+		sm.books[v.Code] = v
+	}
 	sm.mu.Unlock()
 
+	if config.GetDataConfig().Snapshot.OrderBook {
+		factors.SaveOrderBooks(books)
+	}
+
 	if barIndex != nil {
 		*barIndex++
 	}
 }
+
+// updateOrderBooks 计算个股快照的盘口分析, 封单变化和上一次同步的盘口比较
+func (sm *SnapshotManager) updateOrderBooks(snapshots []quotes.Snapshot) []factors.OrderBook {
+	books := make([]factors.OrderBook, 0, len(snapshots))
+	for _, v := range snapshots {
+		if v.State != quotes.SECURITY_TRADE_STATE_NORMAL || !exchange.AssertStockBySecurityCode(v.SecurityCode) {
+			continue
+		}
+		snapshot := factors.QuoteSnapshot{}
+		_ = copier.Copy(&snapshot, &v)
+		last := sm.GetOrderBookFromMemory(v.SecurityCode)
+		books = append(books, factors.NewOrderBook(snapshot, last))
+	}
+	return books
+}
@@ -28,6 +28,7 @@ const (
 	ModelNo7                 ModelKind = 7          // 7号策略, 不允许覆盖
 	ModelNo8                 ModelKind = 8          // 8号策略, 不允许覆盖
 	ModelNo9                 ModelKind = 9          // 9号策略, 不允许覆盖
+	ModelNo10                ModelKind = 10         // 10号策略, 不允许覆盖
 	Model89K                 ModelKind = 89         // 89号策略, 89K策略, 不允许覆盖
 	ModelOneSizeFitsAllSells ModelKind = 117        // 卖出策略: 一刀切(Panic sell, cookie-cutter, One size fits all sales)
 	ModelNoShareHolding      ModelKind = 861        // 卖出策略: 不留了
@@ -47,6 +48,7 @@ var (
 		ModelNo7,
 		ModelNo8,
 		ModelNo9,
+		ModelNo10,
 		Model89K,
 		ModelOneSizeFitsAllSells,
 		ModelNoShareHolding,
@@ -90,7 +92,9 @@ type Strategy interface {
 	// Sort 排序
 	Sort([]factors.QuoteSnapshot) SortedStatus
 	// Evaluate 评估 日线数据
-	Evaluate(securityCode string, result *concurrent.TreeMap[string, ResultInfo])
+	//
+	//	snapshot为被评估的快照, 历史数据和K线必须截止到snapshot.Date, 不能读取实时数据
+	Evaluate(securityCode string, snapshot factors.QuoteSnapshot, result *concurrent.TreeMap[string, ResultInfo])
 }
 
 // QmtStrategyName 获取用于QMT系统的策略名称
@@ -113,7 +117,14 @@ var (
 )
 
 // Register 注册策略
+//
+//	配置文件中策略的params和策略声明的参数描述不符时返回错误
 func Register(strategy Strategy) error {
+	if strategyParameter, ok := config.LookupStrategyParameter(strategy.Code() &^ ModelForceOverwrite); ok {
+		if err := GetMetadata(strategy).ValidateParams(strategyParameter.Params); err != nil {
+			return err
+		}
+	}
 	_mutexStrategies.Lock()
 	defer _mutexStrategies.Unlock()
 	strategyCode := strategy.Code()
@@ -167,6 +178,9 @@ type StrategySummary struct {
 }
 
 var (
+	// MapStrategies 手工维护的策略摘要
+	//
+	// Deprecated: 推荐使用 StrategyCatalog
 	MapStrategies = map[ModelKind]StrategySummary{
 		ModelZero:    {Type: ModelZero, Name: "0号策略"},
 		ModelHousNo1: {Type: ModelHousNo1, Name: "1号策略"},
@@ -0,0 +1,197 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gitee.com/quant1x/gox/api"
+	"xquant/pkg/config"
+)
+
+const (
+	DefaultStrategyVersion = "1.0.0" // 默认的策略版本号
+)
+
+// 策略参数类型
+const (
+	ParameterTypeInt    = "int"
+	ParameterTypeFloat  = "float"
+	ParameterTypeBool   = "bool"
+	ParameterTypeString = "string"
+)
+
+var (
+	ErrInvalidParams = errors.New("策略参数无效") // 策略配置的params和参数描述不符
+)
+
+func init() {
+	// 配置重载时按参数描述校验已注册策略的params
+	config.RegisterReloadHook(config.ReloadHook{
+		Name: "strategy_params",
+		Validate: func(newConfig *config.Quant1XConfig) error {
+			for _, v := range newConfig.Trader.Strategies {
+				model, err := CheckoutStrategy(v.Id)
+				if err != nil {
+					continue
+				}
+				if err = GetMetadata(model).ValidateParams(v.Params); err != nil {
+					return fmt.Errorf("策略[%d]: %w", v.Id, err)
+				}
+			}
+			return nil
+		},
+	})
+}
+
+// ParameterSchema 策略可调参数描述
+type ParameterSchema struct {
+	Name        string `json:"name"`        // 参数名, 对应策略配置params中的key
+	Type        string `json:"type"`        // 参数类型
+	Default     string `json:"default"`     // 默认值
+	Description string `json:"description"` // 描述
+}
+
+// StrategyMetadata 策略元数据
+type StrategyMetadata struct {
+	Code        ModelKind         `json:"code"`        // 策略编码
+	Name        string            `json:"name"`        // 策略名称
+	Description string            `json:"description"` // 策略描述
+	OrderFlag   string            `json:"orderFlag"`   // 订单标志
+	Version     string            `json:"version"`     // 版本号
+	Features    []string          `json:"features"`    // 依赖的特征, 特征的缓存关键字
+	Parameters  []ParameterSchema `json:"parameters"`  // 可调参数
+}
+
+// StrategyDescriber 策略元数据接口, 策略可选实现
+type StrategyDescriber interface {
+	// Metadata 策略元数据
+	Metadata() StrategyMetadata
+}
+
+// GetMetadata 获取策略元数据
+//
+//	策略没有实现StrategyDescriber时, 由Code, Name和OrderFlag生成
+func GetMetadata(model Strategy) StrategyMetadata {
+	var meta StrategyMetadata
+	if v, ok := model.(StrategyDescriber); ok {
+		meta = v.Metadata()
+	}
+	// 以策略接口的返回值为准
+	meta.Code = model.Code()
+	meta.Name = model.Name()
+	meta.OrderFlag = model.OrderFlag()
+	if len(meta.Version) == 0 {
+		meta.Version = DefaultStrategyVersion
+	}
+	return meta
+}
+
+// StrategyCatalog 已注册的全部策略元数据, 按策略编码排序
+func StrategyCatalog() []StrategyMetadata {
+	_mutexStrategies.Lock()
+	kinds := api.Keys(_mapStrategies)
+	slices.Sort(kinds)
+	list := make([]Strategy, 0, len(kinds))
+	for _, kind := range kinds {
+		list = append(list, _mapStrategies[kind])
+	}
+	_mutexStrategies.Unlock()
+
+	catalog := make([]StrategyMetadata, 0, len(list))
+	for _, model := range list {
+		catalog = append(catalog, GetMetadata(model))
+	}
+	return catalog
+}
+
+// EffectiveParams 合并策略声明的默认参数和配置文件中的params
+func (m StrategyMetadata) EffectiveParams(strategyParameter config.StrategyParameter) map[string]string {
+	params := make(map[string]string, len(m.Parameters))
+	for _, p := range m.Parameters {
+		params[p.Name] = p.Default
+	}
+	for k, v := range strategyParameter.Params {
+		params[k] = v
+	}
+	return params
+}
+
+// ValidateParams 按参数描述校验策略配置中的params, 未声明的参数和类型不符的值返回错误
+func (m StrategyMetadata) ValidateParams(params map[string]string) error {
+	for k, v := range params {
+		i := slices.IndexFunc(m.Parameters, func(p ParameterSchema) bool {
+			return p.Name == k
+		})
+		if i < 0 {
+			return fmt.Errorf("%w: 未声明的参数%s", ErrInvalidParams, k)
+		}
+		var err error
+		v = strings.TrimSpace(v)
+		switch m.Parameters[i].Type {
+		case ParameterTypeInt:
+			_, err = strconv.Atoi(v)
+		case ParameterTypeFloat:
+			_, err = strconv.ParseFloat(v, 64)
+		case ParameterTypeBool:
+			_, err = strconv.ParseBool(v)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %s=%s, 类型应为%s", ErrInvalidParams, k, v, m.Parameters[i].Type)
+		}
+	}
+	return nil
+}
+
+// StrategyParams 策略参数读取器
+type StrategyParams struct {
+	values map[string]string
+}
+
+// LoadStrategyParams 加载策略的有效参数
+func LoadStrategyParams(model Strategy) StrategyParams {
+	meta := GetMetadata(model)
+	strategyParameter, _ := config.LookupStrategyParameter(meta.Code)
+	return StrategyParams{values: meta.EffectiveParams(strategyParameter)}
+}
+
+// Float64 读取浮点参数, 不存在或格式错误时返回elseValue
+func (p StrategyParams) Float64(name string, elseValue float64) float64 {
+	v, ok := p.values[name]
+	if !ok {
+		return elseValue
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil {
+		return elseValue
+	}
+	return f
+}
+
+// Int 读取整型参数, 不存在或格式错误时返回elseValue
+func (p StrategyParams) Int(name string, elseValue int) int {
+	v, ok := p.values[name]
+	if !ok {
+		return elseValue
+	}
+	i, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil {
+		return elseValue
+	}
+	return i
+}
+
+// Bool 读取布尔参数, 不存在或格式错误时返回elseValue
+func (p StrategyParams) Bool(name string, elseValue bool) bool {
+	v, ok := p.values[name]
+	if !ok {
+		return elseValue
+	}
+	b, err := strconv.ParseBool(strings.TrimSpace(v))
+	if err != nil {
+		return elseValue
+	}
+	return b
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"xquant/pkg/config"
+)
+
+func TestStrategyMetadataEffectiveParams(t *testing.T) {
+	meta := StrategyMetadata{
+		Parameters: []ParameterSchema{
+			{Name: "target_ratio", Type: ParameterTypeFloat, Default: "10.00"},
+			{Name: "days", Type: ParameterTypeInt, Default: "5"},
+		},
+	}
+	sp := config.StrategyParameter{Params: map[string]string{"target_ratio": "12.5"}}
+	params := StrategyParams{values: meta.EffectiveParams(sp)}
+	if v := params.Float64("target_ratio", 0); v != 12.5 {
+		t.Errorf("Float64(target_ratio) = %f, want 12.5", v)
+	}
+	if v := params.Int("days", 0); v != 5 {
+		t.Errorf("Int(days) = %d, want 5", v)
+	}
+	if v := params.Bool("missing", true); !v {
+		t.Errorf("Bool(missing) = %v, want true", v)
+	}
+}
+
+func TestStrategyMetadataValidateParams(t *testing.T) {
+	meta := StrategyMetadata{
+		Parameters: []ParameterSchema{
+			{Name: "target_ratio", Type: ParameterTypeFloat, Default: "10.00"},
+			{Name: "days", Type: ParameterTypeInt, Default: "5"},
+		},
+	}
+	if err := meta.ValidateParams(map[string]string{"target_ratio": "12.5", "days": " 3 "}); err != nil {
+		t.Errorf("ValidateParams() = %v, want nil", err)
+	}
+	tests := []map[string]string{
+		{"days": "3.5"},
+		{"target_ratio": "abc"},
+		{"unknown": "1"},
+	}
+	for _, params := range tests {
+		if err := meta.ValidateParams(params); !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("ValidateParams(%v) = %v, want %v", params, err, ErrInvalidParams)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"gitee.com/quant1x/gox/concurrent"
+	"gitee.com/quant1x/pandas"
+	"xquant/pkg/factors"
+)
+
+// MultiTimeframe 多周期上下文
+//
+//	日线取自History, 周线和月线取自特征缓存, 盘中用快照合并出当前周期的K线和均线
+type MultiTimeframe struct {
+	SecurityCode string                 // 证券代码
+	Date         string                 // 日期
+	Snapshot     *factors.QuoteSnapshot // 快照, 可能为nil
+	Daily        *factors.History       // 日线历史
+	Weekly       *factors.PeriodHistory // 周线, 已合并当日快照
+	Monthly      *factors.PeriodHistory // 月线, 已合并当日快照
+}
+
+// NewMultiTimeframe 创建多周期上下文
+//
+//	snapshot为nil时从SnapshotMgr获取策略快照
+func NewMultiTimeframe(securityCode string, snapshot *factors.QuoteSnapshot) *MultiTimeframe {
+	if snapshot == nil {
+		snapshot = SnapshotMgr.GetStrategySnapshot(securityCode)
+	}
+	// 特征数据和快照的日期保持一致
+	var date []string
+	if snapshot != nil && len(snapshot.Date) > 0 {
+		date = append(date, snapshot.Date)
+	}
+	mtf := MultiTimeframe{
+		SecurityCode: securityCode,
+		Snapshot:     snapshot,
+		Daily:        factors.GetL5History(securityCode, date...),
+		Weekly:       factors.GetL5WeeklyHistory(securityCode, date...),
+		Monthly:      factors.GetL5MonthlyHistory(securityCode, date...),
+	}
+	if snapshot != nil {
+		mtf.Date = snapshot.Date
+		mtf.Weekly = increasePeriod(mtf.Weekly, *snapshot)
+		mtf.Monthly = increasePeriod(mtf.Monthly, *snapshot)
+	}
+	return &mtf
+}
+
+func increasePeriod(v *factors.PeriodHistory, snapshot factors.QuoteSnapshot) *factors.PeriodHistory {
+	if v == nil {
+		return nil
+	}
+	if p, ok := v.Increase(snapshot).(*factors.PeriodHistory); ok {
+		return p
+	}
+	return v
+}
+
+// WeeklyKLine 周线K线, 不含当日
+func (m *MultiTimeframe) WeeklyKLine() pandas.DataFrame {
+	return factors.CheckoutPeriodKLines(factors.PeriodWeekly, m.SecurityCode, m.Date)
+}
+
+// MonthlyKLine 月线K线, 不含当日
+func (m *MultiTimeframe) MonthlyKLine() pandas.DataFrame {
+	return factors.CheckoutPeriodKLines(factors.PeriodMonthly, m.SecurityCode, m.Date)
+}
+
+// WeeklyBull 周线是否多头排列
+func (m *MultiTimeframe) WeeklyBull() bool {
+	return m.Weekly != nil && m.Weekly.IsBull()
+}
+
+// MonthlyBull 月线是否多头排列
+func (m *MultiTimeframe) MonthlyBull() bool {
+	return m.Monthly != nil && m.Monthly.IsBull()
+}
+
+// MultiTimeframeStrategy 需要多周期数据的策略
+type MultiTimeframeStrategy interface {
+	Strategy
+	// EvaluateMultiTimeframe 带多周期上下文的评估
+	EvaluateMultiTimeframe(securityCode string, mtf *MultiTimeframe, result *concurrent.TreeMap[string, ResultInfo])
+}
+
+// Evaluate 执行策略评估
+//
+//	实现了MultiTimeframeStrategy的策略传入多周期上下文, 否则调用Strategy.Evaluate.
+//	snapshot为被评估的快照, 历史日期必须传入, 否则会评估实时数据; 为nil时使用实时快照, 没有快照不评估
+func Evaluate(model Strategy, securityCode string, snapshot *factors.QuoteSnapshot, result *concurrent.TreeMap[string, ResultInfo]) {
+	if snapshot == nil {
+		snapshot = SnapshotMgr.GetStrategySnapshot(securityCode)
+	}
+	if snapshot == nil {
+		return
+	}
+	if v, ok := model.(MultiTimeframeStrategy); ok {
+		mtf := NewMultiTimeframe(securityCode, snapshot)
+		v.EvaluateMultiTimeframe(securityCode, mtf, result)
+		return
+	}
+	model.Evaluate(securityCode, *snapshot, result)
+}
@@ -0,0 +1,95 @@
+package panel
+
+import (
+	"fmt"
+
+	"xquant/pkg/factors"
+)
+
+// Frame 一个交易日的截面数据, 包括宽表和选定的特征
+//
+//	Frame加载完成后只读, 可以在多个goroutine中共享.
+//	返回的列切片是零拷贝的, 调用方不能修改
+type Frame struct {
+	Date     string            // 日期
+	wide     *table            // 宽表
+	features map[string]*table // 特征, key为特征的缓存关键字
+}
+
+// Codes 宽表的证券代码列表, 和宽表的列对齐
+func (f *Frame) Codes() []string {
+	return f.wide.codes
+}
+
+// Len 宽表的证券数量
+func (f *Frame) Len() int {
+	return len(f.wide.codes)
+}
+
+// Wide 获取个股当日的宽表数据
+func (f *Frame) Wide(securityCode string) (factors.SecurityFeature, bool) {
+	v, ok := f.wide.row(securityCode)
+	if !ok {
+		return factors.SecurityFeature{}, false
+	}
+	return v.(factors.SecurityFeature), true
+}
+
+// Float64s 宽表的浮点列, name为dataframe标签名
+func (f *Frame) Float64s(name string) ([]float64, error) {
+	return f.wide.float64s(name)
+}
+
+// Int64s 宽表的整型列, name为dataframe标签名
+func (f *Frame) Int64s(name string) ([]int64, error) {
+	return f.wide.int64s(name)
+}
+
+func (f *Frame) feature(key string) (*table, error) {
+	t, ok := f.features[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", factors.ErrFeatureNotFound, key)
+	}
+	return t, nil
+}
+
+// Feature 获取个股当日的特征数据
+func (f *Frame) Feature(key, securityCode string) (factors.Feature, bool) {
+	t, ok := f.features[key]
+	if !ok {
+		return nil, false
+	}
+	v, ok := t.row(securityCode)
+	if !ok {
+		return nil, false
+	}
+	feature, ok := v.(factors.Feature)
+	return feature, ok
+}
+
+// FeatureCodes 特征的证券代码列表, 和特征的列对齐
+func (f *Frame) FeatureCodes(key string) []string {
+	t, err := f.feature(key)
+	if err != nil {
+		return nil
+	}
+	return t.codes
+}
+
+// FeatureFloat64s 特征的浮点列
+func (f *Frame) FeatureFloat64s(key, name string) ([]float64, error) {
+	t, err := f.feature(key)
+	if err != nil {
+		return nil, err
+	}
+	return t.float64s(name)
+}
+
+// FeatureInt64s 特征的整型列
+func (f *Frame) FeatureInt64s(key, name string) ([]int64, error) {
+	t, err := f.feature(key)
+	if err != nil {
+		return nil, err
+	}
+	return t.int64s(name)
+}
@@ -0,0 +1,258 @@
+// Package panel 面板数据
+//
+//	按日期和证券代码组织宽表和特征数据, 一次加载后供回测、参数寻优和研究工具共享
+package panel
+
+import (
+	"container/list"
+	"sort"
+	"sync"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/gox/logger"
+
+	"xquant/pkg/cache"
+	"xquant/pkg/factors"
+	"xquant/pkg/market"
+)
+
+const (
+	DefaultCapacity     = 20   // 默认缓存的日期数
+	DefaultWideCapacity = 6000 // 默认缓存宽表的证券数, 覆盖全市场, 构建截面数据时不会反复加载
+)
+
+// entry LRU中的一个日期
+type entry struct {
+	date  string
+	once  sync.Once
+	frame *Frame
+}
+
+// wideEntry 宽表LRU中的一个证券
+type wideEntry struct {
+	securityCode string
+	lines        []factors.SecurityFeature
+}
+
+// Store 面板数据存储
+//
+//	个股的宽表和按日期切出的截面数据都用LRU限制内存, 每日数据更新后调用Purge失效
+type Store struct {
+	m        sync.Mutex
+	capacity int
+	features []string
+	codes    []string
+	lru      *list.List
+	frames   map[string]*list.Element
+
+	wideMutex    sync.Mutex
+	wideCapacity int
+	wideLru      *list.List
+	wides        map[string]*list.Element
+
+	loadWide    func(securityCode string) []factors.SecurityFeature
+	loadFeature func(key, date string) (any, error)
+}
+
+// NewStore 创建面板数据存储
+//
+//	capacity为缓存的日期数, 小于1时使用默认值; features为需要加载的特征缓存关键字
+func NewStore(capacity int, features ...string) *Store {
+	if capacity < 1 {
+		capacity = DefaultCapacity
+	}
+	return &Store{
+		capacity:     capacity,
+		features:     features,
+		lru:          list.New(),
+		frames:       map[string]*list.Element{},
+		wideCapacity: DefaultWideCapacity,
+		wideLru:      list.New(),
+		wides:        map[string]*list.Element{},
+		loadWide:     loadWideTable,
+		loadFeature:  factors.LoadFeatureSlices,
+	}
+}
+
+var (
+	defaultOnce  sync.Once
+	defaultStore *Store
+)
+
+// Default 默认的共享存储, 只包含宽表
+func Default() *Store {
+	defaultOnce.Do(func() {
+		defaultStore = NewStore(DefaultCapacity)
+	})
+	return defaultStore
+}
+
+func loadWideTable(securityCode string) []factors.SecurityFeature {
+	var list []factors.SecurityFeature
+	filename := cache.WideFilename(securityCode)
+	if err := cache.LoadSlices(filename, &list); err != nil {
+		return nil
+	}
+	return list
+}
+
+// Codes 证券代码列表, 默认是全部代码
+func (s *Store) Codes() []string {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.codes == nil {
+		s.codes = market.GetCodeList()
+	}
+	return s.codes
+}
+
+// SetCodes 设置证券代码范围, 已缓存的截面数据全部失效
+func (s *Store) SetCodes(codes []string) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.codes = codes
+	s.lru.Init()
+	s.frames = map[string]*list.Element{}
+}
+
+// SetWideCapacity 设置缓存宽表的证券数, 小于1时使用默认值, 超出容量的宽表立即淘汰
+func (s *Store) SetWideCapacity(capacity int) {
+	if capacity < 1 {
+		capacity = DefaultWideCapacity
+	}
+	s.wideMutex.Lock()
+	defer s.wideMutex.Unlock()
+	s.wideCapacity = capacity
+	s.evictWides()
+}
+
+// evictWides 淘汰超出容量的宽表, 调用方持有wideMutex
+func (s *Store) evictWides() {
+	for s.wideLru.Len() > s.wideCapacity {
+		last := s.wideLru.Back()
+		s.wideLru.Remove(last)
+		delete(s.wides, last.Value.(*wideEntry).securityCode)
+	}
+}
+
+// WideTable 获取个股的宽表, 不在缓存中时加载, 超出容量时淘汰最久未使用的证券
+//
+//	返回的切片在多个调用方之间共享, 不能修改
+func (s *Store) WideTable(securityCode string) []factors.SecurityFeature {
+	securityCode = exchange.CorrectSecurityCode(securityCode)
+	s.wideMutex.Lock()
+	if elem, ok := s.wides[securityCode]; ok {
+		s.wideLru.MoveToFront(elem)
+		lines := elem.Value.(*wideEntry).lines
+		s.wideMutex.Unlock()
+		return lines
+	}
+	s.wideMutex.Unlock()
+	// 加载过程不持有锁
+	lines := s.loadWide(securityCode)
+	s.wideMutex.Lock()
+	defer s.wideMutex.Unlock()
+	// 并发加载时以先写入的为准
+	if elem, found := s.wides[securityCode]; found {
+		s.wideLru.MoveToFront(elem)
+		return elem.Value.(*wideEntry).lines
+	}
+	s.wides[securityCode] = s.wideLru.PushFront(&wideEntry{securityCode: securityCode, lines: lines})
+	s.evictWides()
+	return lines
+}
+
+// Frame 获取指定日期的截面数据, 不存在时加载, 超出容量时淘汰最久未使用的日期
+func (s *Store) Frame(date string) *Frame {
+	date = exchange.FixTradeDate(date)
+	s.m.Lock()
+	var e *entry
+	if elem, ok := s.frames[date]; ok {
+		s.lru.MoveToFront(elem)
+		e = elem.Value.(*entry)
+	} else {
+		e = &entry{date: date}
+		s.frames[date] = s.lru.PushFront(e)
+		for s.lru.Len() > s.capacity {
+			last := s.lru.Back()
+			s.lru.Remove(last)
+			delete(s.frames, last.Value.(*entry).date)
+		}
+	}
+	s.m.Unlock()
+	// 加载过程不持有锁, 同一日期只加载一次
+	e.once.Do(func() {
+		e.frame = s.build(date)
+	})
+	return e.frame
+}
+
+// Preload 预加载多个日期, 超出容量的日期会被淘汰
+func (s *Store) Preload(dates ...string) {
+	for _, date := range dates {
+		_ = s.Frame(date)
+	}
+}
+
+// Dates 已缓存的日期, 按最近使用排序
+func (s *Store) Dates() []string {
+	s.m.Lock()
+	defer s.m.Unlock()
+	dates := make([]string, 0, s.lru.Len())
+	for elem := s.lru.Front(); elem != nil; elem = elem.Next() {
+		dates = append(dates, elem.Value.(*entry).date)
+	}
+	return dates
+}
+
+// Purge 清空缓存的宽表和截面数据, 宽表和特征数据更新后调用
+func (s *Store) Purge() {
+	s.m.Lock()
+	s.lru.Init()
+	s.frames = map[string]*list.Element{}
+	s.m.Unlock()
+	s.wideMutex.Lock()
+	s.wideLru.Init()
+	s.wides = map[string]*list.Element{}
+	s.wideMutex.Unlock()
+}
+
+// build 从宽表和特征缓存构建截面数据
+func (s *Store) build(date string) *Frame {
+	codes := s.Codes()
+	rows := make([]factors.SecurityFeature, 0, len(codes))
+	rowCodes := make([]string, 0, len(codes))
+	for _, securityCode := range codes {
+		securityCode = exchange.CorrectSecurityCode(securityCode)
+		lines := s.WideTable(securityCode)
+		if v, ok := findByDate(lines, date); ok {
+			rows = append(rows, v)
+			rowCodes = append(rowCodes, securityCode)
+		}
+	}
+	frame := &Frame{
+		Date:     date,
+		wide:     newTable(rows, rowCodes),
+		features: make(map[string]*table, len(s.features)),
+	}
+	for _, key := range s.features {
+		list, err := s.loadFeature(key, date)
+		if err != nil {
+			logger.Errorf("panel: 加载特征%s失败, date=%s, error=%+v", key, date, err)
+			continue
+		}
+		frame.features[key] = newFeatureTable(list)
+	}
+	return frame
+}
+
+// findByDate 宽表按日期升序, 二分查找指定日期的数据
+func findByDate(lines []factors.SecurityFeature, date string) (factors.SecurityFeature, bool) {
+	i := sort.Search(len(lines), func(i int) bool {
+		return lines[i].Date >= date
+	})
+	if i < len(lines) && lines[i].Date == date {
+		return lines[i], true
+	}
+	return factors.SecurityFeature{}, false
+}
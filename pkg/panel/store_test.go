@@ -0,0 +1,74 @@
+package panel
+
+import (
+	"fmt"
+	"testing"
+
+	"xquant/pkg/factors"
+)
+
+func newTestStore(capacity int) *Store {
+	s := NewStore(capacity)
+	s.SetCodes([]string{"sh600000", "sz000001"})
+	s.loadWide = func(securityCode string) []factors.SecurityFeature {
+		return []factors.SecurityFeature{
+			{Date: "2024-01-02", Close: 10},
+			{Date: "2024-01-03", Close: 11},
+			{Date: "2024-01-04", Close: 12},
+		}
+	}
+	return s
+}
+
+func TestStoreFrame(t *testing.T) {
+	s := newTestStore(2)
+	frame := s.Frame("2024-01-03")
+	fmt.Println(frame.Codes())
+	v, ok := frame.Wide("sz000001")
+	if !ok || v.Close != 11 {
+		t.Errorf("Wide = %+v, %t", v, ok)
+	}
+	closes, err := frame.Float64s("close")
+	if err != nil || len(closes) != 2 || closes[0] != 11 {
+		t.Errorf("Float64s = %v, %v", closes, err)
+	}
+	// 再次获取列是同一块内存
+	again, _ := frame.Float64s("close")
+	if &again[0] != &closes[0] {
+		t.Error("column is copied")
+	}
+	_ = s.Frame("2024-01-02")
+	_ = s.Frame("2024-01-04")
+	fmt.Println(s.Dates())
+	if dates := s.Dates(); len(dates) != 2 || dates[0] != "2024-01-04" {
+		t.Errorf("Dates = %v", dates)
+	}
+}
+
+func TestStoreWideCapacity(t *testing.T) {
+	s := newTestStore(2)
+	loads := 0
+	load := s.loadWide
+	s.loadWide = func(securityCode string) []factors.SecurityFeature {
+		loads++
+		return load(securityCode)
+	}
+	s.SetWideCapacity(1)
+	_ = s.WideTable("sh600000")
+	_ = s.WideTable("sh600000")
+	if loads != 1 {
+		t.Errorf("loads = %d, want 1", loads)
+	}
+	// 超出容量淘汰最久未使用的证券
+	_ = s.WideTable("sz000001")
+	_ = s.WideTable("sh600000")
+	if loads != 3 {
+		t.Errorf("loads = %d, want 3", loads)
+	}
+	// 数据更新后失效
+	s.Purge()
+	_ = s.WideTable("sh600000")
+	if loads != 4 {
+		t.Errorf("loads = %d, want 4", loads)
+	}
+}
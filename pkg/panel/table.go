@@ -0,0 +1,83 @@
+package panel
+
+import (
+	"reflect"
+	"sync"
+
+	"xquant/pkg/columnar"
+	"xquant/pkg/factors"
+)
+
+// table 同一个日期的一组截面数据
+//
+//	行是结构体, 列在第一次访问时按列式格式编码一次, 之后的列切片都引用同一块内存
+type table struct {
+	rows  reflect.Value  // 结构体切片
+	codes []string       // 证券代码, 和行对齐
+	index map[string]int // 证券代码->行号
+	once  sync.Once
+	file  *columnar.File
+	err   error
+}
+
+// newTable 从结构体切片创建截面数据, codes为和行对齐的证券代码
+func newTable(slice any, codes []string) *table {
+	t := &table{rows: reflect.ValueOf(slice), codes: codes, index: make(map[string]int, len(codes))}
+	for i, code := range codes {
+		t.index[code] = i
+	}
+	return t
+}
+
+// newFeatureTable 从特征切片创建截面数据, 证券代码取自特征
+func newFeatureTable(slice any) *table {
+	rv := reflect.ValueOf(slice)
+	if rv.Kind() != reflect.Slice {
+		return newTable([]factors.SecurityFeature{}, nil)
+	}
+	codes := make([]string, rv.Len())
+	for i := range codes {
+		if v, ok := rv.Index(i).Interface().(factors.Feature); ok {
+			codes[i] = v.GetSecurityCode()
+		}
+	}
+	return newTable(slice, codes)
+}
+
+// row 按证券代码取行
+func (t *table) row(securityCode string) (any, bool) {
+	i, ok := t.index[securityCode]
+	if !ok {
+		return nil, false
+	}
+	return t.rows.Index(i).Interface(), true
+}
+
+// columns 列式数据, 不压缩才能零拷贝
+func (t *table) columns() (*columnar.File, error) {
+	t.once.Do(func() {
+		var data []byte
+		data, t.err = columnar.Marshal(t.rows.Interface(), columnar.WithCompression(columnar.CompressionNone))
+		if t.err != nil {
+			return
+		}
+		t.file, t.err = columnar.NewFile(data)
+	})
+	return t.file, t.err
+}
+
+func (t *table) float64s(name string) ([]float64, error) {
+	f, err := t.columns()
+	if err != nil {
+		return nil, err
+	}
+	return f.Float64s(name)
+}
+
+func (t *table) int64s(name string) ([]int64, error) {
+	f, err := t.columns()
+	if err != nil {
+		return nil, err
+	}
+	return f.Int64s(name)
+}
@@ -0,0 +1,170 @@
+package research
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gitee.com/quant1x/gox/api"
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/components"
+	"github.com/go-echarts/go-echarts/v2/opts"
+
+	"xquant/pkg/cache"
+)
+
+// DefaultOutputPath 默认的输出路径
+func DefaultOutputPath() string {
+	return filepath.Join(cache.GetRootPath(), "research")
+}
+
+// Export 导出csv和html报告, 返回生成的文件列表
+//
+//	dir为空时输出到默认路径
+func Export(report *Report, dir string) ([]string, error) {
+	if len(dir) == 0 {
+		dir = DefaultOutputPath()
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	prefix := filepath.Join(dir, fmt.Sprintf("factor-%s-%s-%s", strings.ReplaceAll(report.Factor, ".", "_"), report.StartDate, report.EndDate))
+	var files []string
+	tables := []struct {
+		suffix string
+		data   any
+	}{
+		{"daily", report.Daily},
+		{"summary", report.Summary},
+		{"quantile", report.Quantiles},
+		{"decay", report.Decay},
+	}
+	for _, v := range tables {
+		filename := prefix + "-" + v.suffix + ".csv"
+		if err := api.SlicesToCsv(filename, v.data); err != nil {
+			return files, err
+		}
+		files = append(files, filename)
+	}
+	filename := prefix + ".html"
+	if err := ExportHTML(report, filename); err != nil {
+		return files, err
+	}
+	files = append(files, filename)
+	return files, nil
+}
+
+// ExportHTML 导出go-echarts图表: 每日IC、累计IC、分位数收益和IC衰减
+func ExportHTML(report *Report, filename string) error {
+	page := components.NewPage()
+	page.PageTitle = "因子研究: " + report.Factor
+	page.AddCharts(
+		icChart(report, false),
+		icChart(report, true),
+		quantileChart(report),
+		decayChart(report),
+	)
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	return page.Render(f)
+}
+
+func horizonName(h int) string {
+	return strconv.Itoa(h) + "日"
+}
+
+// icChart 每日IC或累计IC
+func icChart(report *Report, cumulative bool) *charts.Line {
+	title := "每日RankIC"
+	if cumulative {
+		title = "累计RankIC"
+	}
+	line := charts.NewLine()
+	line.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{Title: title, Subtitle: report.Factor}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true), Trigger: "axis"}),
+		charts.WithLegendOpts(opts.Legend{Show: opts.Bool(true), Right: "10%"}),
+		charts.WithYAxisOpts(opts.YAxis{Scale: opts.Bool(true)}),
+	)
+	var dates []string
+	series := map[int]map[string]float64{}
+	for _, v := range report.Daily {
+		if series[v.Horizon] == nil {
+			series[v.Horizon] = map[string]float64{}
+		}
+		// 每日IC按日期顺序生成
+		if len(dates) == 0 || dates[len(dates)-1] != v.Date {
+			dates = append(dates, v.Date)
+		}
+		series[v.Horizon][v.Date] = v.IC
+	}
+	line.SetXAxis(dates)
+	for _, s := range report.Summary {
+		values := series[s.Horizon]
+		items := make([]opts.LineData, 0, len(dates))
+		sum := 0.0
+		for _, date := range dates {
+			ic, ok := values[date]
+			if !ok {
+				items = append(items, opts.LineData{Value: "-"})
+				continue
+			}
+			if cumulative {
+				sum += ic
+				ic = sum
+			}
+			items = append(items, opts.LineData{Value: ic})
+		}
+		line.AddSeries(horizonName(s.Horizon), items)
+	}
+	return line
+}
+
+// quantileChart 分位数组合的平均收益
+func quantileChart(report *Report) *charts.Bar {
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{Title: "分位数组合平均收益", Subtitle: "因子值从小到大分组"}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true), Trigger: "axis"}),
+		charts.WithLegendOpts(opts.Legend{Show: opts.Bool(true), Right: "10%"}),
+	)
+	groups := map[int][]opts.BarData{}
+	maxQuantile := 0
+	for _, v := range report.Quantiles {
+		groups[v.Horizon] = append(groups[v.Horizon], opts.BarData{Value: v.Return})
+		maxQuantile = max(maxQuantile, v.Quantile)
+	}
+	xAxis := make([]string, maxQuantile)
+	for i := range xAxis {
+		xAxis[i] = "Q" + strconv.Itoa(i+1)
+	}
+	bar.SetXAxis(xAxis)
+	for _, s := range report.Summary {
+		if items, ok := groups[s.Horizon]; ok {
+			bar.AddSeries(horizonName(s.Horizon), items)
+		}
+	}
+	return bar
+}
+
+// decayChart IC衰减曲线
+func decayChart(report *Report) *charts.Line {
+	line := charts.NewLine()
+	line.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{Title: "IC衰减", Subtitle: "因子与滞后N日单日收益的IC均值"}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true), Trigger: "axis"}),
+	)
+	xAxis := make([]string, 0, len(report.Decay))
+	items := make([]opts.LineData, 0, len(report.Decay))
+	for _, v := range report.Decay {
+		xAxis = append(xAxis, strconv.Itoa(v.Lag))
+		items = append(items, opts.LineData{Value: v.ICMean})
+	}
+	line.SetXAxis(xAxis).AddSeries("IC均值", items)
+	return line
+}
@@ -0,0 +1,268 @@
+// Package research 因子研究工具
+//
+//	计算特征字段对未来收益的预测能力: 截面RankIC、IC均值、IR、分位数组合收益、多空收益和IC衰减
+package research
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"gitee.com/quant1x/exchange"
+
+	"xquant/pkg/factors"
+	"xquant/pkg/panel"
+)
+
+const (
+	// WideKey 宽表的关键字, 因子可以直接使用宽表的字段
+	WideKey = "wide"
+
+	defaultQuantiles = 5  // 默认分组数
+	defaultDecay     = 10 // 默认衰减的最大滞后天数
+)
+
+var (
+	defaultHorizons = []int{1, 3, 5} // 默认的未来收益周期
+
+	ErrInvalidFactor    = errors.New("因子格式错误, 应为<特征关键字>.<字段名>, 比如history.ma5")
+	ErrInvalidDateRange = errors.New("日期范围内没有交易日")
+)
+
+// Parameter 因子研究参数
+type Parameter struct {
+	Factor    string `json:"factor"`    // 因子, 格式为<特征关键字>.<字段名>, 特征关键字为wide时取宽表字段
+	StartDate string `json:"startDate"` // 开始日期
+	EndDate   string `json:"endDate"`   // 结束日期
+	Horizons  []int  `json:"horizons"`  // 未来收益的周期, 默认1,3,5日
+	Quantiles int    `json:"quantiles"` // 分组数, 默认5组
+	Decay     int    `json:"decay"`     // IC衰减的最大滞后天数, 默认10天
+}
+
+// parse 解析因子的特征关键字和字段名
+func (this *Parameter) parse() (key, field string, err error) {
+	key, field, found := strings.Cut(strings.TrimSpace(this.Factor), ".")
+	if !found || len(key) == 0 || len(field) == 0 {
+		return "", "", ErrInvalidFactor
+	}
+	return strings.ToLower(key), field, nil
+}
+
+func (this *Parameter) fix() {
+	if len(this.Horizons) == 0 {
+		this.Horizons = defaultHorizons
+	}
+	this.Horizons = slices.DeleteFunc(slices.Clone(this.Horizons), func(h int) bool { return h < 1 })
+	slices.Sort(this.Horizons)
+	this.Horizons = slices.Compact(this.Horizons)
+	if this.Quantiles < 2 {
+		this.Quantiles = defaultQuantiles
+	}
+	if this.Decay < 1 {
+		this.Decay = defaultDecay
+	}
+}
+
+// DailyIC 每日截面IC
+type DailyIC struct {
+	Date    string  `name:"日期" dataframe:"date" json:"date"`
+	Horizon int     `name:"周期" dataframe:"horizon" json:"horizon"`
+	IC      float64 `name:"RankIC" dataframe:"ic" json:"ic"`
+	Count   int     `name:"样本数" dataframe:"count" json:"count"`
+}
+
+// Summary 按周期汇总的IC统计
+type Summary struct {
+	Horizon       int     `name:"周期" dataframe:"horizon" json:"horizon"`
+	Days          int     `name:"天数" dataframe:"days" json:"days"`
+	ICMean        float64 `name:"IC均值" dataframe:"ic_mean" json:"icMean"`
+	ICStd         float64 `name:"IC标准差" dataframe:"ic_std" json:"icStd"`
+	IR            float64 `name:"IR" dataframe:"ir" json:"ir"`
+	PositiveRatio float64 `name:"IC为正占比" dataframe:"positive_ratio" json:"positiveRatio"`
+	LongShort     float64 `name:"多空收益" dataframe:"long_short" json:"longShort"` // 最高分组减最低分组的平均收益
+}
+
+// QuantileReturn 分位数组合的平均收益
+type QuantileReturn struct {
+	Horizon  int     `name:"周期" dataframe:"horizon" json:"horizon"`
+	Quantile int     `name:"分组" dataframe:"quantile" json:"quantile"` // 从1开始, 因子值从小到大
+	Return   float64 `name:"平均收益" dataframe:"return" json:"return"`
+}
+
+// DecayPoint IC衰减, 因子和滞后lag天的单日收益的IC
+type DecayPoint struct {
+	Lag    int     `name:"滞后天数" dataframe:"lag" json:"lag"`
+	ICMean float64 `name:"IC均值" dataframe:"ic_mean" json:"icMean"`
+	IR     float64 `name:"IR" dataframe:"ir" json:"ir"`
+}
+
+// Report 因子研究报告
+type Report struct {
+	Factor    string           `json:"factor"`
+	StartDate string           `json:"startDate"`
+	EndDate   string           `json:"endDate"`
+	Daily     []DailyIC        `json:"daily"`
+	Summary   []Summary        `json:"summary"`
+	Quantiles []QuantileReturn `json:"quantiles"`
+	Decay     []DecayPoint     `json:"decay"`
+}
+
+// Analyze 计算因子的IC、IR、分位数收益和IC衰减
+//
+//	收益率取自宽表的收盘价, 第t日的h日收益为close[t+h]/close[t]-1
+func Analyze(parameter Parameter) (*Report, error) {
+	key, field, err := parameter.parse()
+	if err != nil {
+		return nil, err
+	}
+	parameter.fix()
+	dates := exchange.TradingDateRange(exchange.FixTradeDate(parameter.StartDate), exchange.FixTradeDate(parameter.EndDate))
+	if len(dates) == 0 {
+		return nil, ErrInvalidDateRange
+	}
+	var store *panel.Store
+	if key == WideKey {
+		store = panel.NewStore(2)
+	} else {
+		store = panel.NewStore(2, key)
+	}
+	report := &Report{
+		Factor:    parameter.Factor,
+		StartDate: dates[0],
+		EndDate:   dates[len(dates)-1],
+	}
+	dailyICs := make(map[int][]float64, len(parameter.Horizons))
+	quantileSums := make(map[int][]float64, len(parameter.Horizons))
+	quantileDays := make(map[int]int, len(parameter.Horizons))
+	decayICs := make([][]float64, parameter.Decay)
+	for _, date := range dates {
+		frame := store.Frame(date)
+		codes, values, err := factorValues(frame, key, field)
+		if err != nil {
+			return nil, err
+		}
+		if len(codes) == 0 {
+			continue
+		}
+		// 未来收益
+		for _, h := range parameter.Horizons {
+			returns := make([]float64, len(codes))
+			for i, code := range codes {
+				returns[i] = forwardReturn(store.WideTable(code), date, 0, h)
+			}
+			ic, count := RankIC(values, returns)
+			if count < parameter.Quantiles || !isValid(ic) {
+				continue
+			}
+			report.Daily = append(report.Daily, DailyIC{Date: date, Horizon: h, IC: ic, Count: count})
+			dailyICs[h] = append(dailyICs[h], ic)
+			if qr := QuantileReturns(values, returns, parameter.Quantiles); qr != nil {
+				if quantileSums[h] == nil {
+					quantileSums[h] = make([]float64, parameter.Quantiles)
+				}
+				for q, v := range qr {
+					quantileSums[h][q] += v
+				}
+				quantileDays[h]++
+			}
+		}
+		// IC衰减, 滞后lag天的单日收益
+		for lag := 1; lag <= parameter.Decay; lag++ {
+			returns := make([]float64, len(codes))
+			for i, code := range codes {
+				returns[i] = forwardReturn(store.WideTable(code), date, lag-1, 1)
+			}
+			if ic, count := RankIC(values, returns); count >= parameter.Quantiles && isValid(ic) {
+				decayICs[lag-1] = append(decayICs[lag-1], ic)
+			}
+		}
+	}
+	for _, h := range parameter.Horizons {
+		ics := dailyICs[h]
+		summary := Summary{Horizon: h, Days: len(ics), ICMean: finite(mean(ics)), ICStd: finite(std(ics))}
+		if summary.ICStd > 0 {
+			summary.IR = summary.ICMean / summary.ICStd
+		}
+		positive := 0
+		for _, v := range ics {
+			if v > 0 {
+				positive++
+			}
+		}
+		if len(ics) > 0 {
+			summary.PositiveRatio = float64(positive) / float64(len(ics))
+		}
+		if days := quantileDays[h]; days > 0 {
+			sums := quantileSums[h]
+			for q, v := range sums {
+				report.Quantiles = append(report.Quantiles, QuantileReturn{Horizon: h, Quantile: q + 1, Return: v / float64(days)})
+			}
+			summary.LongShort = (sums[len(sums)-1] - sums[0]) / float64(days)
+		}
+		report.Summary = append(report.Summary, summary)
+	}
+	for i, ics := range decayICs {
+		point := DecayPoint{Lag: i + 1, ICMean: finite(mean(ics))}
+		if sd := finite(std(ics)); sd > 0 {
+			point.IR = point.ICMean / sd
+		}
+		report.Decay = append(report.Decay, point)
+	}
+	return report, nil
+}
+
+// factorValues 取截面的因子值, 整型字段转成浮点
+func factorValues(frame *panel.Frame, key, field string) ([]string, []float64, error) {
+	if key == WideKey {
+		values, err := frame.Float64s(field)
+		if err != nil {
+			ints, e := frame.Int64s(field)
+			if e != nil {
+				return nil, nil, fmt.Errorf("%s.%s: %w", key, field, err)
+			}
+			values = toFloat64s(ints)
+		}
+		return frame.Codes(), values, nil
+	}
+	codes := frame.FeatureCodes(key)
+	if codes == nil {
+		return nil, nil, nil
+	}
+	values, err := frame.FeatureFloat64s(key, field)
+	if err != nil {
+		ints, e := frame.FeatureInt64s(key, field)
+		if e != nil {
+			return nil, nil, fmt.Errorf("%s.%s: %w", key, field, err)
+		}
+		values = toFloat64s(ints)
+	}
+	return codes, values, nil
+}
+
+func toFloat64s(values []int64) []float64 {
+	list := make([]float64, len(values))
+	for i, v := range values {
+		list[i] = float64(v)
+	}
+	return list
+}
+
+// forwardReturn 从date之后第offset个交易日开始, 持有h个交易日的收益率
+//
+//	数据不足时返回NaN
+func forwardReturn(lines []factors.SecurityFeature, date string, offset, h int) float64 {
+	i := sort.Search(len(lines), func(i int) bool {
+		return lines[i].Date >= date
+	})
+	if i >= len(lines) || lines[i].Date != date {
+		return math.NaN()
+	}
+	begin, end := i+offset, i+offset+h
+	if end >= len(lines) || lines[begin].Close <= 0 {
+		return math.NaN()
+	}
+	return lines[end].Close/lines[begin].Close - 1
+}
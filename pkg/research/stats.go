@@ -0,0 +1,153 @@
+package research
+
+import (
+	"math"
+	"sort"
+)
+
+// pair 同一个证券的因子值和收益率
+type pair struct {
+	x float64
+	y float64
+}
+
+// validPairs 过滤掉因子值或收益率无效的样本
+func validPairs(x, y []float64) []pair {
+	n := min(len(x), len(y))
+	pairs := make([]pair, 0, n)
+	for i := 0; i < n; i++ {
+		if isValid(x[i]) && isValid(y[i]) {
+			pairs = append(pairs, pair{x: x[i], y: y[i]})
+		}
+	}
+	return pairs
+}
+
+func isValid(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// ranks 计算秩, 相同的值取平均秩
+func ranks(values []float64) []float64 {
+	n := len(values)
+	index := make([]int, n)
+	for i := range index {
+		index[i] = i
+	}
+	sort.SliceStable(index, func(a, b int) bool {
+		return values[index[a]] < values[index[b]]
+	})
+	result := make([]float64, n)
+	for i := 0; i < n; {
+		j := i + 1
+		for j < n && values[index[j]] == values[index[i]] {
+			j++
+		}
+		// i..j-1 并列, 秩从1开始
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			result[index[k]] = rank
+		}
+		i = j
+	}
+	return result
+}
+
+// pearson 皮尔逊相关系数, 样本不足或方差为0时返回NaN
+func pearson(x, y []float64) float64 {
+	n := len(x)
+	if n < 2 || n != len(y) {
+		return math.NaN()
+	}
+	mx, my := mean(x), mean(y)
+	var sxy, sxx, syy float64
+	for i := 0; i < n; i++ {
+		dx, dy := x[i]-mx, y[i]-my
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return math.NaN()
+	}
+	return sxy / math.Sqrt(sxx*syy)
+}
+
+// RankIC 截面秩相关系数(Spearman), 返回IC和有效样本数
+func RankIC(x, y []float64) (float64, int) {
+	pairs := validPairs(x, y)
+	xs := make([]float64, len(pairs))
+	ys := make([]float64, len(pairs))
+	for i, p := range pairs {
+		xs[i], ys[i] = p.x, p.y
+	}
+	return pearson(ranks(xs), ranks(ys)), len(pairs)
+}
+
+// QuantileReturns 按因子值从小到大分成n组, 返回每组的平均收益率
+//
+//	样本数少于分组数时返回nil
+func QuantileReturns(x, y []float64, n int) []float64 {
+	pairs := validPairs(x, y)
+	if n < 1 || len(pairs) < n {
+		return nil
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].x < pairs[j].x
+	})
+	result := make([]float64, n)
+	total := len(pairs)
+	for q := 0; q < n; q++ {
+		begin, end := q*total/n, (q+1)*total/n
+		var sum float64
+		for _, p := range pairs[begin:end] {
+			sum += p.y
+		}
+		result[q] = sum / float64(end-begin)
+	}
+	return result
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// std 样本标准差
+func std(values []float64) float64 {
+	n := len(values)
+	if n < 2 {
+		return math.NaN()
+	}
+	m := mean(values)
+	var sum float64
+	for _, v := range values {
+		sum += (v - m) * (v - m)
+	}
+	return math.Sqrt(sum / float64(n-1))
+}
+
+// validValues 过滤掉无效值
+func validValues(values []float64) []float64 {
+	list := make([]float64, 0, len(values))
+	for _, v := range values {
+		if isValid(v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// finite 无效值替换成0, json不支持NaN
+func finite(v float64) float64 {
+	if isValid(v) {
+		return v
+	}
+	return 0
+}
@@ -0,0 +1,34 @@
+package research
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRankIC(t *testing.T) {
+	x := []float64{1, 2, 3, 4, 5, math.NaN()}
+	y := []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6}
+	ic, n := RankIC(x, y)
+	if n != 5 || math.Abs(ic-1) > 1e-9 {
+		t.Errorf("RankIC = %f, %d", ic, n)
+	}
+	ic, _ = RankIC(x, []float64{5, 4, 3, 2, 1, 0})
+	if math.Abs(ic+1) > 1e-9 {
+		t.Errorf("RankIC = %f", ic)
+	}
+	if got := ranks([]float64{3, 1, 3, 2}); got[0] != 3.5 || got[1] != 1 || got[2] != 3.5 || got[3] != 2 {
+		t.Errorf("ranks = %v", got)
+	}
+}
+
+func TestQuantileReturns(t *testing.T) {
+	x := []float64{6, 5, 4, 3, 2, 1}
+	y := []float64{6, 5, 4, 3, 2, 1}
+	got := QuantileReturns(x, y, 3)
+	if len(got) != 3 || got[0] != 1.5 || got[2] != 5.5 {
+		t.Errorf("QuantileReturns = %v", got)
+	}
+	if QuantileReturns(x[:2], y[:2], 3) != nil {
+		t.Error("expected nil")
+	}
+}
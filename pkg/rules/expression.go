@@ -0,0 +1,666 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// 表达式规则的语法:
+//
+//	逻辑: && || ! and or not
+//	比较: < <= > >= == !=
+//	算术: + - * / %
+//	函数: abs(x) min(x,y...) max(x,y...) isnan(x)
+//	字段: 数据源.字段名, 字段名可以是结构体字段名(不区分大小写)或dataframe标签, 例如 snapshot.OpenTurnZ, history.ma5
+//	指标: indicator.指标名_输出列, 按默认参数计算, 例如 indicator.kdj_j, indicator.rsi_rsi1
+//	板块: sector.字段名, 个股所属排名最靠前板块的轮动特征, 例如 sector.rank, sector.top_streak, sector.is_leader
+//	龙虎榜: lhb.字段名, 个股当日的龙虎榜席位汇总, 例如 lhb.institution_net, lhb.hot_money, lhb.rise_probability_3d
+//	资金流向: fundflow.字段名, 单位元, 例如 fundflow.main, fundflow.main5, fundflow.main_inflow_days
+//	成交分析: orderflow.字段名, 按历史成交数据统计, 例如 orderflow.imbalance, orderflow.large_net_ratio, orderflow.tail_net
+//	限售解禁: unlock.字段名, 下一个解禁日, 例如 unlock.unlock_days, unlock.float_ratio
+//	除权除息: dividend.字段名, 下一个除权除息日, 例如 dividend.ex_days, dividend.dividend_yield, dividend.trailing_yield
+//	情绪: emotion.字段名, 市场情绪周期指数, 历史日期取前一交易日的结果, 例如 emotion.score, emotion.max_boards, emotion.broken_rate
+//
+// 布尔值按1和0参与运算, 表达式结果非0即为成立.
+// 特征不存在或字段值为NaN时视为数据缺失, 按规则组的missing_data策略处理, 也可以用isnan(x)显式判断
+
+var (
+	ErrExpressionSyntax = errors.New("表达式语法错误")
+	ErrFieldNotFound    = errors.New("字段不存在")
+	ErrSourceNotFound   = errors.New("数据源不存在")
+)
+
+// FieldResolver 字段解析器
+type FieldResolver interface {
+	// Resolve 解析字段值, source为数据源名称, field为字段名
+	Resolve(source, field string) (float64, error)
+}
+
+// FieldValuer 自定义取值的数据源, 实现该接口的数据源不再按结构体字段解析
+type FieldValuer interface {
+	// FieldValue 字段值
+	FieldValue(field string) (float64, error)
+}
+
+// Expression 编译后的表达式
+type Expression struct {
+	text string
+	root exprNode
+}
+
+// CompileExpression 编译表达式
+func CompileExpression(text string) (*Expression, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("%w: 多余的符号 %q", ErrExpressionSyntax, p.tokens[p.pos].text)
+	}
+	return &Expression{text: text, root: root}, nil
+}
+
+// String 返回表达式原文
+func (e *Expression) String() string {
+	return e.text
+}
+
+// Eval 计算表达式的值
+func (e *Expression) Eval(resolver FieldResolver) (float64, error) {
+	return e.root.eval(resolver)
+}
+
+// Test 判断表达式是否成立
+func (e *Expression) Test(resolver FieldResolver) (bool, error) {
+	v, err := e.Eval(resolver)
+	if err != nil {
+		return false, err
+	}
+	return !math.IsNaN(v) && v != 0, nil
+}
+
+// Fields 返回表达式引用的全部字段, 格式为 数据源.字段名
+func (e *Expression) Fields() []string {
+	var fields []string
+	e.root.walk(func(n exprNode) {
+		if f, ok := n.(*fieldNode); ok {
+			fields = append(fields, f.source+"."+f.field)
+		}
+	})
+	return fields
+}
+
+// ============================================
+// 词法分析
+// ============================================
+
+type tokenKind int
+
+const (
+	tokenNumber tokenKind = iota
+	tokenIdent
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenize(text string) ([]token, error) {
+	var tokens []token
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsDigit(c) || (c == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.' || runes[j] == 'e' || runes[j] == 'E' ||
+				((runes[j] == '+' || runes[j] == '-') && j > i && (runes[j-1] == 'e' || runes[j-1] == 'E'))) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[i:j])})
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_' || runes[j] == '.') {
+				j++
+			}
+			word := string(runes[i:j])
+			switch strings.ToLower(word) {
+			case "and":
+				tokens = append(tokens, token{kind: tokenOperator, text: "&&"})
+			case "or":
+				tokens = append(tokens, token{kind: tokenOperator, text: "||"})
+			case "not":
+				tokens = append(tokens, token{kind: tokenOperator, text: "!"})
+			default:
+				tokens = append(tokens, token{kind: tokenIdent, text: word})
+			}
+			i = j
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLeftParen, text: "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRightParen, text: ")"})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ","})
+			i++
+		default:
+			if i+1 < len(runes) {
+				two := string(runes[i : i+2])
+				switch two {
+				case "&&", "||", "<=", ">=", "==", "!=":
+					tokens = append(tokens, token{kind: tokenOperator, text: two})
+					i += 2
+					continue
+				}
+			}
+			switch c {
+			case '<', '>', '!', '+', '-', '*', '/', '%':
+				tokens = append(tokens, token{kind: tokenOperator, text: string(c)})
+				i++
+			default:
+				return nil, fmt.Errorf("%w: 非法字符 %q", ErrExpressionSyntax, c)
+			}
+		}
+	}
+	return tokens, nil
+}
+
+// ============================================
+// 语法分析
+// ============================================
+
+type exprParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *exprParser) peek() *token {
+	if p.pos < len(p.tokens) {
+		return &p.tokens[p.pos]
+	}
+	return nil
+}
+
+func (p *exprParser) acceptOperator(ops ...string) (string, bool) {
+	t := p.peek()
+	if t == nil || t.kind != tokenOperator {
+		return "", false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOperator("||")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOperator("&&")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseNot() (exprNode, error) {
+	if _, ok := p.acceptOperator("!"); ok {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: "!", operand: operand}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *exprParser) parseCompare() (exprNode, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if op, ok := p.acceptOperator("<", "<=", ">", ">=", "==", "!="); ok {
+		right, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		return &binaryNode{op: op, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseSum() (exprNode, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOperator("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseTerm() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOperator("*", "/", "%")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if _, ok := p.acceptOperator("-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: "-", operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	t := p.peek()
+	if t == nil {
+		return nil, fmt.Errorf("%w: 表达式不完整", ErrExpressionSyntax)
+	}
+	switch t.kind {
+	case tokenNumber:
+		p.pos++
+		v, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: 非法数值 %q", ErrExpressionSyntax, t.text)
+		}
+		return &constNode{value: v}, nil
+	case tokenLeftParen:
+		p.pos++
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.peek(); t == nil || t.kind != tokenRightParen {
+			return nil, fmt.Errorf("%w: 缺少右括号", ErrExpressionSyntax)
+		}
+		p.pos++
+		return node, nil
+	case tokenIdent:
+		p.pos++
+		name := t.text
+		// 函数调用
+		if next := p.peek(); next != nil && next.kind == tokenLeftParen {
+			return p.parseCall(strings.ToLower(name))
+		}
+		switch strings.ToLower(name) {
+		case "true":
+			return &constNode{value: 1}, nil
+		case "false":
+			return &constNode{value: 0}, nil
+		case "nan":
+			return &constNode{value: math.NaN()}, nil
+		}
+		source, field, found := strings.Cut(name, ".")
+		if !found || len(source) == 0 || len(field) == 0 {
+			return nil, fmt.Errorf("%w: 字段必须是 数据源.字段名 的形式, %q", ErrExpressionSyntax, name)
+		}
+		return &fieldNode{source: strings.ToLower(source), field: field}, nil
+	default:
+		return nil, fmt.Errorf("%w: 非预期的符号 %q", ErrExpressionSyntax, t.text)
+	}
+}
+
+func (p *exprParser) parseCall(name string) (exprNode, error) {
+	fn, ok := mapExprFunctions[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: 未知的函数 %s", ErrExpressionSyntax, name)
+	}
+	// 跳过左括号
+	p.pos++
+	var args []exprNode
+	if t := p.peek(); t != nil && t.kind == tokenRightParen {
+		p.pos++
+	} else {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			t := p.peek()
+			if t == nil {
+				return nil, fmt.Errorf("%w: 缺少右括号", ErrExpressionSyntax)
+			}
+			p.pos++
+			if t.kind == tokenRightParen {
+				break
+			}
+			if t.kind != tokenComma {
+				return nil, fmt.Errorf("%w: 非预期的符号 %q", ErrExpressionSyntax, t.text)
+			}
+		}
+	}
+	if len(args) < fn.minArgs || (fn.maxArgs > 0 && len(args) > fn.maxArgs) {
+		return nil, fmt.Errorf("%w: 函数%s参数个数错误", ErrExpressionSyntax, name)
+	}
+	return &callNode{name: name, fn: fn.call, args: args}, nil
+}
+
+// ============================================
+// 语法树
+// ============================================
+
+type exprNode interface {
+	eval(resolver FieldResolver) (float64, error)
+	walk(fn func(exprNode))
+}
+
+type constNode struct {
+	value float64
+}
+
+func (n *constNode) eval(FieldResolver) (float64, error) { return n.value, nil }
+func (n *constNode) walk(fn func(exprNode))              { fn(n) }
+
+type fieldNode struct {
+	source string
+	field  string
+}
+
+func (n *fieldNode) eval(resolver FieldResolver) (float64, error) {
+	return resolver.Resolve(n.source, n.field)
+}
+func (n *fieldNode) walk(fn func(exprNode)) { fn(n) }
+
+type unaryNode struct {
+	op      string
+	operand exprNode
+}
+
+func (n *unaryNode) eval(resolver FieldResolver) (float64, error) {
+	v, err := n.operand.eval(resolver)
+	if err != nil {
+		return 0, err
+	}
+	if n.op == "-" {
+		return -v, nil
+	}
+	return boolToFloat(math.IsNaN(v) || v == 0), nil
+}
+
+func (n *unaryNode) walk(fn func(exprNode)) {
+	fn(n)
+	n.operand.walk(fn)
+}
+
+type binaryNode struct {
+	op    string
+	left  exprNode
+	right exprNode
+}
+
+func (n *binaryNode) eval(resolver FieldResolver) (float64, error) {
+	l, err := n.left.eval(resolver)
+	if err != nil {
+		return 0, err
+	}
+	// 逻辑运算短路
+	switch n.op {
+	case "&&":
+		if math.IsNaN(l) || l == 0 {
+			return 0, nil
+		}
+	case "||":
+		if !math.IsNaN(l) && l != 0 {
+			return 1, nil
+		}
+	}
+	r, err := n.right.eval(resolver)
+	if err != nil {
+		return 0, err
+	}
+	switch n.op {
+	case "&&", "||":
+		return boolToFloat(!math.IsNaN(r) && r != 0), nil
+	case "<":
+		return boolToFloat(l < r), nil
+	case "<=":
+		return boolToFloat(l <= r), nil
+	case ">":
+		return boolToFloat(l > r), nil
+	case ">=":
+		return boolToFloat(l >= r), nil
+	case "==":
+		return boolToFloat(l == r), nil
+	case "!=":
+		return boolToFloat(l != r), nil
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		return l / r, nil
+	case "%":
+		return math.Mod(l, r), nil
+	}
+	return 0, fmt.Errorf("%w: 未知的运算符 %s", ErrExpressionSyntax, n.op)
+}
+
+func (n *binaryNode) walk(fn func(exprNode)) {
+	fn(n)
+	n.left.walk(fn)
+	n.right.walk(fn)
+}
+
+type callNode struct {
+	name string
+	fn   func(args []float64) float64
+	args []exprNode
+}
+
+func (n *callNode) eval(resolver FieldResolver) (float64, error) {
+	values := make([]float64, len(n.args))
+	for i, arg := range n.args {
+		v, err := arg.eval(resolver)
+		if err != nil {
+			return 0, err
+		}
+		values[i] = v
+	}
+	return n.fn(values), nil
+}
+
+func (n *callNode) walk(fn func(exprNode)) {
+	fn(n)
+	for _, arg := range n.args {
+		arg.walk(fn)
+	}
+}
+
+type exprFunction struct {
+	minArgs int
+	maxArgs int // 0表示不限制
+	call    func(args []float64) float64
+}
+
+var mapExprFunctions = map[string]exprFunction{
+	"abs": {minArgs: 1, maxArgs: 1, call: func(args []float64) float64 { return math.Abs(args[0]) }},
+	"isnan": {minArgs: 1, maxArgs: 1, call: func(args []float64) float64 {
+		return boolToFloat(math.IsNaN(args[0]))
+	}},
+	"min": {minArgs: 1, call: func(args []float64) float64 {
+		v := args[0]
+		for _, a := range args[1:] {
+			v = math.Min(v, a)
+		}
+		return v
+	}},
+	"max": {minArgs: 1, call: func(args []float64) float64 {
+		v := args[0]
+		for _, a := range args[1:] {
+			v = math.Max(v, a)
+		}
+		return v
+	}},
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// ============================================
+// 结构体字段解析
+// ============================================
+
+// structFieldValue 通过反射获取结构体的数值字段, 字段名不区分大小写, 也可以使用dataframe标签
+func structFieldValue(obj any, field string) (float64, error) {
+	if valuer, ok := obj.(FieldValuer); ok {
+		return valuer.FieldValue(field)
+	}
+	v := reflect.ValueOf(obj)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return math.NaN(), nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return 0, fmt.Errorf("%w: %s", ErrFieldNotFound, field)
+	}
+	i, found := findStructField(v.Type(), field)
+	if !found {
+		return 0, fmt.Errorf("%w: %s", ErrFieldNotFound, field)
+	}
+	fv := v.Field(i)
+	if !isNumericKind(fv.Kind()) {
+		return 0, fmt.Errorf("%w: %s不是数值类型", ErrFieldNotFound, field)
+	}
+	switch fv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return fv.Float(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(fv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(fv.Uint()), nil
+	default:
+		return boolToFloat(fv.Bool()), nil
+	}
+}
+
+// checkField 按数据源的类型校验字段, 用于编译时提前发现拼写错误
+//
+//	实现了FieldValuer的数据源和接口类型的数据源无法静态校验, 直接放行
+func checkField(t reflect.Type, field string) error {
+	if t == nil || t.Kind() == reflect.Interface || t.Implements(reflect.TypeFor[FieldValuer]()) {
+		return nil
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %s", ErrFieldNotFound, field)
+	}
+	i, found := findStructField(t, field)
+	if !found {
+		return fmt.Errorf("%w: %s", ErrFieldNotFound, field)
+	}
+	if !isNumericKind(t.Field(i).Type.Kind()) {
+		return fmt.Errorf("%w: %s不是数值类型", ErrFieldNotFound, field)
+	}
+	return nil
+}
+
+// findStructField 查找字段的序号, 字段名不区分大小写, 也可以使用dataframe标签
+func findStructField(t reflect.Type, field string) (int, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		tag, _, _ := strings.Cut(sf.Tag.Get("dataframe"), ",")
+		if strings.EqualFold(sf.Name, field) || tag == field {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// isNumericKind 是否可以作为数值参与运算
+func isNumericKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Bool:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,163 @@
+package rules
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"xquant/pkg/config"
+	"xquant/pkg/factors"
+)
+
+func TestCompileExpression(t *testing.T) {
+	snapshot := factors.QuoteSnapshot{
+		SecurityCode: "sh600600",
+		Price:        10.5,
+		LastClose:    10.0,
+		OpenTurnZ:    2.5,
+	}
+	sources := map[string]fieldSource{}
+	resolver := newSnapshotResolver(snapshot, sources)
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"snapshot.Price > snapshot.LastClose", true},
+		{"snapshot.price >= 11 || snapshot.OpenTurnZ > 2", true},
+		{"(snapshot.Price - snapshot.LastClose) / snapshot.LastClose * 100 < 3", true},
+		{"not (snapshot.OpenTurnZ > 1 and snapshot.OpenTurnZ < 3)", false},
+		{"abs(-1) == 1 && max(1, 2, 3) == 3 && min(4, 5) == 4", true},
+	}
+	for _, tt := range tests {
+		expr, err := CompileExpression(tt.expr)
+		if err != nil {
+			t.Fatalf("CompileExpression(%q) failed: %v", tt.expr, err)
+		}
+		if err = checkExpressionFields(expr, sources); err != nil {
+			t.Fatalf("checkExpressionFields(%q) failed: %v", tt.expr, err)
+		}
+		got, err := expr.Test(resolver)
+		if err != nil {
+			t.Fatalf("Test(%q) failed: %v", tt.expr, err)
+		}
+		if got != tt.want {
+			t.Errorf("Test(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestCompileExpressionError(t *testing.T) {
+	for _, text := range []string{"snapshot.Price >", "Price > 1", "(snapshot.Price > 1", "foo(1)", "snapshot.Price # 1"} {
+		if _, err := CompileExpression(text); err == nil {
+			t.Errorf("CompileExpression(%q) expected error", text)
+		}
+	}
+}
+
+func TestExpressionRuleGroup(t *testing.T) {
+	group := config.RuleGroupParameter{
+		Kind: 900,
+		Name: "风控排除",
+		Rules: []config.ExpressionRuleParameter{
+			{Name: "股价上限", Expr: "snapshot.Price < 100", Message: "股价过高"},
+			{Name: "换手下限", Expr: "snapshot.OpenTurnZ >= 1"},
+		},
+	}
+	g, err := NewExpressionRuleGroup(group)
+	if err != nil {
+		t.Fatalf("NewExpressionRuleGroup() failed: %v", err)
+	}
+	param := config.RuleParameter{Groups: []string{"风控排除"}}
+	if !Enabled(param, g) {
+		t.Errorf("Enabled() = false, want true")
+	}
+	if Enabled(config.RuleParameter{}, g) {
+		t.Errorf("Enabled() = true, want false")
+	}
+	err = g.Exec(param, factors.QuoteSnapshot{Price: 10, OpenTurnZ: 0.5})
+	if err == nil {
+		t.Errorf("Exec() = nil, want 换手下限")
+	}
+}
+
+func TestExpressionRuleGroupFieldCheck(t *testing.T) {
+	for _, expr := range []string{"snapshot.Prise > 1", "foo.Price > 1", "snapshot.SecurityCode > 1"} {
+		group := config.RuleGroupParameter{
+			Kind:  901,
+			Name:  "字段校验",
+			Rules: []config.ExpressionRuleParameter{{Name: "校验", Expr: expr}},
+		}
+		if _, err := NewExpressionRuleGroup(group); err == nil {
+			t.Errorf("NewExpressionRuleGroup(%q) expected error", expr)
+		}
+	}
+}
+
+type testMissingSource struct {
+	Value float64
+}
+
+func TestExpressionRuleGroupMissingData(t *testing.T) {
+	RegisterFieldSource("test_missing", func(snapshot factors.QuoteSnapshot) *testMissingSource { return nil })
+	defer func() {
+		mutex.Lock()
+		delete(mapFieldSources, "test_missing")
+		mutex.Unlock()
+	}()
+	group := config.RuleGroupParameter{
+		Kind: 902,
+		Name: "缺失数据",
+		Rules: []config.ExpressionRuleParameter{
+			{Name: "缺失", Expr: "test_missing.Value > 1"},
+			{Name: "股价上限", Expr: "snapshot.Price < 100"},
+		},
+	}
+	snapshot := factors.QuoteSnapshot{Price: 10}
+	// 默认不通过, 错误信息附带缺失的字段
+	g, err := NewExpressionRuleGroup(group)
+	if err != nil {
+		t.Fatalf("NewExpressionRuleGroup() failed: %v", err)
+	}
+	err = g.Exec(config.RuleParameter{}, snapshot)
+	if err == nil || !strings.Contains(err.Error(), "test_missing.Value") {
+		t.Errorf("Exec() = %v, want missing test_missing.Value", err)
+	}
+	// 跳过引用缺失数据的规则
+	group.MissingData = config.MissingDataSkip
+	g, err = NewExpressionRuleGroup(group)
+	if err != nil {
+		t.Fatalf("NewExpressionRuleGroup() failed: %v", err)
+	}
+	if err = g.Exec(config.RuleParameter{}, snapshot); err != nil {
+		t.Errorf("Exec() = %v, want nil", err)
+	}
+	// 无效的策略
+	group.MissingData = "ignore"
+	if _, err = NewExpressionRuleGroup(group); err == nil {
+		t.Errorf("NewExpressionRuleGroup() expected error")
+	}
+}
+
+func TestCompileRuleGroupsConflict(t *testing.T) {
+	err := RegisterFunc(996, "代码规则996", func(ruleParameter config.RuleParameter, snapshot factors.QuoteSnapshot) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterFunc() failed: %v", err)
+	}
+	defer func() {
+		mutex.Lock()
+		delete(mapRules, 996)
+		mutex.Unlock()
+	}()
+	groups := []config.RuleGroupParameter{
+		{Kind: 996, Name: "冲突", Rules: []config.ExpressionRuleParameter{{Expr: "snapshot.Price < 100"}}},
+	}
+	// 配置重载的校验阶段就要发现和代码注册的规则冲突
+	if _, err = compileRuleGroups(groups); !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("compileRuleGroups() = %v, want %v", err, ErrAlreadyExists)
+	}
+	if err = RegisterRuleGroups(groups); !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("RegisterRuleGroups() = %v, want %v", err, ErrAlreadyExists)
+	}
+}
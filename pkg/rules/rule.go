@@ -187,8 +187,8 @@ func Filter(ruleParameter config.RuleParameter, snapshot factors.QuoteSnapshot)
 			continue
 		}
 
-		// 检查是否忽略此规则组
-		if slices.Contains(ruleParameter.IgnoreRuleGroup, int(rule.Kind())) {
+		// 检查是否忽略此规则组, 或者规则组未被策略选择
+		if !Enabled(ruleParameter, rule) {
 			continue
 		}
 
@@ -2,8 +2,10 @@ package rules
 
 import (
 	"fmt"
+	"slices"
 
 	"xquant/pkg/config"
+	"xquant/pkg/emotion"
 	"xquant/pkg/factors"
 
 	"gitee.com/quant1x/gox/exception"
@@ -28,6 +30,8 @@ var (
 	ErrExchangeNotExist             = exception.New(errorRuleBase+6, "没有找到history数据")
 	ErrRangeOfChangeRate            = exception.New(errorRuleBase+7, "非实时涨跌幅范围")
 	ErrRangeOfFinancingBalanceRatio = exception.New(errorRuleBase+8, "融资余额占比过大")
+	ErrRangeOfEmotionScore          = exception.New(errorRuleBase+9, "非市场情绪分范围")
+	ErrPhaseOfEmotion               = exception.New(errorRuleBase+10, "非允许的市场情绪周期")
 )
 
 // 判断是否冗详模式输出错误信息
@@ -68,21 +72,25 @@ func ruleBase(ruleParameter config.RuleParameter, snapshot factors.QuoteSnapshot
 	//	return false
 	//}
 	// 6. exchange 过滤
-	misc := factors.GetL5Misc(securityCode)
+	misc := factors.GetL5Misc(securityCode, snapshot.Date)
 	if misc == nil {
 		//return ErrExchangeNotExist
 	} else {
-		//// 6.1 资金流向
-		//if exchange.FundFlow != 0 && (exchange.FundFlow/TenThousand) < RuleParameters.MaxReduceAmount {
-		//	return ErrRangeOfFundFlow
-		//}
 		// 6.2 检查融资余额占比
 		if misc.RZYEZB > 0 && misc.RZYEZB >= ruleParameter.FinancingBalanceRatio {
 			return throwException(ErrRangeOfFinancingBalanceRatio, ruleParameter, misc.RZYEZB)
 		}
 	}
+	// 6.1 资金流向, 主力净流出不能超过最大流出金额, 没有资金流向数据时不过滤
+	fundFlow := factors.GetL5FundFlow(securityCode, snapshot.Date)
+	if fundFlow != nil && fundFlow.Main != 0 {
+		mainAmount := fundFlow.Main / config.TenThousand
+		if mainAmount < ruleParameter.MaxReduceAmount {
+			return throwException(ErrRangeOfFundFlow, ruleParameter, mainAmount)
+		}
+	}
 	// 7. 历史数据
-	history := factors.GetL5History(securityCode)
+	history := factors.GetL5History(securityCode, snapshot.Date)
 	if history == nil {
 		return ErrHistoryNotExist
 	} else {
@@ -91,6 +99,15 @@ func ruleBase(ruleParameter config.RuleParameter, snapshot factors.QuoteSnapshot
 			return ErrRiskOfGapDown
 		}
 	}
+	// 8. 市场情绪周期, 历史日期取前一交易日的结果, 没有情绪数据时不过滤
+	if e := emotion.Available(snapshot.Date); e != nil {
+		if !ruleParameter.EmotionScore.Validate(e.Score) {
+			return throwException(ErrRangeOfEmotionScore, ruleParameter, e.Score)
+		}
+		if len(ruleParameter.EmotionPhases) > 0 && !slices.Contains(ruleParameter.EmotionPhases, e.Phase) {
+			return ErrPhaseOfEmotion
+		}
+	}
 	// 规则通过
 	return nil
 }
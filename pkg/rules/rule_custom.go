@@ -0,0 +1,338 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gitee.com/quant1x/gox/exception"
+	"gitee.com/quant1x/gox/logger"
+	"xquant/pkg/config"
+	"xquant/pkg/emotion"
+	"xquant/pkg/factors"
+	"xquant/pkg/sector"
+)
+
+// KRuleCustom 自定义规则组的起始类型, 与config.MinimumCustomRuleKind保持一致
+const KRuleCustom Kind = config.MinimumCustomRuleKind
+
+func init() {
+	err := RegisterRuleGroups(config.GetRuleGroups())
+	if err != nil {
+		logger.Fatalf("自定义规则组注册失败: %+v", err)
+	}
+	// 配置重载时先编译校验, 替换配置后重新注册
+	config.RegisterReloadHook(config.ReloadHook{
+		Name: "rule_groups",
+		Validate: func(newConfig *config.Quant1XConfig) error {
+			_, err := compileRuleGroups(newConfig.Trader.RuleGroups)
+			return err
+		},
+		Apply: func(newConfig *config.Quant1XConfig) {
+			if err := RegisterRuleGroups(newConfig.Trader.RuleGroups); err != nil {
+				logger.Errorf("自定义规则组重新注册失败: %+v", err)
+			}
+		},
+	})
+}
+
+// fieldSource 表达式数据源
+type fieldSource struct {
+	typ  reflect.Type                             // 数据源的类型, 用于编译时校验字段
+	load func(snapshot factors.QuoteSnapshot) any // 加载数据
+}
+
+// newFieldSource 创建数据源, 类型由loader的返回值确定
+func newFieldSource[T any](loader func(snapshot factors.QuoteSnapshot) T) fieldSource {
+	return fieldSource{
+		typ:  reflect.TypeFor[T](),
+		load: func(snapshot factors.QuoteSnapshot) any { return loader(snapshot) },
+	}
+}
+
+// 表达式中可以引用的数据源
+var mapFieldSources = map[string]fieldSource{
+	"snapshot": newFieldSource(func(snapshot factors.QuoteSnapshot) factors.QuoteSnapshot { return snapshot }),
+	"history": newFieldSource(func(snapshot factors.QuoteSnapshot) *factors.History {
+		return factors.GetL5History(snapshot.SecurityCode, snapshot.Date)
+	}),
+	"f10": newFieldSource(func(snapshot factors.QuoteSnapshot) *factors.F10 {
+		return factors.GetL5F10(snapshot.SecurityCode, snapshot.Date)
+	}),
+	"misc": newFieldSource(func(snapshot factors.QuoteSnapshot) *factors.Misc {
+		return factors.GetL5Misc(snapshot.SecurityCode, snapshot.Date)
+	}),
+	"box": newFieldSource(func(snapshot factors.QuoteSnapshot) *factors.Box {
+		return factors.GetL5Box(snapshot.SecurityCode, snapshot.Date)
+	}),
+	"ism": newFieldSource(func(snapshot factors.QuoteSnapshot) *factors.InvestmentSentimentMaster {
+		return factors.GetL5InvestmentSentimentMaster(snapshot.SecurityCode, snapshot.Date)
+	}),
+	"rzrq": newFieldSource(func(snapshot factors.QuoteSnapshot) *factors.SecuritiesMarginTrading {
+		return factors.GetL5SecuritiesMarginTrading(snapshot.SecurityCode, snapshot.Date)
+	}),
+	"chip": newFieldSource(func(snapshot factors.QuoteSnapshot) *factors.Chip {
+		return factors.GetL5Chip(snapshot.SecurityCode, snapshot.Date)
+	}),
+	"alpha": newFieldSource(func(snapshot factors.QuoteSnapshot) *factors.Alpha {
+		return factors.GetL5Alpha(snapshot.SecurityCode, snapshot.Date)
+	}),
+	"lhb": newFieldSource(func(snapshot factors.QuoteSnapshot) *factors.BillBoard {
+		return factors.GetL5BillBoard(snapshot.SecurityCode, snapshot.Date)
+	}),
+	"fundflow": newFieldSource(func(snapshot factors.QuoteSnapshot) *factors.FundFlow {
+		return factors.GetL5FundFlow(snapshot.SecurityCode, snapshot.Date)
+	}),
+	"orderflow": newFieldSource(func(snapshot factors.QuoteSnapshot) *factors.OrderFlow {
+		return factors.GetL5OrderFlow(snapshot.SecurityCode, snapshot.Date)
+	}),
+	"unlock": newFieldSource(func(snapshot factors.QuoteSnapshot) *factors.Unlock {
+		return factors.GetL5Unlock(snapshot.SecurityCode, snapshot.Date)
+	}),
+	"dividend": newFieldSource(func(snapshot factors.QuoteSnapshot) *factors.Dividend {
+		return factors.GetL5Dividend(snapshot.SecurityCode, snapshot.Date)
+	}),
+	"indicator": newFieldSource(newIndicatorSource),
+	"sector": newFieldSource(func(snapshot factors.QuoteSnapshot) *sector.StockRotation {
+		return sector.GetStockRotation(snapshot.SecurityCode, snapshot.Date)
+	}),
+	"emotion": newFieldSource(func(snapshot factors.QuoteSnapshot) *emotion.Emotion {
+		return emotion.Available(snapshot.Date)
+	}),
+}
+
+// RegisterFieldSource 注册表达式数据源, 用于扩展新的特征
+//
+//	数据源的类型由loader的返回值确定, 编译表达式时按类型校验字段
+func RegisterFieldSource[T any](name string, loader func(snapshot factors.QuoteSnapshot) T) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	mapFieldSources[strings.ToLower(name)] = newFieldSource(loader)
+}
+
+// checkExpressionFields 校验表达式引用的数据源和字段, 引用的数据源复制到sources
+//
+//	执行时只从sources中加载数据, 不再访问mapFieldSources, 规则执行期间不需要加锁
+func checkExpressionFields(expr *Expression, sources map[string]fieldSource) error {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	var err error
+	expr.root.walk(func(n exprNode) {
+		f, ok := n.(*fieldNode)
+		if !ok || err != nil {
+			return
+		}
+		source, found := mapFieldSources[f.source]
+		if !found {
+			err = fmt.Errorf("%w: %s", ErrSourceNotFound, f.source)
+			return
+		}
+		err = checkField(source.typ, f.field)
+		sources[f.source] = source
+	})
+	return err
+}
+
+// snapshotResolver 以快照为中心的字段解析器, 同一次规则执行中特征只加载一次
+type snapshotResolver struct {
+	snapshot factors.QuoteSnapshot
+	sources  map[string]fieldSource // 编译时绑定的数据源
+	objects  map[string]any
+	missing  []string // 值为NaN的字段, 格式为 数据源.字段名
+}
+
+func newSnapshotResolver(snapshot factors.QuoteSnapshot, sources map[string]fieldSource) *snapshotResolver {
+	return &snapshotResolver{snapshot: snapshot, sources: sources, objects: map[string]any{}}
+}
+
+// Resolve 实现 FieldResolver 接口
+func (r *snapshotResolver) Resolve(source, field string) (float64, error) {
+	obj, ok := r.objects[source]
+	if !ok {
+		loader, found := r.sources[source]
+		if !found {
+			return 0, fmt.Errorf("%w: %s", ErrSourceNotFound, source)
+		}
+		obj = loader.load(r.snapshot)
+		r.objects[source] = obj
+	}
+	v, err := structFieldValue(obj, field)
+	if err == nil && math.IsNaN(v) {
+		if name := source + "." + field; !slices.Contains(r.missing, name) {
+			r.missing = append(r.missing, name)
+		}
+	}
+	return v, err
+}
+
+// expressionRule 表达式规则
+type expressionRule struct {
+	name string
+	expr *Expression
+	err  error
+}
+
+// ExpressionRuleGroup 配置文件定义的表达式规则组
+type ExpressionRuleGroup struct {
+	kind        Kind
+	name        string
+	description string
+	missingData string                 // 缺失数据的处理策略
+	sources     map[string]fieldSource // 规则引用的数据源
+	rules       []expressionRule
+}
+
+// NewExpressionRuleGroup 根据配置创建表达式规则组
+func NewExpressionRuleGroup(group config.RuleGroupParameter) (*ExpressionRuleGroup, error) {
+	if err := group.Validate(); err != nil {
+		return nil, err
+	}
+	baseCode := group.BaseErrorCode()
+	g := &ExpressionRuleGroup{
+		kind:        Kind(group.Kind),
+		name:        group.Name,
+		description: group.Description,
+		missingData: group.MissingDataPolicy(),
+		sources:     map[string]fieldSource{},
+	}
+	for i, v := range group.Rules {
+		expr, err := CompileExpression(v.Expr)
+		if err == nil {
+			err = checkExpressionFields(expr, g.sources)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("规则组[%s]第%d条规则[%s]: %w", group.Name, i+1, v.Expr, err)
+		}
+		code := v.Code
+		if code == 0 {
+			code = baseCode + i
+		}
+		message := v.Message
+		if len(message) == 0 {
+			message = v.Name
+		}
+		if len(message) == 0 {
+			message = v.Expr
+		}
+		g.rules = append(g.rules, expressionRule{
+			name: v.Name,
+			expr: expr,
+			err:  exception.New(code, message),
+		})
+	}
+	return g, nil
+}
+
+// Kind 实现 Rule 接口
+func (g *ExpressionRuleGroup) Kind() Kind {
+	return g.kind
+}
+
+// Name 实现 Rule 接口
+func (g *ExpressionRuleGroup) Name() string {
+	return g.name
+}
+
+// Description 实现 Rule 接口
+func (g *ExpressionRuleGroup) Description() string {
+	return g.description
+}
+
+// Exec 实现 Rule 接口, 按顺序执行表达式, 遇到不成立的立即返回
+//
+//	引用的数据缺失时按规则组的策略处理, skip跳过该规则, fail在表达式不成立时返回附带缺失字段的错误
+func (g *ExpressionRuleGroup) Exec(ruleParameter config.RuleParameter, snapshot factors.QuoteSnapshot) error {
+	resolver := newSnapshotResolver(snapshot, g.sources)
+	for _, rule := range g.rules {
+		resolver.missing = resolver.missing[:0]
+		ok, err := rule.expr.Test(resolver)
+		if err != nil {
+			return fmt.Errorf("%s, 表达式[%s]执行失败: %w", rule.err.Error(), rule.expr, err)
+		}
+		if len(resolver.missing) > 0 && g.missingData == config.MissingDataSkip {
+			continue
+		}
+		if !ok {
+			if len(resolver.missing) > 0 {
+				return fmt.Errorf("%w, 缺失数据: %s", rule.err, strings.Join(resolver.missing, ","))
+			}
+			if ruleParameter.Verbose {
+				return fmt.Errorf("%s, %s", rule.err.Error(), rule.expr)
+			}
+			return rule.err
+		}
+	}
+	return nil
+}
+
+// compileRuleGroups 编译全部规则组, 并检查与已注册规则的kind冲突, 不修改已注册的规则
+func compileRuleGroups(groups []config.RuleGroupParameter) ([]*ExpressionRuleGroup, error) {
+	list := make([]*ExpressionRuleGroup, 0, len(groups))
+	for _, v := range groups {
+		g, err := NewExpressionRuleGroup(v)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, g)
+	}
+	mutex.RLock()
+	defer mutex.RUnlock()
+	if _, err := mergeRuleGroups(list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// mergeRuleGroups 保留代码注册的规则, 合并自定义规则组, 检查kind冲突, 调用方持有mutex
+func mergeRuleGroups(list []*ExpressionRuleGroup) (map[Kind]Rule, error) {
+	rules := make(map[Kind]Rule, len(mapRules)+len(list))
+	for kind, rule := range mapRules {
+		if _, ok := rule.(*ExpressionRuleGroup); !ok {
+			rules[kind] = rule
+		}
+	}
+	for _, g := range list {
+		if _, ok := rules[g.kind]; ok {
+			return nil, fmt.Errorf("规则组[%s] kind=%d: %w", g.name, g.kind, ErrAlreadyExists)
+		}
+		rules[g.kind] = g
+	}
+	return rules, nil
+}
+
+// RegisterRuleGroups 注册配置文件中的自定义规则组, 已经注册的自定义规则组全部被替换
+//
+//	先校验全部规则组, 有错误时不修改已注册的规则
+func RegisterRuleGroups(groups []config.RuleGroupParameter) error {
+	list, err := compileRuleGroups(groups)
+	if err != nil {
+		return err
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	// 1. 保留代码注册的规则, 检查kind冲突, 校验之后可能有新注册的规则
+	rules, err := mergeRuleGroups(list)
+	if err != nil {
+		return err
+	}
+	// 2. 替换
+	mapRules = rules
+	return nil
+}
+
+// Enabled 判断规则在当前规则参数下是否需要执行
+//
+//  1. IgnoreRuleGroup中的规则不执行
+//  2. 代码注册的规则默认执行
+//  3. 配置文件定义的规则组需要在Groups中显式选择
+func Enabled(ruleParameter config.RuleParameter, rule Rule) bool {
+	if slices.Contains(ruleParameter.IgnoreRuleGroup, int(rule.Kind())) {
+		return false
+	}
+	if _, custom := rule.(*ExpressionRuleGroup); !custom {
+		return true
+	}
+	return slices.Contains(ruleParameter.Groups, rule.Name())
+}
@@ -31,6 +31,10 @@ var (
 	ErrF10RangeOfMarketCap            = exception.New(errorRuleF10+10, "非市值范围")
 	ErrF10RangeOfTotalOperateIncome   = exception.New(errorRuleF10+11, "非营业总收入")
 	ErrF10ReportingRiskPeriod         = exception.New(errorRuleF10+12, "财报披露前的风险期")
+	ErrF10UnlockRiskPeriod            = exception.New(errorRuleF10+13, "大额限售解禁前的风险期")
+	ErrF10DividendRecordDate          = exception.New(errorRuleF10+14, "股权登记日")
+	ErrF10DividendWindow              = exception.New(errorRuleF10+15, "非抢权窗口")
+	ErrF10RangeOfDividendYield        = exception.New(errorRuleF10+16, "非股息率范围")
 )
 
 // RuleF10 基本面规则
@@ -54,7 +58,7 @@ func ruleF10(ruleParameter config.RuleParameter, snapshot factors.QuoteSnapshot)
 		return ErrF10PriceRange
 	}
 	// 5. F10数据
-	f10 := factors.GetL5F10(securityCode)
+	f10 := factors.GetL5F10(securityCode, snapshot.Date)
 	if f10 != nil {
 		// 5.1 流通股本控制
 		capital := f10.Capital / config.Billion
@@ -96,6 +100,46 @@ func ruleF10(ruleParameter config.RuleParameter, snapshot factors.QuoteSnapshot)
 		//	return false
 		//}
 	}
+	// 6. 限售解禁, N个交易日内有大额解禁的过滤掉, 没有解禁数据时不过滤
+	if ruleParameter.UnlockDays > 0 {
+		unlock := factors.GetL5Unlock(securityCode, snapshot.Date)
+		if unlock != nil && isUnlockRiskPeriod(unlock, ruleParameter.UnlockDays, ruleParameter.UnlockRatio) {
+			return throwException(ErrF10UnlockRiskPeriod, ruleParameter, unlock.FloatRatio)
+		}
+	}
+	// 7. 除权除息, 股权登记日买入次日即除权, 抢权只买入窗口期内的标的
+	if ruleParameter.AvoidRecordDate || ruleParameter.DividendDays > 0 {
+		dividend := factors.GetL5Dividend(securityCode, snapshot.Date)
+		if dividend != nil && ruleParameter.AvoidRecordDate && isDividendRecordDate(dividend) {
+			return ErrF10DividendRecordDate
+		}
+		if ruleParameter.DividendDays > 0 {
+			if dividend == nil || !isDividendWindow(dividend, ruleParameter.DividendDays) {
+				return ErrF10DividendWindow
+			}
+			if !ruleParameter.DividendYield.Validate(dividend.DividendYield) {
+				return throwException(ErrF10RangeOfDividendYield, ruleParameter, dividend.DividendYield)
+			}
+		}
+	}
 	// 规则通过
 	return nil
 }
+
+// isUnlockRiskPeriod 是否处于大额限售解禁的风险期
+func isUnlockRiskPeriod(unlock *factors.Unlock, days int, ratio float64) bool {
+	if unlock.UnlockDays < 0 || unlock.UnlockDays > days {
+		return false
+	}
+	return unlock.FloatRatio >= ratio
+}
+
+// isDividendRecordDate 当日是否股权登记日, 即下一个交易日除权除息
+func isDividendRecordDate(dividend *factors.Dividend) bool {
+	return dividend.ExDays == 1
+}
+
+// isDividendWindow 是否处于抢权窗口, 即days个交易日内除权除息且当日不是除权除息日
+func isDividendWindow(dividend *factors.Dividend, days int) bool {
+	return dividend.ExDays > 0 && dividend.ExDays <= days
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"testing"
 
+	"xquant/pkg/cache"
 	"xquant/pkg/config"
+	"xquant/pkg/factors"
 	"xquant/pkg/models"
 )
 
@@ -18,3 +20,50 @@ func Test_baseFilter(t *testing.T) {
 	passed, failKind, err := Filter(strategyParameter.Rules, *snapshot)
 	fmt.Println(passed, failKind, err)
 }
+
+func Test_isUnlockRiskPeriod(t *testing.T) {
+	tests := []struct {
+		unlock factors.Unlock
+		want   bool
+	}{
+		{factors.Unlock{UnlockDays: -1}, false},
+		{factors.Unlock{UnlockDays: 0, FloatRatio: 5}, true},
+		{factors.Unlock{UnlockDays: 5, FloatRatio: 12.5}, true},
+		{factors.Unlock{UnlockDays: 6, FloatRatio: 12.5}, false},
+		{factors.Unlock{UnlockDays: 3, FloatRatio: 4.99}, false},
+	}
+	for _, tt := range tests {
+		if got := isUnlockRiskPeriod(&tt.unlock, 5, 5); got != tt.want {
+			t.Errorf("%+v: got=%t, want=%t", tt.unlock, got, tt.want)
+		}
+	}
+}
+
+func Test_isDividendWindow(t *testing.T) {
+	// 股权登记日2024-06-18, 除权除息日2024-06-19
+	events := []factors.DividendEvent{
+		{Code: "sh600000", RecordDate: "2024-06-18", ExDate: "2024-06-19", CashDividend: 0.5},
+	}
+	tests := []struct {
+		date       string
+		recordDate bool
+		window     bool
+	}{
+		{"2024-06-13", false, false},
+		{"2024-06-14", false, true},
+		{"2024-06-18", true, true},
+		{"2024-06-19", false, false},
+		{"2024-06-20", false, false},
+	}
+	for _, tt := range tests {
+		cacheDate, featureDate := cache.CorrectDate(tt.date)
+		dividend := factors.NewDividend(featureDate, "sh600000")
+		dividend.Compute(events, 10, cacheDate)
+		if got := isDividendRecordDate(dividend); got != tt.recordDate {
+			t.Errorf("%s: record date got=%t, want=%t, ex_days=%d", tt.date, got, tt.recordDate, dividend.ExDays)
+		}
+		if got := isDividendWindow(dividend, 3); got != tt.window {
+			t.Errorf("%s: window got=%t, want=%t, ex_days=%d", tt.date, got, tt.window, dividend.ExDays)
+		}
+	}
+}
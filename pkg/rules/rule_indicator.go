@@ -0,0 +1,69 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"gitee.com/quant1x/exchange"
+	"xquant/pkg/datasource/base"
+	"xquant/pkg/factors"
+	"xquant/pkg/indicators"
+)
+
+// indicatorSource 技术指标数据源
+//
+//	字段名为"指标名_输出列", 按默认参数计算, 取最后一个周期的值.
+//	快照日期晚于最后一根K线时, 快照作为最新的K线参与计算
+type indicatorSource struct {
+	snapshot factors.QuoteSnapshot
+	bars     *indicators.Bars
+	results  map[string]map[string][]float64
+}
+
+func newIndicatorSource(snapshot factors.QuoteSnapshot) *indicatorSource {
+	return &indicatorSource{snapshot: snapshot, results: map[string]map[string][]float64{}}
+}
+
+// FieldValue 实现 FieldValuer 接口
+func (this *indicatorSource) FieldValue(field string) (float64, error) {
+	name, column, ok := strings.Cut(strings.ToUpper(field), "_")
+	if !ok {
+		return 0, fmt.Errorf("%w: %s, 格式为指标名_输出列", ErrFieldNotFound, field)
+	}
+	result, ok := this.results[name]
+	if !ok {
+		var err error
+		result, err = indicators.Compute(name, this.loadBars(), nil)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s, %v", ErrFieldNotFound, field, err)
+		}
+		this.results[name] = result
+	}
+	values, ok := result[column]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrFieldNotFound, field)
+	}
+	if len(values) == 0 {
+		return math.NaN(), nil
+	}
+	return values[len(values)-1], nil
+}
+
+// loadBars 加载K线, 同一次规则执行只加载一次
+func (this *indicatorSource) loadBars() indicators.Bars {
+	if this.bars != nil {
+		return *this.bars
+	}
+	date := exchange.FixTradeDate(this.snapshot.Date)
+	// 锚定快照日期前复权, 和追加的快照价格使用同一个价格基准
+	klines := base.CheckoutAdjustedKLines(this.snapshot.SecurityCode, date, base.ForwardAdjustedAt(date))
+	bars := indicators.BarsFromKLines(klines)
+	n := len(klines)
+	if this.snapshot.Price > 0 && (n == 0 || klines[n-1].Date < date) {
+		s := this.snapshot
+		bars = bars.Append(date, s.Open, s.High, s.Low, s.Price, float64(s.Vol), s.Amount)
+	}
+	this.bars = &bars
+	return bars
+}
@@ -0,0 +1,144 @@
+package sector
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/gotdx/securities"
+)
+
+// StockRotation 个股所属板块的轮动特征, 供策略读取
+//
+//	个股属于多个板块时, 取最新排名最靠前的板块
+type StockRotation struct {
+	Date        string  `name:"日期" dataframe:"date"`              // 日期
+	Code        string  `name:"证券代码" dataframe:"code"`            // 证券代码
+	SectorCode  string  `name:"板块代码" dataframe:"sector_code"`     // 板块代码
+	SectorName  string  `name:"板块名称" dataframe:"sector_name"`     // 板块名称
+	SectorType  string  `name:"板块类型" dataframe:"sector_type"`     // 板块类型
+	Rank        int     `name:"板块排名" dataframe:"rank"`            // 板块最新排名
+	RankChange  int     `name:"排名变化" dataframe:"rank_change"`     // 板块排名变化
+	Momentum    float64 `name:"板块累计涨幅%" dataframe:"momentum"`     // 板块累计涨幅
+	TopDays     int     `name:"热点天数" dataframe:"top_days"`        // 板块进入前N的天数
+	TopStreak   int     `name:"连续热点天数" dataframe:"top_streak"`    // 板块连续进入前N的天数
+	Emerging    bool    `name:"新晋热点" dataframe:"emerging"`        // 板块是否新晋热点
+	IsLeader    bool    `name:"是否龙头" dataframe:"is_leader"`       // 个股是否板块的领涨个股
+	LeaderDays  int     `name:"龙头连续天数" dataframe:"leader_days"`   // 个股为龙头时, 连续领涨的天数
+	HotSectors  int     `name:"热点板块数" dataframe:"hot_sectors"`    // 个股所属板块中排名在前N的板块数
+	AmountRatio float64 `name:"板块成交额放大" dataframe:"amount_ratio"` // 板块成交额放大倍数
+}
+
+var (
+	stockIndexOnce sync.Once
+	stockIndex     map[string][]string // 个股所属的行业和概念板块
+
+	rotationMutex sync.Mutex
+	rotationCache = map[string]rotationEntry{} // 按日期缓存默认参数的轮动分析结果
+)
+
+// 轮动分析结果的缓存
+type rotationEntry struct {
+	rotation *Rotation
+	modTime  time.Time // 截止日期排名文件的修改时间, 文件更新之后重新分析
+}
+
+// 截止日期排名文件的修改时间, 文件不存在时返回零值
+func rankingModTime(date string) time.Time {
+	fi, err := os.Stat(RankingFilename(date))
+	if err != nil {
+		return time.Time{}
+	}
+	return fi.ModTime()
+}
+
+// loadStockIndex 建立个股到行业和概念板块的索引
+func loadStockIndex() {
+	stockIndex = map[string][]string{}
+	for _, v := range securities.BlockList() {
+		if v.Type != securities.BK_HANGYE && v.Type != securities.BK_GAINIAN {
+			continue
+		}
+		for _, stockCode := range securities.GetBlockInfo(v.Code).ConstituentStocks {
+			securityCode := exchange.CorrectSecurityCode(stockCode)
+			stockIndex[securityCode] = append(stockIndex[securityCode], v.Code)
+		}
+	}
+}
+
+// StockSectors 个股所属的行业和概念板块代码
+func StockSectors(securityCode string) []string {
+	stockIndexOnce.Do(loadStockIndex)
+	return stockIndex[exchange.CorrectSecurityCode(securityCode)]
+}
+
+// GetRotation 获取截止date的默认参数的轮动分析结果
+//
+//	结果按日期缓存, 当日的排名在盘中持续更新, 截止日期的排名文件更新之后重新分析
+func GetRotation(date string) *Rotation {
+	date = exchange.FixTradeDate(date)
+	modTime := rankingModTime(date)
+	rotationMutex.Lock()
+	defer rotationMutex.Unlock()
+	if v, ok := rotationCache[date]; ok && v.modTime.Equal(modTime) {
+		return v.rotation
+	}
+	rotation, err := Analyze(date, DefaultRotationDays, DefaultRotationTopN)
+	if err != nil {
+		rotation = nil
+	}
+	rotationCache[date] = rotationEntry{rotation: rotation, modTime: modTime}
+	return rotation
+}
+
+// GetStockRotation 获取个股在指定日期的板块轮动特征, 没有排名数据时返回nil
+func GetStockRotation(securityCode, date string) *StockRotation {
+	securityCode = exchange.CorrectSecurityCode(securityCode)
+	rotation := GetRotation(date)
+	if rotation == nil {
+		return nil
+	}
+	return stockRotation(rotation, securityCode, StockSectors(securityCode))
+}
+
+// stockRotation 从个股所属的板块中选出最新排名最靠前的板块
+func stockRotation(rotation *Rotation, securityCode string, sectorCodes []string) *StockRotation {
+	var best *SectorRotation
+	hot := 0
+	for _, code := range sectorCodes {
+		v, ok := rotation.Lookup(code)
+		if !ok || v.Rank < 1 {
+			continue
+		}
+		if v.Rank <= rotation.TopN {
+			hot++
+		}
+		if best == nil || v.Rank < best.Rank || (v.Rank == best.Rank && v.Momentum > best.Momentum) {
+			best = &v
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	feature := &StockRotation{
+		Date:        rotation.EndDate,
+		Code:        securityCode,
+		SectorCode:  best.Code,
+		SectorName:  best.Name,
+		SectorType:  best.Type,
+		Rank:        best.Rank,
+		RankChange:  best.RankChange,
+		Momentum:    best.Momentum,
+		TopDays:     best.TopDays,
+		TopStreak:   best.TopStreak,
+		Emerging:    best.Emerging,
+		IsLeader:    best.Leader == securityCode,
+		HotSectors:  hot,
+		AmountRatio: best.AmountRatio,
+	}
+	if feature.IsLeader {
+		feature.LeaderDays = best.LeaderDays
+	}
+	return feature
+}
@@ -0,0 +1,123 @@
+package sector
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/gox/api"
+	"xquant/pkg/cache"
+)
+
+const (
+	rankingPrefix   = "rank."    // 板块排名文件前缀
+	rankingExt      = ".csv"     // 板块排名文件扩展名
+	intradayPath    = "intraday" // 盘中板块排名路径
+	defaultCalendar = 10         // 按交易日数回溯时额外多取的自然日数
+)
+
+var (
+	rankingMutex sync.Mutex
+)
+
+// Ranking 板块排名记录
+type Ranking struct {
+	Date           string  `name:"日期" dataframe:"date"`                // 交易日期
+	Time           string  `name:"时间" dataframe:"time"`                // 扫描时间
+	Code           string  `name:"板块代码" dataframe:"code"`              // 板块代码
+	Name           string  `name:"板块名称" dataframe:"name"`              // 板块名称
+	Type           string  `name:"板块类型" dataframe:"type"`              // 板块类型
+	Rank           int     `name:"排名" dataframe:"rank"`                // 同类型板块中的排名, 从1开始
+	ChangeRate     float64 `name:"涨幅%" dataframe:"change_rate"`        // 板块涨幅
+	OpenChangeRate float64 `name:"开盘涨幅%" dataframe:"open_change_rate"` // 板块开盘涨幅
+	Count          int     `name:"个股数" dataframe:"count"`              // 有行情的成分股数
+	UpCount        int     `name:"上涨家数" dataframe:"up_count"`          // 上涨家数
+	DownCount      int     `name:"下跌家数" dataframe:"down_count"`        // 下跌家数
+	LimitUpNum     int     `name:"涨停数" dataframe:"limit_up_num"`       // 涨停家数
+	TopCode        string  `name:"领涨个股" dataframe:"top_code"`          // 领涨个股代码
+	TopName        string  `name:"领涨个股名称" dataframe:"top_name"`        // 领涨个股名称
+	TopRate        float64 `name:"领涨个股涨幅%" dataframe:"top_rate"`       // 领涨个股涨幅
+	Amount         float64 `name:"成交额" dataframe:"amount"`             // 板块成交额
+	TurnoverRate   float64 `name:"换手率%" dataframe:"turnover_rate"`     // 成分股成交量合计/流通股本合计
+	UpdateTime     string  `name:"更新时间" dataframe:"update_time"`       // 更新时间
+}
+
+// RankingFilename 板块每日排名文件名, 保存当日最后一次扫描的结果
+//
+//	sector/rank.yyyy-mm-dd.csv
+func RankingFilename(date string) string {
+	return filepath.Join(cache.GetSectorPath(), rankingPrefix+exchange.FixTradeDate(date)+rankingExt)
+}
+
+// IntradayFilename 板块盘中排名文件名, 按扫描时间追加
+//
+//	sector/intraday/rank.yyyy-mm-dd.csv
+func IntradayFilename(date string) string {
+	return filepath.Join(cache.GetSectorPath(), intradayPath, rankingPrefix+exchange.FixTradeDate(date)+rankingExt)
+}
+
+// LoadRankings 加载指定日期的板块排名
+func LoadRankings(date string) []Ranking {
+	var list []Ranking
+	_ = api.CsvToSlices(RankingFilename(date), &list)
+	return list
+}
+
+// LoadIntraday 加载指定日期的盘中板块排名
+func LoadIntraday(date string) []Ranking {
+	var list []Ranking
+	_ = api.CsvToSlices(IntradayFilename(date), &list)
+	return list
+}
+
+// SaveRankings 保存板块排名
+//
+//	覆盖当日的排名文件, intraday为true时同时追加到盘中排名文件
+func SaveRankings(date string, list []Ranking, intraday bool) error {
+	if len(list) == 0 {
+		return nil
+	}
+	date = exchange.FixTradeDate(date)
+	now := time.Now().Format(cache.TimeStampMilli)
+	for i := range list {
+		list[i].Date = date
+		list[i].UpdateTime = now
+	}
+	rankingMutex.Lock()
+	defer rankingMutex.Unlock()
+	if err := saveRankings(RankingFilename(date), list); err != nil {
+		return err
+	}
+	if !intraday {
+		return nil
+	}
+	var history []Ranking
+	_ = api.CsvToSlices(IntradayFilename(date), &history)
+	history = append(history, list...)
+	return saveRankings(IntradayFilename(date), history)
+}
+
+func saveRankings(filename string, list []Ranking) error {
+	if err := os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+		return err
+	}
+	return api.SlicesToCsv(filename, list, true)
+}
+
+// LastTradingDates 截止endDate的最近n个交易日, 按日期升序
+func LastTradingDates(endDate string, n int) []string {
+	endDate = exchange.FixTradeDate(endDate)
+	end, err := time.Parse(time.DateOnly, endDate)
+	if err != nil || n < 1 {
+		return nil
+	}
+	// 自然日按交易日的2倍回溯, 覆盖长假
+	start := end.AddDate(0, 0, -(n*2 + defaultCalendar)).Format(time.DateOnly)
+	dates := exchange.TradingDateRange(start, endDate)
+	if len(dates) > n {
+		dates = dates[len(dates)-n:]
+	}
+	return dates
+}
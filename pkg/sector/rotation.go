@@ -0,0 +1,181 @@
+package sector
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+const (
+	DefaultRotationDays = 5  // 默认观察的交易日数
+	DefaultRotationTopN = 20 // 默认的热点板块排名
+)
+
+var (
+	ErrNoRankings = errors.New("板块排名数据不存在")
+)
+
+// SectorRotation 单个板块在观察窗口内的轮动指标
+type SectorRotation struct {
+	Code        string  `name:"板块代码" json:"code"`         // 板块代码
+	Name        string  `name:"板块名称" json:"name"`         // 板块名称
+	Type        string  `name:"板块类型" json:"type"`         // 板块类型
+	Days        int     `name:"有效天数" json:"days"`         // 窗口内有排名数据的天数
+	Rank        int     `name:"最新排名" json:"rank"`         // 最新排名
+	FirstRank   int     `name:"首日排名" json:"firstRank"`    // 窗口首日排名
+	RankChange  int     `name:"排名变化" json:"rankChange"`   // 首日排名-最新排名, 正数为排名上升
+	AvgRank     float64 `name:"平均排名" json:"avgRank"`      // 平均排名
+	ChangeRate  float64 `name:"最新涨幅%" json:"changeRate"`  // 最新涨幅
+	Momentum    float64 `name:"累计涨幅%" json:"momentum"`    // 窗口内按日涨幅复利累计
+	LimitUpNum  int     `name:"累计涨停数" json:"limitUpNum"`  // 窗口内涨停家数合计
+	TopDays     int     `name:"热点天数" json:"topDays"`      // 排名进入前N的天数
+	TopStreak   int     `name:"连续热点天数" json:"topStreak"`  // 截至最新连续进入前N的天数
+	Leader      string  `name:"龙头" json:"leader"`         // 最新的领涨个股
+	LeaderName  string  `name:"龙头名称" json:"leaderName"`   // 最新的领涨个股名称
+	LeaderDays  int     `name:"龙头连续天数" json:"leaderDays"` // 最新的领涨个股连续领涨的天数
+	Emerging    bool    `name:"新晋热点" json:"emerging"`     // 最新进入前N, 窗口内之前的交易日都不在前N
+	Amount      float64 `name:"最新成交额" json:"amount"`      // 最新成交额
+	AmountRatio float64 `name:"成交额放大" json:"amountRatio"` // 最新成交额/窗口内此前的平均成交额
+	ranks       []int   // 按日期的排名, 0为缺失
+}
+
+// Rotation 板块轮动分析结果
+type Rotation struct {
+	EndDate  string           `json:"endDate"`  // 截止日期
+	Dates    []string         `json:"dates"`    // 观察窗口的交易日
+	TopN     int              `json:"topN"`     // 热点板块排名
+	Sectors  []SectorRotation `json:"sectors"`  // 全部板块, 按累计涨幅降序
+	Emerging []SectorRotation `json:"emerging"` // 新晋热点板块, 按最新排名升序
+	index    map[string]int   // 板块代码在Sectors中的序号
+}
+
+// Lookup 按板块代码查找轮动指标
+func (this *Rotation) Lookup(code string) (SectorRotation, bool) {
+	i, ok := this.index[code]
+	if !ok {
+		return SectorRotation{}, false
+	}
+	return this.Sectors[i], true
+}
+
+// Analyze 分析截止endDate最近days个交易日的板块轮动
+//
+//	topN为热点板块的排名阈值, 小于1时取默认值
+func Analyze(endDate string, days, topN int) (*Rotation, error) {
+	if days < 1 {
+		days = DefaultRotationDays
+	}
+	if topN < 1 {
+		topN = DefaultRotationTopN
+	}
+	dates := LastTradingDates(endDate, days)
+	history := make([][]Ranking, len(dates))
+	found := false
+	for i, date := range dates {
+		history[i] = LoadRankings(date)
+		found = found || len(history[i]) > 0
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrNoRankings, endDate)
+	}
+	return analyze(dates, history, topN), nil
+}
+
+// analyze 按日期升序的排名数据计算轮动指标
+func analyze(dates []string, history [][]Ranking, topN int) *Rotation {
+	n := len(dates)
+	result := &Rotation{Dates: dates, TopN: topN}
+	if n > 0 {
+		result.EndDate = dates[n-1]
+	}
+	mapSectors := map[string]*SectorRotation{}
+	var codes []string
+	leaders := map[string][]string{}
+	amounts := map[string][]float64{}
+	for i, list := range history {
+		for _, v := range list {
+			s, ok := mapSectors[v.Code]
+			if !ok {
+				s = &SectorRotation{Code: v.Code, ranks: make([]int, n), Momentum: 1}
+				mapSectors[v.Code] = s
+				codes = append(codes, v.Code)
+				leaders[v.Code] = make([]string, n)
+				amounts[v.Code] = make([]float64, n)
+			}
+			s.Name, s.Type = v.Name, v.Type
+			s.ranks[i] = v.Rank
+			s.Days++
+			s.Momentum *= 1 + v.ChangeRate/100
+			s.LimitUpNum += v.LimitUpNum
+			if s.FirstRank == 0 {
+				s.FirstRank = v.Rank
+			}
+			if v.Rank > 0 && v.Rank <= topN {
+				s.TopDays++
+			}
+			leaders[v.Code][i] = v.TopCode
+			amounts[v.Code][i] = v.Amount
+			if i == n-1 {
+				s.Rank, s.ChangeRate, s.Amount = v.Rank, v.ChangeRate, v.Amount
+				s.Leader, s.LeaderName = v.TopCode, v.TopName
+			}
+		}
+	}
+	for _, code := range codes {
+		s := mapSectors[code]
+		s.Momentum = (s.Momentum - 1) * 100
+		sum := 0
+		for _, r := range s.ranks {
+			sum += r
+		}
+		s.AvgRank = float64(sum) / float64(s.Days)
+		if s.Rank > 0 {
+			s.RankChange = s.FirstRank - s.Rank
+		}
+		// 连续热点天数和新晋热点
+		for i := n - 1; i >= 0; i-- {
+			if s.ranks[i] == 0 || s.ranks[i] > topN {
+				break
+			}
+			s.TopStreak++
+		}
+		s.Emerging = n > 1 && s.TopStreak == 1 && s.TopDays == 1
+		// 龙头连续领涨天数
+		for i := n - 1; i >= 0 && len(s.Leader) > 0; i-- {
+			if leaders[code][i] != s.Leader {
+				break
+			}
+			s.LeaderDays++
+		}
+		// 成交额放大倍数
+		total, count := 0.00, 0
+		for _, a := range amounts[code][:max(n-1, 0)] {
+			if a > 0 {
+				total += a
+				count++
+			}
+		}
+		if count > 0 && total > 0 {
+			s.AmountRatio = s.Amount / (total / float64(count))
+		}
+		result.Sectors = append(result.Sectors, *s)
+	}
+	slices.SortFunc(result.Sectors, func(a, b SectorRotation) int {
+		if c := cmp.Compare(b.Momentum, a.Momentum); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Code, b.Code)
+	})
+	result.index = make(map[string]int, len(result.Sectors))
+	for i, v := range result.Sectors {
+		result.index[v.Code] = i
+		if v.Emerging {
+			result.Emerging = append(result.Emerging, v)
+		}
+	}
+	slices.SortFunc(result.Emerging, func(a, b SectorRotation) int {
+		return cmp.Compare(a.Rank, b.Rank)
+	})
+	return result
+}
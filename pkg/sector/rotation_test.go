@@ -0,0 +1,67 @@
+package sector
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	dates := []string{"2024-01-02", "2024-01-03", "2024-01-04"}
+	history := [][]Ranking{
+		{
+			{Code: "sh880001", Name: "A", Rank: 1, ChangeRate: 3, TopCode: "sh600001", Amount: 100},
+			{Code: "sh880002", Name: "B", Rank: 2, ChangeRate: 1, TopCode: "sh600002", Amount: 100},
+			{Code: "sh880003", Name: "C", Rank: 3, ChangeRate: -1, TopCode: "sh600003", Amount: 100},
+		},
+		{
+			{Code: "sh880001", Name: "A", Rank: 1, ChangeRate: 2, TopCode: "sh600001", Amount: 100},
+			{Code: "sh880002", Name: "B", Rank: 3, ChangeRate: -1, TopCode: "sh600002", Amount: 100},
+			{Code: "sh880003", Name: "C", Rank: 2, ChangeRate: 0, TopCode: "sh600003", Amount: 100},
+		},
+		{
+			{Code: "sh880003", Name: "C", Rank: 1, ChangeRate: 5, TopCode: "sh600004", Amount: 300, LimitUpNum: 3},
+			{Code: "sh880001", Name: "A", Rank: 2, ChangeRate: 1, TopCode: "sh600001", Amount: 100},
+			{Code: "sh880002", Name: "B", Rank: 3, ChangeRate: -2, TopCode: "sh600002", Amount: 100},
+		},
+	}
+	r := analyze(dates, history, 1)
+	if r.EndDate != "2024-01-04" || len(r.Sectors) != 3 {
+		t.Fatalf("rotation: %+v", r)
+	}
+	a, _ := r.Lookup("sh880001")
+	if r.Sectors[0].Code != "sh880001" || a.TopDays != 2 || a.TopStreak != 0 || a.LeaderDays != 3 || a.RankChange != -1 {
+		t.Errorf("sh880001: %+v", a)
+	}
+	if want := (1.03*1.02*1.01 - 1) * 100; math.Abs(a.Momentum-want) > 1e-9 {
+		t.Errorf("momentum=%v, want %v", a.Momentum, want)
+	}
+	c, _ := r.Lookup("sh880003")
+	if !c.Emerging || c.RankChange != 2 || c.LeaderDays != 1 || c.AmountRatio != 3 || c.LimitUpNum != 3 {
+		t.Errorf("sh880003: %+v", c)
+	}
+	if len(r.Emerging) != 1 || r.Emerging[0].Code != "sh880003" {
+		t.Errorf("emerging: %+v", r.Emerging)
+	}
+}
+
+func TestStockRotation(t *testing.T) {
+	dates := []string{"2024-01-02", "2024-01-03"}
+	history := [][]Ranking{
+		{
+			{Code: "sh880001", Rank: 1, TopCode: "sh600001"},
+			{Code: "sh880002", Rank: 2, TopCode: "sh600002"},
+		},
+		{
+			{Code: "sh880002", Rank: 1, TopCode: "sh600001"},
+			{Code: "sh880001", Rank: 2, TopCode: "sh600001"},
+		},
+	}
+	r := analyze(dates, history, 2)
+	v := stockRotation(r, "sh600001", []string{"sh880001", "sh880002", "sh880003"})
+	if v == nil || v.SectorCode != "sh880002" || !v.IsLeader || v.LeaderDays != 1 || v.HotSectors != 2 {
+		t.Errorf("stock rotation: %+v", v)
+	}
+	if v := stockRotation(r, "sh600009", []string{"sh880003"}); v != nil {
+		t.Errorf("stock rotation: %+v", v)
+	}
+}
@@ -4,11 +4,8 @@ import (
 	"gitee.com/quant1x/gox/logger"
 	"gitee.com/quant1x/gox/progressbar"
 	"gitee.com/quant1x/pkg/runewidth"
-	"sync"
-	"time"
 	"xquant/pkg/cache"
 	"xquant/pkg/factors"
-	"xquant/pkg/market"
 )
 
 // FeaturesBackTest FeaturesUpdate 特征-数据有效性验证
@@ -29,51 +26,20 @@ func FeaturesBackTest(barIndex *int, cacheDate, featureDate string, plugins []ca
 	}
 	logger.Infof("%s: all, begin", moduleName)
 
-	var wgAdapter sync.WaitGroup
-	cacheCount := len(adapters)
-	barAdapter := progressbar.NewBar(*barIndex, "执行["+moduleName+"]", cacheCount)
-	allCodes := market.GetCodeList()
-	allCodes = allCodes[:]
-	codeCount := len(allCodes)
+	barAdapter := progressbar.NewBar(*barIndex, "执行["+moduleName+"]", len(adapters))
 	var metrics []cache.AdapterMetric
 	for _, adapter := range adapters {
 		logger.Infof("%s: %s, begin", moduleName, adapter.Name())
-
-		wgAdapter.Add(1)
-		// 加载指定日期的特征
-		adapter.Checkout(cacheDate)
-		var sb cache.ScoreBoard
-
-		barCode := progressbar.NewBar(*barIndex+1, "执行["+adapter.Name()+"]", codeCount)
-		for _, code := range allCodes {
-			now := time.Now()
-			passed := false
-			raw := adapter.Element(code)
-			kind := adapter.Kind()
-			sb.From(cache.GetDataAdapter(kind))
-			// 判断是否实现验证接口
-			feature, ok := raw.(cache.Validator)
-			if ok {
-				err := feature.Check(featureDate)
-				if err == nil {
-					passed = true
-				}
-			}
-			sb.Add(1, time.Since(now), passed)
-			barCode.Add(1)
-			//wg.Add(1)
-			//go updateStockFeature(wg, barCode, feature, code, cacheDate, featureDate, op, mapFeature, &sb, now)
+		// 按校验策略检查指定日期的特征, 不切换特征当前的缓存日期
+		report := adapter.Validate(cacheDate, featureDate)
+		for _, v := range report.Failures {
+			logger.Warnf("%s: %s, code=%s, %s", moduleName, adapter.Name(), v.Code, v.Reason)
 		}
-		//wg.Wait()
-		barCode.Wait()
-
 		// 适配器进度条+1
 		barAdapter.Add(1)
-		wgAdapter.Done()
-		metrics = append(metrics, sb.Metric())
-		logger.Infof("%s: %s, end", moduleName, adapter.Name())
+		metrics = append(metrics, report.Metric)
+		logger.Infof("%s: %s, end, count=%d, passed=%d", moduleName, adapter.Name(), report.Metric.Count, report.Metric.Passed)
 	}
-	wgAdapter.Wait()
 	barAdapter.Wait()
 	logger.Infof("%s: all, end", moduleName)
 
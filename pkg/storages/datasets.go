@@ -13,6 +13,7 @@ import (
 	"xquant/pkg/factors"
 	"xquant/pkg/log"
 	"xquant/pkg/market"
+	"xquant/pkg/panel"
 )
 
 // DataSetUpdate 修复数据
@@ -67,6 +68,8 @@ func DataSetUpdate(barIndex int, date string, plugins []cache.DataAdapter, op ca
 	}
 	barCache.Wait()
 	wg.Wait()
+	// 宽表已更新, 面板数据中缓存的宽表和截面数据失效
+	panel.Default().Purge()
 	log.Infof("%s: all, end", moduleName)
 }
 
@@ -16,8 +16,10 @@ import (
 	"gitee.com/quant1x/pkg/tablewriter"
 	
 	"xquant/pkg/cache"
+	"xquant/pkg/config"
 	"xquant/pkg/factors"
 	"xquant/pkg/market"
+	"xquant/pkg/panel"
 )
 
 // MetricCallback 性能指标回调函数
@@ -37,7 +39,10 @@ func updateStockFeature(wg *coroutine.RollingWaitGroup, bar *progressbar.Bar, fe
 }
 
 // FeaturesUpdate 更新特征
-func FeaturesUpdate(barIndex *int, cacheDate, featureDate string, plugins []cache.DataAdapter, op cache.OpKind) MetricCallback {
+//
+//	按特征声明的依赖关系构建有向无环图, 特征在依赖的特征完成当日数据之后才开始更新,
+//	没有依赖关系的特征并行更新, 并发数由data.feature.concurrency控制
+func FeaturesUpdate(barIndex *int, cacheDate, featureDate string, plugins []cache.DataAdapter, op cache.OpKind) (MetricCallback, error) {
 	moduleName := "特征数据"
 	if op == cache.OpRepair {
 		moduleName = "修复" + moduleName
@@ -57,54 +62,60 @@ func FeaturesUpdate(barIndex *int, cacheDate, featureDate string, plugins []cach
 			}
 		}
 	}
+	// 拓扑排序, 检测循环依赖
+	layers, err := cache.SortByDependency(adapters)
+	if err != nil {
+		logger.Errorf("%s: %+v", moduleName, err)
+		return nil, err
+	}
+	var sortedAdapters []factors.FeatureRotationAdapter
+	for _, layer := range layers {
+		sortedAdapters = append(sortedAdapters, layer...)
+	}
 	logger.Infof("%s: all, begin", moduleName)
 
+	workers := config.GetDataConfig().Feature.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	// 每个工作槽位占用一行进度条
+	slots := make(chan int, workers)
+	for i := 0; i < workers; i++ {
+		slots <- i
+	}
+	// 特征完成信号
+	mapDone := make(map[cache.Kind]chan struct{}, len(sortedAdapters))
+	for _, adapter := range sortedAdapters {
+		mapDone[adapter.Kind()] = make(chan struct{})
+	}
+
 	var wgAdapter sync.WaitGroup
-	cacheCount := len(adapters)
+	cacheCount := len(sortedAdapters)
 	barAdapter := progressbar.NewBar(*barIndex, "执行["+moduleName+"]", cacheCount)
 	allCodes := market.GetCodeList()
-	allCodes = allCodes[:]
-	codeCount := len(allCodes)
-	var metrics []cache.AdapterMetric
-	for _, adapter := range adapters {
-		logger.Infof("%s: %s, begin", moduleName, adapter.Name())
-
+	metrics := make([]cache.AdapterMetric, cacheCount)
+	for i, adapter := range sortedAdapters {
 		wgAdapter.Add(1)
-		var sb cache.ScoreBoard
-		barCode := progressbar.NewBar(*barIndex+1, "执行["+adapter.Name()+"]", codeCount)
-		mapFeature := treemap.NewWithStringComparator()
-		wg := coroutine.NewRollingWaitGroup(5)
-		dataSource := adapter.Factory(featureDate, "")
-		parent := coroutine.Context()
-		ctx := context.WithValue(parent, cache.KBarIndex, barIndex)
-		_ = dataSource.Init(ctx, featureDate)
-		for _, code := range allCodes {
-			now := time.Now()
-			feature := adapter.Factory(featureDate, code).(factors.Feature)
-			if feature.Kind() != factors.FeatureHistory {
-				history := factors.GetL5History(code, cacheDate)
-				if history != nil {
-					feature = feature.FromHistory(*history)
+		go func(index int, adapter factors.FeatureRotationAdapter) {
+			defer wgAdapter.Done()
+			defer close(mapDone[adapter.Kind()])
+			// 等待依赖的特征完成
+			for _, dep := range cache.DependOn(adapter) {
+				if done, ok := mapDone[dep]; ok {
+					<-done
 				}
 			}
-			sb.From(cache.GetDataAdapter(feature.Kind()))
-			wg.Add(1)
-			go updateStockFeature(wg, barCode, feature, code, cacheDate, featureDate, op, mapFeature, &sb, now)
-		}
-		wg.Wait()
-		barCode.Wait()
-		// 加载缓存
-		adapter.Checkout(cacheDate)
-		// 合并
-		adapter.Merge(mapFeature)
-		// 适配器进度条+1
-		barAdapter.Add(1)
-		wgAdapter.Done()
-		metrics = append(metrics, sb.Metric())
-		logger.Infof("%s: %s, end", moduleName, adapter.Name())
+			slot := <-slots
+			defer func() { slots <- slot }()
+			metrics[index] = updateFeatureAdapter(barIndex, *barIndex+1+slot, moduleName, adapter, allCodes, cacheDate, featureDate, op)
+			// 适配器进度条+1
+			barAdapter.Add(1)
+		}(i, adapter)
 	}
 	wgAdapter.Wait()
 	barAdapter.Wait()
+	// 特征数据已更新, 面板数据中缓存的截面数据失效
+	panel.Default().Purge()
 	logger.Infof("%s: all, end", moduleName)
 	// 输出衡量性能的指标列表
 	mcb := func() {
@@ -123,5 +134,39 @@ func FeaturesUpdate(barIndex *int, cacheDate, featureDate string, plugins []cach
 			table.Render()
 		}
 	}
-	return mcb
+	return mcb, nil
+}
+
+// updateFeatureAdapter 更新单个特征的全部个股数据
+func updateFeatureAdapter(barIndex *int, barLine int, moduleName string, adapter factors.FeatureRotationAdapter, allCodes []string, cacheDate, featureDate string, op cache.OpKind) cache.AdapterMetric {
+	logger.Infof("%s: %s, begin", moduleName, adapter.Name())
+	var sb cache.ScoreBoard
+	barCode := progressbar.NewBar(barLine, "执行["+adapter.Name()+"]", len(allCodes))
+	mapFeature := treemap.NewWithStringComparator()
+	wg := coroutine.NewRollingWaitGroup(5)
+	dataSource := adapter.Factory(featureDate, "")
+	parent := coroutine.Context()
+	ctx := context.WithValue(parent, cache.KBarIndex, barIndex)
+	_ = dataSource.Init(ctx, featureDate)
+	for _, code := range allCodes {
+		now := time.Now()
+		feature := adapter.Factory(featureDate, code).(factors.Feature)
+		if feature.Kind() != factors.FeatureHistory {
+			history := factors.GetL5History(code, cacheDate)
+			if history != nil {
+				feature = feature.FromHistory(*history)
+			}
+		}
+		sb.From(cache.GetDataAdapter(feature.Kind()))
+		wg.Add(1)
+		go updateStockFeature(wg, barCode, feature, code, cacheDate, featureDate, op, mapFeature, &sb, now)
+	}
+	wg.Wait()
+	barCode.Wait()
+	// 加载缓存
+	adapter.Checkout(cacheDate)
+	// 合并
+	adapter.Merge(mapFeature)
+	logger.Infof("%s: %s, end", moduleName, adapter.Name())
+	return sb.Metric()
 }
@@ -0,0 +1,191 @@
+package storages
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/gox/api"
+	"gitee.com/quant1x/num"
+
+	"xquant/pkg/cache"
+	"xquant/pkg/factors"
+)
+
+const (
+	shadowOrderPath   = "shadow"
+	shadowOrderPrefix = "shadow."
+)
+
+// 影子订单状态
+const (
+	ShadowOrderPending = 0 // 已委托, 等待成交
+	ShadowOrderFilled  = 1 // 已成交
+	ShadowOrderExpired = 2 // 收盘未成交, 已过期
+)
+
+var (
+	shadowMutex     sync.Mutex
+	shadowOrderRoot = cache.GetQmtCachePath // 影子订单的根路径
+)
+
+// ShadowOrder 影子模式的虚拟订单
+type ShadowOrder struct {
+	Date         string  `name:"交易日期" dataframe:"date"`
+	StrategyCode uint64  `name:"策略编码" dataframe:"strategy_code"`
+	StrategyName string  `name:"策略名称" dataframe:"strategy_name"`
+	Code         string  `name:"证券代码" dataframe:"code"`
+	Name         string  `name:"证券名称" dataframe:"name"`
+	SignalPrice  float64 `name:"信号价格" dataframe:"signal_price"`
+	OrderPrice   float64 `name:"委托价格" dataframe:"order_price"`
+	Volume       int     `name:"委托数量" dataframe:"volume"`
+	Status       int     `name:"订单状态" dataframe:"status"`
+	FillPrice    float64 `name:"成交价格" dataframe:"fill_price"`
+	FillTime     string  `name:"成交时间" dataframe:"fill_time"`
+	LastPrice    float64 `name:"最新价" dataframe:"last_price"`
+	Yield        float64 `name:"收益率%" dataframe:"yield"`
+	CreateTime   string  `name:"创建时间" dataframe:"create_time"`
+	UpdateTime   string  `name:"更新时间" dataframe:"update_time"`
+}
+
+// Key 索引字段: 日期/策略代码/证券代码
+func (o ShadowOrder) Key() string {
+	return fmt.Sprintf("%s/%d/%s", o.Date, o.StrategyCode, o.Code)
+}
+
+// IsFilled 是否已成交
+func (o ShadowOrder) IsFilled() bool {
+	return o.Status == ShadowOrderFilled
+}
+
+// GetShadowOrderFilename 影子订单文件名
+//
+//	qmt/shadow/shadow.yyyy-mm-dd
+func GetShadowOrderFilename(date string) string {
+	tradeDate := exchange.FixTradeDate(date)
+	return filepath.Join(shadowOrderRoot(), shadowOrderPath, shadowOrderPrefix+tradeDate)
+}
+
+// GetShadowOrders 获取指定日期的影子订单
+func GetShadowOrders(date string) []ShadowOrder {
+	shadowMutex.Lock()
+	defer shadowMutex.Unlock()
+	return loadShadowOrders(date)
+}
+
+func loadShadowOrders(date string) (list []ShadowOrder) {
+	filename := GetShadowOrderFilename(date)
+	_ = api.CsvToSlices(filename, &list)
+	return
+}
+
+func saveShadowOrders(date string, list []ShadowOrder) error {
+	filename := GetShadowOrderFilename(date)
+	if err := os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+		return err
+	}
+	return api.SlicesToCsv(filename, list, true)
+}
+
+// CountShadowOrders 统计策略当日的影子订单数
+func CountShadowOrders(date string, strategyCode uint64) int {
+	count := 0
+	for _, v := range GetShadowOrders(date) {
+		if v.StrategyCode == strategyCode {
+			count++
+		}
+	}
+	return count
+}
+
+// CheckShadowOrder 检查影子订单是否已存在
+func CheckShadowOrder(date string, strategyCode uint64, code string) bool {
+	securityCode := exchange.CorrectSecurityCode(code)
+	for _, v := range GetShadowOrders(date) {
+		if v.StrategyCode == strategyCode && v.Code == securityCode {
+			return true
+		}
+	}
+	return false
+}
+
+// PlaceShadowOrder 记录影子订单, 同一策略同一标的每日只记录一次
+func PlaceShadowOrder(order ShadowOrder) error {
+	order.Date = exchange.FixTradeDate(order.Date)
+	order.Code = exchange.CorrectSecurityCode(order.Code)
+	order.Status = ShadowOrderPending
+	now := time.Now().Format(cache.TimeStampMilli)
+	if len(order.CreateTime) == 0 {
+		order.CreateTime = now
+	}
+	order.UpdateTime = now
+	shadowMutex.Lock()
+	defer shadowMutex.Unlock()
+	list := loadShadowOrders(order.Date)
+	for _, v := range list {
+		if v.Key() == order.Key() {
+			return nil
+		}
+	}
+	list = append(list, order)
+	return saveShadowOrders(order.Date, list)
+}
+
+// MatchShadowOrders 用最新快照撮合影子订单
+//
+//	买入委托价不低于现价即视为按现价成交, 现价为涨停价时不成交.
+//	closed为true时, 表示已收盘, 未成交的订单标记为过期, 已成交的订单更新收盘价和收益率.
+//	只在订单成交、过期或收盘更新时写文件, 返回本次成交的订单数
+func MatchShadowOrders(date string, snapshotOf func(securityCode string) *factors.QuoteSnapshot, closed bool) (int, error) {
+	shadowMutex.Lock()
+	defer shadowMutex.Unlock()
+	list := loadShadowOrders(date)
+	if len(list) == 0 {
+		return 0, nil
+	}
+	filled := 0
+	changed := false
+	now := time.Now().Format(cache.TimeStampMilli)
+	for i := range list {
+		v := &list[i]
+		if v.Status == ShadowOrderExpired {
+			continue
+		}
+		snapshot := snapshotOf(v.Code)
+		valid := snapshot != nil && snapshot.Price > 0
+		if valid && v.Status == ShadowOrderPending {
+			// 涨停价上的买单视为排队未成交
+			sealed := snapshot.LastClose > 0 && factors.CheckoutPriceLimit(v.Code, date, snapshot.LastClose).IsLimitUp(snapshot.Price)
+			if snapshot.Price <= v.OrderPrice && !sealed {
+				v.Status = ShadowOrderFilled
+				v.FillPrice = snapshot.Price
+				v.FillTime = snapshot.ServerTime
+				v.LastPrice = snapshot.Price
+				v.Yield = 0
+				v.UpdateTime = now
+				filled++
+				changed = true
+			}
+		}
+		if !closed {
+			continue
+		}
+		if v.Status == ShadowOrderPending {
+			v.Status = ShadowOrderExpired
+			v.UpdateTime = now
+			changed = true
+		} else if valid && v.LastPrice != snapshot.Price {
+			v.LastPrice = snapshot.Price
+			v.Yield = num.NetChangeRate(v.FillPrice, v.LastPrice)
+			v.UpdateTime = now
+			changed = true
+		}
+	}
+	if !changed {
+		return filled, nil
+	}
+	return filled, saveShadowOrders(date, list)
+}
@@ -0,0 +1,71 @@
+package storages
+
+import (
+	"testing"
+
+	"xquant/pkg/cache"
+	"xquant/pkg/factors"
+)
+
+func TestShadowOrder(t *testing.T) {
+	root := t.TempDir()
+	shadowOrderRoot = func() string { return root }
+	defer func() { shadowOrderRoot = cache.GetQmtCachePath }()
+	date := "2024-05-17"
+	order := ShadowOrder{
+		Date:         date,
+		StrategyCode: 82,
+		StrategyName: "测试策略",
+		Code:         "sh600178",
+		SignalPrice:  10.00,
+		OrderPrice:   10.05,
+		Volume:       1000,
+	}
+	if err := PlaceShadowOrder(order); err != nil {
+		t.Fatalf("PlaceShadowOrder() failed: %v", err)
+	}
+	order.Code = "sz000001"
+	if err := PlaceShadowOrder(order); err != nil {
+		t.Fatalf("PlaceShadowOrder() failed: %v", err)
+	}
+	// 同一策略同一标的每日只记录一次
+	_ = PlaceShadowOrder(order)
+	if !CheckShadowOrder(date, 82, "600178") || CountShadowOrders(date, 82) != 2 {
+		t.Fatalf("orders=%+v", GetShadowOrders(date))
+	}
+	prices := map[string]float64{"sh600178": 10.10, "sz000001": 10.20}
+	snapshotOf := func(securityCode string) *factors.QuoteSnapshot {
+		return &factors.QuoteSnapshot{SecurityCode: securityCode, Price: prices[securityCode], ServerTime: "09:35:00"}
+	}
+	// 现价高于委托价, 不成交
+	filled, err := MatchShadowOrders(date, snapshotOf, false)
+	if err != nil || filled != 0 {
+		t.Fatalf("filled=%d, err=%v", filled, err)
+	}
+	// 现价不高于委托价, 按现价成交
+	prices["sh600178"] = 10.02
+	filled, err = MatchShadowOrders(date, snapshotOf, false)
+	if err != nil || filled != 1 {
+		t.Fatalf("filled=%d, err=%v", filled, err)
+	}
+	// 盘中价格变动不写文件
+	prices["sh600178"] = 10.50
+	if filled, _ = MatchShadowOrders(date, snapshotOf, false); filled != 0 {
+		t.Errorf("filled=%d", filled)
+	}
+	list := GetShadowOrders(date)
+	if len(list) != 2 || !list[0].IsFilled() || list[0].FillPrice != 10.02 || list[0].FillTime != "09:35:00" || list[0].LastPrice != 10.02 {
+		t.Fatalf("orders=%+v", list)
+	}
+	// 收盘后未成交的订单过期, 已成交的订单更新收盘价和收益率
+	if _, err = MatchShadowOrders(date, snapshotOf, true); err != nil {
+		t.Fatalf("MatchShadowOrders() failed: %v", err)
+	}
+	list = GetShadowOrders(date)
+	if list[0].LastPrice != 10.50 || list[0].Yield <= 0 {
+		t.Errorf("filled order=%+v", list[0])
+	}
+	if list[1].Status != ShadowOrderExpired {
+		t.Errorf("pending order=%+v", list[1])
+	}
+}
@@ -31,7 +31,7 @@ func (m TestModel) Sort(snapshots []factors.QuoteSnapshot) models.SortedStatus {
 	panic("implement me")
 }
 
-func (m TestModel) Evaluate(securityCode string, result *concurrent.TreeMap[string, models.ResultInfo]) {
+func (m TestModel) Evaluate(securityCode string, snapshot factors.QuoteSnapshot, result *concurrent.TreeMap[string, models.ResultInfo]) {
 	//TODO implement me
 	panic("implement me")
 }
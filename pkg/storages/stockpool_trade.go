@@ -5,6 +5,7 @@ import (
 	"gitee.com/quant1x/gox/logger"
 
 	"xquant/pkg/config"
+	"xquant/pkg/factors"
 	"xquant/pkg/models"
 	"xquant/pkg/trader"
 )
@@ -45,6 +46,11 @@ func CheckOrderForBuy(list []StockPool, model models.Strategy, date string) bool
 		logger.Errorf("%s[%d]: 策略未配置或不买入, 放弃", model.Name(), model.Code())
 		return false
 	}
+	if strategyParameter.IsShadow() {
+		// 影子模式的虚拟订单由tracker记录, 这里不下单
+		logger.Warnf("%s[%d]: 影子模式, 不下单", model.Name(), model.Code())
+		return false
+	}
 
 	// 3. 判断交易时段
 	if !strategyParameter.Session.IsTrading() {
@@ -149,6 +155,8 @@ func CheckOrderForBuy(list []StockPool, model models.Strategy, date string) bool
 		_ = PushOrderState(date, model, securityCode, direction)
 		// 10.5 启用价格笼子的计算方法
 		price := trader.CalculatePriceCage(*strategyParameter, direction, v.Buy)
+		// 10.5.1 委托价格不能超出当日的涨跌停价
+		price = factors.CheckoutPriceLimit(securityCode, tradeDate, 0).Clamp(price)
 		// 10.6 计算买入费用
 		tradeFee := trader.EvaluateFeeForBuy(securityCode, singleFundsAvailable, price)
 		if tradeFee.Volume <= trader.InvalidVolume {
@@ -25,7 +25,7 @@ func GetResultCachePath() string {
 // OutputStatistics 输出策略结果
 func OutputStatistics(model models.Strategy, date string, v []models.Statistics) {
 	tradeRule := config.GetStrategyParameterByCode(model.Code())
-	if tradeRule == nil || !tradeRule.Enable() || tradeRule.Total == 0 {
+	if tradeRule == nil || !tradeRule.Running() || tradeRule.Total == 0 {
 		// 配置不存在, 或者规则无效
 		return
 	}
@@ -36,7 +36,7 @@ func (TestModel82) Sort(snapshots []factors.QuoteSnapshot) models.SortedStatus {
 	panic("implement me")
 }
 
-func (TestModel82) Evaluate(securityCode string, result *concurrent.TreeMap[string, models.ResultInfo]) {
+func (TestModel82) Evaluate(securityCode string, snapshot factors.QuoteSnapshot, result *concurrent.TreeMap[string, models.ResultInfo]) {
 	//TODO implement me
 	panic("implement me")
 }
@@ -41,6 +41,18 @@ func (m ModelAvgPriceDown) OrderFlag() string {
 	return models.OrderFlagTail
 }
 
+// Metadata 策略元数据
+func (m ModelAvgPriceDown) Metadata() models.StrategyMetadata {
+	return models.StrategyMetadata{
+		Description: "7日均线向下穿过25日均线和99日均线, 趋势转弱的卖出信号",
+		Version:     models.DefaultStrategyVersion,
+		Features:    []string{"history", "day"},
+		Parameters: []models.ParameterSchema{
+			{Name: "target_ratio", Type: models.ParameterTypeFloat, Default: "-8.00", Description: "目标涨跌幅(%), 用于计算目标价格"},
+		},
+	}
+}
+
 func (m ModelAvgPriceDown) Filter(ruleParameter config.RuleParameter, snapshot factors.QuoteSnapshot) error {
 	return ChainFilters(GeneralFilter)(ruleParameter, snapshot)
 }
@@ -49,21 +61,16 @@ func (m ModelAvgPriceDown) Sort(snapshots []factors.QuoteSnapshot) models.Sorted
 	return models.SortDefault
 }
 
-func (m ModelAvgPriceDown) Evaluate(securityCode string, result *concurrent.TreeMap[string, models.ResultInfo]) {
-	// 1. 获取历史数据
-	history := factors.GetL5History(securityCode)
+func (m ModelAvgPriceDown) Evaluate(securityCode string, snapshot factors.QuoteSnapshot, result *concurrent.TreeMap[string, models.ResultInfo]) {
+	params := models.LoadStrategyParams(m)
+	// 1. 获取评估日期的历史数据
+	history := factors.GetL5History(securityCode, snapshot.Date)
 	if history == nil {
 		return
 	}
 
-	// 2. 获取策略快照
-	snapshot := models.SnapshotMgr.GetStrategySnapshot(securityCode)
-	if snapshot == nil {
-		return
-	}
-
-	// 3. 获取 K 线数据
-	df := factors.BasicKLine(securityCode)
+	// 2. 获取截止评估日期的K线数据
+	df := checkoutKLine(securityCode, snapshot.Date)
 	if df.Nrow() < 100 {
 		return
 	}
@@ -73,7 +80,7 @@ func (m ModelAvgPriceDown) Evaluate(securityCode string, result *concurrent.Tree
 		return
 	}
 
-	// 4. 计算均线
+	// 3. 计算均线
 	// 7日均线
 	ma7 := MA(CLOSE, 7)
 	// 25日均线
@@ -85,12 +92,12 @@ func (m ModelAvgPriceDown) Evaluate(securityCode string, result *concurrent.Tree
 		return
 	}
 
-	// 5. 判断是否满足均线向下形态
+	// 4. 判断是否满足均线向下形态
 	if !m.isAvgPriceDown(ma7, ma25, ma99) {
 		return
 	}
 
-	// 6. 如果满足条件，加入结果（卖出信号）
+	// 5. 如果满足条件，加入结果（卖出信号）
 	price := snapshot.Price
 	date := snapshot.Date
 	result.Put(securityCode, models.ResultInfo{
@@ -99,7 +106,7 @@ func (m ModelAvgPriceDown) Evaluate(securityCode string, result *concurrent.Tree
 		Date:         date,
 		Rate:         0.00,
 		Buy:          price,
-		Sell:         price * 0.92, // 目标跌幅 8%（卖出信号）
+		Sell:         price * (1 + params.Float64("target_ratio", -8.00)/100), // 目标跌幅 8%（卖出信号）
 		StrategyCode: m.Code(),
 		StrategyName: m.Name(),
 	})
@@ -41,6 +41,18 @@ func (m ModelBlackThree) OrderFlag() string {
 	return models.OrderFlagTail
 }
 
+// Metadata 策略元数据
+func (m ModelBlackThree) Metadata() models.StrategyMetadata {
+	return models.StrategyMetadata{
+		Description: "连续3根光头光脚的阴线且收盘价逐日降低, 卖出信号",
+		Version:     models.DefaultStrategyVersion,
+		Features:    []string{"history", "day"},
+		Parameters: []models.ParameterSchema{
+			{Name: "target_ratio", Type: models.ParameterTypeFloat, Default: "-5.00", Description: "目标涨跌幅(%), 用于计算目标价格"},
+		},
+	}
+}
+
 func (m ModelBlackThree) Filter(ruleParameter config.RuleParameter, snapshot factors.QuoteSnapshot) error {
 	return ChainFilters(GeneralFilter)(ruleParameter, snapshot)
 }
@@ -49,21 +61,16 @@ func (m ModelBlackThree) Sort(snapshots []factors.QuoteSnapshot) models.SortedSt
 	return models.SortDefault
 }
 
-func (m ModelBlackThree) Evaluate(securityCode string, result *concurrent.TreeMap[string, models.ResultInfo]) {
-	// 1. 获取历史数据
-	history := factors.GetL5History(securityCode)
+func (m ModelBlackThree) Evaluate(securityCode string, snapshot factors.QuoteSnapshot, result *concurrent.TreeMap[string, models.ResultInfo]) {
+	params := models.LoadStrategyParams(m)
+	// 1. 获取评估日期的历史数据
+	history := factors.GetL5History(securityCode, snapshot.Date)
 	if history == nil {
 		return
 	}
 
-	// 2. 获取策略快照
-	snapshot := models.SnapshotMgr.GetStrategySnapshot(securityCode)
-	if snapshot == nil {
-		return
-	}
-
-	// 3. 获取 K 线数据
-	df := factors.BasicKLine(securityCode)
+	// 2. 获取截止评估日期的K线数据
+	df := checkoutKLine(securityCode, snapshot.Date)
 	if df.Nrow() < 3 {
 		return
 	}
@@ -77,12 +84,12 @@ func (m ModelBlackThree) Evaluate(securityCode string, result *concurrent.TreeMa
 		return
 	}
 
-	// 4. 判断是否是黑色三乌鸦形态
+	// 3. 判断是否是黑色三乌鸦形态
 	if !m.isBlackThree(OPEN, CLOSE, HIGH, LOW) {
 		return
 	}
 
-	// 5. 如果满足条件，加入结果（卖出信号）
+	// 4. 如果满足条件，加入结果（卖出信号）
 	price := snapshot.Price
 	date := snapshot.Date
 	result.Put(securityCode, models.ResultInfo{
@@ -91,7 +98,7 @@ func (m ModelBlackThree) Evaluate(securityCode string, result *concurrent.TreeMa
 		Date:         date,
 		Rate:         0.00,
 		Buy:          price,
-		Sell:         price * 0.95, // 目标跌幅 5%（卖出信号）
+		Sell:         price * (1 + params.Float64("target_ratio", -5.00)/100), // 目标跌幅 5%（卖出信号）
 		StrategyCode: m.Code(),
 		StrategyName: m.Name(),
 	})
@@ -40,6 +40,19 @@ func (m ModelBreakthrough) OrderFlag() string {
 	return models.OrderFlagTick // 盘中实时突破
 }
 
+// Metadata 策略元数据
+func (m ModelBreakthrough) Metadata() models.StrategyMetadata {
+	return models.StrategyMetadata{
+		Description: "价格突破20日最高价, 成交量大于5日均量的1.5倍, 并且价格在MA20之上",
+		Version:     models.DefaultStrategyVersion,
+		Features:    []string{"history", "day"},
+		Parameters: []models.ParameterSchema{
+			{Name: "target_ratio", Type: models.ParameterTypeFloat, Default: "12.00", Description: "目标涨跌幅(%), 用于计算目标价格"},
+			{Name: "volume_ratio", Type: models.ParameterTypeFloat, Default: "1.50", Description: "成交量相对5日均量的放大倍数"},
+		},
+	}
+}
+
 func (m ModelBreakthrough) Filter(ruleParameter config.RuleParameter, snapshot factors.QuoteSnapshot) error {
 	return ChainFilters(GeneralFilter)(ruleParameter, snapshot)
 }
@@ -48,21 +61,16 @@ func (m ModelBreakthrough) Sort(snapshots []factors.QuoteSnapshot) models.Sorted
 	return models.SortDefault
 }
 
-func (m ModelBreakthrough) Evaluate(securityCode string, result *concurrent.TreeMap[string, models.ResultInfo]) {
-	// 1. 获取历史数据
-	history := factors.GetL5History(securityCode)
+func (m ModelBreakthrough) Evaluate(securityCode string, snapshot factors.QuoteSnapshot, result *concurrent.TreeMap[string, models.ResultInfo]) {
+	params := models.LoadStrategyParams(m)
+	// 1. 获取评估日期的历史数据
+	history := factors.GetL5History(securityCode, snapshot.Date)
 	if history == nil {
 		return
 	}
 
-	// 2. 获取策略快照
-	snapshot := models.SnapshotMgr.GetStrategySnapshot(securityCode)
-	if snapshot == nil {
-		return
-	}
-
-	// 3. 获取 K 线数据
-	df := factors.BasicKLine(securityCode)
+	// 2. 获取截止评估日期的K线数据
+	df := checkoutKLine(securityCode, snapshot.Date)
 	if df.Nrow() < 25 {
 		return
 	}
@@ -75,26 +83,26 @@ func (m ModelBreakthrough) Evaluate(securityCode string, result *concurrent.Tree
 		return
 	}
 
-	// 4. 计算近期高点（20日内最高价）
+	// 3. 计算近期高点（20日内最高价）
 	recentHigh := HHV(HIGH, 20)
 	maxHigh := utils.Float64IndexOf(recentHigh, -1)
 
-	// 5. 判断价格是否突破近期高点
+	// 4. 判断价格是否突破近期高点
 	isBreakthrough := snapshot.Price > maxHigh
 
-	// 6. 计算 5 日均量
+	// 5. 计算 5 日均量
 	avgVol5Series := MA(VOL, 5)
 	avgVol5 := utils.Float64IndexOf(avgVol5Series, -1)
 	currentVol := float64(snapshot.Vol)
 
-	// 7. 判断成交量是否放大（当前成交量 > 5日均量的 1.5 倍）
-	isVolumeAmplified := currentVol > avgVol5*1.5
+	// 6. 判断成交量是否放大（当前成交量 > 5日均量的 1.5 倍）
+	isVolumeAmplified := currentVol > avgVol5*params.Float64("volume_ratio", 1.50)
 
-	// 8. 计算 MA20，判断价格是否在均线上方
+	// 7. 计算 MA20，判断价格是否在均线上方
 	ma20 := utils.Float64IndexOf(MA(CLOSE, 20), -1)
 	isPriceAboveMA20 := snapshot.Price > ma20
 
-	// 9. 如果满足所有条件，加入结果
+	// 8. 如果满足所有条件，加入结果
 	if isBreakthrough && isVolumeAmplified && isPriceAboveMA20 {
 		price := snapshot.Price
 		date := snapshot.Date
@@ -104,7 +112,7 @@ func (m ModelBreakthrough) Evaluate(securityCode string, result *concurrent.Tree
 			Date:         date,
 			Rate:         0.00,
 			Buy:          price,
-			Sell:         price * 1.12, // 目标涨幅 12%
+			Sell:         price * (1 + params.Float64("target_ratio", 12.00)/100), // 目标涨幅 12%
 			StrategyCode: m.Code(),
 			StrategyName: m.Name(),
 		})
@@ -0,0 +1,15 @@
+package strategy
+
+import (
+	"gitee.com/quant1x/pandas"
+
+	"xquant/pkg/datasource/base"
+)
+
+// 捡出截止评估日期的日K线
+//
+//	锚定评估日期前复权, 评估日期的价格等于实际价格, 可以和快照的价格直接比较
+func checkoutKLine(securityCode, date string) pandas.DataFrame {
+	klines := base.CheckoutAdjustedKLines(securityCode, date, base.ForwardAdjustedAt(date))
+	return pandas.LoadStructs(klines)
+}
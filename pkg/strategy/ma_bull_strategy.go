@@ -26,6 +26,7 @@ func init() {
 //	1. 均线多头排列（MA5 > MA10 > MA20）
 //	2. 价格在均线上方（Price > MA5）
 //	3. 均线向上发散（MA5 持续上升）
+//	4. 可选周线共振（周线 MA5 > MA10 > MA20）
 type ModelMABull struct {
 }
 
@@ -41,6 +42,19 @@ func (m ModelMABull) OrderFlag() string {
 	return models.OrderFlagTail
 }
 
+// Metadata 策略元数据
+func (m ModelMABull) Metadata() models.StrategyMetadata {
+	return models.StrategyMetadata{
+		Description: "均线多头排列(MA5>MA10>MA20), 价格在MA5之上并且MA5向上",
+		Version:     models.DefaultStrategyVersion,
+		Features:    []string{"history", "day"},
+		Parameters: []models.ParameterSchema{
+			{Name: "target_ratio", Type: models.ParameterTypeFloat, Default: "10.00", Description: "目标涨跌幅(%), 用于计算目标价格"},
+			{Name: "weekly_align", Type: models.ParameterTypeBool, Default: "false", Description: "是否要求周线多头排列"},
+		},
+	}
+}
+
 func (m ModelMABull) Filter(ruleParameter config.RuleParameter, snapshot factors.QuoteSnapshot) error {
 	return ChainFilters(GeneralFilter)(ruleParameter, snapshot)
 }
@@ -49,42 +63,32 @@ func (m ModelMABull) Sort(snapshots []factors.QuoteSnapshot) models.SortedStatus
 	return models.SortDefault
 }
 
-func (m ModelMABull) Evaluate(securityCode string, result *concurrent.TreeMap[string, models.ResultInfo]) {
-	// 1. 获取历史数据
-	history := factors.GetL5History(securityCode)
+func (m ModelMABull) Evaluate(securityCode string, snapshot factors.QuoteSnapshot, result *concurrent.TreeMap[string, models.ResultInfo]) {
+	m.EvaluateMultiTimeframe(securityCode, models.NewMultiTimeframe(securityCode, &snapshot), result)
+}
+
+// EvaluateMultiTimeframe 实现 models.MultiTimeframeStrategy 接口, 日线信号可以要求周线共振
+func (m ModelMABull) EvaluateMultiTimeframe(securityCode string, mtf *models.MultiTimeframe, result *concurrent.TreeMap[string, models.ResultInfo]) {
+	params := models.LoadStrategyParams(m)
+	// 1. 获取历史数据和快照, 和多周期上下文保持同一个日期
+	history := mtf.Daily
 	if history == nil {
 		return
 	}
-
-	// 2. 获取策略快照
-	snapshot := models.SnapshotMgr.GetStrategySnapshot(securityCode)
+	snapshot := mtf.Snapshot
 	if snapshot == nil {
 		return
 	}
 
-	// 3. 计算增量均线
-	ma5 := realtime.IncrementalMovingAverage(history.MA4, 5, snapshot.Price)
-	ma10 := realtime.IncrementalMovingAverage(history.MA9, 10, snapshot.Price)
-	ma20 := realtime.IncrementalMovingAverage(history.MA19, 20, snapshot.Price)
-
-	// 4. 判断均线多头排列：MA5 > MA10 > MA20
-	isBullAlignment := ma5 > ma10 && ma10 > ma20
-
-	// 5. 判断价格在均线上方：Price > MA5
-	isPriceAboveMA5 := snapshot.Price > ma5
-
-	// 6. 获取 K 线数据，判断均线是否向上发散
-	df := factors.BasicKLine(securityCode)
+	// 2. 获取截止评估日期的K线数据, 计算前一日均线
+	df := checkoutKLine(securityCode, mtf.Date)
 	if df.Nrow() < 6 {
 		return
 	}
-
 	CLOSE := df.ColAsNDArray("close")
 	if CLOSE.Len() < 6 {
 		return
 	}
-
-	// 7. 计算前一日均线，判断是否上升趋势
 	prevCLOSE := REF(CLOSE, 1)
 	if prevCLOSE.Len() < 5 {
 		return
@@ -92,11 +96,8 @@ func (m ModelMABull) Evaluate(securityCode string, result *concurrent.TreeMap[st
 	prevMA5 := MA(prevCLOSE, 5)
 	prevMA5Value := utils.Float64IndexOf(prevMA5, -1)
 
-	// 8. 判断 MA5 是否上升
-	isMA5Rising := ma5 > prevMA5Value
-
-	// 9. 如果满足所有条件，加入结果
-	if isBullAlignment && isPriceAboveMA5 && isMA5Rising {
+	// 3. 如果满足所有条件，加入结果
+	if maBullMatched(history, *snapshot, prevMA5Value, mtf.Weekly, params.Bool("weekly_align", false)) {
 		price := snapshot.Price
 		date := snapshot.Date
 		result.Put(securityCode, models.ResultInfo{
@@ -105,9 +106,37 @@ func (m ModelMABull) Evaluate(securityCode string, result *concurrent.TreeMap[st
 			Date:         date,
 			Rate:         0.00,
 			Buy:          price,
-			Sell:         price * 1.10, // 目标涨幅 10%
+			Sell:         price * (1 + params.Float64("target_ratio", 10.00)/100), // 目标涨幅 10%
 			StrategyCode: m.Code(),
 			StrategyName: m.Name(),
 		})
 	}
 }
+
+// maBullMatched 判断是否满足均线多头排列的买入条件
+//
+//	weeklyAlign为true时周线必须多头排列, 没有周线数据视为不满足
+func maBullMatched(history *factors.History, snapshot factors.QuoteSnapshot, prevMA5 float64, weekly *factors.PeriodHistory, weeklyAlign bool) bool {
+	// 1. 计算增量均线
+	ma5 := realtime.IncrementalMovingAverage(history.MA4, 5, snapshot.Price)
+	ma10 := realtime.IncrementalMovingAverage(history.MA9, 10, snapshot.Price)
+	ma20 := realtime.IncrementalMovingAverage(history.MA19, 20, snapshot.Price)
+
+	// 2. 判断均线多头排列：MA5 > MA10 > MA20
+	isBullAlignment := ma5 > ma10 && ma10 > ma20
+
+	// 3. 判断价格在均线上方：Price > MA5
+	isPriceAboveMA5 := snapshot.Price > ma5
+
+	// 4. 判断 MA5 是否上升
+	isMA5Rising := ma5 > prevMA5
+
+	if !isBullAlignment || !isPriceAboveMA5 || !isMA5Rising {
+		return false
+	}
+	// 5. 周线共振
+	if weeklyAlign {
+		return weekly != nil && weekly.IsBull()
+	}
+	return true
+}
@@ -0,0 +1,32 @@
+package strategy
+
+import (
+	"testing"
+
+	"xquant/pkg/factors"
+)
+
+func Test_maBullMatched(t *testing.T) {
+	// 日线: MA5=10.2 > MA10=9.65 > MA20=9.1, 价格在MA5之上, MA5向上
+	history := &factors.History{MA4: 10, MA9: 9.5, MA19: 9}
+	snapshot := factors.QuoteSnapshot{SecurityCode: "sh600600", Price: 11}
+	prevMA5 := 10.0
+	weeklyBull := &factors.PeriodHistory{Period: factors.PeriodWeekly, MA5: 12, MA10: 11, MA20: 10}
+	weeklyBear := &factors.PeriodHistory{Period: factors.PeriodWeekly, MA5: 10, MA10: 11, MA20: 12}
+	tests := []struct {
+		name        string
+		weekly      *factors.PeriodHistory
+		weeklyAlign bool
+		want        bool
+	}{
+		{"不要求周线共振", weeklyBear, false, true},
+		{"周线多头排列", weeklyBull, true, true},
+		{"周线空头排列", weeklyBear, true, false},
+		{"没有周线数据", nil, true, false},
+	}
+	for _, tt := range tests {
+		if got := maBullMatched(history, snapshot, prevMA5, tt.weekly, tt.weeklyAlign); got != tt.want {
+			t.Errorf("%s: got=%t, want=%t", tt.name, got, tt.want)
+		}
+	}
+}
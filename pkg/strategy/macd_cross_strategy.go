@@ -40,6 +40,18 @@ func (m ModelMacdCross) OrderFlag() string {
 	return models.OrderFlagTail
 }
 
+// Metadata 策略元数据
+func (m ModelMacdCross) Metadata() models.StrategyMetadata {
+	return models.StrategyMetadata{
+		Description: "MACD金叉(DIF上穿DEA), MACD柱转正, 并且价格在MA20之上",
+		Version:     models.DefaultStrategyVersion,
+		Features:    []string{"history", "day"},
+		Parameters: []models.ParameterSchema{
+			{Name: "target_ratio", Type: models.ParameterTypeFloat, Default: "8.00", Description: "目标涨跌幅(%), 用于计算目标价格"},
+		},
+	}
+}
+
 func (m ModelMacdCross) Filter(ruleParameter config.RuleParameter, snapshot factors.QuoteSnapshot) error {
 	return ChainFilters(GeneralFilter)(ruleParameter, snapshot)
 }
@@ -48,21 +60,16 @@ func (m ModelMacdCross) Sort(snapshots []factors.QuoteSnapshot) models.SortedSta
 	return models.SortDefault
 }
 
-func (m ModelMacdCross) Evaluate(securityCode string, result *concurrent.TreeMap[string, models.ResultInfo]) {
-	// 1. 获取历史数据
-	history := factors.GetL5History(securityCode)
+func (m ModelMacdCross) Evaluate(securityCode string, snapshot factors.QuoteSnapshot, result *concurrent.TreeMap[string, models.ResultInfo]) {
+	params := models.LoadStrategyParams(m)
+	// 1. 获取评估日期的历史数据
+	history := factors.GetL5History(securityCode, snapshot.Date)
 	if history == nil {
 		return
 	}
 
-	// 2. 获取策略快照
-	snapshot := models.SnapshotMgr.GetStrategySnapshot(securityCode)
-	if snapshot == nil {
-		return
-	}
-
-	// 3. 获取 K 线数据计算 MACD
-	df := factors.BasicKLine(securityCode)
+	// 2. 获取截止评估日期的K线数据计算 MACD
+	df := checkoutKLine(securityCode, snapshot.Date)
 	if df.Nrow() < 30 {
 		return
 	}
@@ -72,10 +79,10 @@ func (m ModelMacdCross) Evaluate(securityCode string, result *concurrent.TreeMap
 		return
 	}
 
-	// 4. 计算 MACD（12, 26, 9）
+	// 3. 计算 MACD（12, 26, 9）
 	_, _, dif, dea, macd := realtime.MovingAverageConvergenceDivergence(CLOSE, 12, 26, 9)
 
-	// 5. 获取前一日 MACD 值（用于判断金叉）
+	// 4. 获取前一日 MACD 值（用于判断金叉）
 	// 使用 REF 函数获取前一日收盘价序列
 	prevCLOSE := REF(CLOSE, 1)
 	if prevCLOSE.Len() < 30 {
@@ -84,17 +91,17 @@ func (m ModelMacdCross) Evaluate(securityCode string, result *concurrent.TreeMap
 	// 计算前一日MACD
 	_, _, prevDIF, prevDEA, _ := realtime.MovingAverageConvergenceDivergence(prevCLOSE, 12, 26, 9)
 
-	// 6. 判断 MACD 金叉：DIF 上穿 DEA
+	// 5. 判断 MACD 金叉：DIF 上穿 DEA
 	isGoldenCross := prevDIF <= prevDEA && dif > dea
 
-	// 7. 判断 MACD 柱状图转正：MACD > 0
+	// 6. 判断 MACD 柱状图转正：MACD > 0
 	isMacdPositive := macd > 0
 
-	// 8. 判断价格在均线上方：Price > MA20
+	// 7. 判断价格在均线上方：Price > MA20
 	ma20 := realtime.IncrementalMovingAverage(history.MA19, 20, snapshot.Price)
 	isPriceAboveMA20 := snapshot.Price > ma20
 
-	// 9. 如果满足所有条件，加入结果
+	// 8. 如果满足所有条件，加入结果
 	if isGoldenCross && isMacdPositive && isPriceAboveMA20 {
 		price := snapshot.Price
 		date := snapshot.Date
@@ -104,7 +111,7 @@ func (m ModelMacdCross) Evaluate(securityCode string, result *concurrent.TreeMap
 			Date:         date,
 			Rate:         0.00,
 			Buy:          price,
-			Sell:         price * 1.08, // 目标涨幅 8%
+			Sell:         price * (1 + params.Float64("target_ratio", 8.00)/100), // 目标涨幅 8%
 			StrategyCode: m.Code(),
 			StrategyName: m.Name(),
 		})
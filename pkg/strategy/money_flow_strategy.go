@@ -0,0 +1,141 @@
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"gitee.com/quant1x/gotdx/securities"
+	"gitee.com/quant1x/gox/concurrent"
+	"gitee.com/quant1x/gox/logger"
+
+	"xquant/pkg/config"
+	"xquant/pkg/factors"
+	"xquant/pkg/models"
+)
+
+func init() {
+	err := models.Register(ModelMoneyFlow{})
+	if err != nil {
+		logger.Fatalf("注册资金流入策略失败: %+v", err)
+	}
+}
+
+var (
+	ErrFundFlowNotExist = errors.New("没有找到资金流向数据")
+)
+
+// ModelMoneyFlow 10号策略：资金流入策略
+//
+//	策略逻辑：
+//	1. 5日主力净流入不低于阈值（默认3000万）
+//	2. 3日主力净流入为正, 且超大单5日净流入为正
+//	3. 主力连续净流入不少于N天（默认2天）
+//	4. 按5日主力净流入降序排列
+//
+//	资金流向取截至前一个交易日的特征数据, 回测和实盘使用同一套过滤逻辑
+type ModelMoneyFlow struct {
+}
+
+func (m ModelMoneyFlow) Code() models.ModelKind {
+	return models.ModelNo10
+}
+
+func (m ModelMoneyFlow) Name() string {
+	return "资金流入策略"
+}
+
+func (m ModelMoneyFlow) OrderFlag() string {
+	return models.OrderFlagTail
+}
+
+// Metadata 策略元数据
+func (m ModelMoneyFlow) Metadata() models.StrategyMetadata {
+	return models.StrategyMetadata{
+		Description: "主力资金持续净流入, 5日主力净流入超过阈值, 按5日主力净流入排序",
+		Version:     models.DefaultStrategyVersion,
+		Features:    []string{"fundflow", "history"},
+		Parameters: []models.ParameterSchema{
+			{Name: "target_ratio", Type: models.ParameterTypeFloat, Default: "8.00", Description: "目标涨跌幅(%), 用于计算目标价格"},
+			{Name: "main5_min", Type: models.ParameterTypeFloat, Default: "3000", Description: "5日主力净流入的最小值(万元)"},
+			{Name: "inflow_days_min", Type: models.ParameterTypeInt, Default: "2", Description: "主力连续净流入的最少天数"},
+		},
+	}
+}
+
+// moneyFlowFilter 资金流向过滤器
+func moneyFlowFilter(params models.StrategyParams) FilterFunc {
+	main5Min := params.Float64("main5_min", 3000) * config.TenThousand
+	inflowDaysMin := params.Int("inflow_days_min", 2)
+	return func(ruleParameter config.RuleParameter, snapshot factors.QuoteSnapshot) error {
+		fundFlow := factors.GetL5FundFlow(snapshot.SecurityCode, snapshot.Date)
+		if fundFlow == nil {
+			return ErrFundFlowNotExist
+		}
+		return checkMoneyFlow(fundFlow, main5Min, inflowDaysMin)
+	}
+}
+
+// checkMoneyFlow 检查资金流向是否满足策略条件
+func checkMoneyFlow(fundFlow *factors.FundFlow, main5Min float64, inflowDaysMin int) error {
+	if fundFlow.Main5 < main5Min {
+		return fmt.Errorf("5日主力净流入 %.2f万 低于 %.2f万", fundFlow.Main5/config.TenThousand, main5Min/config.TenThousand)
+	}
+	if fundFlow.Main3 <= 0 || fundFlow.SuperLarge5 <= 0 {
+		return fmt.Errorf("3日主力净流入 %.2f万, 5日超大单净流入 %.2f万", fundFlow.Main3/config.TenThousand, fundFlow.SuperLarge5/config.TenThousand)
+	}
+	if fundFlow.MainInflowDays < inflowDaysMin {
+		return fmt.Errorf("主力连续净流入 %d天 少于 %d天", fundFlow.MainInflowDays, inflowDaysMin)
+	}
+	return nil
+}
+
+func (m ModelMoneyFlow) Filter(ruleParameter config.RuleParameter, snapshot factors.QuoteSnapshot) error {
+	params := models.LoadStrategyParams(m)
+	return ChainFilters(GeneralFilter, moneyFlowFilter(params))(ruleParameter, snapshot)
+}
+
+func (m ModelMoneyFlow) Sort(snapshots []factors.QuoteSnapshot) models.SortedStatus {
+	main5 := make(map[string]float64, len(snapshots))
+	for _, v := range snapshots {
+		if fundFlow := factors.GetL5FundFlow(v.SecurityCode, v.Date); fundFlow != nil {
+			main5[v.SecurityCode] = fundFlow.Main5
+		}
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return main5[snapshots[i].SecurityCode] > main5[snapshots[j].SecurityCode]
+	})
+	return models.SortFinished
+}
+
+func (m ModelMoneyFlow) Evaluate(securityCode string, snapshot factors.QuoteSnapshot, result *concurrent.TreeMap[string, models.ResultInfo]) {
+	params := models.LoadStrategyParams(m)
+	if snapshot.Price <= 0 {
+		return
+	}
+	// 1. 获取评估日期的资金流向, 和过滤器、排序使用同一个日期
+	fundFlow := factors.GetL5FundFlow(securityCode, snapshot.Date)
+	if fundFlow == nil {
+		return
+	}
+
+	// 2. 判断资金流向
+	main5Min := params.Float64("main5_min", 3000) * config.TenThousand
+	inflowDaysMin := params.Int("inflow_days_min", 2)
+	if checkMoneyFlow(fundFlow, main5Min, inflowDaysMin) != nil {
+		return
+	}
+
+	// 3. 加入结果
+	price := snapshot.Price
+	result.Put(securityCode, models.ResultInfo{
+		Code:         securityCode,
+		Name:         securities.GetStockName(securityCode),
+		Date:         snapshot.Date,
+		Rate:         0.00,
+		Buy:          price,
+		Sell:         price * (1 + params.Float64("target_ratio", 8.00)/100),
+		StrategyCode: m.Code(),
+		StrategyName: m.Name(),
+	})
+}
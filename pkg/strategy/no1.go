@@ -9,7 +9,6 @@ import (
 
 	"xquant/pkg/config"
 	"xquant/pkg/factors"
-	"xquant/pkg/models"
 	"xquant/pkg/realtime"
 	"xquant/pkg/utils"
 )
@@ -54,15 +53,11 @@ func (m ModelNo1) Sort(snapshots []factors.QuoteSnapshot) SortedStatus {
 	return SortDefault
 }
 
-func (m ModelNo1) Evaluate(securityCode string, result *concurrent.TreeMap[string, ResultInfo]) {
-	history := factors.GetL5History(securityCode)
+func (m ModelNo1) Evaluate(securityCode string, snapshot factors.QuoteSnapshot, result *concurrent.TreeMap[string, ResultInfo]) {
+	history := factors.GetL5History(securityCode, snapshot.Date)
 	if history == nil {
 		return
 	}
-	snapshot := models.SnapshotMgr.GetStrategySnapshot(securityCode)
-	if snapshot == nil {
-		return
-	}
 
 	// 取出昨日的数据
 	r1MA5 := history.MA5
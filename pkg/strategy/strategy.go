@@ -90,7 +90,9 @@ type Strategy interface {
 	// Sort 排序
 	Sort([]factors.QuoteSnapshot) SortedStatus
 	// Evaluate 评估 日线数据
-	Evaluate(securityCode string, result *concurrent.TreeMap[string, ResultInfo])
+	//
+	//	snapshot为被评估的快照, 历史数据和K线必须截止到snapshot.Date, 不能读取实时数据
+	Evaluate(securityCode string, snapshot factors.QuoteSnapshot, result *concurrent.TreeMap[string, ResultInfo])
 }
 
 // QmtStrategyName 获取用于QMT系统的策略名称
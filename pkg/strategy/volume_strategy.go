@@ -41,6 +41,18 @@ func (m ModelVolume) OrderFlag() string {
 	return models.OrderFlagTail
 }
 
+// Metadata 策略元数据
+func (m ModelVolume) Metadata() models.StrategyMetadata {
+	return models.StrategyMetadata{
+		Description: "下跌趋势后放量上涨, 成交量大于之前均量的5倍, 买入信号",
+		Version:     models.DefaultStrategyVersion,
+		Features:    []string{"history", "day"},
+		Parameters: []models.ParameterSchema{
+			{Name: "target_ratio", Type: models.ParameterTypeFloat, Default: "12.00", Description: "目标涨跌幅(%), 用于计算目标价格"},
+		},
+	}
+}
+
 func (m ModelVolume) Filter(ruleParameter config.RuleParameter, snapshot factors.QuoteSnapshot) error {
 	return ChainFilters(GeneralFilter)(ruleParameter, snapshot)
 }
@@ -49,21 +61,16 @@ func (m ModelVolume) Sort(snapshots []factors.QuoteSnapshot) models.SortedStatus
 	return models.SortDefault
 }
 
-func (m ModelVolume) Evaluate(securityCode string, result *concurrent.TreeMap[string, models.ResultInfo]) {
-	// 1. 获取历史数据
-	history := factors.GetL5History(securityCode)
+func (m ModelVolume) Evaluate(securityCode string, snapshot factors.QuoteSnapshot, result *concurrent.TreeMap[string, models.ResultInfo]) {
+	params := models.LoadStrategyParams(m)
+	// 1. 获取评估日期的历史数据
+	history := factors.GetL5History(securityCode, snapshot.Date)
 	if history == nil {
 		return
 	}
 
-	// 2. 获取策略快照
-	snapshot := models.SnapshotMgr.GetStrategySnapshot(securityCode)
-	if snapshot == nil {
-		return
-	}
-
-	// 3. 获取 K 线数据
-	df := factors.BasicKLine(securityCode)
+	// 2. 获取截止评估日期的K线数据
+	df := checkoutKLine(securityCode, snapshot.Date)
 	if df.Nrow() < 10 {
 		return
 	}
@@ -76,12 +83,12 @@ func (m ModelVolume) Evaluate(securityCode string, result *concurrent.TreeMap[st
 		return
 	}
 
-	// 4. 判断是否满足放量上涨形态
+	// 3. 判断是否满足放量上涨形态
 	if !m.isVolumePattern(OPEN, CLOSE, VOL) {
 		return
 	}
 
-	// 5. 如果满足条件，加入结果（买入信号）
+	// 4. 如果满足条件，加入结果（买入信号）
 	price := snapshot.Price
 	date := snapshot.Date
 	result.Put(securityCode, models.ResultInfo{
@@ -90,7 +97,7 @@ func (m ModelVolume) Evaluate(securityCode string, result *concurrent.TreeMap[st
 		Date:         date,
 		Rate:         0.00,
 		Buy:          price,
-		Sell:         price * 1.12, // 目标涨幅 12%
+		Sell:         price * (1 + params.Float64("target_ratio", 12.00)/100), // 目标涨幅 12%
 		StrategyCode: m.Code(),
 		StrategyName: m.Name(),
 	})
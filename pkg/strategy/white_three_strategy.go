@@ -41,6 +41,18 @@ func (m ModelWhiteThree) OrderFlag() string {
 	return models.OrderFlagTail
 }
 
+// Metadata 策略元数据
+func (m ModelWhiteThree) Metadata() models.StrategyMetadata {
+	return models.StrategyMetadata{
+		Description: "连续3根光头光脚的阳线且收盘价逐日升高, 买入信号",
+		Version:     models.DefaultStrategyVersion,
+		Features:    []string{"history", "day"},
+		Parameters: []models.ParameterSchema{
+			{Name: "target_ratio", Type: models.ParameterTypeFloat, Default: "10.00", Description: "目标涨跌幅(%), 用于计算目标价格"},
+		},
+	}
+}
+
 func (m ModelWhiteThree) Filter(ruleParameter config.RuleParameter, snapshot factors.QuoteSnapshot) error {
 	return ChainFilters(GeneralFilter)(ruleParameter, snapshot)
 }
@@ -49,21 +61,16 @@ func (m ModelWhiteThree) Sort(snapshots []factors.QuoteSnapshot) models.SortedSt
 	return models.SortDefault
 }
 
-func (m ModelWhiteThree) Evaluate(securityCode string, result *concurrent.TreeMap[string, models.ResultInfo]) {
-	// 1. 获取历史数据
-	history := factors.GetL5History(securityCode)
+func (m ModelWhiteThree) Evaluate(securityCode string, snapshot factors.QuoteSnapshot, result *concurrent.TreeMap[string, models.ResultInfo]) {
+	params := models.LoadStrategyParams(m)
+	// 1. 获取评估日期的历史数据
+	history := factors.GetL5History(securityCode, snapshot.Date)
 	if history == nil {
 		return
 	}
 
-	// 2. 获取策略快照
-	snapshot := models.SnapshotMgr.GetStrategySnapshot(securityCode)
-	if snapshot == nil {
-		return
-	}
-
-	// 3. 获取 K 线数据
-	df := factors.BasicKLine(securityCode)
+	// 2. 获取截止评估日期的K线数据
+	df := checkoutKLine(securityCode, snapshot.Date)
 	if df.Nrow() < 3 {
 		return
 	}
@@ -77,12 +84,12 @@ func (m ModelWhiteThree) Evaluate(securityCode string, result *concurrent.TreeMa
 		return
 	}
 
-	// 4. 判断是否是白色三兵形态
+	// 3. 判断是否是白色三兵形态
 	if !m.isWhiteThree(OPEN, CLOSE, HIGH, LOW) {
 		return
 	}
 
-	// 5. 如果满足条件，加入结果（买入信号）
+	// 4. 如果满足条件，加入结果（买入信号）
 	price := snapshot.Price
 	date := snapshot.Date
 	result.Put(securityCode, models.ResultInfo{
@@ -91,7 +98,7 @@ func (m ModelWhiteThree) Evaluate(securityCode string, result *concurrent.TreeMa
 		Date:         date,
 		Rate:         0.00,
 		Buy:          price,
-		Sell:         price * 1.10, // 目标涨幅 10%
+		Sell:         price * (1 + params.Float64("target_ratio", 10.00)/100), // 目标涨幅 10%
 		StrategyCode: m.Code(),
 		StrategyName: m.Name(),
 	})
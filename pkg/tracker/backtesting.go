@@ -15,9 +15,11 @@ import (
 
 	"xquant/pkg/cache"
 	"xquant/pkg/config"
+	"xquant/pkg/datasource/base"
 	"xquant/pkg/factors"
 	"xquant/pkg/market"
 	"xquant/pkg/models"
+	"xquant/pkg/panel"
 	"xquant/pkg/storages"
 )
 
@@ -70,6 +72,63 @@ func checkWideOffset(klines []factors.SecurityFeature, date string) (offset int)
 	return
 }
 
+// backtestFill 回测的成交价格, 全部是不复权的实际价格
+//
+//	宽表是前复权的, 只用于计算信号; 买入和卖出按当日的实际价格成交
+type backtestFill struct {
+	LastClose float64               // 昨收, 测试日期除权除息时为除权除息参考价
+	Open      float64               // 开盘价
+	Price     float64               // 收盘价
+	NextDate  string                // 下一个交易日
+	NextOpen  float64               // 下一个交易日开盘价
+	NextClose float64               // 下一个交易日收盘价
+	NextHigh  float64               // 下一个交易日最高价
+	NextLow   float64               // 下一个交易日最低价
+	ExRights  factors.DividendEvent // 持有到下一个交易日期间发生的除权除息
+	HasRights bool                  // 是否有除权除息
+}
+
+// newBacktestFill 从不复权的K线中取测试日期和下一个交易日的成交价格
+func newBacktestFill(klines []base.KLine, dividends []factors.DividendEvent, testDate string) (fill backtestFill, ok bool) {
+	pos := -1
+	for i := len(klines) - 1; i >= 0; i-- {
+		if klines[i].Date == testDate {
+			pos = i
+			break
+		} else if klines[i].Date < testDate {
+			break
+		}
+	}
+	if pos < 0 {
+		return fill, false
+	}
+	kline := klines[pos]
+	fill.Open = kline.Open
+	fill.Price = kline.Close
+	if pos > 0 {
+		fill.LastClose = klines[pos-1].Close
+		if event, ok := factors.DividendOnDate(dividends, testDate); ok {
+			fill.LastClose = event.AdjustPrice(fill.LastClose)
+		}
+	}
+	if pos+1 < len(klines) {
+		next := klines[pos+1]
+		fill.NextDate = next.Date
+		fill.NextOpen = next.Open
+		fill.NextClose = next.Close
+		fill.NextHigh = next.High
+		fill.NextLow = next.Low
+		for _, v := range dividends {
+			if v.ExDate > testDate && v.ExDate <= next.Date {
+				fill.ExRights = v
+				fill.HasRights = true
+				break
+			}
+		}
+	}
+	return fill, true
+}
+
 // BackTesting 回测
 func BackTesting(strategyNo uint64, countDays, countTopN int) {
 	currentlyDay := exchange.GetCurrentlyDay()
@@ -95,13 +154,26 @@ func BackTesting(strategyNo uint64, countDays, countTopN int) {
 	var gcs []GoodCase
 	dates = dates[s : e+1]
 	codes := market.GetCodeList()
-	mapStock := map[string][]factors.SecurityFeature{}
+	// 宽表由面板数据共享, 多次回测和参数寻优不重复加载
+	store := panel.Default()
+	// 除权除息日历, 按证券代码缓存
+	mapDividends := map[string][]factors.DividendEvent{}
+	checkoutDividends := func(securityCode string) []factors.DividendEvent {
+		list, ok := mapDividends[securityCode]
+		if !ok {
+			list = factors.DividendEvents(securityCode)
+			mapDividends[securityCode] = list
+		}
+		return list
+	}
 	for i, date := range dates {
 		testDate := date
-		// 切换策略数据的缓存日期
-		factors.SwitchDate(testDate)
+		// 持有测试日期的特征视图, 规则按快照日期读取特征, 不切换全局缓存日期
+		view := factors.Acquire(testDate)
 		var marketPrices []float64
 		var stockSnapshots []factors.QuoteSnapshot
+		// 不复权的成交价格
+		fills := map[string]backtestFill{}
 		total := len(codes)
 		//pos := 0
 		bar := progressbar.NewBar(1, "执行["+testDate+"涨幅扫描]", total)
@@ -111,14 +183,9 @@ func BackTesting(strategyNo uint64, countDays, countTopN int) {
 				continue
 			}
 			//features := factors.CheckoutWideTableByDate(securityCode, date)
-			features, ok := mapStock[securityCode]
-			if !ok {
-				filename := cache.WideFilename(securityCode)
-				err := api.CsvToSlices(filename, &features)
-				if err != nil {
-					continue
-				}
-				mapStock[securityCode] = features
+			features := store.WideTable(securityCode)
+			if len(features) == 0 {
+				continue
 			}
 			if securityCode == backTestingParameter.TargetIndex && len(marketPrices) == 0 {
 				for _, m := range features {
@@ -143,6 +210,16 @@ func BackTesting(strategyNo uint64, countDays, countTopN int) {
 				// 停牌导致的日期无法从后往前对齐
 				continue
 			}
+			// 信号用前复权的宽表, 成交用不复权的实际价格
+			var dividends []factors.DividendEvent
+			if exchange.AssertStockBySecurityCode(securityCode) {
+				dividends = checkoutDividends(securityCode)
+			}
+			fill, ok := newBacktestFill(base.LoadAdjustedKLines(securityCode, base.Unadjusted), dividends, testDate)
+			if !ok {
+				continue
+			}
+			fills[securityCode] = fill
 			snapshot := models.FeatureToSnapshot(feature, securityCode)
 			// 下一个交易日开盘价
 			diffDays := 1
@@ -161,6 +238,7 @@ func BackTesting(strategyNo uint64, countDays, countTopN int) {
 		}
 		bar.Wait()
 		if len(stockSnapshots) == 0 {
+			view.Release()
 			continue
 		}
 
@@ -189,44 +267,60 @@ func BackTesting(strategyNo uint64, countDays, countTopN int) {
 		var samples []SampleFeature
 		for _, snapshot := range stockSnapshots {
 			securityCode := snapshot.SecurityCode
+			fill := fills[securityCode]
+			// 早盘按开盘价买入, 其它按现价买入, 买入价为涨停价时视为无法成交
+			buyPrice := fill.Price
+			if tradeRule.Flag == models.OrderFlagHead {
+				buyPrice = fill.Open
+			}
+			if factors.CheckoutPriceLimit(securityCode, testDate, fill.LastClose).IsLimitUp(buyPrice) {
+				continue
+			}
 			// 获取证券名称
 			securityName := "unknown"
-			f10 := factors.GetL5F10(securityCode, testDate)
+			f10 := view.F10(securityCode)
 			if f10 != nil {
 				securityName = f10.SecurityName
 			}
 
+			// 持仓成本, 持有到下一个交易日期间除权除息时按分红送转调整
+			openCost, priceCost := fill.Open, fill.Price
+			if fill.HasRights {
+				openCost = fill.ExRights.AdjustPrice(openCost)
+				priceCost = fill.ExRights.AdjustPrice(priceCost)
+			}
 			sample := SampleFeature{
 				Name:              securityName,
 				SecurityCode:      securityCode,
 				OpenQuantityRatio: snapshot.OpenQuantityRatio,
 				OpenTurnZ:         snapshot.OpenTurnZ,
-				OpenChangeRate:    num.NetChangeRate(snapshot.LastClose, snapshot.Open),
-				LastClose:         snapshot.LastClose,
-				Open:              snapshot.Open,
-				Price:             snapshot.Price,
-				UpRate:            num.NetChangeRate(snapshot.LastClose, snapshot.Price),
-				OpenPremiumRate:   num.NetChangeRate(snapshot.Open, snapshot.Price),
-				NextPremiumRate:   num.NetChangeRate(snapshot.Open, snapshot.NextOpen),
+				OpenChangeRate:    num.NetChangeRate(fill.LastClose, fill.Open),
+				LastClose:         fill.LastClose,
+				Open:              fill.Open,
+				Price:             fill.Price,
+				UpRate:            num.NetChangeRate(fill.LastClose, fill.Price),
+				OpenPremiumRate:   num.NetChangeRate(fill.Open, fill.Price),
+				NextPremiumRate:   num.NetChangeRate(openCost, fill.NextOpen),
 			}
 			switch tradeRule.Flag {
 			case models.OrderFlagHead:
-				sample.OpenPremiumRate = num.NetChangeRate(snapshot.Open, snapshot.Price)
-				sample.NextPremiumRate = num.NetChangeRate(snapshot.Open, snapshot.NextOpen)
+				sample.OpenPremiumRate = num.NetChangeRate(fill.Open, fill.Price)
+				sample.NextPremiumRate = num.NetChangeRate(openCost, fill.NextOpen)
 			case models.OrderFlagTail:
-				sample.OpenPremiumRate = num.NetChangeRate(snapshot.Price, snapshot.Price)
-				sample.NextPremiumRate = num.NetChangeRate(snapshot.Price, snapshot.NextClose)
-				if snapshot.Price < snapshot.NextClose && snapshot.Price*(1+backTestingParameter.NextPremiumRate+0.005) < snapshot.NextHigh {
-					sample.NextPremiumRate = num.NetChangeRate(snapshot.Price, snapshot.Price*(1+backTestingParameter.NextPremiumRate))
+				sample.OpenPremiumRate = num.NetChangeRate(fill.Price, fill.Price)
+				sample.NextPremiumRate = num.NetChangeRate(priceCost, fill.NextClose)
+				if priceCost < fill.NextClose && priceCost*(1+backTestingParameter.NextPremiumRate+0.005) < fill.NextHigh {
+					sample.NextPremiumRate = num.NetChangeRate(priceCost, priceCost*(1+backTestingParameter.NextPremiumRate))
 				}
 			case models.OrderFlagTick:
-				sample.OpenPremiumRate = num.NetChangeRate(snapshot.Price, snapshot.Price)
-				sample.NextPremiumRate = num.NetChangeRate(snapshot.Price, snapshot.NextClose)
+				sample.OpenPremiumRate = num.NetChangeRate(fill.Price, fill.Price)
+				sample.NextPremiumRate = num.NetChangeRate(priceCost, fill.NextClose)
 			}
 			sample.Beta = snapshot.Beta
 			sample.Alpha = snapshot.Alpha
 			samples = append(samples, sample)
 		}
+		view.Release()
 
 		// 单日回测结果
 		// 检查有效记录最大数
@@ -0,0 +1,45 @@
+package tracker
+
+import (
+	"math"
+	"testing"
+
+	"xquant/pkg/datasource/base"
+	"xquant/pkg/factors"
+)
+
+func Test_newBacktestFill(t *testing.T) {
+	klines := []base.KLine{
+		{Date: "2024-06-18", Open: 10.0, Close: 10.2, High: 10.3, Low: 9.9},
+		{Date: "2024-06-19", Open: 9.8, Close: 9.9, High: 10.0, Low: 9.7},
+		{Date: "2024-06-20", Open: 9.0, Close: 9.1, High: 9.2, Low: 8.9},
+	}
+	dividends := []factors.DividendEvent{
+		{ExDate: "2024-06-19", CashDividend: 0.2},
+		{ExDate: "2024-06-20", CashDividend: 0.5, BonusShares: 0.1},
+	}
+	fill, ok := newBacktestFill(klines, dividends, "2024-06-19")
+	if !ok {
+		t.Fatal("fill not found")
+	}
+	// 测试日期除息, 昨收为除息参考价
+	if math.Abs(fill.LastClose-10.0) > 1e-9 {
+		t.Errorf("last close = %v", fill.LastClose)
+	}
+	if fill.Open != 9.8 || fill.Price != 9.9 || fill.NextDate != "2024-06-20" || fill.NextOpen != 9.0 {
+		t.Errorf("fill = %+v", fill)
+	}
+	// 持有到下一个交易日期间除权除息
+	if !fill.HasRights || fill.ExRights.ExDate != "2024-06-20" {
+		t.Errorf("ex-rights = %+v", fill.ExRights)
+	}
+	// 停牌没有K线
+	if _, ok = newBacktestFill(klines, dividends, "2024-06-17"); ok {
+		t.Error("expect fill not found")
+	}
+	// 最后一个交易日没有下一个交易日的价格
+	fill, ok = newBacktestFill(klines, nil, "2024-06-20")
+	if !ok || fill.NextOpen != 0 || fill.HasRights {
+		t.Errorf("fill = %+v", fill)
+	}
+}
@@ -2,104 +2,28 @@ package tracker
 
 import (
 	"fmt"
-	"slices"
-	"strings"
-
-	"gitee.com/quant1x/exchange"
-	"gitee.com/quant1x/gotdx/securities"
-	"gitee.com/quant1x/gox/api"
-	"xquant/pkg/cache"
-	"xquant/pkg/config"
-	"xquant/pkg/factors"
-	"xquant/pkg/models"
 )
 
-// CheckStrategy 检查当前交易日中个股在策略中的执行情况
+// CheckStrategy 检查当前交易日中个股在策略中的执行情况, 控制台输出DiagnoseStrategy的结果
 func CheckStrategy(strategyCode uint64, securityCode, testDate string) {
-	fmt.Printf("\n策略检测中...\n")
-	// 1. 获取快照
-	name := securities.GetStockName(securityCode)
-	fmt.Printf("\t=> 证券代码: %s, 证券名称: %s...\n", securityCode, name)
-	fmt.Printf("\t=> 1. 获取tick[%s]...\n", securityCode)
-	testDate = strings.TrimSpace(testDate)
-	var snapshot *factors.QuoteSnapshot
-	if len(testDate) == 0 {
-		snapshot = models.GetTick(securityCode)
-		testDate = cache.DefaultCanReadDate()
-	} else {
-		testDate = exchange.FixTradeDate(testDate)
-		//cacheDate, featureDate := cache.CorrectDate(testDate)
-		features := factors.CheckoutWideTableByDate(securityCode, testDate)
-		rows := len(features)
-		if rows > 0 {
-			tick := models.FeatureToSnapshot(features[rows-1], securityCode)
-			snapshot = &tick
-			factors.SwitchDate(testDate)
+	v := DiagnoseStrategy(strategyCode, securityCode, testDate)
+	fmt.Printf("\n策略检测: 策略=%d %s, 证券=%s %s, 日期=%s\n", v.StrategyCode, v.StrategyName, v.SecurityCode, v.SecurityName, v.Date)
+	for _, rule := range v.Rules {
+		if rule.Ignored {
+			continue
 		}
-	}
-	fmt.Printf("\t=> 1. 获取tick[%s], date=%s...\n", securityCode, testDate)
-	if snapshot == nil {
-		fmt.Printf("\t=> 1. 获取tick[%s]...failed\n", securityCode)
-		return
-	}
-	fmt.Printf("\t=> 1. 获取tick[%s]...success\n", securityCode)
-
-	// 2. 获取策略配置
-	fmt.Printf("\t=> 2. 获取策略[%d]配置...\n", strategyCode)
-	strategyParameter := config.GetStrategyParameterByCode(strategyCode)
-	if strategyParameter == nil {
-		fmt.Printf("\t=> 2. 获取策略[%d]配置...not found\n", strategyCode)
-		return
-	}
-	fmt.Printf("\t=> 2. 获取策略[%d]配置...success\n", strategyCode)
-	fmt.Printf("\t=> 2. 获取策略[%d]配置, 策略名称=%s\n", strategyCode, strategyParameter.Name)
-
-	// 3. 检测板块及两融匹配
-	fmt.Printf("\t=> 3. 检测策略[%d]板块配置...\n", strategyCode)
-	fmt.Printf("\t=> 3. 检测策略[%d]板块配置...是否需要剔除两融...\n", strategyCode)
-	if strategyParameter.IgnoreMarginTrading {
-		fmt.Printf("\t=> 3. 检测策略[%d]板块配置...是否需要剔除两融, 需要\n", strategyCode)
-		// 过滤两融
-		marginTradingList := securities.MarginTradingList()
-		if len(marginTradingList) == 0 {
-			fmt.Printf("\t=> 3. 检测策略[%d]板块配置...是否需要剔除两融, 需要, 两融列表为空, 跳过检测\n", strategyCode)
-		} else if slices.Contains(marginTradingList, securityCode) {
-			fmt.Printf("\t=> 3. 检测策略[%d]板块配置...是否需要剔除两融, 需要, 检测失败: [%s]为两融标的,\n", strategyCode, securityCode)
-			return
+		status := "passed"
+		if !rule.Passed {
+			status = "failed: " + rule.Error
 		}
-	} else {
-		fmt.Printf("\t=> 3. 检测策略[%d]板块配置...是否需要剔除两融, 不需要\n", strategyCode)
-	}
-	fmt.Printf("\t=> 3. 检测策略[%d]板块配置...是否需要剔除两融...success\n", strategyCode)
-
-	// 4. 检测板块及两融匹配
-	fmt.Printf("\t=> 4. 检测策略[%d]板块是否匹配...\n", strategyCode)
-	stockList := strategyParameter.StockList()
-	if !slices.Contains(stockList, securityCode) {
-		fmt.Printf("\t=> 4. 检测策略[%d]板块是否匹配...失败, %s非策略配置的板块成分股\n", strategyCode, securityCode)
-		return
+		fmt.Printf("\t=> 规则[%d] %s...%s\n", rule.Kind, rule.Name, status)
 	}
-	fmt.Printf("\t=> 4. 检测策略[%d]板块是否匹配...success\n", strategyCode)
-
-	// 5. 获取策略对象
-	fmt.Printf("\t=> 5. 获取策略[%d]对象...\n", strategyCode)
-	model, err := models.CheckoutStrategy(strategyCode)
-	if err != nil {
-		fmt.Printf("\t=> 5. 获取策略[%d]对象...失败: %+v\n", strategyCode, err)
-		return
+	if v.Evaluate != nil {
+		fmt.Printf("\t=> 策略评估: 选中, 委托价格=%.2f, 目标价格=%.2f\n", v.Evaluate.Buy, v.Evaluate.Sell)
 	}
-	fmt.Printf("\t=> 5. 获取策略[%d]对象...success\n", strategyCode)
-
-	// 6. 执行过滤规则
-	fmt.Printf("\t=> 6. 执行策略[%d]过滤规则...\n", strategyCode)
-	var rules config.RuleParameter
-	_ = api.Copy(&rules, &strategyParameter.Rules)
-	//rules = strategyParameter.Rules
-	rules.Verbose = true
-	v := model.Filter(rules, *snapshot)
-	if v == nil {
-		fmt.Printf("\t=> 6. 执行策略[%d]过滤规则...passed\n", strategyCode)
+	if v.Passed {
+		fmt.Printf("\t=> 检测通过\n")
 	} else {
-		fmt.Printf("\t=> 6. 执行策略[%d]过滤规则...failed: %+v\n", strategyCode, v)
+		fmt.Printf("\t=> 检测失败, 步骤=%d: %s\n", v.FailedStep, v.Message)
 	}
 }
@@ -0,0 +1,253 @@
+package tracker
+
+import (
+	"slices"
+	"strings"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/gotdx/securities"
+	"gitee.com/quant1x/gox/api"
+	"gitee.com/quant1x/gox/concurrent"
+	"xquant/pkg/cache"
+	"xquant/pkg/config"
+	"xquant/pkg/factors"
+	"xquant/pkg/models"
+	"xquant/pkg/rules"
+)
+
+// 诊断步骤
+const (
+	DiagnosisStepSnapshot      = 1 // 获取快照
+	DiagnosisStepStrategy      = 2 // 获取策略配置
+	DiagnosisStepMarginTrading = 3 // 两融剔除检测
+	DiagnosisStepSector        = 4 // 板块成分检测
+	DiagnosisStepModel         = 5 // 获取策略对象
+	DiagnosisStepRules         = 6 // 执行过滤规则
+	DiagnosisStepEvaluate      = 7 // 执行策略评估
+)
+
+var (
+	diagnosisSnapshot  = checkoutDiagnosisSnapshot         // 获取诊断使用的快照
+	diagnosisParameter = config.GetStrategyParameterByCode // 获取策略配置
+)
+
+// MarginTradingVerdict 两融剔除检测结果
+type MarginTradingVerdict struct {
+	Required  bool   `json:"required"`  // 策略是否要求剔除两融
+	ListEmpty bool   `json:"listEmpty"` // 两融列表是否为空, 为空时跳过检测
+	IsMargin  bool   `json:"isMargin"`  // 是否两融标的
+	Passed    bool   `json:"passed"`    // 是否通过
+	Message   string `json:"message"`   // 说明
+}
+
+// SectorVerdict 板块成分检测结果
+type SectorVerdict struct {
+	Sectors    []string `json:"sectors"`    // 策略配置的板块
+	StockCount int      `json:"stockCount"` // 板块成分股数量
+	IsMember   bool     `json:"isMember"`   // 是否板块成分股
+	Passed     bool     `json:"passed"`     // 是否通过
+}
+
+// RuleVerdict 单条规则的执行结果
+type RuleVerdict struct {
+	Kind        rules.Kind `json:"kind"`        // 规则类型
+	Name        string     `json:"name"`        // 规则名称
+	Description string     `json:"description"` // 规则描述
+	Ignored     bool       `json:"ignored"`     // 是否被忽略或未被策略选择
+	Passed      bool       `json:"passed"`      // 是否通过
+	Error       string     `json:"error"`       // 不通过的原因, verbose模式下附带数值
+}
+
+// StrategyDiagnosis 个股在策略中的诊断结果
+type StrategyDiagnosis struct {
+	StrategyCode  uint64                    `json:"strategyCode"`            // 策略编码
+	StrategyName  string                    `json:"strategyName"`            // 策略名称
+	SecurityCode  string                    `json:"securityCode"`            // 证券代码
+	SecurityName  string                    `json:"securityName"`            // 证券名称
+	Date          string                    `json:"date"`                    // 检测日期
+	Passed        bool                      `json:"passed"`                  // 是否全部通过
+	FailedStep    int                       `json:"failedStep"`              // 失败的步骤, 0表示全部通过
+	Message       string                    `json:"message"`                 // 失败的原因
+	Snapshot      *factors.QuoteSnapshot    `json:"snapshot,omitempty"`      // 检测使用的快照
+	Parameter     *config.StrategyParameter `json:"parameter,omitempty"`     // 策略配置
+	MarginTrading *MarginTradingVerdict     `json:"marginTrading,omitempty"` // 两融剔除结果
+	Sector        *SectorVerdict            `json:"sector,omitempty"`        // 板块成分结果
+	Rules         []RuleVerdict             `json:"rules,omitempty"`         // 逐条规则结果
+	FilterError   string                    `json:"filterError,omitempty"`   // 策略Filter的返回
+	Evaluate      *models.ResultInfo        `json:"evaluate,omitempty"`      // 策略Evaluate的输出, nil表示未选中
+}
+
+// fail 记录失败的步骤
+func (this *StrategyDiagnosis) fail(step int, message string) {
+	this.Passed = false
+	this.FailedStep = step
+	this.Message = message
+}
+
+// DiagnosisDate 诊断实际使用的交易日, testDate为空时为实时快照的日期
+func DiagnosisDate(testDate string) string {
+	testDate = strings.TrimSpace(testDate)
+	if len(testDate) == 0 {
+		return cache.DefaultCanReadDate()
+	}
+	return exchange.FixTradeDate(testDate)
+}
+
+// DiagnoseStrategies 批量检测个股在策略中的执行情况
+//
+//	testDate为空时使用实时快照, 否则使用指定日期的历史特征数据
+func DiagnoseStrategies(strategyCode uint64, securityCodes []string, testDate string) []StrategyDiagnosis {
+	testDate = strings.TrimSpace(testDate)
+	if len(testDate) > 0 {
+		testDate = exchange.FixTradeDate(testDate)
+		// 持有历史日期的特征视图, 规则按快照日期读取, 不切换全局缓存日期
+		view := factors.Acquire(testDate)
+		defer view.Release()
+	}
+	list := make([]StrategyDiagnosis, 0, len(securityCodes))
+	for _, securityCode := range securityCodes {
+		securityCode = strings.TrimSpace(securityCode)
+		if len(securityCode) == 0 {
+			continue
+		}
+		securityCode = exchange.CorrectSecurityCode(securityCode)
+		list = append(list, diagnose(strategyCode, securityCode, testDate))
+	}
+	return list
+}
+
+// DiagnoseStrategy 检测个股在策略中的执行情况
+func DiagnoseStrategy(strategyCode uint64, securityCode, testDate string) StrategyDiagnosis {
+	list := DiagnoseStrategies(strategyCode, []string{securityCode}, testDate)
+	if len(list) == 0 {
+		return StrategyDiagnosis{StrategyCode: strategyCode, SecurityCode: securityCode, FailedStep: DiagnosisStepSnapshot, Message: "证券代码为空"}
+	}
+	return list[0]
+}
+
+// diagnose 检测单个证券, 逐步记录快照、配置、两融、板块、规则和Evaluate的结果
+func diagnose(strategyCode uint64, securityCode, testDate string) StrategyDiagnosis {
+	result := StrategyDiagnosis{
+		StrategyCode: strategyCode,
+		SecurityCode: securityCode,
+		SecurityName: securities.GetStockName(securityCode),
+		Passed:       true,
+	}
+	// 1. 获取快照
+	snapshot := diagnosisSnapshot(securityCode, testDate)
+	if len(testDate) == 0 {
+		testDate = cache.DefaultCanReadDate()
+	}
+	result.Date = testDate
+	if snapshot == nil {
+		result.fail(DiagnosisStepSnapshot, "获取快照失败")
+		return result
+	}
+	result.Snapshot = snapshot
+
+	// 2. 获取策略配置
+	strategyParameter := diagnosisParameter(strategyCode)
+	if strategyParameter == nil {
+		result.fail(DiagnosisStepStrategy, "策略未配置或未开启自动交易")
+		return result
+	}
+	result.StrategyName = strategyParameter.Name
+	result.Parameter = strategyParameter
+
+	// 3. 两融剔除
+	margin := MarginTradingVerdict{Required: strategyParameter.IgnoreMarginTrading, Passed: true}
+	if margin.Required {
+		marginTradingList := securities.MarginTradingList()
+		if len(marginTradingList) == 0 {
+			margin.ListEmpty = true
+			margin.Message = "两融列表为空, 跳过检测"
+		} else if slices.Contains(marginTradingList, securityCode) {
+			margin.IsMargin = true
+			margin.Passed = false
+			margin.Message = "两融标的"
+		}
+	}
+	result.MarginTrading = &margin
+	if !margin.Passed {
+		result.fail(DiagnosisStepMarginTrading, margin.Message)
+		return result
+	}
+
+	// 4. 板块成分
+	stockList := strategyParameter.StockList()
+	sector := SectorVerdict{
+		Sectors:    strategyParameter.Sectors,
+		StockCount: len(stockList),
+		IsMember:   slices.Contains(stockList, securityCode),
+	}
+	sector.Passed = sector.IsMember
+	result.Sector = &sector
+	if !sector.Passed {
+		result.fail(DiagnosisStepSector, "非策略配置的板块成分股")
+		return result
+	}
+
+	// 5. 获取策略对象
+	model, err := models.CheckoutStrategy(strategyCode)
+	if err != nil {
+		result.fail(DiagnosisStepModel, err.Error())
+		return result
+	}
+
+	// 6. 执行过滤规则, 逐条执行不短路, 便于一次看到全部不通过的原因
+	var ruleParameter config.RuleParameter
+	_ = api.Copy(&ruleParameter, &strategyParameter.Rules)
+	ruleParameter.Verbose = true
+	result.Rules = diagnoseRules(ruleParameter, *snapshot)
+	if err = model.Filter(ruleParameter, *snapshot); err != nil {
+		result.FilterError = err.Error()
+		result.fail(DiagnosisStepRules, err.Error())
+	}
+
+	// 7. 执行评估, 即使过滤未通过也执行, 仅作为参考
+	mapStock := concurrent.NewTreeMap[string, models.ResultInfo]()
+	models.Evaluate(model, securityCode, snapshot, mapStock)
+	if v, ok := mapStock.Get(securityCode); ok {
+		result.Evaluate = &v
+	} else if result.Passed {
+		result.fail(DiagnosisStepEvaluate, "策略评估未选中")
+	}
+	return result
+}
+
+// checkoutDiagnosisSnapshot 获取诊断使用的快照, testDate为空时取实时快照, 否则取宽表中截止testDate的最后一条
+func checkoutDiagnosisSnapshot(securityCode, testDate string) *factors.QuoteSnapshot {
+	if len(testDate) == 0 {
+		return models.GetTick(securityCode)
+	}
+	features := factors.CheckoutWideTableByDate(securityCode, testDate)
+	rows := len(features)
+	if rows == 0 {
+		return nil
+	}
+	tick := models.FeatureToSnapshot(features[rows-1], securityCode)
+	return &tick
+}
+
+// diagnoseRules 逐条执行已注册的规则
+func diagnoseRules(ruleParameter config.RuleParameter, snapshot factors.QuoteSnapshot) []RuleVerdict {
+	list := rules.GetAllRules()
+	verdicts := make([]RuleVerdict, 0, len(list))
+	for _, rule := range list {
+		v := RuleVerdict{
+			Kind:        rule.Kind(),
+			Name:        rule.Name(),
+			Description: rule.Description(),
+		}
+		if !rules.Enabled(ruleParameter, rule) {
+			v.Ignored = true
+			v.Passed = true
+		} else if err := rule.Exec(ruleParameter, snapshot); err != nil {
+			v.Error = err.Error()
+		} else {
+			v.Passed = true
+		}
+		verdicts = append(verdicts, v)
+	}
+	return verdicts
+}
@@ -0,0 +1,86 @@
+package tracker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"gitee.com/quant1x/gox/concurrent"
+
+	"xquant/pkg/config"
+	"xquant/pkg/factors"
+	"xquant/pkg/models"
+)
+
+func TestDiagnoseStrategies(t *testing.T) {
+	list := DiagnoseStrategies(1, []string{"sh600178", "sz000759"}, "2024-06-14")
+	data, _ := json.MarshalIndent(list, "", "  ")
+	fmt.Println(string(data))
+}
+
+// diagnosisTestModel 诊断测试用的策略, 过滤全部通过, 评估选中全部个股
+type diagnosisTestModel struct{}
+
+func (diagnosisTestModel) Code() models.ModelKind {
+	return 9902
+}
+
+func (diagnosisTestModel) Name() string {
+	return "诊断测试"
+}
+
+func (diagnosisTestModel) OrderFlag() string {
+	return models.OrderFlagTail
+}
+
+func (diagnosisTestModel) Filter(ruleParameter config.RuleParameter, snapshot factors.QuoteSnapshot) error {
+	return nil
+}
+
+func (diagnosisTestModel) Sort(snapshots []factors.QuoteSnapshot) models.SortedStatus {
+	return models.SortNotRequired
+}
+
+func (diagnosisTestModel) Evaluate(securityCode string, snapshot factors.QuoteSnapshot, result *concurrent.TreeMap[string, models.ResultInfo]) {
+	result.Put(securityCode, models.ResultInfo{Code: securityCode, Date: snapshot.Date})
+}
+
+func Test_diagnose(t *testing.T) {
+	if err := models.Register(diagnosisTestModel{}); err != nil && !errors.Is(err, models.ErrAlreadyExists) {
+		t.Fatalf("Register() failed: %v", err)
+	}
+	defer func() {
+		diagnosisSnapshot = checkoutDiagnosisSnapshot
+		diagnosisParameter = config.GetStrategyParameterByCode
+	}()
+	date := "2024-06-14"
+	code := "sh600178"
+	diagnosisSnapshot = func(securityCode, testDate string) *factors.QuoteSnapshot {
+		return &factors.QuoteSnapshot{Date: testDate, SecurityCode: securityCode, Price: 10, LastClose: 9.8}
+	}
+	diagnosisParameter = func(strategyCode uint64) *config.StrategyParameter {
+		if strategyCode != 9902 {
+			return nil
+		}
+		return &config.StrategyParameter{Id: strategyCode, Name: "诊断测试", Total: 1}
+	}
+	// 全部通过
+	v := diagnose(9902, code, date)
+	if !v.Passed || v.FailedStep != 0 || v.Date != date || v.Evaluate == nil {
+		t.Errorf("passed=%t, failed_step=%d, date=%s, message=%s", v.Passed, v.FailedStep, v.Date, v.Message)
+	}
+	// 策略未配置
+	v = diagnose(9901, code, date)
+	if v.Passed || v.FailedStep != DiagnosisStepStrategy || v.Date != date {
+		t.Errorf("passed=%t, failed_step=%d, date=%s", v.Passed, v.FailedStep, v.Date)
+	}
+	// 没有快照
+	diagnosisSnapshot = func(securityCode, testDate string) *factors.QuoteSnapshot {
+		return nil
+	}
+	v = diagnose(9902, code, date)
+	if v.Passed || v.FailedStep != DiagnosisStepSnapshot || v.Date != date {
+		t.Errorf("passed=%t, failed_step=%d, date=%s", v.Passed, v.FailedStep, v.Date)
+	}
+}
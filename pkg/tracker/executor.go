@@ -6,6 +6,7 @@ import (
 	"xquant/pkg/utils"
 
 	"xquant/pkg/config"
+	"xquant/pkg/factors"
 	"xquant/pkg/models"
 )
 
@@ -18,6 +19,7 @@ func ExecuteStrategy(model models.Strategy, barIndex *int) {
 	}
 	// 加载快照数据
 	models.SnapshotMgr.SyncAllSnapshots(context.Background(), utils.Ptr(1))
+	factors.FlushOrderBooks()
 	// 计算市场情绪
 	MarketSentiment()
 	// 扫描板块
@@ -107,11 +107,9 @@ func ScanAllSectors(barIndex *int, model models.Strategy) {
 		for j := 0; j < len(stockSnapshots); j++ {
 			gp := stockSnapshots[j]
 			total += 1
-			zfLimit := exchange.MarketLimit(gp.SecurityCode)
 			lastClose := num.Decimal(gp.LastClose)
-			zhangting := num.Decimal(lastClose * float64(1.000+zfLimit))
 			price := num.Decimal(gp.Price)
-			if price >= zhangting {
+			if factors.CheckoutPriceLimit(gp.SecurityCode, gp.Date, gp.LastClose).IsLimitUp(price) {
 				limits += 1
 			}
 			if price > lastClose {
@@ -261,5 +259,5 @@ func output(strategyNo uint64, v []models.ResultInfo) {
 // 个股评估
 func evaluate(api models.Strategy, wg *sync.WaitGroup, code string, result *concurrent.TreeMap[string, models.ResultInfo]) {
 	defer wg.Done()
-	api.Evaluate(code, result)
+	models.Evaluate(api, code, nil, result)
 }
@@ -3,8 +3,10 @@ package tracker
 import (
 	"fmt"
 
+	"gitee.com/quant1x/exchange"
 	"gitee.com/quant1x/num"
 	"github.com/fatih/color"
+	"xquant/pkg/emotion"
 	"xquant/pkg/models"
 )
 
@@ -32,4 +34,9 @@ func MarketSentiment() {
 	down := sh880005.AskVol1 + sh880005.AskVol2 + sh880005.AskVol3 + sh880005.AskVol4 + sh880005.AskVol5
 	//fmt.Printf("市场情绪：%.2f\n", 100*num.ChangeRate(up+down, up))
 	_, _ = fmt.Fprintf(color.Output, "\n市场情绪：%s\n", color.RedString("%.2f", 100*num.ChangeRate(up+down, up)))
+	// 情绪周期
+	if e := emotion.Checkout(exchange.GetCurrentlyDay()); e != nil {
+		_, _ = fmt.Fprintf(color.Output, "情绪周期：%s, 情绪分: %.2f, 涨停: %d, 炸板率: %.2f%%, 最高连板: %d, 跌停: %d\n",
+			color.RedString(e.PhaseName()), e.Score, e.LimitUpNum, e.BrokenRate, e.MaxBoards, e.LimitDownNum)
+	}
 }
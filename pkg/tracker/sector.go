@@ -1,6 +1,8 @@
 package tracker
 
 import (
+	"sync"
+
 	"gitee.com/quant1x/gotdx/securities"
 	"xquant/pkg/config"
 	"xquant/pkg/models"
@@ -106,9 +108,17 @@ func TopBlockWithType(pbarIndex *int, rule *config.StrategyParameter) map[securi
 
 var (
 	// 缓存板块类型名称
-	__mapBlockTypeName = map[string]string{}
+	__mutexBlockTypeName sync.RWMutex
+	__mapBlockTypeName   = map[string]string{}
 )
 
+// 缓存板块类型名称
+func setBlockTypeName(blockCode, typeName string) {
+	__mutexBlockTypeName.Lock()
+	defer __mutexBlockTypeName.Unlock()
+	__mapBlockTypeName[blockCode] = typeName
+}
+
 func init() {
 	_ = GetBlockList()
 }
@@ -125,13 +135,15 @@ func GetBlockList() []string {
 		blockCode := v.Code
 		blockCodes = append(blockCodes, blockCode)
 		blockTypeName, _ := securities.BlockTypeNameByTypeCode(v.Type)
-		__mapBlockTypeName[blockCode] = blockTypeName
+		setBlockTypeName(blockCode, blockTypeName)
 	}
 
 	return blockCodes
 }
 
 func BlockTypeName(blockCode string) string {
+	__mutexBlockTypeName.RLock()
+	defer __mutexBlockTypeName.RUnlock()
 	name, _ := __mapBlockTypeName[blockCode]
 	return name
 }
@@ -0,0 +1,118 @@
+package tracker
+
+import (
+	"time"
+
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/gotdx/securities"
+	"gitee.com/quant1x/gox/api"
+	"gitee.com/quant1x/gox/logger"
+	"gitee.com/quant1x/num"
+	"xquant/pkg/factors"
+	"xquant/pkg/models"
+	"xquant/pkg/sector"
+)
+
+var (
+	// 参与排名持久化的板块类型
+	rankingBlockTypes = []securities.BlockType{securities.BK_HANGYE, securities.BK_GAINIAN}
+)
+
+// CollectSectorRankings 采集行业和概念板块的排名
+//
+//	同类型板块按盘中排序规则排名, 排名从1开始, 成分股统计涨停数、领涨个股和换手率
+func CollectSectorRankings() []sector.Ranking {
+	now := time.Now().Format(time.TimeOnly)
+	var list []sector.Ranking
+	for _, blockType := range rankingBlockTypes {
+		typeName, _ := securities.BlockTypeNameByTypeCode(blockType)
+		var snapshots []factors.QuoteSnapshot
+		for _, v := range securities.BlockList() {
+			if v.Type != blockType {
+				continue
+			}
+			snapshot := models.SnapshotMgr.GetStrategySnapshot(v.Code)
+			if snapshot == nil {
+				continue
+			}
+			snapshots = append(snapshots, *snapshot)
+		}
+		api.SliceSort(snapshots, SectorSortForTick)
+		rank := 0
+		for _, v := range snapshots {
+			stockCodes := securities.GetBlockInfo(v.SecurityCode).ConstituentStocks
+			if len(stockCodes) == 0 {
+				continue
+			}
+			rank++
+			ranking := sector.Ranking{
+				Time:           now,
+				Code:           v.SecurityCode,
+				Name:           v.Name,
+				Type:           typeName,
+				Rank:           rank,
+				ChangeRate:     v.ChangeRate,
+				OpenChangeRate: v.OpeningChangeRate,
+				Amount:         v.Amount,
+			}
+			statSectorStocks(&ranking, stockCodes)
+			list = append(list, ranking)
+		}
+	}
+	return list
+}
+
+// statSectorStocks 统计板块成分股的涨跌家数、涨停数、领涨个股和换手率
+func statSectorStocks(ranking *sector.Ranking, stockCodes []string) {
+	var vol, capital float64
+	ranking.TopRate = -100
+	for _, stockCode := range stockCodes {
+		securityCode := exchange.CorrectSecurityCode(stockCode)
+		snapshot := models.SnapshotMgr.GetStrategySnapshot(securityCode)
+		if snapshot == nil || snapshot.LastClose <= 0 {
+			continue
+		}
+		ranking.Count++
+		if snapshot.Price > snapshot.LastClose {
+			ranking.UpCount++
+		} else if snapshot.Price < snapshot.LastClose {
+			ranking.DownCount++
+		}
+		if factors.CheckoutPriceLimit(securityCode, snapshot.Date, snapshot.LastClose).IsLimitUp(snapshot.Price) {
+			ranking.LimitUpNum++
+		}
+		rate := num.NetChangeRate(snapshot.LastClose, snapshot.Price)
+		if rate > ranking.TopRate {
+			ranking.TopCode, ranking.TopName, ranking.TopRate = securityCode, snapshot.Name, rate
+		}
+		freeCapital := snapshot.FreeCapital
+		if freeCapital <= 0 {
+			freeCapital = snapshot.Capital
+		}
+		if freeCapital > 0 {
+			vol += float64(snapshot.Vol)
+			capital += freeCapital
+		}
+	}
+	if ranking.Count == 0 {
+		ranking.TopRate = 0
+	}
+	if capital > 0 {
+		// 成交量单位为手, 换手率以百分比表示
+		ranking.TurnoverRate = num.Decimal(vol * 100 / capital * 100)
+	}
+}
+
+// PersistSectorRankings 采集并保存当日的板块排名
+//
+//	intraday为true时同时追加到盘中排名文件
+func PersistSectorRankings(intraday bool) {
+	list := CollectSectorRankings()
+	if len(list) == 0 {
+		return
+	}
+	date := exchange.GetCurrentlyDay()
+	if err := sector.SaveRankings(date, list, intraday); err != nil {
+		logger.Errorf("板块排名保存失败: %s, error=%+v", date, err)
+	}
+}
@@ -24,7 +24,7 @@ func scanSectorSnapshots(pbarIndex *int, blockType securities.BlockType, isHead
 		blockCode := v.Code
 		blockCodes = append(blockCodes, blockCode)
 		blockTypeName, _ := securities.BlockTypeNameByTypeCode(v.Type)
-		__mapBlockTypeName[blockCode] = blockTypeName
+		setBlockTypeName(blockCode, blockTypeName)
 	}
 
 	blockCount := len(blockCodes)
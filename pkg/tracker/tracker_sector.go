@@ -93,11 +93,9 @@ func ScanSectorForTick(barIndex *int) []string {
 		for j := 0; j < len(stockSnapshots); j++ {
 			gp := stockSnapshots[j]
 			total += 1
-			zfLimit := exchange.MarketLimit(gp.SecurityCode)
 			lastClose := num.Decimal(gp.LastClose)
-			zhangting := num.Decimal(lastClose * float64(1.000+zfLimit))
 			price := num.Decimal(gp.Price)
-			if price >= zhangting {
+			if factors.CheckoutPriceLimit(gp.SecurityCode, gp.Date, gp.LastClose).IsLimitUp(price) {
 				limits += 1
 			}
 			if price > lastClose {
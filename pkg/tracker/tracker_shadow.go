@@ -0,0 +1,193 @@
+package tracker
+
+import (
+	"gitee.com/quant1x/exchange"
+	"gitee.com/quant1x/num"
+
+	"xquant/pkg/config"
+	"xquant/pkg/factors"
+	"xquant/pkg/log"
+	"xquant/pkg/models"
+	"xquant/pkg/storages"
+	"xquant/pkg/trader"
+)
+
+// triggerShadowBuy 影子模式的买入检查
+//
+//	流程和triggerBuyCheck一致, 按价格笼子计算委托价, 只记录虚拟订单, 不调用PlaceOrder.
+//	不检查真实账户的禁买清单和可用资金, 每个标的按策略的最大费用估算委托数量
+func triggerShadowBuy(model models.Strategy, tradeDate string, tradeRule *config.StrategyParameter) bool {
+	maxBuyTotal := tradeRule.Total
+	boughtCount := storages.CountShadowOrders(tradeDate, model.Code())
+	if boughtCount >= maxBuyTotal {
+		log.Infof("%s[%d]: 影子模式买入配额已用完（计划%d，已完成%d）", model.Name(), model.Code(), maxBuyTotal, boughtCount)
+		return true
+	}
+
+	poolMutex.Lock()
+	localPool := getStockPoolFromCache()
+	poolMutex.Unlock()
+
+	var buyTargets []storages.StockPool
+	for _, v := range localPool {
+		if v.Date != tradeDate || v.StrategyCode != model.Code() || v.OrderStatus != 1 {
+			continue
+		}
+		if storages.CheckShadowOrder(tradeDate, model.Code(), v.Code) {
+			continue
+		}
+		buyTargets = append(buyTargets, v)
+	}
+	if len(buyTargets) == 0 {
+		return false
+	}
+	// 影子模式不依赖账户资金, 单个标的按最大费用估算
+	singleFunds := tradeRule.FeeMax
+
+	completedCount := boughtCount
+	for _, target := range buyTargets {
+		if completedCount >= maxBuyTotal {
+			break
+		}
+		buyPrice := trader.CalculatePriceCage(*tradeRule, trader.BUY, target.Buy)
+		buyPrice = factors.CheckoutPriceLimit(target.Code, tradeDate, 0).Clamp(buyPrice)
+		tradeFee := trader.EvaluateFeeForBuy(target.Code, singleFunds, buyPrice)
+		if tradeFee.Volume <= trader.InvalidVolume {
+			log.Errorf("%s[%d]: 影子模式标的%s可买数量为0，跳过", model.Name(), model.Code(), target.Code)
+			continue
+		}
+		order := storages.ShadowOrder{
+			Date:         tradeDate,
+			StrategyCode: model.Code(),
+			StrategyName: model.Name(),
+			Code:         target.Code,
+			Name:         target.Name,
+			SignalPrice:  target.Buy,
+			OrderPrice:   tradeFee.Price,
+			Volume:       tradeFee.Volume,
+		}
+		if err := storages.PlaceShadowOrder(order); err != nil {
+			log.Errorf("%s[%d]: 影子订单%s记录失败：%v", model.Name(), model.Code(), target.Code, err)
+			continue
+		}
+		completedCount++
+		log.Infof("%s[%d]: 影子模式虚拟委托%s，价格%.2f，数量%d", model.Name(), model.Code(), target.Code, tradeFee.Price, tradeFee.Volume)
+	}
+	return completedCount >= maxBuyTotal
+}
+
+// MatchShadowOrders 用内存中的最新快照撮合当日的影子订单
+func MatchShadowOrders(date string, closed bool) {
+	tradeDate := exchange.FixTradeDate(date)
+	filled, err := storages.MatchShadowOrders(tradeDate, models.SnapshotMgr.GetStrategySnapshot, closed)
+	if err != nil {
+		log.Errorf("影子订单撮合失败：%v", err)
+		return
+	}
+	if filled > 0 {
+		log.Infof("影子订单撮合完成，新增成交%d笔", filled)
+	}
+}
+
+// StrategyReport 策略运行报告, 实盘和影子模式使用相同的口径
+type StrategyReport struct {
+	StrategyCode  uint64  `name:"策略编码" json:"strategy_code"`
+	StrategyName  string  `name:"策略名称" json:"strategy_name"`
+	Mode          string  `name:"运行模式" json:"mode"`
+	Hits          int     `name:"命中数" json:"hits"`
+	HitYield      float64 `name:"命中收益率%" json:"hit_yield"`
+	Orders        int     `name:"委托数" json:"orders"`
+	Filled        int     `name:"成交数" json:"filled"`
+	FillRate      float64 `name:"成交率%" json:"fill_rate"`
+	OrderYield    float64 `name:"成交收益率%" json:"order_yield"`
+	OrderedVolume int     `name:"委托数量" json:"ordered_volume"`
+}
+
+// StrategyReports 生成指定日期实盘和影子模式策略的对照报告
+//
+//	命中收益率按股票池信号价到最新价计算, 成交收益率按成交价到最新价计算
+func StrategyReports(date string) []StrategyReport {
+	tradeDate := exchange.FixTradeDate(date)
+	snapshotOf := models.SnapshotMgr.GetStrategySnapshot
+	latestPrice := func(code string, elseValue float64) float64 {
+		if snapshot := snapshotOf(code); snapshot != nil && snapshot.Price > 0 {
+			return snapshot.Price
+		}
+		return elseValue
+	}
+
+	poolMutex.Lock()
+	localPool := getStockPoolFromCache()
+	poolMutex.Unlock()
+	shadowOrders := storages.GetShadowOrders(tradeDate)
+	liveOrders := trader.GetOrderList(tradeDate)
+
+	var reports []StrategyReport
+	for _, strategyParameter := range config.TraderConfig().Strategies {
+		if !strategyParameter.Running() {
+			continue
+		}
+		report := StrategyReport{
+			StrategyCode: strategyParameter.Id,
+			StrategyName: strategyParameter.Name,
+			Mode:         strategyParameter.RunMode(),
+		}
+		// 1. 命中
+		var hitYields []float64
+		for _, v := range localPool {
+			if v.Date != tradeDate || v.StrategyCode != report.StrategyCode || v.Status.IsCancel() {
+				continue
+			}
+			report.Hits++
+			if v.Buy > 0 {
+				hitYields = append(hitYields, num.NetChangeRate(v.Buy, latestPrice(v.Code, v.Buy)))
+			}
+		}
+		report.HitYield = averageOf(hitYields)
+		// 2. 订单
+		var orderYields []float64
+		if strategyParameter.IsShadow() {
+			for _, v := range shadowOrders {
+				if v.StrategyCode != report.StrategyCode {
+					continue
+				}
+				report.Orders++
+				report.OrderedVolume += v.Volume
+				if v.IsFilled() {
+					report.Filled++
+					orderYields = append(orderYields, num.NetChangeRate(v.FillPrice, latestPrice(v.Code, v.LastPrice)))
+				}
+			}
+		} else {
+			qmtStrategyName := strategyParameter.QmtStrategyName()
+			for _, v := range liveOrders {
+				if v.StrategyName != qmtStrategyName || v.OrderType != trader.STOCK_BUY {
+					continue
+				}
+				report.Orders++
+				report.OrderedVolume += v.OrderVolume
+				if v.TradedVolume > 0 && v.TradedPrice > 0 {
+					report.Filled++
+					orderYields = append(orderYields, num.NetChangeRate(v.TradedPrice, latestPrice(v.SecurityCode(), v.TradedPrice)))
+				}
+			}
+		}
+		if report.Orders > 0 {
+			report.FillRate = 100 * float64(report.Filled) / float64(report.Orders)
+		}
+		report.OrderYield = averageOf(orderYields)
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+func averageOf(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total / float64(len(values))
+}
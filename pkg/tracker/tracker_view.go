@@ -22,6 +22,7 @@ import (
 
 // HandleTrackerResult 跟踪结果处理器：协调“数据转换→表格输出→股票池→交易检查”流程
 // 作为 snapshotTracker 的“输出结果”环节入口，职责仅为流程调度
+// 影子模式的策略同样执行全部流程，只在交易检查环节记录虚拟订单
 func HandleTrackerResult(model models.Strategy, sortedSnapshots []factors.QuoteSnapshot) {
 	// 1. 第一步：将快照转换为统计模型（Statistics）
 	stats, currentDate, updateTime, err := buildStatistics(sortedSnapshots)
@@ -216,7 +217,7 @@ func calculateWinRate(stats []models.Statistics) (int, int, int, int, int, float
 func processStockPool(model models.Strategy, date string, stats []models.Statistics) error {
 	// 1. 先获取策略参数，判断是否需要处理股票池
 	tradeRule := config.GetStrategyParameterByCode(model.Code())
-	if tradeRule == nil || !tradeRule.Enable() || tradeRule.Total == 0 {
+	if tradeRule == nil || !tradeRule.Running() || tradeRule.Total == 0 {
 		return errors.New("策略未启用或无配置，跳过股票池处理")
 	}
 	topN := tradeRule.Total
@@ -309,7 +310,8 @@ func triggerBuyCheck(model models.Strategy, date string, stats []models.Statisti
 	}
 
 	tradeRule := config.GetStrategyParameterByCode(model.Code())
-	if tradeRule == nil || !tradeRule.BuyEnable() {
+	// 影子模式只记录虚拟订单, 不要求自动执行
+	if tradeRule == nil || !(tradeRule.BuyEnable() || tradeRule.ShadowBuyEnable()) {
 		log.Errorf("%s[%d]: 策略未配置买入或买入未启用，跳过", model.Name(), model.Code())
 		return false
 	}
@@ -321,6 +323,10 @@ func triggerBuyCheck(model models.Strategy, date string, stats []models.Statisti
 
 	// 2. 初始化变量
 	tradeDate := exchange.FixTradeDate(date)
+	// 影子模式只记录虚拟订单, 不下单
+	if tradeRule.IsShadow() {
+		return triggerShadowBuy(model, tradeDate, tradeRule)
+	}
 	direction := trader.BUY
 	maxBuyTotal := tradeRule.Total
 	// 统计已买入的标的数量
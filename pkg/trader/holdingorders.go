@@ -1,12 +1,14 @@
 package trader
 
 import (
+	"math"
 	"slices"
 	"sync"
 	
 	"gitee.com/quant1x/exchange"
 	"gitee.com/quant1x/gox/api"
 	"gitee.com/quant1x/gox/logger"
+	"xquant/pkg/factors"
 )
 
 //// HoldingOrder 持仓订单
@@ -93,6 +95,8 @@ func lazyLoadHoldingOrder() {
 		volume := position.Volume
 		// 矫正证券代码
 		securityCode := exchange.CorrectSecurityCode(position.StockCode)
+		// 除权除息日历, 持仓数量包含了送转股
+		dividends := factors.DividendEvents(securityCode)
 		// 历史记录合计买数量
 		tmpTradedVolume := 0
 		// 最早的持股日期
@@ -138,7 +142,9 @@ func lazyLoadHoldingOrder() {
 				}
 			}
 			earlierDate = date
-			tmpTradedVolume += currentTradedVolume
+			// 历史成交数量按之后的送转股折算到最后一个交易日
+			factor := factors.ShareFactor(dividends, date, lastTradeDate)
+			tmpTradedVolume += int(math.Floor(float64(currentTradedVolume)*factor + 1e-6))
 			if tmpTradedVolume == volume {
 				// 如果订单合计成交量等于持仓量, 则退出
 				break
@@ -9,6 +9,7 @@ import (
 	"gitee.com/quant1x/gox/logger"
 	"sync"
 	"xquant/pkg/cache"
+	"xquant/pkg/factors"
 	"xquant/pkg/models"
 )
 
@@ -41,6 +42,7 @@ type Position struct {
 	SellPrice       float64 `name:"卖出价格" dataframe:"sell_price"`       // 卖出价格
 	SellVolume      int     `name:"卖出数量" dataframe:"sell_volume"`      // 卖出数量
 	CancelTime      string  `name:"撤单时间" dataframe:"cancel_time"`      // 撤单时间
+	ExRightsDate    string  `name:"除权日期" dataframe:"ex_rights_date"`   // 最近一次调整持仓的除权除息日
 	UpdateTime      string  `name:"更新时间" dataframe:"update_time"`      // 更新时间
 }
 
@@ -116,6 +118,28 @@ func (p *Position) MergeFromOrder(order OrderDetail) bool {
 	return true
 }
 
+// ApplyDividend 除权除息日调整持仓数量和成本价, 同一个除权除息日只调整一次
+//
+//	股权登记日之后建仓的持仓不参与分红送转, 现金分红从成本中扣除
+func (p *Position) ApplyDividend(event factors.DividendEvent) bool {
+	if p.Volume <= 0 || event.ExDate <= p.ExRightsDate {
+		return false
+	}
+	if len(p.CreateTime) >= len(event.RecordDate) && p.CreateTime[:len(event.RecordDate)] > event.RecordDate {
+		return false
+	}
+	volume := p.Volume
+	newVolume, avgPrice, _ := event.AdjustPosition(volume, p.AvgPrice)
+	_, openPrice, _ := event.AdjustPosition(volume, p.OpenPrice)
+	// 送转股当日到账
+	p.Volume = newVolume
+	p.CanUseVolume += newVolume - volume
+	p.AvgPrice = avgPrice
+	p.OpenPrice = openPrice
+	p.ExRightsDate = event.ExDate
+	return true
+}
+
 var (
 	periodicOnce   coroutine.PeriodicOnce
 	mutexPositions sync.RWMutex
@@ -156,6 +180,7 @@ func SyncPositions() {
 	if err != nil {
 		return
 	}
+	today := exchange.GetCurrentlyDay()
 	for _, v := range list {
 		securityCode := exchange.CorrectSecurityCode(v.StockCode)
 		position, found := mapPositions.Get(securityCode)
@@ -168,6 +193,10 @@ func SyncPositions() {
 		}
 		ok := position.Sync(v)
 		if ok {
+			// 券商的持仓已经按除权除息调整过, 标记之后不再重复调整
+			if event, found := factors.DividendOnDate(factors.DividendEvents(securityCode), today); found {
+				position.ExRightsDate = event.ExDate
+			}
 			mapPositions.Put(securityCode, position)
 		}
 	}
@@ -180,6 +209,8 @@ func UpdatePositions() {
 	if err != nil {
 		return
 	}
+	// 先按除权除息调整隔夜持仓, 再合并当日订单
+	ApplyDividends(exchange.GetCurrentlyDay())
 	for _, v := range list {
 		securityCode := exchange.CorrectSecurityCode(v.StockCode)
 		position, found := mapPositions.Get(securityCode)
@@ -197,6 +228,24 @@ func UpdatePositions() {
 	}
 }
 
+// ApplyDividends 除权除息日是date的个股调整持仓
+func ApplyDividends(date string) {
+	periodicOnce.Do(lazyLoadLocalPositions)
+	date = exchange.FixTradeDate(date)
+	mapPositions.Each(func(key string, value *Position) {
+		if value.Volume <= 0 {
+			return
+		}
+		event, ok := factors.DividendOnDate(factors.DividendEvents(value.SecurityCode), date)
+		if !ok {
+			return
+		}
+		if value.ApplyDividend(event) {
+			logger.Warnf("%s 除权除息, 持仓调整为%d股, 成本价%.3f", key, value.Volume, value.AvgPrice)
+		}
+	})
+}
+
 // CacheSync 缓存同步
 func CacheSync() {
 	methodName := "CacheSync"
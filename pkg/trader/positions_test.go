@@ -2,7 +2,10 @@ package trader
 
 import (
 	"context"
+	"math"
 	"testing"
+
+	"xquant/pkg/factors"
 	"xquant/pkg/models"
 )
 
@@ -13,3 +16,23 @@ func TestCacheSync(t *testing.T) {
 	SyncPositions()
 	CacheSync()
 }
+
+func TestPosition_ApplyDividend(t *testing.T) {
+	event := factors.DividendEvent{Code: "sh600000", RecordDate: "2024-06-18", ExDate: "2024-06-19", CashDividend: 0.5, BonusShares: 0.3}
+	p := Position{SecurityCode: "sh600000", Volume: 1000, CanUseVolume: 1000, OpenPrice: 13.5, AvgPrice: 13.5, CreateTime: "2024-06-18 09:31:00"}
+	if !p.ApplyDividend(event) {
+		t.Fatalf("position should be adjusted")
+	}
+	if p.Volume != 1300 || p.CanUseVolume != 1300 || math.Abs(p.AvgPrice-10) > 1e-9 || math.Abs(p.OpenPrice-10) > 1e-9 {
+		t.Errorf("%+v", p)
+	}
+	// 同一个除权除息日只调整一次
+	if p.ApplyDividend(event) {
+		t.Errorf("position adjusted twice")
+	}
+	// 除权除息日建仓不参与分红送转
+	p = Position{SecurityCode: "sh600000", Volume: 1000, AvgPrice: 10, CreateTime: "2024-06-19 09:31:00"}
+	if p.ApplyDividend(event) {
+		t.Errorf("position created on ex-date should not be adjusted")
+	}
+}
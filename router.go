@@ -5,6 +5,11 @@ package main
 import (
 	"github.com/cloudwego/hertz/pkg/app/server"
 	handler "xquant/biz/handler"
+	"xquant/biz/handler/config"
+	"xquant/biz/handler/indicator"
+	"xquant/biz/handler/research"
+	"xquant/biz/handler/sector"
+	"xquant/biz/handler/strategy"
 	"xquant/biz/handler/tracker"
 )
 
@@ -13,6 +18,30 @@ func customizedRegister(r *server.Hertz) {
 	r.GET("/ping", handler.Ping)
 
 	r.POST("/tracker", tracker.Tracker)
+	r.GET("/tracker/report", tracker.StrategyReport)
+
+	// 策略目录
+	_strategy := r.Group("/strategy")
+	_strategy.GET("/list", strategy.ListStrategies)
+	_strategy.GET("/schema", strategy.StrategySchema)
+	_strategy.GET("/parameter", strategy.EffectiveParameter)
+
+	// 配置热加载
+	r.POST("/config/reload", config.ReloadConfig)
+
+	// 因子研究
+	r.POST("/research/factor", research.FactorResearch)
+
+	// 技术指标
+	_indicator := r.Group("/indicator")
+	_indicator.GET("/list", indicator.ListIndicators)
+	_indicator.GET("/chart", indicator.IndicatorChart)
+	_indicator.POST("/chart", indicator.IndicatorChart)
+
+	// 板块轮动
+	_sector := r.Group("/sector")
+	_sector.GET("/ranking", sector.SectorRanking)
+	_sector.GET("/rotation", sector.SectorRotation)
 
 	// your code ...
 }